@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"cm/internal/config"
+	"cm/internal/docker"
+)
+
+// listTimeout bounds the container listing behind tab completion
+const listTimeout = 5 * time.Second
+
+// completionScripts are the scripts --completion prints, per shell. Container
+// names come from the hidden --list-containers flag.
+var completionScripts = map[string]string{
+	"bash": `# cm bash completion: source <(cm --completion bash)
+_cm() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local prev="${COMP_WORDS[COMP_CWORD-1]}"
+    case "$prev" in
+        --completion)
+            COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur"))
+            return
+            ;;
+        --debug-file)
+            COMPREPLY=($(compgen -f -- "$cur"))
+            return
+            ;;
+    esac
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W "--help --version --doctor --debug --debug-file --completion --usage-full" -- "$cur"))
+    else
+        COMPREPLY=($(compgen -W "$(cm --list-containers 2>/dev/null)" -- "$cur"))
+    fi
+}
+complete -F _cm cm
+`,
+	"zsh": `# cm zsh completion: source <(cm --completion zsh)
+_cm() {
+    local state
+    _arguments \
+        '(-h --help)'{-h,--help}'[Show help]' \
+        '(-v --version)'{-v,--version}'[Show version information]' \
+        '--doctor[Check Docker, compose, clipboard, terminal and config files]' \
+        '(-d --debug)'{-d,--debug}'[Enable debug logging]' \
+        '--debug-file[Write debug logging to a file]:file:_files' \
+        '--completion[Print a shell completion script]:shell:(bash zsh fish)' \
+        '--usage-full[Print every flag, key binding and setting]' \
+        '*:container:->containers'
+    if [[ $state == containers ]]; then
+        local -a names
+        names=(${(f)"$(cm --list-containers 2>/dev/null)"})
+        compadd -a names
+    fi
+}
+compdef _cm cm
+`,
+	"fish": `# cm fish completion: cm --completion fish | source
+complete -c cm -f
+complete -c cm -s h -l help -d 'Show help'
+complete -c cm -s v -l version -d 'Show version information'
+complete -c cm -l doctor -d 'Check Docker, compose, clipboard, terminal and config files'
+complete -c cm -s d -l debug -d 'Enable debug logging'
+complete -c cm -l debug-file -r -F -d 'Write debug logging to a file'
+complete -c cm -l completion -x -a 'bash zsh fish' -d 'Print a shell completion script'
+complete -c cm -l usage-full -d 'Print every flag, key binding and setting'
+complete -c cm -a '(cm --list-containers 2>/dev/null)'
+`,
+}
+
+// runCompletion prints the completion script for shell and returns the exit code
+func runCompletion(shell string) int {
+	script, ok := completionScripts[shell]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: no completion for %q, use bash, zsh or fish\n", shell)
+		return 1
+	}
+	fmt.Print(script)
+	return 0
+}
+
+// runListContainers prints the names cm accepts as arguments, one per line:
+// the service and container names of running containers and the saved
+// projects with a view. It backs container name completion.
+func runListContainers() int {
+	dockerClient, err := docker.NewClient()
+	if err != nil {
+		return 1
+	}
+	defer dockerClient.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), listTimeout)
+	defer cancel()
+	running, err := dockerClient.ListRunningNames(ctx)
+	if err != nil {
+		return 1
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range running {
+		seen[name] = true
+	}
+	for name, proj := range config.LoadProjects().SavedProjects {
+		if proj.View != nil {
+			seen[name] = true
+		}
+	}
+	delete(seen, "")
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Println(name)
+	}
+	return 0
+}
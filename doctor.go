@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"cm/internal/config"
+	"cm/internal/docker"
+	"cm/internal/notify"
+
+	"github.com/atotto/clipboard"
+)
+
+// doctorTimeout bounds each check that talks to Docker
+const doctorTimeout = 5 * time.Second
+
+// checkStatus is the outcome of a single doctor check
+type checkStatus int
+
+const (
+	checkOK checkStatus = iota
+	checkWarn
+	checkFail
+)
+
+// doctorCheck is one line of the doctor report
+type doctorCheck struct {
+	name   string
+	status checkStatus
+	detail string
+}
+
+// runDoctor checks the environment cm depends on, prints a report and
+// returns the exit code: 1 if any check failed
+func runDoctor() int {
+	checks := []doctorCheck{checkDocker(), checkCompose(), checkClipboard(), checkTerminal()}
+	for _, f := range []struct {
+		name, path string
+		v          interface{}
+	}{
+		{"config.json", config.GetConfigPath(), &config.Config{}},
+		{"keybindings.json", config.GetKeybindingsPath(), &config.KeyBindings{}},
+		{"projects.json", config.GetProjectsPath(), &config.Projects{}},
+	} {
+		checks = append(checks, checkConfigFile(f.name, f.path, f.v))
+	}
+	checks = append(checks, checkConfigDirWritable())
+
+	fmt.Printf("cm %s doctor\n\n", Version)
+	code := 0
+	for _, c := range checks {
+		mark := "✓"
+		switch c.status {
+		case checkWarn:
+			mark = "!"
+		case checkFail:
+			mark = "✗"
+			code = 1
+		}
+		fmt.Printf("  %s %-18s %s\n", mark, c.name, c.detail)
+	}
+	return code
+}
+
+// checkDocker pings the daemon and reports its version
+func checkDocker() doctorCheck {
+	client, err := docker.NewClient()
+	if err != nil {
+		return doctorCheck{"docker", checkFail, err.Error()}
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+	version, apiVersion, err := client.ServerVersion(ctx)
+	if err != nil {
+		return doctorCheck{"docker", checkFail, err.Error()}
+	}
+	return doctorCheck{"docker", checkOK, fmt.Sprintf("engine %s (API %s)", version, apiVersion)}
+}
+
+// checkCompose looks for the docker compose v2 plugin
+func checkCompose() doctorCheck {
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+	version, err := docker.ComposeVersion(ctx)
+	if err != nil {
+		return doctorCheck{"compose", checkWarn, err.Error() + " (compose actions won't work)"}
+	}
+	return doctorCheck{"compose", checkOK, version}
+}
+
+// checkClipboard reports whether copying to the clipboard can work
+func checkClipboard() doctorCheck {
+	if clipboard.Unsupported {
+		return doctorCheck{"clipboard", checkWarn, "no clipboard utility found (install xclip, xsel or wl-clipboard)"}
+	}
+	return doctorCheck{"clipboard", checkOK, "available"}
+}
+
+// checkTerminal reports the terminal notifications are sent for
+func checkTerminal() doctorCheck {
+	mode := config.DefaultNotificationSettings().Mode
+	if cfg, err := config.Load(); err == nil {
+		mode = cfg.GetNotificationSettings().Mode
+	}
+
+	terminal := notify.GetTerminal()
+	if terminal == "unknown" && mode == config.NotifyTerminal {
+		return doctorCheck{"terminal", checkWarn, "unknown terminal; terminal notifications may not show"}
+	}
+	return doctorCheck{"terminal", checkOK, fmt.Sprintf("%s (notifications: %s)", terminal, mode)}
+}
+
+// checkConfigFile reports whether a config file decodes into v
+func checkConfigFile(name, path string, v interface{}) doctorCheck {
+	if path == "" {
+		return doctorCheck{name, checkFail, "cannot determine home directory"}
+	}
+	if err := config.CheckFile(path, v); err != nil {
+		return doctorCheck{name, checkFail, fmt.Sprintf("%v (defaults are used instead)", err)}
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return doctorCheck{name, checkOK, path + " (not created yet, defaults are used)"}
+	}
+	return doctorCheck{name, checkOK, path}
+}
+
+// checkConfigDirWritable makes sure settings, exports and logs can be saved
+func checkConfigDirWritable() doctorCheck {
+	path := config.GetConfigPath()
+	if path == "" {
+		return doctorCheck{"config dir", checkFail, "cannot determine home directory"}
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return doctorCheck{"config dir", checkFail, err.Error()}
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return doctorCheck{"config dir", checkFail, fmt.Sprintf("%s is not writable: %v", dir, err)}
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return doctorCheck{"config dir", checkOK, dir + " is writable"}
+}
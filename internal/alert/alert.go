@@ -0,0 +1,224 @@
+package alert
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"cm/internal/config"
+	"cm/internal/docker"
+)
+
+// Metrics a rule can watch
+const (
+	MetricCPU    = "cpu"
+	MetricMemory = "memory"
+)
+
+// Alert is a notification raised by a rule
+type Alert struct {
+	Title   string
+	Message string
+}
+
+// maxLineLength caps how much of a matching log line a notification shows
+const maxLineLength = 200
+
+// rule is a validated config.AlertRule
+type rule struct {
+	config.AlertRule
+	match *regexp.Regexp
+}
+
+// key identifies one rule watching one container
+type key struct {
+	rule      int
+	container string
+}
+
+// Engine evaluates alert rules against the stats samples and log lines the
+// log view receives. It is not safe for concurrent use.
+type Engine struct {
+	rules    []rule
+	since    time.Time         // log lines from before the engine started are history, not news
+	breaches map[key]time.Time // when a metric rule's threshold was first exceeded
+	fired    map[key]time.Time // when a rule last fired for a container
+	now      func() time.Time
+}
+
+// New creates an engine for the rules. Rules that can't be evaluated are
+// left out and reported, one error each.
+func New(rules []config.AlertRule) (*Engine, []error) {
+	e := &Engine{
+		breaches: make(map[key]time.Time),
+		fired:    make(map[key]time.Time),
+		now:      time.Now,
+	}
+	e.since = e.now()
+
+	var errs []error
+	for i, r := range rules {
+		compiled, err := compile(r)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("alert %d (%s): %w", i+1, describe(r), err))
+			continue
+		}
+		e.rules = append(e.rules, compiled)
+	}
+	return e, errs
+}
+
+// compile validates a rule and compiles its pattern
+func compile(r config.AlertRule) (rule, error) {
+	if _, err := path.Match(r.Container, ""); err != nil {
+		return rule{}, fmt.Errorf("bad container pattern %q", r.Container)
+	}
+	switch {
+	case r.Metric != "" && r.Match != "":
+		return rule{}, fmt.Errorf("set either metric or match, not both")
+	case r.Match != "":
+		re, err := regexp.Compile(r.Match)
+		if err != nil {
+			return rule{}, fmt.Errorf("bad match pattern: %w", err)
+		}
+		return rule{AlertRule: r, match: re}, nil
+	case r.Metric == MetricCPU || r.Metric == MetricMemory:
+		if r.Above <= 0 {
+			return rule{}, fmt.Errorf("%s rule needs a threshold above 0", r.Metric)
+		}
+		return rule{AlertRule: r}, nil
+	case r.Metric != "":
+		return rule{}, fmt.Errorf("unknown metric %q, want %q or %q", r.Metric, MetricCPU, MetricMemory)
+	default:
+		return rule{}, fmt.Errorf("set a metric or a match pattern")
+	}
+}
+
+// describe names a rule for notifications and errors
+func describe(r config.AlertRule) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	target := r.Container
+	if target == "" {
+		target = "any container"
+	}
+	if r.Match != "" {
+		return fmt.Sprintf("%s logged /%s/", target, r.Match)
+	}
+	s := fmt.Sprintf("%s %s > %g%%", target, r.Metric, r.Above)
+	if r.For > 0 {
+		s += fmt.Sprintf(" for %ds", r.For)
+	}
+	return s
+}
+
+// Len returns the number of rules being evaluated
+func (e *Engine) Len() int {
+	if e == nil {
+		return 0
+	}
+	return len(e.rules)
+}
+
+// appliesTo reports whether a rule watches the container, matching its
+// pattern against the service, container and project/service names
+func (r rule) appliesTo(c docker.Container) bool {
+	if r.Container == "" {
+		return true
+	}
+	for _, name := range []string{c.DisplayName(), c.Name, c.QualifiedName()} {
+		if ok, _ := path.Match(r.Container, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fire returns the alert unless the rule fired for the container within its cooldown
+func (e *Engine) fire(k key, r rule, message string) (Alert, bool) {
+	now := e.now()
+	if last, ok := e.fired[k]; ok && now.Sub(last) < r.GetCooldown() {
+		return Alert{}, false
+	}
+	e.fired[k] = now
+	return Alert{Title: "cm alert: " + describe(r.AlertRule), Message: message}, true
+}
+
+// Stats checks a stats sample for a container against the metric rules
+func (e *Engine) Stats(c docker.Container, s docker.ContainerStats) []Alert {
+	if e == nil {
+		return nil
+	}
+	at := s.Timestamp
+	if at.IsZero() {
+		at = e.now()
+	}
+
+	var alerts []Alert
+	for i, r := range e.rules {
+		if r.match != nil || !r.appliesTo(c) {
+			continue
+		}
+		value := s.CPUPercent
+		if r.Metric == MetricMemory {
+			value = s.MemoryPercent
+		}
+		k := key{rule: i, container: c.ID}
+		if value <= r.Above {
+			delete(e.breaches, k)
+			continue
+		}
+		start, ok := e.breaches[k]
+		if !ok {
+			start = at
+			e.breaches[k] = at
+		}
+		if at.Sub(start) < time.Duration(r.For)*time.Second {
+			continue
+		}
+		message := fmt.Sprintf("%s %s at %.1f%%", c.DisplayName(), r.Metric, value)
+		if a, ok := e.fire(k, r, message); ok {
+			alerts = append(alerts, a)
+		}
+	}
+	return alerts
+}
+
+// Log checks a log line from a container against the match rules. Lines
+// timestamped before the engine was created are replayed history and ignored.
+func (e *Engine) Log(c docker.Container, line docker.LogLine) []Alert {
+	if e == nil || line.Stream == "system" {
+		return nil
+	}
+	if !line.Timestamp.IsZero() && line.Timestamp.Before(e.since) {
+		return nil
+	}
+	text := line.Plain
+	if text == "" {
+		text = line.Content
+	}
+
+	var alerts []Alert
+	for i, r := range e.rules {
+		if r.match == nil || !r.appliesTo(c) || !r.match.MatchString(text) {
+			continue
+		}
+		message := c.DisplayName() + ": " + truncate(strings.TrimSpace(text), maxLineLength)
+		if a, ok := e.fire(key{rule: i, container: c.ID}, r, message); ok {
+			alerts = append(alerts, a)
+		}
+	}
+	return alerts
+}
+
+// truncate shortens s to n runes, marking the cut with an ellipsis
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,98 @@
+package alert
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"cm/internal/config"
+	"cm/internal/docker"
+)
+
+func newTestEngine(t *testing.T, rules ...config.AlertRule) (*Engine, *time.Time) {
+	t.Helper()
+	e, errs := New(rules)
+	if len(errs) > 0 {
+		t.Fatalf("New() errors: %v", errs)
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	e.now = func() time.Time { return now }
+	e.since = now
+	return e, &now
+}
+
+func TestMetricRuleFiresAfterSustainedBreach(t *testing.T) {
+	e, now := newTestEngine(t, config.AlertRule{Container: "api", Metric: "memory", Above: 90, For: 30})
+	api := docker.Container{ID: "a1", Name: "shop-api-1", ComposeService: "api"}
+	sample := func(offset time.Duration, mem float64) []Alert {
+		*now = now.Add(offset)
+		return e.Stats(api, docker.ContainerStats{MemoryPercent: mem, Timestamp: *now})
+	}
+
+	if got := sample(0, 95); len(got) != 0 {
+		t.Fatalf("fired on the first sample: %v", got)
+	}
+	if got := sample(20*time.Second, 96); len(got) != 0 {
+		t.Fatalf("fired before 30s above the threshold: %v", got)
+	}
+	got := sample(10*time.Second, 97)
+	if len(got) != 1 || !strings.Contains(got[0].Message, "api memory at 97.0%") {
+		t.Fatalf("alerts after 30s = %v, want one for api memory", got)
+	}
+	if got := sample(5*time.Second, 97); len(got) != 0 {
+		t.Fatalf("fired again within the cooldown: %v", got)
+	}
+
+	// Dropping below the threshold restarts the clock
+	sample(5*time.Second, 50)
+	*now = now.Add(time.Minute)
+	if got := sample(0, 95); len(got) != 0 {
+		t.Fatalf("fired right after recovering: %v", got)
+	}
+
+	other := docker.Container{ID: "w1", Name: "shop-web-1", ComposeService: "web"}
+	if got := e.Stats(other, docker.ContainerStats{MemoryPercent: 99}); len(got) != 0 {
+		t.Fatalf("rule for api fired for web: %v", got)
+	}
+}
+
+func TestMatchRuleFiresOnNewLines(t *testing.T) {
+	e, now := newTestEngine(t, config.AlertRule{Name: "errors", Match: "ERROR", Cooldown: 10})
+	web := docker.Container{ID: "w1", Name: "web"}
+
+	old := docker.LogLine{Timestamp: now.Add(-time.Hour), Content: "ERROR from yesterday"}
+	if got := e.Log(web, old); len(got) != 0 {
+		t.Fatalf("fired for a replayed line: %v", got)
+	}
+	if got := e.Log(web, docker.LogLine{Timestamp: *now, Content: "all good"}); len(got) != 0 {
+		t.Fatalf("fired for a line that doesn't match: %v", got)
+	}
+	got := e.Log(web, docker.LogLine{Timestamp: *now, Content: "ERROR db down"})
+	if len(got) != 1 || got[0].Title != "cm alert: errors" || got[0].Message != "web: ERROR db down" {
+		t.Fatalf("alerts = %v, want one named errors", got)
+	}
+	if got := e.Log(web, docker.LogLine{Timestamp: *now, Content: "ERROR again"}); len(got) != 0 {
+		t.Fatalf("fired again within the cooldown: %v", got)
+	}
+	*now = now.Add(10 * time.Second)
+	if got := e.Log(web, docker.LogLine{Timestamp: *now, Content: "ERROR again"}); len(got) != 1 {
+		t.Fatalf("alerts after the cooldown = %v, want one", got)
+	}
+}
+
+func TestNewReportsUnusableRules(t *testing.T) {
+	e, errs := New([]config.AlertRule{
+		{Metric: "cpu", Above: 80},
+		{Metric: "disk", Above: 80},
+		{Metric: "cpu"},
+		{Match: "("},
+		{Metric: "cpu", Above: 80, Match: "x"},
+		{},
+	})
+	if e.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", e.Len())
+	}
+	if len(errs) != 5 {
+		t.Fatalf("got %d errors, want 5: %v", len(errs), errs)
+	}
+}
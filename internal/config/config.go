@@ -1,22 +1,74 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
 )
 
 const (
-	configDir       = ".cm"
+	legacyConfigDir = ".cm"
+	xdgConfigDir    = "cm"
 	configFile      = "config.json"
 	keybindingsFile = "keybindings.json"
 	projectsFile    = "projects.json"
+	buildLogsDir    = "builds"
+	exportsDir      = "exports"
 )
 
 // SavedProject stores compose file info for a project
 type SavedProject struct {
-	ConfigFile string `json:"config_file"`
-	WorkingDir string `json:"working_dir"`
+	ConfigFiles []string   `json:"config_files"` // Compose files, in the order they're passed to compose with -f
+	WorkingDir  string     `json:"working_dir"`
+	EnvFile     string     `json:"env_file,omitempty"` // Passed to compose with --env-file
+	View        *SavedView `json:"view,omitempty"`     // Log view restored when monitoring this project
+}
+
+// UnmarshalJSON reads a saved project, migrating the comma-joined
+// config_file string older versions saved to config_files
+func (p *SavedProject) UnmarshalJSON(data []byte) error {
+	type savedProject SavedProject
+	var v struct {
+		savedProject
+		ConfigFile string `json:"config_file"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*p = SavedProject(v.savedProject)
+	if len(p.ConfigFiles) == 0 {
+		p.ConfigFiles = SplitConfigFiles(v.ConfigFile)
+	}
+	return nil
+}
+
+// SplitConfigFiles splits a comma-joined list of compose files, the format
+// of compose's config_files label
+func SplitConfigFiles(s string) []string {
+	var files []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// SavedView is a log view setup remembered for a project
+type SavedView struct {
+	Services     []string  `json:"services"`                // Services shown, in pane order
+	WordWrap     bool      `json:"word_wrap"`               // Word wrap on or off
+	Maximized    string    `json:"maximized,omitempty"`     // Service shown maximized, if any
+	Tab          string    `json:"tab,omitempty"`           // Tab of the maximized pane: "logs", "stats", "env", "config", "top" or "events"
+	ColumnRatios []float64 `json:"column_ratios,omitempty"` // Grid column sizes, when the panes were resized
+	RowRatios    []float64 `json:"row_ratios,omitempty"`    // Grid row sizes, when the panes were resized
 }
 
 // KeyBindings stores all configurable key bindings
@@ -32,6 +84,9 @@ type KeyBindings struct {
 	Bottom     string `json:"bottom"`
 	NextPane   string `json:"next_pane"`
 	PrevPane   string `json:"prev_pane"`
+	NewestPane string `json:"newest_pane"`
+	SyncScroll string `json:"sync_scroll"`
+	SyncTime   string `json:"sync_time"`
 
 	// Selection
 	Select    string `json:"select"`
@@ -41,19 +96,26 @@ type KeyBindings struct {
 	Back      string `json:"back"`
 
 	// Container actions
-	Start   string `json:"start"`
-	Stop    string `json:"stop"`
-	Restart string `json:"restart"`
-	Kill    string `json:"kill"`
-	Remove  string `json:"remove"`
-	Exec    string `json:"exec"`
-	Inspect string `json:"inspect"`
+	Start        string `json:"start"`
+	Stop         string `json:"stop"`
+	Restart      string `json:"restart"`
+	Kill         string `json:"kill"`
+	Remove       string `json:"remove"`
+	Exec         string `json:"exec"`
+	Inspect      string `json:"inspect"`
+	Reconnect    string `json:"reconnect"`
+	ReconnectAll string `json:"reconnect_all"`
+	Pause        string `json:"pause_container"`
+	Signal       string `json:"send_signal"`
+	Rename       string `json:"rename"`
+	Prune        string `json:"prune"`
 
 	// Compose actions
 	ComposeUp      string `json:"compose_up"`
 	ComposeDown    string `json:"compose_down"`
 	ComposeRestart string `json:"compose_restart"`
 	ComposeBuild   string `json:"compose_build"`
+	ComposeLogs    string `json:"compose_logs"`
 
 	// General
 	Refresh       string `json:"refresh"`
@@ -62,12 +124,29 @@ type KeyBindings struct {
 	Quit          string `json:"quit"`
 	SavedProjects string `json:"saved_projects_key"`
 	Config        string `json:"config"`
+	ReloadConfig  string `json:"reload_config"`
+	SystemMenu    string `json:"system_menu"`
 	CopyLogs      string `json:"copy_logs"`
+	CopyLogsANSI  string `json:"copy_logs_ansi"`
 	CopySelection string `json:"copy_selection"`
+	CopyID        string `json:"copy_id"`
+	CopyName      string `json:"copy_name"`
+	CopyExec      string `json:"copy_exec"`
+	CopyCommand   string `json:"copy_command"`
 	WordWrap      string `json:"word_wrap"`
 	DebugToggle   string `json:"debug_toggle"`
+	DebugOverlay  string `json:"debug_overlay"`
 	ClearLogs     string `json:"clear_logs"`
 	PauseLogs     string `json:"pause_logs"`
+	UTCToggle     string `json:"utc_toggle"`
+	CopyStamps    string `json:"copy_stamps"`
+	GroupToggle   string `json:"group_toggle"`
+	LocalFilter   string `json:"local_filter"`
+	FreezeLayout  string `json:"freeze_layout"`
+	DismissPane   string `json:"dismiss_pane"`
+	ExportLogs    string `json:"export_logs"`
+	OpenPager     string `json:"open_pager"`
+	StreamFilter  string `json:"stream_filter"`
 
 	// Pane shortcuts
 	Pane1 string `json:"pane_1"`
@@ -86,6 +165,12 @@ type KeyBindings struct {
 	ResizeUp    string `json:"resize_up"`
 	ResizeDown  string `json:"resize_down"`
 	ResizeReset string `json:"resize_reset"`
+
+	// Pane reorder
+	SwapLeft  string `json:"swap_left"`
+	SwapRight string `json:"swap_right"`
+	SwapUp    string `json:"swap_up"`
+	SwapDown  string `json:"swap_down"`
 }
 
 // DefaultKeyBindings returns the default key bindings
@@ -102,6 +187,9 @@ func DefaultKeyBindings() KeyBindings {
 		Bottom:     "G",
 		NextPane:   "}",
 		PrevPane:   "{",
+		NewestPane: "N",
+		SyncScroll: "alt+s",
+		SyncTime:   "ctrl+t",
 
 		// Selection
 		Select:    "space",
@@ -111,19 +199,26 @@ func DefaultKeyBindings() KeyBindings {
 		Back:      "esc",
 
 		// Container actions
-		Start:   "u",
-		Stop:    "s",
-		Restart: "r",
-		Kill:    "K",
-		Remove:  "D",
-		Exec:    "e",
-		Inspect: "i",
+		Start:        "u",
+		Stop:         "s",
+		Restart:      "r",
+		Kill:         "K",
+		Remove:       "D",
+		Exec:         "e",
+		Inspect:      "i",
+		Reconnect:    "L",
+		ReconnectAll: "alt+l",
+		Pause:        "z",
+		Signal:       "ctrl+k",
+		Rename:       "ctrl+n",
+		Prune:        "X",
 
 		// Compose actions
 		ComposeUp:      "U",
 		ComposeDown:    "S",
 		ComposeRestart: "R",
 		ComposeBuild:   "b",
+		ComposeLogs:    "l",
 
 		// General
 		Refresh:       "ctrl+r",
@@ -132,12 +227,29 @@ func DefaultKeyBindings() KeyBindings {
 		Quit:          "q",
 		SavedProjects: "p",
 		Config:        "c",
+		ReloadConfig:  "C",
+		SystemMenu:    "M",
 		CopyLogs:      "y",
+		CopyLogsANSI:  "alt+y",
 		CopySelection: "ctrl+shift+c",
+		CopyID:        "Y",
+		CopyName:      "ctrl+y",
+		CopyExec:      "ctrl+e",
+		CopyCommand:   "alt+c",
 		WordWrap:      "w",
 		DebugToggle:   "ctrl+g",
+		DebugOverlay:  "ctrl+o",
 		ClearLogs:     "ctrl+l",
 		PauseLogs:     "P",
+		UTCToggle:     "T",
+		CopyStamps:    "alt+t",
+		GroupToggle:   "I",
+		LocalFilter:   ".",
+		FreezeLayout:  "F",
+		DismissPane:   "x",
+		ExportLogs:    "E",
+		OpenPager:     "v",
+		StreamFilter:  "o",
 
 		// Pane shortcuts
 		Pane1: "1",
@@ -156,6 +268,12 @@ func DefaultKeyBindings() KeyBindings {
 		ResizeUp:    "-",
 		ResizeDown:  "+",
 		ResizeReset: "=",
+
+		// Pane reorder
+		SwapLeft:  "shift+left",
+		SwapRight: "shift+right",
+		SwapUp:    "shift+up",
+		SwapDown:  "shift+down",
 	}
 }
 
@@ -180,9 +298,10 @@ const (
 
 // NotificationSettings stores notification preferences
 type NotificationSettings struct {
-	Mode          NotificationMode `json:"mode"`           // "terminal", "os", or "none"
-	ToastDuration int              `json:"toast_duration"` // Toast duration in seconds (1-10)
-	ToastPosition ToastPosition    `json:"toast_position"` // Toast position on screen
+	Mode          NotificationMode `json:"mode"`                        // "terminal", "os", or "none"
+	ToastDuration int              `json:"toast_duration"`              // Toast duration in seconds (1-10)
+	ToastPosition ToastPosition    `json:"toast_position"`              // Toast position on screen
+	UnboundKeys   bool             `json:"show_unbound_keys,omitempty"` // Toast the key when an unbound key is pressed
 }
 
 // DefaultNotificationSettings returns default notification settings
@@ -215,6 +334,176 @@ func (n NotificationSettings) GetToastPosition() ToastPosition {
 	}
 }
 
+// DisplaySettings stores log display preferences
+type DisplaySettings struct {
+	UTCTimestamps         bool   `json:"utc_timestamps"`          // Render log timestamps in UTC instead of local time
+	MillisecondTimestamps bool   `json:"millisecond_timestamps"`  // Render log timestamps as HH:MM:SS.mmm
+	CopyWithoutTimestamps bool   `json:"copy_without_timestamps"` // Leave the timestamp column out of copied text
+	FreezeLayout          bool   `json:"freeze_layout"`           // Keep removed/dead containers as placeholders instead of reflowing the grid
+	Layout                string `json:"layout,omitempty"`        // Grid shape: "wide" (default, fewer wider columns) or "balanced"
+	StreamFilter          string `json:"stream_filter,omitempty"` // Streams new panes show: "both" (default), "stdout" or "stderr"
+	LogBuffer             int    `json:"log_buffer,omitempty"`    // Log lines kept per pane (default 1000)
+}
+
+const (
+	DefaultLogBuffer = 1000
+	minLogBuffer     = 100
+	maxLogBuffer     = 100000
+)
+
+// GetLogBuffer returns the number of log lines kept per pane, within a sane range
+func (d DisplaySettings) GetLogBuffer() int {
+	if d.LogBuffer <= 0 {
+		return DefaultLogBuffer
+	}
+	if d.LogBuffer < minLogBuffer {
+		return minLogBuffer
+	}
+	if d.LogBuffer > maxLogBuffer {
+		return maxLogBuffer
+	}
+	return d.LogBuffer
+}
+
+// ReconnectSettings controls automatic log stream reconnection
+type ReconnectSettings struct {
+	MaxAttempts  int `json:"max_attempts"`  // Number of reconnect attempts before giving up
+	BaseDelay    int `json:"base_delay"`    // Delay before the first attempt in seconds (doubles each attempt)
+	MaxDelay     int `json:"max_delay"`     // Upper bound for a single delay in seconds
+	TotalTimeout int `json:"total_timeout"` // Give up after this many seconds regardless of attempts
+}
+
+// DefaultReconnectSettings returns default reconnect settings
+func DefaultReconnectSettings() ReconnectSettings {
+	return ReconnectSettings{
+		MaxAttempts:  4,
+		BaseDelay:    1,
+		MaxDelay:     5,
+		TotalTimeout: 30,
+	}
+}
+
+// Delays returns the exponential backoff schedule, falling back to defaults for unset values
+func (r ReconnectSettings) Delays() []time.Duration {
+	defaults := DefaultReconnectSettings()
+	attempts := r.MaxAttempts
+	if attempts < 1 {
+		attempts = defaults.MaxAttempts
+	}
+	base := r.BaseDelay
+	if base < 1 {
+		base = defaults.BaseDelay
+	}
+	maxDelay := r.MaxDelay
+	if maxDelay < base {
+		maxDelay = base
+	}
+
+	delays := make([]time.Duration, 0, attempts)
+	delay := base
+	for i := 0; i < attempts; i++ {
+		delays = append(delays, time.Duration(delay)*time.Second)
+		if delay < maxDelay {
+			delay *= 2
+			if delay > maxDelay {
+				delay = maxDelay
+			}
+		}
+	}
+	return delays
+}
+
+// GetTotalTimeout returns the total time budget for reconnecting
+func (r ReconnectSettings) GetTotalTimeout() time.Duration {
+	if r.TotalTimeout < 1 {
+		return time.Duration(DefaultReconnectSettings().TotalTimeout) * time.Second
+	}
+	return time.Duration(r.TotalTimeout) * time.Second
+}
+
+// DiscoverySettings controls which containers are listed in discovery
+type DiscoverySettings struct {
+	ExitedWindow int  `json:"exited_window"` // Show exited containers for this many minutes after they stopped
+	ShowAll      bool `json:"show_all"`      // List every project, not just the one in the current directory
+}
+
+// DefaultDiscoverySettings returns default discovery settings
+func DefaultDiscoverySettings() DiscoverySettings {
+	return DiscoverySettings{
+		ExitedWindow: 60,
+	}
+}
+
+// GetExitedWindow returns how long exited containers stay visible
+func (d DiscoverySettings) GetExitedWindow() time.Duration {
+	if d.ExitedWindow < 1 {
+		return time.Duration(DefaultDiscoverySettings().ExitedWindow) * time.Minute
+	}
+	return time.Duration(d.ExitedWindow) * time.Minute
+}
+
+// BuildSettings controls what happens to build panel output
+type BuildSettings struct {
+	PersistLogs bool `json:"persist_logs"` // Write each completed operation's log to <config dir>/builds
+}
+
+// BuildContext remembers how to rebuild the image of a standalone
+// (non-compose) container
+type BuildContext struct {
+	Context    string `json:"context"`              // Directory passed to docker build
+	Dockerfile string `json:"dockerfile,omitempty"` // Dockerfile path (default: <context>/Dockerfile)
+}
+
+// ReloadSettings controls applying config changes at runtime
+type ReloadSettings struct {
+	Watch bool `json:"watch"` // Reload automatically when the config files change on disk
+}
+
+// DebugSettings controls the debug log file
+type DebugSettings struct {
+	MaxLogSizeMB int    `json:"max_log_size_mb"` // Rotate debug.log to debug.log.old at this size
+	Level        string `json:"level,omitempty"` // Lowest level written: "debug" (default), "info", "warn" or "error"
+}
+
+// DefaultDebugSettings returns default debug settings
+func DefaultDebugSettings() DebugSettings {
+	return DebugSettings{
+		MaxLogSizeMB: 10,
+	}
+}
+
+// GetMaxLogSize returns the debug log rotation size in bytes
+func (d DebugSettings) GetMaxLogSize() int64 {
+	if d.MaxLogSizeMB < 1 {
+		return int64(DefaultDebugSettings().MaxLogSizeMB) * 1024 * 1024
+	}
+	return int64(d.MaxLogSizeMB) * 1024 * 1024
+}
+
+// AlertRule raises a notification when a container stays above a resource
+// threshold or logs a matching line. A rule sets either Metric and Above or
+// Match.
+type AlertRule struct {
+	Name      string  `json:"name,omitempty"`      // Shown in the notification (default: a description of the rule)
+	Container string  `json:"container,omitempty"` // Container or service name, glob patterns allowed (default: every container)
+	Metric    string  `json:"metric,omitempty"`    // "cpu" or "memory", in percent
+	Above     float64 `json:"above,omitempty"`     // Threshold the metric must exceed
+	For       int     `json:"for,omitempty"`       // Seconds the metric must stay above the threshold
+	Match     string  `json:"match,omitempty"`     // Regular expression matched against log lines
+	Cooldown  int     `json:"cooldown,omitempty"`  // Seconds before the rule fires again for the same container (default 60)
+}
+
+// DefaultAlertCooldown is how long a rule stays quiet after firing
+const DefaultAlertCooldown = 60
+
+// GetCooldown returns how long the rule stays quiet after firing
+func (r AlertRule) GetCooldown() time.Duration {
+	if r.Cooldown < 1 {
+		return DefaultAlertCooldown * time.Second
+	}
+	return time.Duration(r.Cooldown) * time.Second
+}
+
 // TutorialSettings stores tutorial progress
 type TutorialSettings struct {
 	Completed bool `json:"completed"`
@@ -222,8 +511,16 @@ type TutorialSettings struct {
 
 // Config represents the application configuration
 type Config struct {
-	Notifications *NotificationSettings `json:"notifications,omitempty"`
-	Tutorial      *TutorialSettings     `json:"tutorial,omitempty"`
+	Notifications *NotificationSettings   `json:"notifications,omitempty"`
+	Display       *DisplaySettings        `json:"display,omitempty"`
+	Reconnect     *ReconnectSettings      `json:"reconnect,omitempty"`
+	Discovery     *DiscoverySettings      `json:"discovery,omitempty"`
+	Builds        *BuildSettings          `json:"builds,omitempty"`
+	Debug         *DebugSettings          `json:"debug,omitempty"`
+	Reload        *ReloadSettings         `json:"reload,omitempty"`
+	Alerts        []AlertRule             `json:"alerts,omitempty"`
+	BuildContexts map[string]BuildContext `json:"build_contexts,omitempty"` // Keyed by container name
+	Tutorial      *TutorialSettings       `json:"tutorial,omitempty"`
 }
 
 // ShouldShowTutorial returns true if the tutorial should be shown
@@ -237,26 +534,196 @@ func (c *Config) MarkTutorialCompleted() error {
 	return c.Save()
 }
 
+// GetBuildContext returns the remembered build context for a standalone container
+func (c *Config) GetBuildContext(containerName string) (BuildContext, bool) {
+	bc, ok := c.BuildContexts[containerName]
+	return bc, ok
+}
+
+// SetBuildContext remembers the build context for a standalone container and saves to disk
+func (c *Config) SetBuildContext(containerName string, bc BuildContext) error {
+	if c.BuildContexts == nil {
+		c.BuildContexts = make(map[string]BuildContext)
+	}
+	c.BuildContexts[containerName] = bc
+	return c.Save()
+}
+
 // Projects represents saved compose projects (stored separately)
 type Projects struct {
 	SavedProjects map[string]SavedProject `json:"saved_projects"`
 }
 
-// GetNotificationSettings returns the configured notification settings or defaults
+// GetNotificationSettings returns the configured notification settings or
+// defaults, with environment overrides applied
 func (c *Config) GetNotificationSettings() NotificationSettings {
+	settings := c.SavedNotificationSettings()
+	applyNotificationEnv(&settings)
+	return settings
+}
+
+// SavedNotificationSettings returns the notification settings as config.json
+// has them, without environment overrides, for editing and saving them
+func (c *Config) SavedNotificationSettings() NotificationSettings {
 	if c.Notifications != nil {
 		return *c.Notifications
 	}
 	return DefaultNotificationSettings()
 }
 
+// GetDisplaySettings returns the configured display settings or defaults,
+// with environment overrides applied
+func (c *Config) GetDisplaySettings() DisplaySettings {
+	settings := c.SavedDisplaySettings()
+	applyDisplayEnv(&settings)
+	return settings
+}
+
+// SavedDisplaySettings returns the display settings as config.json has them,
+// without environment overrides
+func (c *Config) SavedDisplaySettings() DisplaySettings {
+	if c.Display != nil {
+		return *c.Display
+	}
+	return DisplaySettings{}
+}
+
+// GetReconnectSettings returns the configured reconnect settings or defaults
+func (c *Config) GetReconnectSettings() ReconnectSettings {
+	if c.Reconnect != nil {
+		return *c.Reconnect
+	}
+	return DefaultReconnectSettings()
+}
+
+// GetDiscoverySettings returns the configured discovery settings or defaults
+func (c *Config) GetDiscoverySettings() DiscoverySettings {
+	if c.Discovery != nil {
+		return *c.Discovery
+	}
+	return DefaultDiscoverySettings()
+}
+
+// SetShowAll remembers whether discovery lists every project and saves to disk
+func (c *Config) SetShowAll(showAll bool) error {
+	settings := c.GetDiscoverySettings()
+	settings.ShowAll = showAll
+	c.Discovery = &settings
+	return c.Save()
+}
+
+// GetBuildSettings returns the configured build settings or defaults
+func (c *Config) GetBuildSettings() BuildSettings {
+	if c.Builds != nil {
+		return *c.Builds
+	}
+	return BuildSettings{}
+}
+
+// GetReloadSettings returns the configured reload settings or defaults
+func (c *Config) GetReloadSettings() ReloadSettings {
+	if c.Reload != nil {
+		return *c.Reload
+	}
+	return ReloadSettings{}
+}
+
+// GetDebugSettings returns the configured debug settings or defaults
+func (c *Config) GetDebugSettings() DebugSettings {
+	if c.Debug != nil {
+		return *c.Debug
+	}
+	return DefaultDebugSettings()
+}
+
+// GetAlertRules returns the configured alert rules
+func (c *Config) GetAlertRules() []AlertRule {
+	return c.Alerts
+}
+
+// useXDG reports whether the config directory follows the XDG base
+// directory spec; tests override it
+var useXDG = runtime.GOOS == "linux"
+
+// dirs returns the legacy ~/.cm directory and the XDG one,
+// $XDG_CONFIG_HOME/cm or ~/.config/cm when the variable is unset
+func dirs() (legacy, xdg string, err error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", err
+	}
+	legacy = filepath.Join(home, legacyConfigDir)
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if !filepath.IsAbs(base) {
+		// The spec says relative values are invalid and must be ignored
+		base = filepath.Join(home, ".config")
+	}
+	return legacy, filepath.Join(base, xdgConfigDir), nil
+}
+
+// configDirPath returns the directory cm keeps its files in. On Linux that's
+// the XDG directory, unless only an unmigrated ~/.cm exists, which keeps
+// working as before; elsewhere it's ~/.cm.
+func configDirPath() (string, error) {
+	legacy, xdg, err := dirs()
+	if err != nil {
+		return "", err
+	}
+	if !useXDG {
+		return legacy, nil
+	}
+	if _, err := os.Stat(xdg); os.IsNotExist(err) {
+		if info, err := os.Stat(legacy); err == nil && info.IsDir() {
+			return legacy, nil
+		}
+	}
+	return xdg, nil
+}
+
+// Dir returns the directory cm keeps its config files, exports and logs in
+func Dir() string {
+	dir, err := configDirPath()
+	if err != nil {
+		return ""
+	}
+	return dir
+}
+
+// MigrateLegacyDir moves an existing ~/.cm to the XDG config directory the
+// first time cm runs with XDG support. It returns both paths when something
+// was moved, and empty strings when there was nothing to do. If the move
+// fails, ~/.cm stays in use.
+func MigrateLegacyDir() (from, to string, err error) {
+	if !useXDG {
+		return "", "", nil
+	}
+	legacy, xdg, err := dirs()
+	if err != nil {
+		return "", "", err
+	}
+	if info, err := os.Stat(legacy); err != nil || !info.IsDir() {
+		return "", "", nil
+	}
+	if _, err := os.Stat(xdg); !os.IsNotExist(err) {
+		// Already migrated, or both exist: leave the choice to the user
+		return "", "", nil
+	}
+	if err := os.MkdirAll(filepath.Dir(xdg), 0755); err != nil {
+		return "", "", err
+	}
+	if err := os.Rename(legacy, xdg); err != nil {
+		return "", "", err
+	}
+	return legacy, xdg, nil
+}
+
 // configPath returns the full path to the config file
 func configPath() (string, error) {
-	home, err := os.UserHomeDir()
+	dir, err := configDirPath()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, configDir, configFile), nil
+	return filepath.Join(dir, configFile), nil
 }
 
 // GetConfigPath returns the full path to the config file (public version)
@@ -268,13 +735,31 @@ func GetConfigPath() string {
 	return path
 }
 
+// GetBuildLogsDir returns the directory persisted build logs are written to
+func GetBuildLogsDir() string {
+	dir := Dir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, buildLogsDir)
+}
+
+// GetExportsDir returns the directory full log exports are written to
+func GetExportsDir() string {
+	dir := Dir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, exportsDir)
+}
+
 // keybindingsPath returns the full path to the keybindings file
 func keybindingsPath() (string, error) {
-	home, err := os.UserHomeDir()
+	dir, err := configDirPath()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, configDir, keybindingsFile), nil
+	return filepath.Join(dir, keybindingsFile), nil
 }
 
 // GetKeybindingsPath returns the full path to the keybindings file (public version)
@@ -299,7 +784,7 @@ func LoadKeyBindings() KeyBindings {
 	}
 
 	var kb KeyBindings
-	if err := json.Unmarshal(data, &kb); err != nil {
+	if err := parseJSON(path, data, &kb); err != nil {
 		return DefaultKeyBindings()
 	}
 
@@ -325,6 +810,9 @@ func LoadKeyBindings() KeyBindings {
 	setDefault(&kb.Bottom, defaults.Bottom)
 	setDefault(&kb.NextPane, defaults.NextPane)
 	setDefault(&kb.PrevPane, defaults.PrevPane)
+	setDefault(&kb.NewestPane, defaults.NewestPane)
+	setDefault(&kb.SyncScroll, defaults.SyncScroll)
+	setDefault(&kb.SyncTime, defaults.SyncTime)
 	setDefault(&kb.Select, defaults.Select)
 	setDefault(&kb.SelectAll, defaults.SelectAll)
 	setDefault(&kb.ClearAll, defaults.ClearAll)
@@ -337,10 +825,17 @@ func LoadKeyBindings() KeyBindings {
 	setDefault(&kb.Remove, defaults.Remove)
 	setDefault(&kb.Exec, defaults.Exec)
 	setDefault(&kb.Inspect, defaults.Inspect)
+	setDefault(&kb.Reconnect, defaults.Reconnect)
+	setDefault(&kb.ReconnectAll, defaults.ReconnectAll)
+	setDefault(&kb.Pause, defaults.Pause)
+	setDefault(&kb.Signal, defaults.Signal)
+	setDefault(&kb.Rename, defaults.Rename)
+	setDefault(&kb.Prune, defaults.Prune)
 	setDefault(&kb.ComposeUp, defaults.ComposeUp)
 	setDefault(&kb.ComposeDown, defaults.ComposeDown)
 	setDefault(&kb.ComposeRestart, defaults.ComposeRestart)
 	setDefault(&kb.ComposeBuild, defaults.ComposeBuild)
+	setDefault(&kb.ComposeLogs, defaults.ComposeLogs)
 	setDefault(&kb.Refresh, defaults.Refresh)
 	setDefault(&kb.Search, defaults.Search)
 	setDefault(&kb.Help, defaults.Help)
@@ -348,11 +843,28 @@ func LoadKeyBindings() KeyBindings {
 	setDefault(&kb.SavedProjects, defaults.SavedProjects)
 	setDefault(&kb.Config, defaults.Config)
 	setDefault(&kb.CopyLogs, defaults.CopyLogs)
+	setDefault(&kb.CopyLogsANSI, defaults.CopyLogsANSI)
 	setDefault(&kb.CopySelection, defaults.CopySelection)
+	setDefault(&kb.CopyID, defaults.CopyID)
+	setDefault(&kb.CopyName, defaults.CopyName)
+	setDefault(&kb.CopyExec, defaults.CopyExec)
+	setDefault(&kb.CopyCommand, defaults.CopyCommand)
 	setDefault(&kb.WordWrap, defaults.WordWrap)
 	setDefault(&kb.DebugToggle, defaults.DebugToggle)
+	setDefault(&kb.DebugOverlay, defaults.DebugOverlay)
+	setDefault(&kb.ReloadConfig, defaults.ReloadConfig)
+	setDefault(&kb.SystemMenu, defaults.SystemMenu)
 	setDefault(&kb.ClearLogs, defaults.ClearLogs)
 	setDefault(&kb.PauseLogs, defaults.PauseLogs)
+	setDefault(&kb.UTCToggle, defaults.UTCToggle)
+	setDefault(&kb.CopyStamps, defaults.CopyStamps)
+	setDefault(&kb.GroupToggle, defaults.GroupToggle)
+	setDefault(&kb.LocalFilter, defaults.LocalFilter)
+	setDefault(&kb.FreezeLayout, defaults.FreezeLayout)
+	setDefault(&kb.DismissPane, defaults.DismissPane)
+	setDefault(&kb.ExportLogs, defaults.ExportLogs)
+	setDefault(&kb.OpenPager, defaults.OpenPager)
+	setDefault(&kb.StreamFilter, defaults.StreamFilter)
 	setDefault(&kb.Pane1, defaults.Pane1)
 	setDefault(&kb.Pane2, defaults.Pane2)
 	setDefault(&kb.Pane3, defaults.Pane3)
@@ -367,6 +879,10 @@ func LoadKeyBindings() KeyBindings {
 	setDefault(&kb.ResizeUp, defaults.ResizeUp)
 	setDefault(&kb.ResizeDown, defaults.ResizeDown)
 	setDefault(&kb.ResizeReset, defaults.ResizeReset)
+	setDefault(&kb.SwapLeft, defaults.SwapLeft)
+	setDefault(&kb.SwapRight, defaults.SwapRight)
+	setDefault(&kb.SwapUp, defaults.SwapUp)
+	setDefault(&kb.SwapDown, defaults.SwapDown)
 
 	// Save back to file if any new keys were added
 	if modified {
@@ -394,16 +910,16 @@ func SaveKeyBindings(kb KeyBindings) error {
 		return err
 	}
 
-	return os.WriteFile(path, data, 0644)
+	return writeFileAtomic(path, data, 0644)
 }
 
 // projectsPath returns the full path to the projects file
 func projectsPath() (string, error) {
-	home, err := os.UserHomeDir()
+	dir, err := configDirPath()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, configDir, projectsFile), nil
+	return filepath.Join(dir, projectsFile), nil
 }
 
 // GetProjectsPath returns the full path to the projects file (public version)
@@ -428,7 +944,7 @@ func LoadProjects() *Projects {
 	}
 
 	var p Projects
-	if err := json.Unmarshal(data, &p); err != nil {
+	if err := parseJSON(path, data, &p); err != nil {
 		return &Projects{SavedProjects: make(map[string]SavedProject)}
 	}
 
@@ -457,7 +973,7 @@ func (p *Projects) Save() error {
 		return err
 	}
 
-	return os.WriteFile(path, data, 0644)
+	return writeFileAtomic(path, data, 0644)
 }
 
 // RemoveProject removes a project from saved projects
@@ -465,6 +981,18 @@ func (p *Projects) RemoveProject(name string) {
 	delete(p.SavedProjects, name)
 }
 
+// SetView stores (or with nil, clears) the saved view of a project. It
+// returns false if the project isn't saved.
+func (p *Projects) SetView(name string, view *SavedView) bool {
+	proj, ok := p.SavedProjects[name]
+	if !ok {
+		return false
+	}
+	proj.View = view
+	p.SavedProjects[name] = proj
+	return true
+}
+
 // Load loads the config from disk
 func Load() (*Config, error) {
 	path, err := configPath()
@@ -482,7 +1010,7 @@ func Load() (*Config, error) {
 	}
 
 	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	if err := parseJSON(path, data, &cfg); err != nil {
 		return nil, err
 	}
 
@@ -507,24 +1035,193 @@ func (c *Config) Save() error {
 		return err
 	}
 
-	return os.WriteFile(path, data, 0644)
+	return writeFileAtomic(path, data, 0644)
 }
 
-// EnsureDefaults ensures the config files exist with default values
-func EnsureDefaults() error {
-	// Config file
-	cfg, err := Load()
+// writeData writes the file contents; tests swap it out to simulate a
+// write that fails partway
+var writeData = func(f *os.File, data []byte) (int, error) {
+	return f.Write(data)
+}
+
+// writeFileAtomic writes data to a temp file next to path and renames it into
+// place, so a crash or failed write never leaves a truncated file behind. A
+// symlinked path is resolved first so the link is kept and its target updated.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	target := path
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		target = resolved
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".tmp-*")
 	if err != nil {
-		cfg = &Config{}
+		return err
 	}
+	tmpPath := tmp.Name()
 
-	// Set notification defaults
-	notifyDefaults := DefaultNotificationSettings()
-	cfg.Notifications = &notifyDefaults
+	if _, err := writeData(tmp, data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, target); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	recordWrite(path)
+	return nil
+}
+
+// ownWrites holds the version of each config file cm last wrote, so a file
+// watcher can tell cm's own saves from the user's edits
+var (
+	ownWrites     = make(map[string]fileVersion)
+	ownWritesLock sync.Mutex
+)
+
+// fileVersion identifies one version of a file
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+// recordWrite notes the version of a file cm just wrote
+func recordWrite(path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	ownWritesLock.Lock()
+	ownWrites[filepath.Clean(path)] = fileVersion{modTime: info.ModTime(), size: info.Size()}
+	ownWritesLock.Unlock()
+}
+
+// WrittenByCM reports whether a config file is still as cm last wrote it,
+// i.e. it hasn't been edited since
+func WrittenByCM(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	ownWritesLock.Lock()
+	defer ownWritesLock.Unlock()
+	v, ok := ownWrites[filepath.Clean(path)]
+	return ok && v.modTime.Equal(info.ModTime()) && v.size == info.Size()
+}
+
+// ParseError describes a config file that couldn't be decoded, with the
+// position of the problem
+type ParseError struct {
+	Path   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s line %d, column %d: %v", filepath.Base(e.Path), e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// parseJSON decodes data into v, reporting syntax and type errors as a
+// ParseError pointing at the offending line and column
+func parseJSON(path string, data []byte, v interface{}) error {
+	err := json.Unmarshal(data, v)
+	if err == nil {
+		return nil
+	}
+
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return err
+	}
+
+	// Offset counts the bytes read up to and including the offending one
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	if offset > 0 {
+		offset--
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return &ParseError{Path: path, Line: line, Column: column, Err: err}
+}
 
-	if err := cfg.Save(); err != nil {
+// CheckFile reports whether a config file can be decoded into v. A missing
+// file is fine: defaults are used.
+func CheckFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
 		return err
 	}
+	return parseJSON(path, data, v)
+}
+
+// ValidateFiles checks config.json, keybindings.json and projects.json and
+// returns an error for each file that is ignored in favour of defaults
+func ValidateFiles() []error {
+	var errs []error
+	for _, f := range []struct {
+		path string
+		v    interface{}
+	}{
+		{GetConfigPath(), &Config{}},
+		{GetKeybindingsPath(), &KeyBindings{}},
+		{GetProjectsPath(), &Projects{}},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if err := CheckFile(f.path, f.v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// EnsureDefaults ensures the config files exist with default values
+func EnsureDefaults() error {
+	// Config file - left alone when it can't be parsed, so the user's edits
+	// aren't overwritten (ValidateFiles reports the problem)
+	cfg, err := Load()
+	if err == nil {
+		// Set notification defaults
+		if cfg.Notifications == nil {
+			notifyDefaults := DefaultNotificationSettings()
+			cfg.Notifications = &notifyDefaults
+		}
+
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+	}
 
 	// Keybindings file - create if doesn't exist, or load to add any missing keys
 	kbPath, _ := keybindingsPath()
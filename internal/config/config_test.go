@@ -0,0 +1,332 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReconnectDelaysExponentialWithCap(t *testing.T) {
+	r := ReconnectSettings{MaxAttempts: 6, BaseDelay: 1, MaxDelay: 10}
+
+	want := []time.Duration{1, 2, 4, 8, 10, 10}
+	got := r.Delays()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d delays, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i]*time.Second {
+			t.Fatalf("delay %d: expected %v, got %v", i, want[i]*time.Second, got[i])
+		}
+	}
+}
+
+func TestReconnectSettingsFallBackToDefaults(t *testing.T) {
+	var r ReconnectSettings
+
+	if got := len(r.Delays()); got != DefaultReconnectSettings().MaxAttempts {
+		t.Fatalf("expected default attempt count, got %d", got)
+	}
+	if got := r.GetTotalTimeout(); got != 30*time.Second {
+		t.Fatalf("expected default total timeout of 30s, got %v", got)
+	}
+}
+
+func TestDiscoveryExitedWindow(t *testing.T) {
+	if got := DefaultDiscoverySettings().GetExitedWindow(); got != time.Hour {
+		t.Fatalf("expected default window of 1h, got %v", got)
+	}
+	if got := (DiscoverySettings{ExitedWindow: 15}).GetExitedWindow(); got != 15*time.Minute {
+		t.Fatalf("expected 15m window, got %v", got)
+	}
+	if got := (DiscoverySettings{}).GetExitedWindow(); got != time.Hour {
+		t.Fatalf("expected unset window to fall back to 1h, got %v", got)
+	}
+	if got := (&Config{}).GetDiscoverySettings(); got != DefaultDiscoverySettings() {
+		t.Fatalf("expected defaults when discovery section is missing, got %+v", got)
+	}
+}
+
+func TestSavedProjectsMigrateCommaJoinedConfigFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	path := GetProjectsPath()
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte(`{"saved_projects": {
+		"shop": {"config_file": "/src/shop/compose.yaml, /src/shop/compose.override.yaml", "working_dir": "/src/shop"},
+		"blog": {"config_files": ["/src/blog,v2/compose.yaml"], "working_dir": "/src/blog,v2"}
+	}}`), 0644)
+
+	p := LoadProjects()
+	if got := p.SavedProjects["shop"].ConfigFiles; !slices.Equal(got, []string{"/src/shop/compose.yaml", "/src/shop/compose.override.yaml"}) {
+		t.Fatalf("expected the old string to be split into files, got %q", got)
+	}
+	if got := p.SavedProjects["blog"].ConfigFiles; !slices.Equal(got, []string{"/src/blog,v2/compose.yaml"}) {
+		t.Fatalf("expected a path with a comma to stay whole, got %q", got)
+	}
+
+	// Saving writes the new field only
+	if err := p.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), `"config_file"`) || LoadProjects().SavedProjects["shop"].WorkingDir != "/src/shop" {
+		t.Fatalf("expected the migrated projects to be saved, got:\n%s", data)
+	}
+}
+
+func TestBuildContextIsRememberedPerContainer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	cfg := &Config{}
+	if _, ok := cfg.GetBuildContext("web"); ok {
+		t.Fatalf("expected no build context before one is set")
+	}
+	if err := cfg.SetBuildContext("web", BuildContext{Context: "./web", Dockerfile: "Dockerfile.dev"}); err != nil {
+		t.Fatalf("SetBuildContext: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	bc, ok := loaded.GetBuildContext("web")
+	if !ok || bc.Context != "./web" || bc.Dockerfile != "Dockerfile.dev" {
+		t.Fatalf("expected saved build context, got %+v (%v)", bc, ok)
+	}
+}
+
+func TestCheckFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := CheckFile(filepath.Join(dir, "missing.json"), &Config{}); err != nil {
+		t.Fatalf("expected a missing file to be fine, got %v", err)
+	}
+
+	good := filepath.Join(dir, "good.json")
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(good, []byte(`{"display": {"word_wrap": true}}`), 0644)
+	os.WriteFile(bad, []byte(`{"display": {"word_wrap": true,}}`), 0644)
+
+	if err := CheckFile(good, &Config{}); err != nil {
+		t.Fatalf("expected valid JSON to pass, got %v", err)
+	}
+	if err := CheckFile(bad, &Config{}); err == nil {
+		t.Fatalf("expected malformed JSON to be reported")
+	}
+}
+
+func TestParseErrorPointsAtLineAndColumn(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	path := GetConfigPath()
+	os.MkdirAll(filepath.Dir(path), 0755)
+
+	tests := []struct {
+		name      string
+		content   string
+		line, col int
+	}{
+		{"trailing comma", "{\n  \"display\": {\n    \"word_wrap\": true,\n  }\n}", 4, 3},
+		{"wrong type", "{\n  \"display\": {\"freeze_layout\": \"yes\"}\n}", 2, 36},
+	}
+	for _, tt := range tests {
+		os.WriteFile(path, []byte(tt.content), 0644)
+		_, err := Load()
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("%s: expected a ParseError, got %v", tt.name, err)
+		}
+		if perr.Line != tt.line || perr.Column != tt.col {
+			t.Fatalf("%s: expected line %d column %d, got line %d column %d", tt.name, tt.line, tt.col, perr.Line, perr.Column)
+		}
+		if !strings.Contains(err.Error(), "config.json line") {
+			t.Fatalf("%s: expected the file name in %q", tt.name, err.Error())
+		}
+	}
+}
+
+func TestMalformedConfigIsReportedAndKept(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	path := GetConfigPath()
+	os.MkdirAll(filepath.Dir(path), 0755)
+	broken := []byte(`{"display": {"word_wrap": true,}}`)
+	os.WriteFile(path, broken, 0644)
+
+	if err := EnsureDefaults(); err != nil {
+		t.Fatalf("EnsureDefaults: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(broken) {
+		t.Fatalf("expected the malformed config to be left untouched, got %s", data)
+	}
+
+	errs := ValidateFiles()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "config.json") {
+		t.Fatalf("expected one error for config.json, got %v", errs)
+	}
+}
+
+func TestFailedSaveKeepsOriginalFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	cfg := &Config{Debug: &DebugSettings{MaxLogSizeMB: 5}}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	path := GetConfigPath()
+	original, _ := os.ReadFile(path)
+
+	// Write half the data, then fail as a full disk or crash would
+	errFull := errors.New("no space left on device")
+	orig := writeData
+	t.Cleanup(func() { writeData = orig })
+	writeData = func(f *os.File, data []byte) (int, error) {
+		n, _ := f.Write(data[:len(data)/2])
+		return n, errFull
+	}
+
+	cfg.Debug.MaxLogSizeMB = 50
+	if err := cfg.Save(); !errors.Is(err, errFull) {
+		t.Fatalf("expected the write error, got %v", err)
+	}
+	if err := SaveKeyBindings(DefaultKeyBindings()); !errors.Is(err, errFull) {
+		t.Fatalf("expected the write error from SaveKeyBindings, got %v", err)
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != string(original) {
+		t.Fatalf("expected the original config to survive, got %s", data)
+	}
+	if _, err := os.Stat(GetKeybindingsPath()); !os.IsNotExist(err) {
+		t.Fatalf("expected no partial keybindings file, got %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp-") {
+			t.Fatalf("expected temp files to be cleaned up, found %s", e.Name())
+		}
+	}
+}
+
+func TestSaveKeepsASymlinkedConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	if err := os.MkdirAll(filepath.Dir(GetConfigPath()), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	// A dotfiles repo keeps the real file and links it into place
+	target := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(target, []byte("{}"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	path := GetConfigPath()
+	if err := os.Symlink(target, path); err != nil {
+		t.Fatalf("Symlink: %v", err)
+	}
+
+	cfg := &Config{Debug: &DebugSettings{MaxLogSizeMB: 5}}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if info, err := os.Lstat(path); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("expected config.json to still be a symlink, got %v", err)
+	}
+	if data, _ := os.ReadFile(target); !strings.Contains(string(data), "max_log_size_mb") {
+		t.Fatalf("expected the link target to be updated, got %s", data)
+	}
+}
+
+func TestLegacyDirIsMigratedToXDGOnce(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	orig := useXDG
+	useXDG = true
+	t.Cleanup(func() { useXDG = orig })
+
+	legacy := filepath.Join(home, ".cm")
+	xdg := filepath.Join(home, ".config", "cm")
+	os.MkdirAll(legacy, 0755)
+	os.WriteFile(filepath.Join(legacy, "config.json"), []byte(`{"debug": {"max_log_size_mb": 3}}`), 0644)
+
+	// Until it's migrated the old directory keeps working
+	if got := GetConfigPath(); got != filepath.Join(legacy, "config.json") {
+		t.Fatalf("expected the unmigrated ~/.cm to be used, got %s", got)
+	}
+
+	from, to, err := MigrateLegacyDir()
+	if err != nil || from != legacy || to != xdg {
+		t.Fatalf("expected %s to move to %s, got %q %q %v", legacy, xdg, from, to, err)
+	}
+	if got := GetConfigPath(); got != filepath.Join(xdg, "config.json") {
+		t.Fatalf("expected the XDG config path after migrating, got %s", got)
+	}
+	cfg, err := Load()
+	if err != nil || cfg.GetDebugSettings().MaxLogSizeMB != 3 {
+		t.Fatalf("expected the migrated settings to load, got %+v %v", cfg, err)
+	}
+
+	if _, to, _ := MigrateLegacyDir(); to != "" {
+		t.Fatalf("expected the migration to run only once")
+	}
+
+	custom := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", custom)
+	if got := Dir(); got != filepath.Join(custom, "cm") {
+		t.Fatalf("expected $XDG_CONFIG_HOME to be respected, got %s", got)
+	}
+}
+
+func TestEnvOverridesLayerOverFileConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	cfg := &Config{
+		Notifications: &NotificationSettings{Mode: NotifyOS, ToastDuration: 5, ToastPosition: ToastTopLeft},
+		Display:       &DisplaySettings{LogBuffer: 2000},
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	t.Setenv(EnvNotifyMode, "none")
+	t.Setenv(EnvToastDuration, "eleven")
+	t.Setenv(EnvToastPosition, "top-right")
+	t.Setenv(EnvLogBuffer, "5000")
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	n := loaded.GetNotificationSettings()
+	if n.Mode != NotifyNone || n.ToastPosition != ToastTopRight {
+		t.Fatalf("expected env overrides to apply, got %+v", n)
+	}
+	if n.ToastDuration != 5 {
+		t.Fatalf("expected an invalid override to fall back to the file value, got %d", n.ToastDuration)
+	}
+	if saved := loaded.SavedNotificationSettings(); saved.Mode != NotifyOS || saved.ToastPosition != ToastTopLeft {
+		t.Fatalf("expected the saved settings without overrides, got %+v", saved)
+	}
+	if got := loaded.GetDisplaySettings().GetLogBuffer(); got != 5000 {
+		t.Fatalf("expected a log buffer of 5000, got %d", got)
+	}
+
+	warnings := EnvWarnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], EnvToastDuration) {
+		t.Fatalf("expected one warning for %s, got %v", EnvToastDuration, warnings)
+	}
+
+	// Overrides are not written back when the config is saved
+	if err := loaded.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	data, _ := os.ReadFile(GetConfigPath())
+	if strings.Contains(string(data), "none") || strings.Contains(string(data), "5000") {
+		t.Fatalf("expected env overrides to stay out of config.json, got %s", data)
+	}
+}
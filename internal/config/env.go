@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables that override config.json. They are applied by the
+// settings getters rather than by Load, so they never end up saved to disk.
+const (
+	EnvNotifyMode    = "CM_NOTIFY_MODE"    // "terminal", "os" or "none"
+	EnvToastDuration = "CM_TOAST_DURATION" // seconds, 1-10
+	EnvToastPosition = "CM_TOAST_POSITION" // "top-left", "top-right", "bottom-left" or "bottom-right"
+	EnvLogBuffer     = "CM_LOG_BUFFER"     // log lines kept per pane
+)
+
+// applyNotificationEnv overrides notification settings from the environment,
+// skipping invalid values, and returns a warning for each one skipped
+func applyNotificationEnv(n *NotificationSettings) []string {
+	var warnings []string
+	if v, ok := os.LookupEnv(EnvNotifyMode); ok {
+		switch mode := NotificationMode(v); mode {
+		case NotifyTerminal, NotifyOS, NotifyNone:
+			n.Mode = mode
+		default:
+			warnings = append(warnings, invalidEnv(EnvNotifyMode, v))
+		}
+	}
+	if v, ok := os.LookupEnv(EnvToastDuration); ok {
+		if d, err := strconv.Atoi(v); err == nil && d >= 1 && d <= 10 {
+			n.ToastDuration = d
+		} else {
+			warnings = append(warnings, invalidEnv(EnvToastDuration, v))
+		}
+	}
+	if v, ok := os.LookupEnv(EnvToastPosition); ok {
+		switch pos := ToastPosition(v); pos {
+		case ToastTopLeft, ToastTopRight, ToastBottomLeft, ToastBottomRight:
+			n.ToastPosition = pos
+		default:
+			warnings = append(warnings, invalidEnv(EnvToastPosition, v))
+		}
+	}
+	return warnings
+}
+
+// applyDisplayEnv overrides display settings from the environment, skipping
+// invalid values, and returns a warning for each one skipped
+func applyDisplayEnv(d *DisplaySettings) []string {
+	var warnings []string
+	if v, ok := os.LookupEnv(EnvLogBuffer); ok {
+		if lines, err := strconv.Atoi(v); err == nil && lines > 0 {
+			d.LogBuffer = lines
+		} else {
+			warnings = append(warnings, invalidEnv(EnvLogBuffer, v))
+		}
+	}
+	return warnings
+}
+
+func invalidEnv(name, value string) string {
+	return fmt.Sprintf("ignoring %s=%q: invalid value", name, value)
+}
+
+// EnvWarnings describes each override environment variable that is set to an
+// invalid value and therefore ignored
+func EnvWarnings() []string {
+	var n NotificationSettings
+	var d DisplaySettings
+	return append(applyNotificationEnv(&n), applyDisplayEnv(&d)...)
+}
@@ -2,10 +2,21 @@ package debug
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
+
+	"cm/internal/config"
+)
+
+const (
+	// tailBytes is how much of the end of the log file Tail reads
+	tailBytes = 64 * 1024
+	// DefaultMaxSize is the log file size at which it is rotated
+	DefaultMaxSize = 10 * 1024 * 1024
 )
 
 var (
@@ -13,21 +24,104 @@ var (
 	mu      sync.RWMutex
 	logFile *os.File
 	logPath string
+	// Path set by Init; DefaultLogPath is used when empty
+	configuredPath string
+	logSize        int64 // bytes in the current log file
+	maxSize        int64 = DefaultMaxSize
+	// Messages below this level are dropped
+	threshold = LevelDebug
+)
+
+// Level is the severity of a debug log message
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
 )
 
+// String returns the level name written in the log
+func (l Level) String() string {
+	switch l {
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+	return "DEBUG"
+}
+
+// ParseLevel parses a level name such as "info" or "WARN"
+func ParseLevel(s string) (Level, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, true
+	case "info":
+		return LevelInfo, true
+	case "warn", "warning":
+		return LevelWarn, true
+	case "error":
+		return LevelError, true
+	}
+	return LevelDebug, false
+}
+
+// SetLevel sets the lowest level that is written to the log
+func SetLevel(l Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	threshold = l
+}
+
+// Logger writes messages tagged with a component name
+type Logger struct {
+	component string
+}
+
+// For returns a logger that tags its messages with component
+func For(component string) Logger {
+	return Logger{component: component}
+}
+
+// Debug logs a message at LevelDebug
+func (l Logger) Debug(format string, args ...interface{}) {
+	write(LevelDebug, l.component, format, args...)
+}
+
+// Info logs a message at LevelInfo
+func (l Logger) Info(format string, args ...interface{}) {
+	write(LevelInfo, l.component, format, args...)
+}
+
+// Warn logs a message at LevelWarn
+func (l Logger) Warn(format string, args ...interface{}) {
+	write(LevelWarn, l.component, format, args...)
+}
+
+// Error logs a message at LevelError
+func (l Logger) Error(format string, args ...interface{}) {
+	write(LevelError, l.component, format, args...)
+}
+
 // DefaultLogPath returns the default debug log path
 func DefaultLogPath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
+	dir := config.Dir()
+	if dir == "" {
 		return "/tmp/cm_debug.log"
 	}
-	return filepath.Join(home, ".cm", "debug.log")
+	return filepath.Join(dir, "debug.log")
 }
 
-// Init initializes debug logging with the given state
-func Init(enable bool) {
+// Init initializes debug logging with the given state. The log is written to
+// path, or DefaultLogPath when path is empty.
+func Init(enable bool, path string) {
 	mu.Lock()
 	defer mu.Unlock()
+	configuredPath = path
 	enabled = enable
 	if enable {
 		initLogFile()
@@ -39,26 +133,79 @@ func initLogFile() {
 	if logFile != nil {
 		return
 	}
-	logPath = DefaultLogPath()
+	logPath = configuredPath
+	if logPath == "" {
+		logPath = DefaultLogPath()
+	}
 
 	// Ensure directory exists
 	dir := filepath.Dir(logPath)
 	os.MkdirAll(dir, 0755)
 
-	// Rotate log if it's too big (> 10MB)
-	if info, err := os.Stat(logPath); err == nil && info.Size() > 10*1024*1024 {
+	// Rotate a log left too big by an earlier session
+	if info, err := os.Stat(logPath); err == nil && info.Size() >= maxSize {
 		os.Rename(logPath, logPath+".old")
 	}
+	if !openLogFile() {
+		return
+	}
 
-	var err error
-	logFile, err = os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	// Write session start marker
+	writeLocked(fmt.Sprintf("\n=== Debug session started at %s ===\n", time.Now().Format(time.RFC3339)))
+}
+
+// openLogFile opens logPath for appending and records its size
+func openLogFile() bool {
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		logFile = nil
+		return false
+	}
+	logFile = f
+	logSize = 0
+	if info, err := f.Stat(); err == nil {
+		logSize = info.Size()
+	}
+	return true
+}
+
+// writeLocked appends text to the log file and rotates it once it reaches
+// maxSize, keeping one backup (<path>.old). Callers must hold mu for writing.
+func writeLocked(text string) {
+	if logFile == nil {
+		return
+	}
+	n, _ := logFile.WriteString(text)
+	logSize += int64(n)
+	if logSize < maxSize {
 		return
 	}
 
-	// Write session start marker
-	fmt.Fprintf(logFile, "\n=== Debug session started at %s ===\n", time.Now().Format(time.RFC3339))
+	logFile.Close()
+	os.Rename(logPath, logPath+".old")
+	if openLogFile() {
+		n, _ := fmt.Fprintf(logFile, "=== Log rotated at %s ===\n", time.Now().Format(time.RFC3339))
+		logSize += int64(n)
+	}
+}
+
+// SetMaxSize sets the size in bytes at which the log file is rotated.
+// Values below 1 restore the default.
+func SetMaxSize(bytes int64) {
+	mu.Lock()
+	defer mu.Unlock()
+	if bytes < 1 {
+		bytes = DefaultMaxSize
+	}
+	maxSize = bytes
+}
+
+// Configure applies the debug section of config.json: the rotation size and
+// the lowest level written, which falls back to LevelDebug when unset
+func Configure(settings config.DebugSettings) {
+	SetMaxSize(settings.GetMaxLogSize())
+	level, _ := ParseLevel(settings.Level)
+	SetLevel(level)
 }
 
 // Enable turns on debug logging
@@ -70,10 +217,8 @@ func Enable() {
 	}
 	enabled = true
 	initLogFile()
-	// Write directly to avoid deadlock (Log() would try to acquire RLock)
-	if logFile != nil {
-		fmt.Fprintf(logFile, "[%s] Debug logging enabled\n", time.Now().Format("15:04:05.000"))
-	}
+	// Write directly to avoid deadlock (Log() would try to acquire the lock)
+	writeLocked(fmt.Sprintf("[%s] Debug logging enabled\n", time.Now().Format("15:04:05.000")))
 }
 
 // Disable turns off debug logging
@@ -83,10 +228,8 @@ func Disable() {
 	if !enabled {
 		return
 	}
-	// Write directly to avoid deadlock (Log() would try to acquire RLock)
-	if logFile != nil {
-		fmt.Fprintf(logFile, "[%s] Debug logging disabled\n", time.Now().Format("15:04:05.000"))
-	}
+	// Write directly to avoid deadlock (Log() would try to acquire the lock)
+	writeLocked(fmt.Sprintf("[%s] Debug logging disabled\n", time.Now().Format("15:04:05.000")))
 	enabled = false
 }
 
@@ -97,12 +240,10 @@ func Toggle() bool {
 	enabled = !enabled
 	if enabled {
 		initLogFile()
-		// Write directly to avoid deadlock (Log() would try to acquire RLock)
-		if logFile != nil {
-			fmt.Fprintf(logFile, "[%s] Debug logging enabled via toggle\n", time.Now().Format("15:04:05.000"))
-		}
-	} else if logFile != nil {
-		fmt.Fprintf(logFile, "[%s] Debug logging disabled via toggle\n", time.Now().Format("15:04:05.000"))
+		// Write directly to avoid deadlock (Log() would try to acquire the lock)
+		writeLocked(fmt.Sprintf("[%s] Debug logging enabled via toggle\n", time.Now().Format("15:04:05.000")))
+	} else {
+		writeLocked(fmt.Sprintf("[%s] Debug logging disabled via toggle\n", time.Now().Format("15:04:05.000")))
 	}
 	return enabled
 }
@@ -114,18 +255,79 @@ func IsEnabled() bool {
 	return enabled
 }
 
-// Log writes a debug message if debug mode is enabled
+// Log writes an untagged message at LevelDebug if debug mode is enabled
 func Log(format string, args ...interface{}) {
-	mu.RLock()
-	defer mu.RUnlock()
+	write(LevelDebug, "", format, args...)
+}
 
-	if !enabled || logFile == nil {
+// write formats and appends a message if debug mode is enabled and level
+// meets the threshold
+func write(level Level, component string, format string, args ...interface{}) {
+	mu.RLock()
+	skip := !enabled || level < threshold
+	mu.RUnlock()
+	if skip {
 		return
 	}
 
-	timestamp := time.Now().Format("15:04:05.000")
+	prefix := fmt.Sprintf("[%s] %-5s ", time.Now().Format("15:04:05.000"), level)
+	if component != "" {
+		prefix += component + ": "
+	}
 	msg := fmt.Sprintf(format, args...)
-	fmt.Fprintf(logFile, "[%s] %s\n", timestamp, msg)
+
+	// Writing needs the exclusive lock: the size count and rotation must not race
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabled {
+		return
+	}
+	writeLocked(prefix + msg + "\n")
+}
+
+// Tail returns up to n of the most recent lines written to the log file
+func Tail(n int) []string {
+	mu.RLock()
+	path := logPath
+	mu.RUnlock()
+	if path == "" || n <= 0 {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil
+	}
+	offset := info.Size() - tailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	data, err := io.ReadAll(io.NewSectionReader(f, offset, info.Size()-offset))
+	if err != nil {
+		return nil
+	}
+
+	text := strings.TrimRight(string(data), "\n")
+	if offset > 0 {
+		// Drop the line the read started in the middle of
+		if i := strings.IndexByte(text, '\n'); i >= 0 {
+			text = text[i+1:]
+		}
+	}
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
 }
 
 // LogPath returns the current log file path
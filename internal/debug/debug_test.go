@@ -0,0 +1,118 @@
+package debug
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// startLog enables logging to a fresh file under a temp HOME
+func startLog(t *testing.T, max int64) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	SetMaxSize(max)
+	Init(true, "")
+	t.Cleanup(func() {
+		Init(false, "")
+		Close()
+		SetMaxSize(0)
+	})
+}
+
+func TestLogRotatesAtMaxSize(t *testing.T) {
+	const max = 4096
+	startLog(t, max)
+
+	// Log from several goroutines at once; sizes and rotation must stay consistent
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				Log("goroutine %d line %d %s", g, i, strings.Repeat("x", 40))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	info, err := os.Stat(LogPath())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Size() >= max {
+		t.Fatalf("expected log to stay under %d bytes, got %d", max, info.Size())
+	}
+	old, err := os.Stat(LogPath() + ".old")
+	if err != nil {
+		t.Fatalf("expected a rotated backup: %v", err)
+	}
+	if old.Size() < max {
+		t.Fatalf("expected backup to hold a full log, got %d bytes", old.Size())
+	}
+}
+
+func TestTailReturnsMostRecentLines(t *testing.T) {
+	startLog(t, 0)
+	for i := 0; i < 5; i++ {
+		Log("line %d", i)
+	}
+
+	lines := Tail(2)
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "line 3") || !strings.HasSuffix(lines[1], "line 4") {
+		t.Fatalf("unexpected tail %q", lines)
+	}
+}
+
+func TestInitWritesToConfiguredPath(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	path := t.TempDir() + "/repro.log"
+	Init(true, path)
+	t.Cleanup(func() {
+		Init(false, "")
+		Close()
+	})
+	Log("hello")
+
+	if LogPath() != path {
+		t.Fatalf("expected log path %q, got %q", path, LogPath())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), "hello") {
+		t.Fatalf("expected log written to configured path (%v)", err)
+	}
+	if _, err := os.Stat(DefaultLogPath()); !os.IsNotExist(err) {
+		t.Fatalf("expected default log not to be created")
+	}
+}
+
+func TestLevelThresholdAndComponentTags(t *testing.T) {
+	startLog(t, 0)
+	SetLevel(LevelWarn)
+	t.Cleanup(func() { SetLevel(LevelDebug) })
+
+	log := For("docker")
+	log.Info("dropped info")
+	log.Warn("kept warn")
+	log.Error("kept error")
+	Log("dropped legacy")
+
+	lines := Tail(10)
+	text := strings.Join(lines, "\n")
+	if strings.Contains(text, "dropped") {
+		t.Fatalf("expected messages below the threshold to be dropped, got:\n%s", text)
+	}
+	if !strings.Contains(text, "WARN  docker: kept warn") || !strings.Contains(text, "ERROR docker: kept error") {
+		t.Fatalf("expected level and component prefixes, got:\n%s", text)
+	}
+
+	if l, ok := ParseLevel("Warning"); !ok || l != LevelWarn {
+		t.Fatalf("expected warning to parse as WARN")
+	}
+	if _, ok := ParseLevel("loud"); ok {
+		t.Fatalf("expected unknown level to be rejected")
+	}
+}
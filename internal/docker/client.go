@@ -1,21 +1,33 @@
 package docker
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"cm/internal/config"
+	"cm/internal/debug"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 )
 
+// logger tags the docker package's debug log messages
+var logger = debug.For("docker")
+
 // Cache for compose services to avoid spawning processes on every refresh
 var (
 	composeServicesCache     = make(map[string][]string)
@@ -36,6 +48,10 @@ var (
 	projectsCacheLock sync.RWMutex
 	projectsCacheTime time.Time
 	projectsDirty     bool
+
+	// Finish times of exited containers (they don't change while exited)
+	finishedAtCache     = make(map[string]time.Time)
+	finishedAtCacheLock sync.Mutex
 )
 
 // Client wraps the Docker SDK client
@@ -72,6 +88,24 @@ func (c *Client) Close() error {
 	return c.cli.Close()
 }
 
+// ServerVersion returns the Docker engine and API versions
+func (c *Client) ServerVersion(ctx context.Context) (version, apiVersion string, err error) {
+	v, err := c.cli.ServerVersion(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get Docker version: %w", err)
+	}
+	return v.Version, v.APIVersion, nil
+}
+
+// ComposeVersion returns the version reported by the docker compose v2 plugin
+func ComposeVersion(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "docker", "compose", "version", "--short").Output()
+	if err != nil {
+		return "", fmt.Errorf("docker compose v2 not available: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 // ListContainers returns all containers (running and recently exited)
 func (c *Client) ListContainers(ctx context.Context) ([]Container, error) {
 	containers, err := c.cli.ContainerList(ctx, container.ListOptions{
@@ -80,6 +114,11 @@ func (c *Client) ListContainers(ctx context.Context) ([]Container, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to list containers: %w", err)
 	}
+	listed := make(map[string]bool, len(containers))
+	for _, cont := range containers {
+		listed[cont.ID] = true
+	}
+	pruneFinishedAtCache(listed)
 
 	// Load saved projects (cached)
 	projects := getCachedProjects()
@@ -88,25 +127,31 @@ func (c *Client) ListContainers(ctx context.Context) ([]Container, error) {
 	projectInfo := make(map[string]composeProjectInfo)
 
 	// Include local compose project from current directory
-	localProject, localComposeFile := DetectLocalComposeProjectWithFile()
-	if localProject != "" && localComposeFile != "" {
+	localProject, localComposeFiles := DetectLocalComposeProjectWithFiles()
+	if localProject != "" && len(localComposeFiles) > 0 {
 		cwd, _ := os.Getwd()
 		projectInfo[localProject] = composeProjectInfo{
-			configFile: localComposeFile,
-			workingDir: cwd,
+			configFiles: localComposeFiles,
+			workingDir:  cwd,
 		}
 		// Auto-save local compose project
-		updateProject(localProject, localComposeFile, cwd)
+		updateProject(localProject, localComposeFiles, cwd)
 	}
 
+	exitedWindow := getCachedConfig().GetDiscoverySettings().GetExitedWindow()
+
 	for _, cont := range containers {
-		// Skip containers that have been exited for more than 1 hour
+		// Skip containers that exited longer ago than the configured window
 		if cont.State == "exited" {
-			// Check if exited recently (within last hour)
-			created := time.Unix(cont.Created, 0)
-			if time.Since(created) > 24*time.Hour {
+			finished := c.finishedAt(ctx, cont.ID, time.Unix(cont.Created, 0))
+			if time.Since(finished) > exitedWindow {
 				continue
 			}
+		} else {
+			// A restarted container will get a new finish time when it next exits
+			finishedAtCacheLock.Lock()
+			delete(finishedAtCache, cont.ID)
+			finishedAtCacheLock.Unlock()
 		}
 
 		name := ""
@@ -117,28 +162,34 @@ func (c *Client) ListContainers(ctx context.Context) ([]Container, error) {
 		// Collect compose project info from labels
 		project := cont.Labels[LabelComposeProject]
 		if project != "" {
-			configFile := cont.Labels[LabelComposeConfigFile]
+			configFiles := config.SplitConfigFiles(cont.Labels[LabelComposeConfigFile])
 			workingDir := cont.Labels[LabelComposeWorkingDir]
 
-			if _, exists := projectInfo[project]; !exists {
+			if info, exists := projectInfo[project]; !exists {
 				projectInfo[project] = composeProjectInfo{
-					configFile: configFile,
-					workingDir: workingDir,
+					configFiles: configFiles,
+					workingDir:  workingDir,
 				}
 				// Auto-save detected compose projects
-				updateProject(project, configFile, workingDir)
+				updateProject(project, configFiles, workingDir)
+			} else if workingDir != "" && info.workingDir != "" && workingDir != info.workingDir {
+				logProjectConflict(project, info.workingDir, workingDir)
 			}
 		}
 
+		replica, _ := strconv.Atoi(cont.Labels[LabelComposeReplica])
 		result = append(result, Container{
-			ID:             cont.ID[:12],
-			Name:           name,
-			Status:         cont.Status,
-			State:          cont.State,
-			ComposeProject: project,
-			ComposeService: cont.Labels[LabelComposeService],
-			Image:          cont.Image,
-			Created:        time.Unix(cont.Created, 0),
+			ID:                cont.ID[:12],
+			Name:              name,
+			Status:            cont.Status,
+			State:             cont.State,
+			ComposeProject:    project,
+			ComposeService:    cont.Labels[LabelComposeService],
+			ComposeConfigFile: cont.Labels[LabelComposeConfigFile],
+			ComposeWorkingDir: cont.Labels[LabelComposeWorkingDir],
+			ComposeReplica:    replica,
+			Image:             cont.Image,
+			Created:           time.Unix(cont.Created, 0),
 		})
 	}
 
@@ -146,8 +197,8 @@ func (c *Client) ListContainers(ctx context.Context) ([]Container, error) {
 	for name, proj := range projects.SavedProjects {
 		if _, exists := projectInfo[name]; !exists {
 			projectInfo[name] = composeProjectInfo{
-				configFile: proj.ConfigFile,
-				workingDir: proj.WorkingDir,
+				configFiles: proj.ConfigFiles,
+				workingDir:  proj.WorkingDir,
 			}
 		}
 	}
@@ -159,10 +210,51 @@ func (c *Client) ListContainers(ctx context.Context) ([]Container, error) {
 	return result, nil
 }
 
+// ListRunningNames returns the names and compose services of running
+// containers. Unlike ListContainers it reads no compose files and saves no
+// projects, so shell completion stays fast and side-effect free.
+func (c *Client) ListRunningNames(ctx context.Context) ([]string, error) {
+	containers, err := c.cli.ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("status", "running")),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	var names []string
+	for _, cont := range containers {
+		if len(cont.Names) > 0 {
+			names = append(names, strings.TrimPrefix(cont.Names[0], "/"))
+		}
+		if service := cont.Labels[LabelComposeService]; service != "" {
+			names = append(names, service)
+		}
+	}
+	return names, nil
+}
+
+// reportedConflicts remembers the project name collisions already logged
+var (
+	reportedConflicts     = make(map[string]bool)
+	reportedConflictsLock sync.Mutex
+)
+
+// logProjectConflict warns, once per pair of directories, that two compose
+// projects use the same name. Only one of them can be saved in projects.json.
+func logProjectConflict(project, savedDir, otherDir string) {
+	key := project + "\x00" + savedDir + "\x00" + otherDir
+	reportedConflictsLock.Lock()
+	defer reportedConflictsLock.Unlock()
+	if reportedConflicts[key] {
+		return
+	}
+	reportedConflicts[key] = true
+	logger.Warn("Compose project %q is used by both %s and %s; running containers keep their own compose files", project, savedDir, otherDir)
+}
+
 // composeProjectInfo stores compose file info for a project
 type composeProjectInfo struct {
-	configFile string
-	workingDir string
+	configFiles []string
+	workingDir  string
 }
 
 // getStoppedComposeServices finds services defined in compose files that aren't running
@@ -193,13 +285,14 @@ func (c *Client) getStoppedComposeServices(containers []Container, projectInfo m
 	for project, info := range projectInfo {
 		project := project
 		info := info
+		envFile := composeEnvFile(Container{ComposeProject: project, ComposeWorkingDir: info.workingDir})
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			services := getComposeServices(project, info.configFile, info.workingDir)
+			services := getComposeServices(project, info.configFiles, info.workingDir, envFile)
 			results <- stoppedResult{project: project, services: services}
 		}()
 	}
@@ -215,7 +308,7 @@ func (c *Client) getStoppedComposeServices(containers []Container, projectInfo m
 			if !runningServices[result.project][svc] {
 				stopped = append(stopped, Container{
 					ID:             fmt.Sprintf("stopped:%s:%s", result.project, svc), // Unique ID for stopped services
-					Name:           fmt.Sprintf("%s-%s", result.project, svc),
+					Name:           svc,                                               // No container exists yet, so the service is the name
 					Status:         "Not started",
 					State:          "stopped",
 					ComposeProject: result.project,
@@ -228,6 +321,43 @@ func (c *Client) getStoppedComposeServices(containers []Container, projectInfo m
 	return stopped
 }
 
+// pruneFinishedAtCache drops the finish times of containers that are no
+// longer listed, e.g. removed ones, so the cache doesn't grow for good
+func pruneFinishedAtCache(listed map[string]bool) {
+	finishedAtCacheLock.Lock()
+	defer finishedAtCacheLock.Unlock()
+	for id := range finishedAtCache {
+		if !listed[id] {
+			delete(finishedAtCache, id)
+		}
+	}
+}
+
+// finishedAt returns when an exited container stopped, falling back to
+// its creation time if it can't be inspected
+func (c *Client) finishedAt(ctx context.Context, containerID string, created time.Time) time.Time {
+	finishedAtCacheLock.Lock()
+	finished, ok := finishedAtCache[containerID]
+	finishedAtCacheLock.Unlock()
+	if ok {
+		return finished
+	}
+
+	inspect, err := c.cli.ContainerInspect(ctx, containerID)
+	if err != nil || inspect.State == nil {
+		return created
+	}
+	finished, err = time.Parse(time.RFC3339Nano, inspect.State.FinishedAt)
+	if err != nil || finished.IsZero() {
+		return created
+	}
+
+	finishedAtCacheLock.Lock()
+	finishedAtCache[containerID] = finished
+	finishedAtCacheLock.Unlock()
+	return finished
+}
+
 // getCachedConfig returns the cached config or loads from disk
 func getCachedConfig() *config.Config {
 	configCacheLock.RLock()
@@ -247,7 +377,9 @@ func getCachedConfig() *config.Config {
 
 	// Save dirty config before reloading
 	if configDirty && configCache != nil {
-		_ = configCache.Save()
+		if err := configCache.Save(); err != nil {
+			logger.Error("Failed to save config: %v", err)
+		}
 		configDirty = false
 	}
 
@@ -272,11 +404,28 @@ func SaveConfigIfDirty() {
 	configCacheLock.Lock()
 	defer configCacheLock.Unlock()
 	if configDirty && configCache != nil {
-		_ = configCache.Save()
+		if err := configCache.Save(); err != nil {
+			logger.Error("Failed to save config: %v", err)
+		}
 		configDirty = false
 	}
 }
 
+// InvalidateConfigCache drops the cached config and projects, saving them
+// first if dirty, so the next read picks up edits made to the files
+func InvalidateConfigCache() {
+	SaveConfigIfDirty()
+	SaveProjectsIfDirty()
+
+	configCacheLock.Lock()
+	configCache = nil
+	configCacheLock.Unlock()
+
+	projectsCacheLock.Lock()
+	projectsCache = nil
+	projectsCacheLock.Unlock()
+}
+
 // getCachedProjects returns the cached projects or loads from disk
 func getCachedProjects() *config.Projects {
 	projectsCacheLock.RLock()
@@ -296,7 +445,9 @@ func getCachedProjects() *config.Projects {
 
 	// Save dirty projects before reloading
 	if projectsDirty && projectsCache != nil {
-		_ = projectsCache.Save()
+		if err := projectsCache.Save(); err != nil {
+			logger.Error("Failed to save projects: %v", err)
+		}
 		projectsDirty = false
 	}
 
@@ -305,9 +456,25 @@ func getCachedProjects() *config.Projects {
 	return projectsCache
 }
 
+// ProjectConfigFiles returns the compose file(s) of each known project, keyed
+// by project name, as recorded from container labels and projects.json
+func ProjectConfigFiles() map[string][]string {
+	projects := getCachedProjects()
+	projectsCacheLock.RLock()
+	defer projectsCacheLock.RUnlock()
+
+	files := make(map[string][]string, len(projects.SavedProjects))
+	for name, proj := range projects.SavedProjects {
+		if len(proj.ConfigFiles) > 0 {
+			files[name] = proj.ConfigFiles
+		}
+	}
+	return files
+}
+
 // updateProject adds or updates a project in the cache and saves immediately
-func updateProject(name string, configFile, workingDir string) {
-	if name == "" || (configFile == "" && workingDir == "") {
+func updateProject(name string, configFiles []string, workingDir string) {
+	if name == "" || (len(configFiles) == 0 && workingDir == "") {
 		return
 	}
 
@@ -320,20 +487,20 @@ func updateProject(name string, configFile, workingDir string) {
 	}
 
 	// Check if project already exists with same info
-	if existing, ok := projectsCache.SavedProjects[name]; ok {
-		if existing.ConfigFile == configFile && existing.WorkingDir == workingDir {
-			return // No change needed
-		}
+	existing, ok := projectsCache.SavedProjects[name]
+	if ok && slices.Equal(existing.ConfigFiles, configFiles) && existing.WorkingDir == workingDir {
+		return // No change needed
 	}
 
-	// Add or update the project
-	projectsCache.SavedProjects[name] = config.SavedProject{
-		ConfigFile: configFile,
-		WorkingDir: workingDir,
-	}
+	// Add or update the project, keeping its env file and saved view
+	existing.ConfigFiles = configFiles
+	existing.WorkingDir = workingDir
+	projectsCache.SavedProjects[name] = existing
 
 	// Save immediately so it's available when modal opens
-	_ = projectsCache.Save()
+	if err := projectsCache.Save(); err != nil {
+		logger.Error("Failed to save projects: %v", err)
+	}
 }
 
 // SaveProjectsIfDirty saves the projects if they have been modified
@@ -341,14 +508,26 @@ func SaveProjectsIfDirty() {
 	projectsCacheLock.Lock()
 	defer projectsCacheLock.Unlock()
 	if projectsDirty && projectsCache != nil {
-		_ = projectsCache.Save()
+		if err := projectsCache.Save(); err != nil {
+			logger.Error("Failed to save projects: %v", err)
+		}
 		projectsDirty = false
 	}
 }
 
+// composeServicesArgs returns the args listing a project's services, with its
+// env file so compose files interpolating from it still parse
+func composeServicesArgs(project string, configFiles []string, envFile string) []string {
+	args := composeFileArgs(configFiles)
+	if envFile != "" {
+		args = append(args, "--env-file", envFile)
+	}
+	return append(args, "-p", project, "config", "--services")
+}
+
 // getComposeServices runs docker compose to get service names (cached)
-func getComposeServices(project, configFile, workingDir string) []string {
-	cacheKey := project + ":" + configFile + ":" + workingDir
+func getComposeServices(project string, configFiles []string, workingDir, envFile string) []string {
+	cacheKey := fmt.Sprintf("%s:%q:%s:%s", project, configFiles, workingDir, envFile)
 
 	// Check cache first
 	composeServicesCacheLock.RLock()
@@ -360,20 +539,7 @@ func getComposeServices(project, configFile, workingDir string) []string {
 	}
 	composeServicesCacheLock.RUnlock()
 
-	var args []string
-
-	// Use specific compose file if available
-	if configFile != "" {
-		// configFile may contain multiple files separated by comma
-		for _, f := range strings.Split(configFile, ",") {
-			f = strings.TrimSpace(f)
-			if f != "" {
-				args = append(args, "-f", f)
-			}
-		}
-	}
-
-	args = append(args, "-p", project, "config", "--services")
+	args := composeServicesArgs(project, configFiles, envFile)
 
 	cmdCtx, cancel := context.WithTimeout(context.Background(), composeCmdTimeout)
 	defer cancel()
@@ -417,11 +583,143 @@ func (c *Client) KillContainer(ctx context.Context, containerID string) error {
 	return c.cli.ContainerKill(ctx, containerID, "SIGKILL")
 }
 
+// PauseContainer freezes all processes in a container
+func (c *Client) PauseContainer(ctx context.Context, containerID string) error {
+	return c.cli.ContainerPause(ctx, containerID)
+}
+
+// UnpauseContainer resumes the processes of a paused container
+func (c *Client) UnpauseContainer(ctx context.Context, containerID string) error {
+	return c.cli.ContainerUnpause(ctx, containerID)
+}
+
+// signalNames lists the signals SignalContainer accepts by name, without the SIG prefix
+var signalNames = []string{
+	"HUP", "INT", "QUIT", "ILL", "TRAP", "ABRT", "BUS", "FPE", "KILL", "USR1",
+	"SEGV", "USR2", "PIPE", "ALRM", "TERM", "STKFLT", "CHLD", "CONT", "STOP",
+	"TSTP", "TTIN", "TTOU", "URG", "XCPU", "XFSZ", "VTALRM", "PROF", "WINCH",
+	"IO", "PWR", "SYS",
+}
+
+// ParseSignal validates a signal given as a name, with or without the SIG
+// prefix, or a number, and returns it in the form ContainerKill expects
+func ParseSignal(signal string) (string, error) {
+	signal = strings.ToUpper(strings.TrimSpace(signal))
+	if signal == "" {
+		return "", fmt.Errorf("no signal given")
+	}
+	if n, err := strconv.Atoi(signal); err == nil {
+		if n < 1 || n > 64 {
+			return "", fmt.Errorf("signal %d out of range", n)
+		}
+		return signal, nil
+	}
+	name := strings.TrimPrefix(signal, "SIG")
+	for _, s := range signalNames {
+		if s == name {
+			return "SIG" + name, nil
+		}
+	}
+	return "", fmt.Errorf("unknown signal %q", signal)
+}
+
+// SignalContainer sends a signal, e.g. SIGHUP to reload a config, to a container
+func (c *Client) SignalContainer(ctx context.Context, containerID, signal string) error {
+	signal, err := ParseSignal(signal)
+	if err != nil {
+		return err
+	}
+	return c.cli.ContainerKill(ctx, containerID, signal)
+}
+
+// containerNamePattern matches the names the daemon accepts for containers
+var containerNamePattern = regexp.MustCompile(`^/?[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+
+// RenameContainer gives a container a new name. A name that is already taken
+// is reported as such rather than as the daemon's conflict message.
+func (c *Client) RenameContainer(ctx context.Context, containerID, name string) error {
+	name = strings.TrimSpace(name)
+	if !containerNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid name %q (letters, digits, _ . - only)", name)
+	}
+	if err := c.cli.ContainerRename(ctx, containerID, name); err != nil {
+		if cerrdefs.IsConflict(err) {
+			return fmt.Errorf("name %q is already in use", strings.TrimPrefix(name, "/"))
+		}
+		return err
+	}
+	return nil
+}
+
 // RemoveContainer removes a container (force removes if running)
 func (c *Client) RemoveContainer(ctx context.Context, containerID string) error {
 	return c.cli.ContainerRemove(ctx, containerID, container.RemoveOptions{Force: true})
 }
 
+// PruneStoppedContainers removes every stopped container, like docker
+// container prune, and returns how many were removed and the space reclaimed
+func (c *Client) PruneStoppedContainers(ctx context.Context) (int, uint64, error) {
+	report, err := c.cli.ContainersPrune(ctx, filters.Args{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to prune containers: %w", err)
+	}
+
+	finishedAtCacheLock.Lock()
+	for _, id := range report.ContainersDeleted {
+		delete(finishedAtCache, id)
+	}
+	finishedAtCacheLock.Unlock()
+
+	return len(report.ContainersDeleted), report.SpaceReclaimed, nil
+}
+
+// PruneDanglingImages removes untagged images not used by any container and
+// returns how many were deleted and the space reclaimed
+func (c *Client) PruneDanglingImages(ctx context.Context) (int, uint64, error) {
+	report, err := c.cli.ImagesPrune(ctx, filters.NewArgs(filters.Arg("dangling", "true")))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to prune images: %w", err)
+	}
+
+	// Untagging a shared image also gets an entry, only count deleted images
+	deleted := 0
+	for _, img := range report.ImagesDeleted {
+		if img.Deleted != "" {
+			deleted++
+		}
+	}
+	return deleted, report.SpaceReclaimed, nil
+}
+
+// PruneUnusedVolumes removes volumes not used by any container, like docker
+// volume prune (on current daemons only anonymous volumes), and returns how
+// many were removed and the space reclaimed
+func (c *Client) PruneUnusedVolumes(ctx context.Context) (int, uint64, error) {
+	report, err := c.cli.VolumesPrune(ctx, filters.Args{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to prune volumes: %w", err)
+	}
+	return len(report.VolumesDeleted), report.SpaceReclaimed, nil
+}
+
+// InspectContainerJSON returns a container's full `docker inspect` JSON, indented
+func (c *Client) InspectContainerJSON(ctx context.Context, containerID string) ([]byte, error) {
+	_, raw, err := c.cli.ContainerInspectWithRaw(ctx, containerID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	return indentJSON(raw), nil
+}
+
+// indentJSON indents raw JSON for display, returning it unchanged if it doesn't parse
+func indentJSON(raw []byte) []byte {
+	var out bytes.Buffer
+	if err := json.Indent(&out, raw, "", "  "); err != nil {
+		return raw
+	}
+	return out.Bytes()
+}
+
 // InspectContainer returns detailed information about a container
 func (c *Client) InspectContainer(ctx context.Context, containerID string) (*ContainerDetails, error) {
 	info, err := c.cli.ContainerInspect(ctx, containerID)
@@ -436,6 +734,9 @@ func (c *Client) InspectContainer(ctx context.Context, containerID string) (*Con
 		Status: info.State.Status,
 		State:  info.State.Status,
 		Labels: info.Config.Labels,
+
+		ExitCode:  info.State.ExitCode,
+		OOMKilled: info.State.OOMKilled,
 	}
 
 	// Parse created time
@@ -500,6 +801,99 @@ func (c *Client) InspectContainer(ctx context.Context, containerID string) (*Con
 	return details, nil
 }
 
+// RecreateContainer replaces a container with one created from newImage, with
+// the same name, settings and networks, like compose does when a service's
+// image changed. A restart would keep running the old image. It returns the
+// new container's ID.
+func (c *Client) RecreateContainer(ctx context.Context, containerID, newImage string) (string, error) {
+	info, err := c.cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if info.Config == nil || info.HostConfig == nil {
+		return "", fmt.Errorf("container %s has no config to recreate it from", containerID)
+	}
+	// Settings that came from the old image are left to the new one
+	oldImage, err := c.cli.ImageInspect(ctx, info.Image)
+	if err != nil {
+		oldImage = image.InspectResponse{}
+	}
+	cfg := recreateConfig(*info.Config, oldImage, newImage, info.ID)
+
+	endpoints := make(map[string]*network.EndpointSettings)
+	if info.NetworkSettings != nil {
+		for name, ep := range info.NetworkSettings.Networks {
+			if ep == nil {
+				continue
+			}
+			endpoints[name] = &network.EndpointSettings{
+				IPAMConfig: ep.IPAMConfig,
+				Links:      ep.Links,
+				Aliases:    ep.Aliases,
+				DriverOpts: ep.DriverOpts,
+			}
+		}
+	}
+
+	if err := c.StopContainer(ctx, containerID); err != nil {
+		return "", fmt.Errorf("failed to stop container: %w", err)
+	}
+	if err := c.cli.ContainerRemove(ctx, containerID, container.RemoveOptions{}); err != nil {
+		return "", fmt.Errorf("failed to remove container: %w", err)
+	}
+	name := strings.TrimPrefix(info.Name, "/")
+	created, err := c.cli.ContainerCreate(ctx, &cfg, info.HostConfig, &network.NetworkingConfig{EndpointsConfig: endpoints}, nil, name)
+	if err != nil {
+		return "", fmt.Errorf("old container removed, but creating %s from %s failed: %w", name, newImage, err)
+	}
+	if err := c.StartContainer(ctx, created.ID); err != nil {
+		return created.ID, fmt.Errorf("failed to start recreated container: %w", err)
+	}
+	return created.ID, nil
+}
+
+// recreateConfig returns a container's config for a container created from
+// newImage. Values the container only had because the old image set them are
+// cleared, so the new image's own apply; values set for the container stay.
+func recreateConfig(cfg container.Config, oldImage image.InspectResponse, newImage, oldID string) container.Config {
+	cfg.Image = newImage
+	// The default hostname is the old container's short ID
+	if len(cfg.Hostname) == 12 && strings.HasPrefix(oldID, cfg.Hostname) {
+		cfg.Hostname = ""
+	}
+	old := oldImage.Config
+	if old == nil {
+		return cfg
+	}
+	if slices.Equal(cfg.Entrypoint, old.Entrypoint) {
+		cfg.Entrypoint = nil
+	}
+	if slices.Equal(cfg.Cmd, old.Cmd) {
+		cfg.Cmd = nil
+	}
+	if cfg.WorkingDir == old.WorkingDir {
+		cfg.WorkingDir = ""
+	}
+	if cfg.User == old.User {
+		cfg.User = ""
+	}
+	var env []string
+	for _, e := range cfg.Env {
+		if !slices.Contains(old.Env, e) {
+			env = append(env, e)
+		}
+	}
+	cfg.Env = env
+	labels := make(map[string]string)
+	for k, v := range cfg.Labels {
+		if ov, ok := old.Labels[k]; !ok || ov != v {
+			labels[k] = v
+		}
+	}
+	cfg.Labels = labels
+	return cfg
+}
+
 // RestartContainer restarts a container
 func (c *Client) RestartContainer(ctx context.Context, containerID string) error {
 	timeout := 10 // seconds
@@ -536,39 +930,7 @@ func (c *Client) StreamStats(ctx context.Context, containerID string) (<-chan Co
 					return
 				}
 
-				stats := ContainerStats{
-					Timestamp: time.Now(),
-				}
-
-				// Calculate CPU percentage
-				cpuDelta := float64(statsJSON.CPUStats.CPUUsage.TotalUsage - statsJSON.PreCPUStats.CPUUsage.TotalUsage)
-				systemDelta := float64(statsJSON.CPUStats.SystemUsage - statsJSON.PreCPUStats.SystemUsage)
-				if systemDelta > 0 && cpuDelta > 0 {
-					cpuCount := float64(statsJSON.CPUStats.OnlineCPUs)
-					if cpuCount == 0 {
-						cpuCount = float64(len(statsJSON.CPUStats.CPUUsage.PercpuUsage))
-					}
-					if cpuCount == 0 {
-						cpuCount = 1
-					}
-					stats.CPUPercent = (cpuDelta / systemDelta) * cpuCount * 100.0
-				}
-
-				// Memory stats
-				stats.MemoryUsage = statsJSON.MemoryStats.Usage
-				stats.MemoryLimit = statsJSON.MemoryStats.Limit
-				if stats.MemoryLimit > 0 {
-					stats.MemoryPercent = float64(stats.MemoryUsage) / float64(stats.MemoryLimit) * 100.0
-				}
-
-				// Network stats
-				for _, netStats := range statsJSON.Networks {
-					stats.NetworkRx += netStats.RxBytes
-					stats.NetworkTx += netStats.TxBytes
-				}
-
-				// PIDs
-				stats.PIDs = statsJSON.PidsStats.Current
+				stats := statsFromResponse(statsJSON, time.Now())
 
 				select {
 				case statsChan <- stats:
@@ -582,6 +944,178 @@ func (c *Client) StreamStats(ctx context.Context, containerID string) (<-chan Co
 	return statsChan, errChan
 }
 
+// GetStatsSnapshot fetches a single stats sample for a container without
+// keeping a stream open. Docker takes about a second to answer, since it
+// samples twice to work out CPU usage.
+func (c *Client) GetStatsSnapshot(ctx context.Context, containerID string) (ContainerStats, error) {
+	resp, err := c.cli.ContainerStats(ctx, containerID, false)
+	if err != nil {
+		return ContainerStats{}, fmt.Errorf("failed to get container stats: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var statsJSON container.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&statsJSON); err != nil {
+		return ContainerStats{}, fmt.Errorf("failed to read container stats: %w", err)
+	}
+	return statsFromResponse(statsJSON, time.Now()), nil
+}
+
+// statsFromResponse computes CPU, memory, network and PID figures from a
+// Docker stats sample
+func statsFromResponse(statsJSON container.StatsResponse, now time.Time) ContainerStats {
+	stats := ContainerStats{
+		Timestamp: now,
+	}
+
+	// Calculate CPU percentage
+	cpuDelta := float64(statsJSON.CPUStats.CPUUsage.TotalUsage - statsJSON.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(statsJSON.CPUStats.SystemUsage - statsJSON.PreCPUStats.SystemUsage)
+	if systemDelta > 0 && cpuDelta > 0 {
+		cpuCount := float64(statsJSON.CPUStats.OnlineCPUs)
+		if cpuCount == 0 {
+			cpuCount = float64(len(statsJSON.CPUStats.CPUUsage.PercpuUsage))
+		}
+		if cpuCount == 0 {
+			cpuCount = 1
+		}
+		stats.CPUPercent = (cpuDelta / systemDelta) * cpuCount * 100.0
+	}
+
+	// Memory stats
+	stats.MemoryUsage = statsJSON.MemoryStats.Usage
+	stats.MemoryLimit = statsJSON.MemoryStats.Limit
+	if stats.MemoryLimit > 0 {
+		stats.MemoryPercent = float64(stats.MemoryUsage) / float64(stats.MemoryLimit) * 100.0
+	}
+
+	// Network stats
+	for _, netStats := range statsJSON.Networks {
+		stats.NetworkRx += netStats.RxBytes
+		stats.NetworkTx += netStats.TxBytes
+	}
+
+	// PIDs
+	stats.PIDs = statsJSON.PidsStats.Current
+
+	return stats
+}
+
+// StatsSnapshot samples CPU and memory of every running container with one
+// `docker stats --no-stream` call, instead of a stats stream per container.
+// The result is keyed by short (12 character) container ID.
+func StatsSnapshot(ctx context.Context) (map[string]ContainerStats, error) {
+	out, err := exec.CommandContext(ctx, "docker", "stats", "--no-stream", "--format", "{{json .}}").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample container stats: %w", err)
+	}
+	return parseStatsSnapshot(out, time.Now()), nil
+}
+
+// parseStatsSnapshot parses `docker stats --format '{{json .}}'` output,
+// skipping lines it can't read
+func parseStatsSnapshot(out []byte, now time.Time) map[string]ContainerStats {
+	percent := func(s string) float64 {
+		v, _ := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+		return v
+	}
+
+	stats := make(map[string]ContainerStats)
+	for _, line := range strings.Split(string(out), "\n") {
+		var row struct {
+			ID       string `json:"ID"`
+			CPUPerc  string `json:"CPUPerc"`
+			MemPerc  string `json:"MemPerc"`
+			MemUsage string `json:"MemUsage"` // e.g. "12.5MiB / 1.9GiB"
+		}
+		if err := json.Unmarshal([]byte(line), &row); err != nil || row.ID == "" {
+			continue
+		}
+		id := row.ID
+		if len(id) > 12 {
+			id = id[:12]
+		}
+		usage, limit, _ := strings.Cut(row.MemUsage, "/")
+		stats[id] = ContainerStats{
+			CPUPercent:    percent(row.CPUPerc),
+			MemoryPercent: percent(row.MemPerc),
+			MemoryUsage:   parseByteSize(usage),
+			MemoryLimit:   parseByteSize(limit),
+			Timestamp:     now,
+		}
+	}
+	return stats
+}
+
+// byteUnits are the suffixes docker stats prints sizes with
+var byteUnits = []struct {
+	suffix string
+	size   float64
+}{
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30}, {"TiB", 1 << 40},
+	{"kB", 1e3}, {"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12},
+	{"B", 1},
+}
+
+// parseByteSize parses a size like "12.5MiB", returning 0 if it can't
+func parseByteSize(s string) uint64 {
+	s = strings.TrimSpace(s)
+	for _, u := range byteUnits {
+		if num, ok := strings.CutSuffix(s, u.suffix); ok {
+			v, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+			if err != nil || v < 0 {
+				return 0
+			}
+			return uint64(v * u.size)
+		}
+	}
+	return 0
+}
+
+// StatsSnapshots samples the given running containers, keyed by ID: one
+// `docker stats` call when the docker CLI works, otherwise a one-off API
+// sample per container, a few at a time. It fails only if nothing could be
+// sampled.
+func (c *Client) StatsSnapshots(ctx context.Context, containerIDs []string) (map[string]ContainerStats, error) {
+	if len(containerIDs) == 0 {
+		return map[string]ContainerStats{}, nil
+	}
+	stats, err := StatsSnapshot(ctx)
+	if err == nil {
+		return stats, nil
+	}
+	logger.Debug("Batch stats failed, sampling per container: %v", err)
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		lastErr error
+	)
+	stats = make(map[string]ContainerStats)
+	sem := make(chan struct{}, 4)
+	for _, id := range containerIDs {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			s, err := c.GetStatsSnapshot(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				lastErr = err
+				return
+			}
+			stats[id] = s
+		}(id)
+	}
+	wg.Wait()
+	if len(stats) == 0 {
+		return nil, lastErr
+	}
+	return stats, nil
+}
+
 // GetTopProcesses returns running processes in a container
 func (c *Client) GetTopProcesses(ctx context.Context, containerID string) ([]ContainerProcess, error) {
 	top, err := c.cli.ContainerTop(ctx, containerID, []string{})
@@ -639,25 +1173,7 @@ func (c *Client) ComposeUp(ctx context.Context, cont Container) error {
 		return fmt.Errorf("container is not part of a compose project")
 	}
 
-	// Get compose file info from projects (cached)
-	projects := getCachedProjects()
-	var configFile, workingDir string
-	if proj, ok := projects.SavedProjects[cont.ComposeProject]; ok {
-		configFile = proj.ConfigFile
-		workingDir = proj.WorkingDir
-	}
-
-	// Build compose args
-	var baseArgs []string
-	if configFile != "" {
-		for _, f := range strings.Split(configFile, ",") {
-			f = strings.TrimSpace(f)
-			if f != "" {
-				baseArgs = append(baseArgs, "-f", f)
-			}
-		}
-	}
-	baseArgs = append(baseArgs, "-p", cont.ComposeProject)
+	baseArgs, workingDir := getComposeBaseArgs(cont)
 
 	// Run compose up for the service
 	upArgs := append(baseArgs, "up", "-d", cont.ComposeService)
@@ -674,23 +1190,7 @@ func (c *Client) ComposeDown(ctx context.Context, cont Container) error {
 		return fmt.Errorf("container is not part of a compose project")
 	}
 
-	projects := getCachedProjects()
-	var configFile, workingDir string
-	if proj, ok := projects.SavedProjects[cont.ComposeProject]; ok {
-		configFile = proj.ConfigFile
-		workingDir = proj.WorkingDir
-	}
-
-	var baseArgs []string
-	if configFile != "" {
-		for _, f := range strings.Split(configFile, ",") {
-			f = strings.TrimSpace(f)
-			if f != "" {
-				baseArgs = append(baseArgs, "-f", f)
-			}
-		}
-	}
-	baseArgs = append(baseArgs, "-p", cont.ComposeProject)
+	baseArgs, workingDir := getComposeBaseArgs(cont)
 
 	downArgs := append(baseArgs, "down", cont.ComposeService)
 	downCmd := exec.CommandContext(ctx, "docker", append([]string{"compose"}, downArgs...)...)
@@ -706,23 +1206,7 @@ func (c *Client) ComposeDownUp(ctx context.Context, cont Container) error {
 		return fmt.Errorf("container is not part of a compose project")
 	}
 
-	projects := getCachedProjects()
-	var configFile, workingDir string
-	if proj, ok := projects.SavedProjects[cont.ComposeProject]; ok {
-		configFile = proj.ConfigFile
-		workingDir = proj.WorkingDir
-	}
-
-	var baseArgs []string
-	if configFile != "" {
-		for _, f := range strings.Split(configFile, ",") {
-			f = strings.TrimSpace(f)
-			if f != "" {
-				baseArgs = append(baseArgs, "-f", f)
-			}
-		}
-	}
-	baseArgs = append(baseArgs, "-p", cont.ComposeProject)
+	baseArgs, workingDir := getComposeBaseArgs(cont)
 
 	// Run compose down for the service
 	downArgs := append(baseArgs, "down", cont.ComposeService)
@@ -747,25 +1231,7 @@ func (c *Client) ComposeBuildUp(ctx context.Context, cont Container) error {
 		return fmt.Errorf("container is not part of a compose project")
 	}
 
-	// Get compose file info from projects (cached)
-	projects := getCachedProjects()
-	var configFile, workingDir string
-	if proj, ok := projects.SavedProjects[cont.ComposeProject]; ok {
-		configFile = proj.ConfigFile
-		workingDir = proj.WorkingDir
-	}
-
-	// Build compose args
-	var baseArgs []string
-	if configFile != "" {
-		for _, f := range strings.Split(configFile, ",") {
-			f = strings.TrimSpace(f)
-			if f != "" {
-				baseArgs = append(baseArgs, "-f", f)
-			}
-		}
-	}
-	baseArgs = append(baseArgs, "-p", cont.ComposeProject)
+	baseArgs, workingDir := getComposeBaseArgs(cont)
 
 	// Run compose build --no-cache for the service
 	buildArgs := append(baseArgs, "build", "--no-cache", cont.ComposeService)
@@ -800,25 +1266,46 @@ type StreamingResult struct {
 	DoneChan <-chan struct{}
 }
 
-// getComposeBaseArgs returns the base args for compose commands
-func getComposeBaseArgs(cont Container) (baseArgs []string, workingDir string) {
-	projects := getCachedProjects()
-	var configFile string
-	if proj, ok := projects.SavedProjects[cont.ComposeProject]; ok {
-		configFile = proj.ConfigFile
-		workingDir = proj.WorkingDir
+// composeFiles returns the compose file(s) and working dir to run compose
+// commands for a container with. The container's own labels win over the
+// saved project, which may belong to another directory using the same name.
+func composeFiles(cont Container) (configFiles []string, workingDir string) {
+	if cont.ComposeWorkingDir != "" {
+		return config.SplitConfigFiles(cont.ComposeConfigFile), cont.ComposeWorkingDir
+	}
+	if proj, ok := getCachedProjects().SavedProjects[cont.ComposeProject]; ok {
+		return proj.ConfigFiles, proj.WorkingDir
 	}
+	return nil, ""
+}
 
-	if configFile != "" {
-		for _, f := range strings.Split(configFile, ",") {
-			f = strings.TrimSpace(f)
-			if f != "" {
-				baseArgs = append(baseArgs, "-f", f)
-			}
-		}
+// composeFileArgs returns a -f flag for each compose file
+func composeFileArgs(configFiles []string) []string {
+	var args []string
+	for _, f := range configFiles {
+		args = append(args, "-f", f)
+	}
+	return args
+}
+
+// composeEnvFile returns the env file saved for a container's project, unless
+// the saved project is another directory using the same name
+func composeEnvFile(cont Container) string {
+	proj, ok := getCachedProjects().SavedProjects[cont.ComposeProject]
+	if !ok || (cont.ComposeWorkingDir != "" && proj.WorkingDir != cont.ComposeWorkingDir) {
+		return ""
+	}
+	return proj.EnvFile
+}
+
+// getComposeBaseArgs returns the base args for compose commands
+func getComposeBaseArgs(cont Container) (baseArgs []string, workingDir string) {
+	configFiles, workingDir := composeFiles(cont)
+	baseArgs = composeFileArgs(configFiles)
+	if envFile := composeEnvFile(cont); envFile != "" {
+		baseArgs = append(baseArgs, "--env-file", envFile)
 	}
-	baseArgs = append(baseArgs, "-p", cont.ComposeProject)
-	return baseArgs, workingDir
+	return append(baseArgs, "-p", cont.ComposeProject), workingDir
 }
 
 // runStreamingCommand executes a command and streams output to channels
@@ -1017,6 +1504,59 @@ func (c *Client) ComposeDownStream(ctx context.Context, cont Container) Streamin
 	return runStreamingCommand(ctx, cmd)
 }
 
+// ComposeLogsStream runs docker compose logs -f for the container's whole
+// project, giving compose's own interleaved output with service prefixes
+func (c *Client) ComposeLogsStream(ctx context.Context, cont Container) StreamingResult {
+	if cont.ComposeProject == "" {
+		errChan := make(chan error, 1)
+		logChan := make(chan OperationLog)
+		doneChan := make(chan struct{})
+		errChan <- fmt.Errorf("container is not part of a compose project")
+		close(errChan)
+		close(logChan)
+		close(doneChan)
+		return StreamingResult{LogChan: logChan, ErrChan: errChan, DoneChan: doneChan}
+	}
+
+	baseArgs, workingDir := getComposeBaseArgs(cont)
+	// Same history as a running container's own pane starts with
+	tail := strconv.Itoa(defaultLogStreamOptions(true).Tail)
+	logsArgs := append(baseArgs, "logs", "--follow", "--tail", tail)
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"compose"}, logsArgs...)...)
+	if workingDir != "" {
+		cmd.Dir = workingDir
+	}
+
+	return runStreamingCommand(ctx, cmd)
+}
+
+// StreamComposeLogs is ComposeLogsStream with its output as log lines, so a
+// log view pane can show it like a container's own logs
+func (c *Client) StreamComposeLogs(ctx context.Context, cont Container) (<-chan LogLine, <-chan error) {
+	result := c.ComposeLogsStream(ctx, cont)
+	logChan := make(chan LogLine, 100)
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer close(logChan)
+		defer close(errChan)
+
+		for op := range result.LogChan {
+			select {
+			case <-ctx.Done():
+				return
+			case logChan <- LogLine{ContainerID: cont.ID, Timestamp: op.Timestamp, Stream: op.Stream, Content: op.Content}:
+			}
+		}
+		// Cancelling the stream kills compose; that isn't an error
+		if err, ok := <-result.ErrChan; ok && err != nil && ctx.Err() == nil {
+			errChan <- err
+		}
+	}()
+
+	return logChan, errChan
+}
+
 // ComposeBuildUpStreamMulti runs docker compose build --no-cache then up -d for multiple services with streaming output
 func (c *Client) ComposeBuildUpStreamMulti(ctx context.Context, containers []Container) StreamingResult {
 	if len(containers) == 0 {
@@ -1074,6 +1614,15 @@ func (c *Client) ComposeBuildUpStreamMulti(ctx context.Context, containers []Con
 		defer close(doneChan)
 
 		baseArgs, workingDir := getComposeBaseArgs(containers[0])
+		// Note each service's image before the build
+		var images []imageBuild
+		seen := make(map[string]bool)
+		for _, cont := range containers {
+			if cont.ComposeService != "" && !seen[cont.ComposeService] {
+				seen[cont.ComposeService] = true
+				images = append(images, c.startImageBuild(ctx, cont, baseArgs, workingDir))
+			}
+		}
 
 		// Phase 1: Build all services
 		logChan <- OperationLog{
@@ -1097,6 +1646,7 @@ func (c *Client) ComposeBuildUpStreamMulti(ctx context.Context, containers []Con
 		for {
 			select {
 			case <-ctx.Done():
+				c.buildCancelled(ctx, project, images, logChan, errChan)
 				return
 			case log, ok := <-buildResult.LogChan:
 				if !ok {
@@ -1119,10 +1669,15 @@ func (c *Client) ComposeBuildUpStreamMulti(ctx context.Context, containers []Con
 				Stream:    "stderr",
 				Content:   fmt.Sprintf("--- Build failed: %v ---", buildErr),
 			}
-			errChan <- buildErr
+			errChan <- c.buildFailure(ctx, project, images, logChan, buildErr)
 			return
 		}
 
+		for _, image := range images {
+			if change, ok := c.imageChange(ctx, image); ok {
+				logChan <- OperationLog{Timestamp: time.Now(), Stream: "system", Content: change}
+			}
+		}
 		logChan <- OperationLog{
 			Timestamp: time.Now(),
 			Stream:    "system",
@@ -1145,6 +1700,7 @@ func (c *Client) ComposeBuildUpStreamMulti(ctx context.Context, containers []Con
 		for {
 			select {
 			case <-ctx.Done():
+				c.buildCancelled(ctx, project, images, logChan, errChan)
 				return
 			case log, ok := <-upResult.LogChan:
 				if !ok {
@@ -1167,7 +1723,7 @@ func (c *Client) ComposeBuildUpStreamMulti(ctx context.Context, containers []Con
 				Stream:    "stderr",
 				Content:   fmt.Sprintf("--- Up failed: %v ---", upErr),
 			}
-			errChan <- upErr
+			errChan <- c.buildFailure(ctx, project, images, logChan, upErr)
 			return
 		}
 
@@ -1185,6 +1741,226 @@ func (c *Client) ComposeBuildUpStreamMulti(ctx context.Context, containers []Con
 	}
 }
 
+// imageSnapshot is an image's ID and size at one point in time
+type imageSnapshot struct {
+	id   string
+	size int64
+}
+
+// imageBuild is the image of a service about to be built, as it was before
+type imageBuild struct {
+	service string
+	image   string
+	before  imageSnapshot
+	existed bool
+}
+
+// startImageBuild notes a service's image before it's built
+func (c *Client) startImageBuild(ctx context.Context, cont Container, baseArgs []string, workingDir string) imageBuild {
+	image := c.composeServiceImage(ctx, cont, baseArgs, workingDir)
+	before, existed := c.inspectImageSnapshot(ctx, image)
+	return imageBuild{service: cont.ComposeService, image: image, before: before, existed: existed}
+}
+
+// imageChange describes what the build did to the image, for the build log.
+// It returns false if the image can't be found after the build.
+func (c *Client) imageChange(ctx context.Context, b imageBuild) (string, bool) {
+	after, ok := c.inspectImageSnapshot(ctx, b.image)
+	if !ok {
+		return "", false
+	}
+	return describeImageChange(b, after), true
+}
+
+// composeServiceImage returns the image a compose service builds, as compose
+// resolves it, falling back to compose's default <project>-<service> name
+func (c *Client) composeServiceImage(ctx context.Context, cont Container, baseArgs []string, workingDir string) string {
+	cmdCtx, cancel := context.WithTimeout(ctx, composeCmdTimeout)
+	defer cancel()
+	args := append(append([]string{"compose"}, baseArgs...), "config", "--images", cont.ComposeService)
+	cmd := exec.CommandContext(cmdCtx, "docker", args...)
+	if workingDir != "" {
+		cmd.Dir = workingDir
+	}
+	if out, err := cmd.Output(); err == nil {
+		if image := strings.TrimSpace(string(out)); image != "" && !strings.Contains(image, "\n") {
+			return image
+		}
+	}
+	return cont.ComposeProject + "-" + cont.ComposeService
+}
+
+// inspectImageSnapshot returns the ID and size of an image, false if there's
+// no such image
+func (c *Client) inspectImageSnapshot(ctx context.Context, image string) (imageSnapshot, bool) {
+	if image == "" {
+		return imageSnapshot{}, false
+	}
+	info, err := c.cli.ImageInspect(ctx, image)
+	if err != nil {
+		return imageSnapshot{}, false
+	}
+	return imageSnapshot{id: info.ID, size: info.Size}, true
+}
+
+// describeImageChange compares an image after a build with before it
+func describeImageChange(b imageBuild, after imageSnapshot) string {
+	switch {
+	case !b.existed:
+		return fmt.Sprintf("--- Image %s built: %s, %s ---", b.image, shortImageID(after.id), formatImageSize(after.size))
+	case b.before.id == after.id:
+		return fmt.Sprintf("--- Image %s unchanged (%s) ---", b.image, shortImageID(after.id))
+	default:
+		return fmt.Sprintf("--- Image %s changed: %s → %s, %s (%+.1f MB) ---", b.image, shortImageID(b.before.id), shortImageID(after.id),
+			formatImageSize(after.size), float64(after.size-b.before.size)/1e6)
+	}
+}
+
+// shortImageID returns the first 12 hex digits of an image ID, like docker images
+func shortImageID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return id
+}
+
+// formatImageSize formats an image size in MB, as docker images does
+func formatImageSize(size int64) string {
+	return fmt.Sprintf("%.1f MB", float64(size)/1e6)
+}
+
+// ServiceState is the state a compose service was left in by a failed build
+type ServiceState struct {
+	Service  string
+	State    string // the container's state, "" when it has none
+	Previous bool   // the container still runs the image from before the build
+}
+
+// String describes the state for the build log and notifications
+func (s ServiceState) String() string {
+	switch {
+	case s.State == "":
+		return s.Service + " has no container"
+	case s.State == "running" && s.Previous:
+		return s.Service + " still running the previous version"
+	case s.State == "running":
+		return s.Service + " running"
+	default:
+		return fmt.Sprintf("%s down (%s)", s.Service, s.State)
+	}
+}
+
+// BuildFailedError is a failed build or up, with the state its services were
+// left in so the user knows whether they're down
+type BuildFailedError struct {
+	Err      error
+	Services []ServiceState
+}
+
+func (e *BuildFailedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BuildFailedError) Unwrap() error {
+	return e.Err
+}
+
+// Report is the error followed by the services' states, for toasts and
+// notifications
+func (e *BuildFailedError) Report() string {
+	if len(e.Services) == 0 {
+		return e.Error()
+	}
+	return e.Error() + ": " + e.Summary()
+}
+
+// Summary describes the services' states, like "api still running the
+// previous version, worker down (exited)"
+func (e *BuildFailedError) Summary() string {
+	states := make([]string, len(e.Services))
+	for i, s := range e.Services {
+		states[i] = s.String()
+	}
+	return strings.Join(states, ", ")
+}
+
+// buildFailure re-checks the services of a failed build, logs the state each
+// was left in and returns err with those states
+func (c *Client) buildFailure(ctx context.Context, project string, images []imageBuild, logChan chan<- OperationLog, err error) error {
+	failed := c.serviceStates(ctx, project, images, err)
+	for _, state := range failed.Services {
+		logChan <- stateLog(state)
+	}
+	return failed
+}
+
+// buildCancelled reports the state the services were left in when a build
+// is cancelled part way, e.g. by leaving the log view. Nothing may be reading
+// the stream anymore, so the debug log has it too and the sends don't block.
+func (c *Client) buildCancelled(ctx context.Context, project string, images []imageBuild, logChan chan<- OperationLog, errChan chan<- error) {
+	failed := c.serviceStates(context.WithoutCancel(ctx), project, images, ctx.Err())
+	logger.Warn("Build of %s cancelled: %s", project, failed.Summary())
+	for _, state := range failed.Services {
+		select {
+		case logChan <- stateLog(state):
+		default:
+		}
+	}
+	select {
+	case errChan <- failed:
+	default:
+	}
+}
+
+// serviceStates re-checks the services of a build that didn't complete and
+// returns err with the state each was left in
+func (c *Client) serviceStates(ctx context.Context, project string, images []imageBuild, err error) *BuildFailedError {
+	failed := &BuildFailedError{Err: err}
+	for _, image := range images {
+		failed.Services = append(failed.Services, c.serviceState(ctx, project, image))
+	}
+	return failed
+}
+
+// stateLog is the build log line for the state a service was left in
+func stateLog(state ServiceState) OperationLog {
+	stream := "system"
+	if state.State != "running" {
+		stream = "stderr"
+	}
+	return OperationLog{Timestamp: time.Now(), Stream: stream, Content: "--- " + state.String() + " ---"}
+}
+
+// serviceState looks up the container of a service after a failed build,
+// preferring a running one when the service has replicas
+func (c *Client) serviceState(ctx context.Context, project string, b imageBuild) ServiceState {
+	state := ServiceState{Service: b.service}
+	listCtx, cancel := context.WithTimeout(ctx, composeCmdTimeout)
+	defer cancel()
+	containers, err := c.cli.ContainerList(listCtx, container.ListOptions{
+		All: true,
+		Filters: filters.NewArgs(
+			filters.Arg("label", "com.docker.compose.project="+project),
+			filters.Arg("label", "com.docker.compose.service="+b.service),
+		),
+	})
+	if err != nil {
+		logger.Warn("Failed to check %s after the failed build: %v", b.service, err)
+		return state
+	}
+	for _, cont := range containers {
+		if state.State == "" || cont.State == "running" {
+			state.State = cont.State
+			state.Previous = b.existed && cont.ImageID == b.before.id
+		}
+		if cont.State == "running" {
+			break
+		}
+	}
+	return state
+}
+
 // ComposeBuildUpStream runs docker compose build --no-cache then up -d with streaming output
 func (c *Client) ComposeBuildUpStream(ctx context.Context, cont Container) StreamingResult {
 	if cont.ComposeProject == "" || cont.ComposeService == "" {
@@ -1208,6 +1984,7 @@ func (c *Client) ComposeBuildUpStream(ctx context.Context, cont Container) Strea
 		defer close(doneChan)
 
 		baseArgs, workingDir := getComposeBaseArgs(cont)
+		image := c.startImageBuild(ctx, cont, baseArgs, workingDir)
 
 		// Phase 1: Build
 		logChan <- OperationLog{
@@ -1230,6 +2007,7 @@ func (c *Client) ComposeBuildUpStream(ctx context.Context, cont Container) Strea
 		for {
 			select {
 			case <-ctx.Done():
+				c.buildCancelled(ctx, cont.ComposeProject, []imageBuild{image}, logChan, errChan)
 				return
 			case log, ok := <-buildResult.LogChan:
 				if !ok {
@@ -1252,10 +2030,13 @@ func (c *Client) ComposeBuildUpStream(ctx context.Context, cont Container) Strea
 				Stream:    "stderr",
 				Content:   fmt.Sprintf("--- Build failed: %v ---", buildErr),
 			}
-			errChan <- buildErr
+			errChan <- c.buildFailure(ctx, cont.ComposeProject, []imageBuild{image}, logChan, buildErr)
 			return
 		}
 
+		if change, ok := c.imageChange(ctx, image); ok {
+			logChan <- OperationLog{Timestamp: time.Now(), Stream: "system", Content: change}
+		}
 		logChan <- OperationLog{
 			Timestamp: time.Now(),
 			Stream:    "system",
@@ -1277,6 +2058,7 @@ func (c *Client) ComposeBuildUpStream(ctx context.Context, cont Container) Strea
 		for {
 			select {
 			case <-ctx.Done():
+				c.buildCancelled(ctx, cont.ComposeProject, []imageBuild{image}, logChan, errChan)
 				return
 			case log, ok := <-upResult.LogChan:
 				if !ok {
@@ -1299,7 +2081,7 @@ func (c *Client) ComposeBuildUpStream(ctx context.Context, cont Container) Strea
 				Stream:    "stderr",
 				Content:   fmt.Sprintf("--- Up failed: %v ---", upErr),
 			}
-			errChan <- upErr
+			errChan <- c.buildFailure(ctx, cont.ComposeProject, []imageBuild{image}, logChan, upErr)
 			return
 		}
 
@@ -1316,3 +2098,123 @@ func (c *Client) ComposeBuildUpStream(ctx context.Context, cont Container) Strea
 		DoneChan: doneChan,
 	}
 }
+
+// errorStream returns a StreamingResult that fails immediately with err
+func errorStream(err error) StreamingResult {
+	errChan := make(chan error, 1)
+	logChan := make(chan OperationLog)
+	doneChan := make(chan struct{})
+	errChan <- err
+	close(errChan)
+	close(logChan)
+	close(doneChan)
+	return StreamingResult{LogChan: logChan, ErrChan: errChan, DoneChan: doneChan}
+}
+
+// imageBuildArgs returns the docker build arguments that rebuild a standalone
+// container's image from a build context, tagged with the container's image name
+func imageBuildArgs(cont Container, bc config.BuildContext) ([]string, error) {
+	if bc.Context == "" {
+		return nil, fmt.Errorf("no build context set")
+	}
+	if cont.Image == "" || strings.HasPrefix(cont.Image, "sha256:") {
+		return nil, fmt.Errorf("container image has no tag to build")
+	}
+
+	args := []string{"build", "--progress=plain", "-t", cont.Image}
+	if bc.Dockerfile != "" {
+		args = append(args, "-f", bc.Dockerfile)
+	}
+	return append(args, bc.Context), nil
+}
+
+// ImageBuildRestartStream runs docker build for a standalone container's image
+// and then restarts the container, with streaming output. Restarting keeps the
+// container's existing image, so this suits containers that load code from the
+// build context; a container created from the old image must be recreated to run
+// the new one.
+func (c *Client) ImageBuildRestartStream(ctx context.Context, cont Container, bc config.BuildContext) StreamingResult {
+	buildArgs, err := imageBuildArgs(cont, bc)
+	if err != nil {
+		return errorStream(err)
+	}
+
+	logChan := make(chan OperationLog, 100)
+	errChan := make(chan error, 1)
+	doneChan := make(chan struct{})
+
+	go func() {
+		defer close(logChan)
+		defer close(errChan)
+		defer close(doneChan)
+
+		logChan <- OperationLog{
+			Timestamp: time.Now(),
+			Stream:    "system",
+			Content:   fmt.Sprintf("--- Building %s from %s ---", cont.Image, bc.Context),
+		}
+
+		buildCmd := exec.CommandContext(ctx, "docker", buildArgs...)
+		buildResult := runStreamingCommand(ctx, buildCmd)
+
+		// Forward build logs
+		var buildErr error
+	buildLoop:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case log, ok := <-buildResult.LogChan:
+				if !ok {
+					break buildLoop
+				}
+				logChan <- log
+			case err := <-buildResult.ErrChan:
+				if err != nil {
+					buildErr = err
+				}
+			}
+		}
+
+		// Wait for build done
+		<-buildResult.DoneChan
+
+		if buildErr != nil {
+			logChan <- OperationLog{
+				Timestamp: time.Now(),
+				Stream:    "stderr",
+				Content:   fmt.Sprintf("--- Build failed: %v ---", buildErr),
+			}
+			errChan <- buildErr
+			return
+		}
+
+		logChan <- OperationLog{
+			Timestamp: time.Now(),
+			Stream:    "system",
+			Content:   fmt.Sprintf("--- Build complete, recreating container from %s ---", cont.Image),
+		}
+
+		if _, err := c.RecreateContainer(ctx, cont.ID, cont.Image); err != nil {
+			logChan <- OperationLog{
+				Timestamp: time.Now(),
+				Stream:    "stderr",
+				Content:   fmt.Sprintf("--- Recreate failed: %v ---", err),
+			}
+			errChan <- err
+			return
+		}
+
+		logChan <- OperationLog{
+			Timestamp: time.Now(),
+			Stream:    "system",
+			Content:   "--- Container recreated from the new image ---",
+		}
+	}()
+
+	return StreamingResult{
+		LogChan:  logChan,
+		ErrChan:  errChan,
+		DoneChan: doneChan,
+	}
+}
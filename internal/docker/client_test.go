@@ -0,0 +1,340 @@
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"cm/internal/config"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+)
+
+func TestImageBuildArgs(t *testing.T) {
+	cont := Container{Name: "web", Image: "myapp:dev"}
+
+	args, err := imageBuildArgs(cont, config.BuildContext{Context: "./app", Dockerfile: "./app/Dockerfile.dev"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(args, " "); got != "build --progress=plain -t myapp:dev -f ./app/Dockerfile.dev ./app" {
+		t.Fatalf("unexpected args: %q", got)
+	}
+
+	args, _ = imageBuildArgs(cont, config.BuildContext{Context: "."})
+	if got := strings.Join(args, " "); got != "build --progress=plain -t myapp:dev ." {
+		t.Fatalf("expected default Dockerfile to be left to docker, got %q", got)
+	}
+
+	if _, err := imageBuildArgs(cont, config.BuildContext{}); err == nil {
+		t.Fatalf("expected an error without a build context")
+	}
+	if _, err := imageBuildArgs(Container{Image: "sha256:abc"}, config.BuildContext{Context: "."}); err == nil {
+		t.Fatalf("expected an error for an untagged image")
+	}
+}
+
+func TestCloseSavesDirtyConfigAndProjects(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://127.0.0.1:1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	configCacheLock.Lock()
+	configCache = &config.Config{BuildContexts: map[string]config.BuildContext{"web": {Context: "./web"}}}
+	configDirty = true
+	configCacheLock.Unlock()
+	projectsCacheLock.Lock()
+	projectsCache = &config.Projects{SavedProjects: map[string]config.SavedProject{"shop": {WorkingDir: "/src/shop"}}}
+	projectsDirty = true
+	projectsCacheLock.Unlock()
+	t.Cleanup(func() {
+		configCache, projectsCache = nil, nil
+	})
+
+	if err := (&Client{cli: cli}).Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bc, ok := cfg.GetBuildContext("web"); !ok || bc.Context != "./web" {
+		t.Fatalf("expected dirty config to be saved on close, got %+v", bc)
+	}
+	if p, ok := config.LoadProjects().SavedProjects["shop"]; !ok || p.WorkingDir != "/src/shop" {
+		t.Fatalf("expected dirty projects to be saved on close")
+	}
+	if configDirty || projectsDirty {
+		t.Fatalf("expected dirty flags to be cleared")
+	}
+}
+
+func TestUpdateProjectKeepsSavedView(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	projectsCacheLock.Lock()
+	projectsCache = &config.Projects{SavedProjects: map[string]config.SavedProject{
+		"shop": {WorkingDir: "/src/shop", View: &config.SavedView{Services: []string{"api"}}},
+	}}
+	projectsCacheLock.Unlock()
+	t.Cleanup(func() { projectsCache = nil })
+
+	updateProject("shop", []string{"compose.yaml"}, "/src/shop-moved")
+
+	p := config.LoadProjects().SavedProjects["shop"]
+	if p.WorkingDir != "/src/shop-moved" || p.View == nil || p.View.Services[0] != "api" {
+		t.Fatalf("expected the project to be updated without losing its view, got %+v", p)
+	}
+}
+
+func TestComposeBaseArgsPassSavedEnvFile(t *testing.T) {
+	projectsCacheLock.Lock()
+	projectsCache = &config.Projects{SavedProjects: map[string]config.SavedProject{
+		"shop": {ConfigFiles: []string{"/src/shop/compose.yaml"}, WorkingDir: "/src/shop", EnvFile: "/src/shop/dev.env"},
+	}}
+	projectsCacheTime = time.Now()
+	projectsCacheLock.Unlock()
+	t.Cleanup(func() { projectsCache = nil })
+
+	args, dir := getComposeBaseArgs(Container{ComposeProject: "shop"})
+	want := []string{"-f", "/src/shop/compose.yaml", "--env-file", "/src/shop/dev.env", "-p", "shop"}
+	if !slices.Equal(args, want) || dir != "/src/shop" {
+		t.Fatalf("got %q in %q, want %q", args, dir, want)
+	}
+
+	// The env file isn't used for a same-named project in another directory
+	other := Container{ComposeProject: "shop", ComposeConfigFile: "/tmp/shop/compose.yaml", ComposeWorkingDir: "/tmp/shop"}
+	if args, _ := getComposeBaseArgs(other); slices.Contains(args, "--env-file") {
+		t.Fatalf("expected no env file for another directory, got %q", args)
+	}
+}
+
+func TestComposeServicesArgsPassEnvFile(t *testing.T) {
+	args := composeServicesArgs("shop", []string{"/src/shop/compose.yaml"}, "/src/shop/dev.env")
+	want := []string{"-f", "/src/shop/compose.yaml", "--env-file", "/src/shop/dev.env", "-p", "shop", "config", "--services"}
+	if !slices.Equal(args, want) {
+		t.Fatalf("got %q, want %q", args, want)
+	}
+	if args := composeServicesArgs("shop", nil, ""); slices.Contains(args, "--env-file") {
+		t.Fatalf("expected no env file flag without one, got %q", args)
+	}
+}
+
+func TestResolveComposeProject(t *testing.T) {
+	dir := t.TempDir()
+	named := filepath.Join(dir, "compose.yaml")
+	os.WriteFile(named, []byte("name: shop\nservices:\n  api:\n    image: nginx\n"), 0644)
+
+	name, files, workingDir, err := ResolveComposeProject(dir)
+	if err != nil || name != "shop" || !slices.Equal(files, []string{named}) || workingDir != dir {
+		t.Fatalf("expected shop from %s, got %q %q %q %v", named, name, files, workingDir, err)
+	}
+
+	notCompose := filepath.Join(dir, "values.yaml")
+	os.WriteFile(notCompose, []byte("replicas: 3\n"), 0644)
+	if _, _, _, err := ResolveComposeProject(notCompose); err == nil {
+		t.Fatalf("expected a YAML file without services to be rejected")
+	}
+	if _, _, _, err := ResolveComposeProject(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Fatalf("expected a missing file to be rejected")
+	}
+	if _, _, _, err := ResolveComposeProject(t.TempDir()); err == nil {
+		t.Fatalf("expected a directory without a compose file to be rejected")
+	}
+}
+
+func TestFindComposeFilesAddsTheOverrideFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"docker-compose.yml", "compose.yml", "compose.override.yml", "docker-compose.override.yaml"} {
+		os.WriteFile(filepath.Join(dir, f), []byte("services: {}\n"), 0644)
+	}
+
+	// compose.yml wins over docker-compose.yml, and the first override is merged over it
+	want := []string{filepath.Join(dir, "compose.yml"), filepath.Join(dir, "compose.override.yml")}
+	if got := findComposeFiles(dir); !slices.Equal(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	os.Remove(filepath.Join(dir, "compose.override.yml"))
+	os.Remove(filepath.Join(dir, "docker-compose.override.yaml"))
+	if got := findComposeFiles(dir); !slices.Equal(got, want[:1]) {
+		t.Fatalf("expected just the default file without an override, got %q", got)
+	}
+	if got := findComposeFiles(t.TempDir()); got != nil {
+		t.Fatalf("expected no files for an empty dir, got %q", got)
+	}
+}
+
+func TestParseSignal(t *testing.T) {
+	for in, want := range map[string]string{"hup": "SIGHUP", "SIGUSR1": "SIGUSR1", " term ": "SIGTERM", "9": "9"} {
+		if got, err := ParseSignal(in); err != nil || got != want {
+			t.Fatalf("ParseSignal(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "SIGFOO", "0", "65", "kill -9"} {
+		if _, err := ParseSignal(in); err == nil {
+			t.Fatalf("expected ParseSignal(%q) to fail", in)
+		}
+	}
+}
+
+func TestParseStatsSnapshot(t *testing.T) {
+	out := []byte(`{"BlockIO":"0B / 0B","CPUPerc":"12.50%","Container":"abc","ID":"0123456789abcdef","MemPerc":"3.20%","MemUsage":"64MiB / 2GiB","Name":"web"}
+{"CPUPerc":"--","ID":"fedcba987654","MemPerc":"--","Name":"starting"}
+not json
+`)
+	now := time.Unix(1700000000, 0)
+	stats := parseStatsSnapshot(out, now)
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 containers, got %d", len(stats))
+	}
+	web := stats["0123456789ab"]
+	if web.CPUPercent != 12.5 || web.MemoryPercent != 3.2 || web.MemoryUsage != 64<<20 || web.MemoryLimit != 2<<30 || !web.Timestamp.Equal(now) {
+		t.Fatalf("unexpected stats for web: %+v", web)
+	}
+	if s := stats["fedcba987654"]; s.CPUPercent != 0 || s.MemoryPercent != 0 {
+		t.Fatalf("expected unreadable percentages to be zero, got %+v", s)
+	}
+}
+
+func TestStatsFromResponse(t *testing.T) {
+	var resp container.StatsResponse
+	resp.PreCPUStats.CPUUsage.TotalUsage = 1000
+	resp.PreCPUStats.SystemUsage = 10000
+	resp.CPUStats.CPUUsage.TotalUsage = 1500
+	resp.CPUStats.SystemUsage = 20000
+	resp.CPUStats.OnlineCPUs = 2
+	resp.MemoryStats.Usage = 256
+	resp.MemoryStats.Limit = 1024
+	resp.Networks = map[string]container.NetworkStats{"eth0": {RxBytes: 10, TxBytes: 20}, "eth1": {RxBytes: 1, TxBytes: 2}}
+	resp.PidsStats.Current = 7
+
+	stats := statsFromResponse(resp, time.Unix(1700000000, 0))
+	if stats.CPUPercent != 10 {
+		t.Fatalf("expected 10%% CPU across 2 CPUs, got %v", stats.CPUPercent)
+	}
+	if stats.MemoryPercent != 25 || stats.NetworkRx != 11 || stats.NetworkTx != 22 || stats.PIDs != 7 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	// The first sample of a stream has no previous CPU reading
+	if s := statsFromResponse(container.StatsResponse{}, time.Now()); s.CPUPercent != 0 || s.MemoryPercent != 0 {
+		t.Fatalf("expected an empty sample to give zero usage, got %+v", s)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	cases := map[string]uint64{
+		"0B":        0,
+		"512B":      512,
+		"1.5KiB":    1536,
+		" 64MiB ":   64 << 20,
+		"2GiB":      2 << 30,
+		"1.2kB":     1200,
+		"3MB":       3000000,
+		"--":        0,
+		"12 apples": 0,
+	}
+	for in, want := range cases {
+		if got := parseByteSize(in); got != want {
+			t.Fatalf("parseByteSize(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestDescribeImageChange(t *testing.T) {
+	before := imageSnapshot{id: "sha256:aaaaaaaaaaaa1111", size: 120_000_000}
+	for _, tc := range []struct {
+		build imageBuild
+		after imageSnapshot
+		want  string
+	}{
+		{imageBuild{image: "shop-api"}, before, "--- Image shop-api built: aaaaaaaaaaaa, 120.0 MB ---"},
+		{imageBuild{image: "shop-api", before: before, existed: true}, before, "--- Image shop-api unchanged (aaaaaaaaaaaa) ---"},
+		{imageBuild{image: "shop-api", before: before, existed: true}, imageSnapshot{id: "sha256:bbbbbbbbbbbb2222", size: 122_500_000},
+			"--- Image shop-api changed: aaaaaaaaaaaa → bbbbbbbbbbbb, 122.5 MB (+2.5 MB) ---"},
+	} {
+		if got := describeImageChange(tc.build, tc.after); got != tc.want {
+			t.Fatalf("got %q, want %q", got, tc.want)
+		}
+	}
+}
+
+func TestBuildFailedErrorSummarizesServiceStates(t *testing.T) {
+	err := fmt.Errorf("compose build: %w", &BuildFailedError{
+		Err: errors.New("exit status 1"),
+		Services: []ServiceState{
+			{Service: "api", State: "running", Previous: true},
+			{Service: "worker", State: "exited"},
+			{Service: "web", State: "running"},
+			{Service: "jobs"},
+		},
+	})
+
+	var failed *BuildFailedError
+	if !errors.As(err, &failed) {
+		t.Fatalf("expected a BuildFailedError in %v", err)
+	}
+	want := "api still running the previous version, worker down (exited), web running, jobs has no container"
+	if got := failed.Summary(); got != want {
+		t.Fatalf("Summary() = %q, want %q", got, want)
+	}
+	if failed.Error() != "exit status 1" {
+		t.Fatalf("expected the build's own error, got %q", failed.Error())
+	}
+	if got := failed.Report(); got != "exit status 1: "+want {
+		t.Fatalf("Report() = %q, want the error then the states", got)
+	}
+}
+
+func TestRecreateConfigLeavesOldImageDefaultsToTheNewImage(t *testing.T) {
+	var old image.InspectResponse
+	if err := json.Unmarshal([]byte(`{"Config": {"Cmd": ["node", "server.js"], "WorkingDir": "/app",
+		"Env": ["PATH=/usr/bin", "NODE_VERSION=20"], "Labels": {"maintainer": "me"}}}`), &old); err != nil {
+		t.Fatal(err)
+	}
+	cfg := container.Config{
+		Hostname:   "0123456789ab",
+		Image:      "myapp:dev",
+		Cmd:        []string{"node", "server.js"},
+		WorkingDir: "/app",
+		User:       "node",
+		Env:        []string{"PATH=/usr/bin", "NODE_VERSION=20", "PORT=8080"},
+		Labels:     map[string]string{"maintainer": "me", "team": "web"},
+	}
+
+	got := recreateConfig(cfg, old, "myapp:dev", "0123456789abcdef")
+	if got.Image != "myapp:dev" || got.Hostname != "" || got.Cmd != nil || got.WorkingDir != "" {
+		t.Fatalf("expected the image's defaults and the default hostname dropped, got %+v", got)
+	}
+	if got.User != "node" || !slices.Equal(got.Env, []string{"PORT=8080"}) || len(got.Labels) != 1 || got.Labels["team"] != "web" {
+		t.Fatalf("expected the container's own settings kept, got %+v", got)
+	}
+}
+
+func TestFinishedAtCacheDropsContainersNoLongerListed(t *testing.T) {
+	finishedAtCacheLock.Lock()
+	finishedAtCache["kept"] = time.Now()
+	finishedAtCache["removed"] = time.Now()
+	finishedAtCacheLock.Unlock()
+	t.Cleanup(func() { pruneFinishedAtCache(nil) })
+
+	pruneFinishedAtCache(map[string]bool{"kept": true, "other": true})
+	finishedAtCacheLock.Lock()
+	defer finishedAtCacheLock.Unlock()
+	if _, ok := finishedAtCache["removed"]; ok || len(finishedAtCache) != 1 {
+		t.Fatalf("expected only the listed container's finish time kept, got %v", finishedAtCache)
+	}
+}
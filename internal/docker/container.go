@@ -1,6 +1,7 @@
 package docker
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -16,21 +17,52 @@ const (
 	LabelComposeService    = "com.docker.compose.service"
 	LabelComposeConfigFile = "com.docker.compose.project.config_files"
 	LabelComposeWorkingDir = "com.docker.compose.project.working_dir"
+	LabelComposeReplica    = "com.docker.compose.container-number"
 )
 
 // Container represents a Docker container with compose metadata
 type Container struct {
-	ID             string
-	Name           string
-	Status         string
-	State          string
-	ComposeProject string
-	ComposeService string
-	Image          string
-	Created        time.Time
+	ID                string
+	Name              string
+	Status            string
+	State             string
+	ComposeProject    string
+	ComposeService    string
+	ComposeConfigFile string // comma-joined, from the container's labels; empty for stopped services
+	ComposeWorkingDir string
+	ComposeReplica    int // replica number of a scaled service, 0 when unknown
+	Image             string
+	Created           time.Time
 }
 
-// DisplayName returns the best name to display for the container
+// composeLogsPrefix marks the ID of a pseudo-container whose logs come from
+// docker compose logs for a whole project. It's longer than a short container
+// ID so code that truncates IDs to 12 characters keeps working.
+const composeLogsPrefix = "compose-logs:"
+
+// ComposeLogsContainer returns a pseudo-container standing for every service
+// of cont's compose project, streamed with docker compose logs -f
+func ComposeLogsContainer(cont Container) Container {
+	return Container{
+		ID:                composeLogsPrefix + cont.ComposeProject,
+		Name:              cont.ComposeProject,
+		Status:            "docker compose logs",
+		State:             "running",
+		ComposeProject:    cont.ComposeProject,
+		ComposeConfigFile: cont.ComposeConfigFile,
+		ComposeWorkingDir: cont.ComposeWorkingDir,
+		Image:             "docker compose logs",
+		Created:           time.Now(),
+	}
+}
+
+// IsComposeLogs reports whether c is a pseudo-container from ComposeLogsContainer
+func (c Container) IsComposeLogs() bool {
+	return strings.HasPrefix(c.ID, composeLogsPrefix)
+}
+
+// DisplayName returns the short name to display for the container: the
+// compose service name, or the container name for standalone containers
 func (c Container) DisplayName() string {
 	if c.ComposeService != "" {
 		return c.ComposeService
@@ -38,7 +70,27 @@ func (c Container) DisplayName() string {
 	return c.Name
 }
 
-// ContainerGroup groups containers by compose project
+// QualifiedName returns "project/service" for compose containers, for places
+// where services with the same name in different projects must be told apart
+func (c Container) QualifiedName() string {
+	if c.ComposeProject != "" && c.ComposeService != "" {
+		return c.ComposeProject + "/" + c.ComposeService
+	}
+	return c.DisplayName()
+}
+
+// MatchesName reports whether the container's service or container name
+// matches, or contains, name. name must be lowercase; this is how cm matches
+// the container names given on its command line.
+func (c Container) MatchesName(name string) bool {
+	return strings.ToLower(c.ComposeService) == name ||
+		strings.ToLower(c.Name) == name ||
+		strings.Contains(strings.ToLower(c.Name), name) ||
+		strings.Contains(strings.ToLower(c.ComposeService), name)
+}
+
+// ContainerGroup groups containers by compose project (or by image, in
+// which case ProjectName holds the image name)
 type ContainerGroup struct {
 	ProjectName string
 	Containers  []Container
@@ -63,6 +115,21 @@ type ContainerDetails struct {
 	Entrypoint    string
 	WorkingDir    string
 	RestartPolicy string
+	ExitCode      int  // of the last run, for stopped containers
+	OOMKilled     bool // the last run was killed for running out of memory
+}
+
+// StatusSummary returns the status with the exit code of a stopped container
+// and whether it was OOM-killed
+func (d ContainerDetails) StatusSummary() string {
+	s := d.Status
+	if d.State == "exited" || d.State == "dead" {
+		s += fmt.Sprintf(" (exit code %d)", d.ExitCode)
+	}
+	if d.OOMKilled {
+		s += ", OOM-killed"
+	}
+	return s
 }
 
 // ContainerStats contains resource usage statistics for a container
@@ -87,53 +154,151 @@ type ContainerProcess struct {
 
 // composeFileHeader is used to parse just the name field from compose files
 type composeFileHeader struct {
-	Name string `yaml:"name"`
+	Name     string                 `yaml:"name"`
+	Services map[string]interface{} `yaml:"services"`
+}
+
+// composeFileNames are the default compose file names, in the order docker
+// compose looks for them
+var composeFileNames = []string{
+	"compose.yaml",
+	"compose.yml",
+	"docker-compose.yml",
+	"docker-compose.yaml",
+}
+
+// composeOverrideFileNames are the override files docker compose merges over
+// the default file, in the order it looks for them
+var composeOverrideFileNames = []string{
+	"compose.override.yml",
+	"compose.override.yaml",
+	"docker-compose.override.yml",
+	"docker-compose.override.yaml",
+}
+
+// findComposeFiles returns the compose files docker compose uses in dir when
+// none are given: the first default file and the first override file, if
+// there is one. It returns nil when dir has no compose file.
+func findComposeFiles(dir string) []string {
+	base := firstFileIn(dir, composeFileNames)
+	if base == "" {
+		return nil
+	}
+	if override := firstFileIn(dir, composeOverrideFileNames); override != "" {
+		return []string{base, override}
+	}
+	return []string{base}
+}
+
+// firstFileIn returns the path of the first of names that exists in dir
+func firstFileIn(dir string, names []string) string {
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
 }
 
 // DetectLocalComposeProject checks if current directory has a compose file
 // and returns the project name (from compose file's name field or directory name)
 func DetectLocalComposeProject() string {
-	name, _ := DetectLocalComposeProjectWithFile()
+	name, _ := DetectLocalComposeProjectWithFiles()
 	return name
 }
 
-// DetectLocalComposeProjectWithFile checks if current directory has a compose file
-// and returns both the project name and the compose file path
-func DetectLocalComposeProjectWithFile() (projectName string, composeFile string) {
+// DetectLocalComposeProjectWithFiles checks if current directory has a compose
+// file and returns both the project name and the compose files docker compose
+// would use there, the default file followed by its override file
+func DetectLocalComposeProjectWithFiles() (projectName string, composeFiles []string) {
 	cwd, err := os.Getwd()
 	if err != nil {
-		return "", ""
+		return "", nil
 	}
 
-	// Check for compose files
-	composeFiles := []string{
-		"compose.yml",
-		"compose.yaml",
-		"docker-compose.yml",
-		"docker-compose.yaml",
+	composeFiles = findComposeFiles(cwd)
+	if composeFiles == nil {
+		return "", nil
+	}
+	// Check the files for a name field
+	if name := getComposeProjectName(composeFiles, cwd); name != "" {
+		return name, composeFiles
 	}
+	// Fall back to directory name
+	return filepath.Base(cwd), composeFiles
+}
 
-	for _, f := range composeFiles {
-		filePath := filepath.Join(cwd, f)
-		if _, err := os.Stat(filePath); err == nil {
-			// Found a compose file - check for name field
-			if name := getComposeProjectName(filePath, cwd); name != "" {
-				return name, filePath
-			}
-			// Fall back to directory name
-			return filepath.Base(cwd), filePath
+// ExpandPath turns a path typed by the user, which may start with ~/,
+// into an absolute path
+func ExpandPath(path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
 		}
+		path = filepath.Join(home, rest)
+	}
+	return filepath.Abs(path)
+}
+
+// ResolveComposeProject validates a compose file, or a directory holding one
+// under a default name, and returns its project name, the compose files and
+// the working directory. For a directory the files are its default file and
+// override file, as docker compose picks them. A leading ~/ is expanded and
+// relative paths are resolved against the current directory.
+func ResolveComposeProject(path string) (projectName string, composeFiles []string, workingDir string, err error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", nil, "", fmt.Errorf("no path given")
+	}
+	path, err = ExpandPath(path)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", nil, "", err
+	}
+	composeFiles = []string{path}
+	if info.IsDir() {
+		if composeFiles = findComposeFiles(path); composeFiles == nil {
+			return "", nil, "", fmt.Errorf("no compose file in %s", path)
+		}
+	}
+
+	composeFile := composeFiles[0]
+	data, err := os.ReadFile(composeFile)
+	if err != nil {
+		return "", nil, "", err
+	}
+	var header composeFileHeader
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return "", nil, "", fmt.Errorf("%s is not valid YAML: %w", filepath.Base(composeFile), err)
+	}
+	if len(header.Services) == 0 {
+		return "", nil, "", fmt.Errorf("%s has no services, not a compose file", filepath.Base(composeFile))
 	}
 
-	return "", ""
+	workingDir = filepath.Dir(composeFile)
+	projectName = getComposeProjectName(composeFiles, workingDir)
+	if projectName == "" {
+		projectName = filepath.Base(workingDir)
+	}
+	return projectName, composeFiles, workingDir, nil
 }
 
-// getComposeProjectName extracts the project name from a compose file
-// It checks the name field in the file, or uses docker compose config as fallback
-func getComposeProjectName(filePath, workingDir string) string {
-	// First try to parse the file directly for the name field
-	data, err := os.ReadFile(filePath)
-	if err == nil {
+// getComposeProjectName extracts the project name from compose files
+// It checks the name field in the files, or uses docker compose config as fallback
+func getComposeProjectName(composeFiles []string, workingDir string) string {
+	// First try to parse the files directly for the name field; a later
+	// file's name wins, like compose merges them
+	for i := len(composeFiles) - 1; i >= 0; i-- {
+		data, err := os.ReadFile(composeFiles[i])
+		if err != nil {
+			continue
+		}
 		var header composeFileHeader
 		if yaml.Unmarshal(data, &header) == nil && header.Name != "" {
 			return header.Name
@@ -142,7 +307,8 @@ func getComposeProjectName(filePath, workingDir string) string {
 
 	// Fallback: use docker compose config to get the resolved project name
 	// This handles environment variables and other compose features
-	cmd := exec.Command("docker", "compose", "-f", filePath, "config", "--format", "json")
+	args := append(append([]string{"compose"}, composeFileArgs(composeFiles)...), "config", "--format", "json")
+	cmd := exec.Command("docker", args...)
 	cmd.Dir = workingDir
 	output, err := cmd.Output()
 	if err == nil {
@@ -163,6 +329,33 @@ func getComposeProjectName(filePath, workingDir string) string {
 	return ""
 }
 
+// ProjectNameConflicts returns, for each compose project name that
+// containers from more than one working dir use, those dirs sorted
+func ProjectNameConflicts(containers []Container) map[string][]string {
+	dirs := make(map[string]map[string]bool)
+	for _, c := range containers {
+		if c.ComposeProject == "" || c.ComposeWorkingDir == "" {
+			continue
+		}
+		if dirs[c.ComposeProject] == nil {
+			dirs[c.ComposeProject] = make(map[string]bool)
+		}
+		dirs[c.ComposeProject][c.ComposeWorkingDir] = true
+	}
+
+	conflicts := make(map[string][]string)
+	for project, set := range dirs {
+		if len(set) < 2 {
+			continue
+		}
+		for dir := range set {
+			conflicts[project] = append(conflicts[project], dir)
+		}
+		sort.Strings(conflicts[project])
+	}
+	return conflicts
+}
+
 // GroupByComposeProject groups containers by their compose project
 // If priorityProject is set, that project will be listed first
 func GroupByComposeProject(containers []Container, priorityProject string) []ContainerGroup {
@@ -226,3 +419,49 @@ func GroupByComposeProject(containers []Container, priorityProject string) []Con
 
 	return result
 }
+
+// GroupByImage groups containers by the image they run, for containers
+// started outside compose. Stopped compose services have no image yet and
+// are listed last.
+func GroupByImage(containers []Container) []ContainerGroup {
+	groups := make(map[string][]Container)
+	var noImage []Container
+
+	for _, c := range containers {
+		if c.Image != "" {
+			groups[c.Image] = append(groups[c.Image], c)
+		} else {
+			noImage = append(noImage, c)
+		}
+	}
+
+	imageNames := make([]string, 0, len(groups))
+	for name := range groups {
+		imageNames = append(imageNames, name)
+	}
+	sort.Strings(imageNames)
+
+	result := make([]ContainerGroup, 0, len(groups)+1)
+	for _, name := range imageNames {
+		imageContainers := groups[name]
+		sort.Slice(imageContainers, func(i, j int) bool {
+			return imageContainers[i].QualifiedName() < imageContainers[j].QualifiedName()
+		})
+		result = append(result, ContainerGroup{
+			ProjectName: name,
+			Containers:  imageContainers,
+		})
+	}
+
+	if len(noImage) > 0 {
+		sort.Slice(noImage, func(i, j int) bool {
+			return noImage[i].QualifiedName() < noImage[j].QualifiedName()
+		})
+		result = append(result, ContainerGroup{
+			ProjectName: "(not created)",
+			Containers:  noImage,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,103 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContainerNames(t *testing.T) {
+	tests := []struct {
+		name          string
+		c             Container
+		wantDisplay   string
+		wantQualified string
+	}{
+		{
+			"compose service",
+			Container{Name: "shop-api-1", ComposeProject: "shop", ComposeService: "api"},
+			"api", "shop/api",
+		},
+		{
+			"stopped compose service",
+			Container{Name: "api", State: "stopped", ComposeProject: "shop", ComposeService: "api"},
+			"api", "shop/api",
+		},
+		{
+			"standalone container",
+			Container{Name: "redis"},
+			"redis", "redis",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.DisplayName(); got != tt.wantDisplay {
+				t.Fatalf("DisplayName() = %q, want %q", got, tt.wantDisplay)
+			}
+			if got := tt.c.QualifiedName(); got != tt.wantQualified {
+				t.Fatalf("QualifiedName() = %q, want %q", got, tt.wantQualified)
+			}
+		})
+	}
+}
+
+func TestGroupByImage(t *testing.T) {
+	containers := []Container{
+		{ID: "1", Name: "web-b", Image: "nginx:latest"},
+		{ID: "2", Name: "cache", Image: "redis:7"},
+		{ID: "3", Name: "web-a", Image: "nginx:latest"},
+		{ID: "4", Name: "api", State: "stopped", ComposeProject: "shop", ComposeService: "api"},
+	}
+
+	groups := GroupByImage(containers)
+	want := []struct {
+		name  string
+		names []string
+	}{
+		{"nginx:latest", []string{"web-a", "web-b"}},
+		{"redis:7", []string{"cache"}},
+		{"(not created)", []string{"shop/api"}},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("expected %d groups, got %d", len(want), len(groups))
+	}
+	for i, w := range want {
+		if groups[i].ProjectName != w.name {
+			t.Fatalf("group %d: expected %q, got %q", i, w.name, groups[i].ProjectName)
+		}
+		if len(groups[i].Containers) != len(w.names) {
+			t.Fatalf("group %q: expected %d containers, got %d", w.name, len(w.names), len(groups[i].Containers))
+		}
+		for j, n := range w.names {
+			if got := groups[i].Containers[j].QualifiedName(); got != n {
+				t.Fatalf("group %q item %d: expected %q, got %q", w.name, j, n, got)
+			}
+		}
+	}
+}
+
+func TestProjectNameConflicts(t *testing.T) {
+	containers := []Container{
+		{ComposeProject: "app", ComposeService: "web", ComposeWorkingDir: "/src/a/app"},
+		{ComposeProject: "app", ComposeService: "db", ComposeWorkingDir: "/src/a/app"},
+		{ComposeProject: "app", ComposeService: "web", ComposeWorkingDir: "/src/b/app"},
+		{ComposeProject: "app", ComposeService: "worker"}, // stopped service, no labels
+		{ComposeProject: "shop", ComposeService: "api", ComposeWorkingDir: "/src/shop"},
+	}
+
+	conflicts := ProjectNameConflicts(containers)
+	if len(conflicts) != 1 || strings.Join(conflicts["app"], ",") != "/src/a/app,/src/b/app" {
+		t.Fatalf("expected only app to clash across two dirs, got %v", conflicts)
+	}
+}
+
+func TestContainerDetailsStatusSummary(t *testing.T) {
+	running := ContainerDetails{Status: "running", State: "running"}
+	if got := running.StatusSummary(); got != "running" {
+		t.Fatalf("running summary = %q", got)
+	}
+	killed := ContainerDetails{Status: "exited", State: "exited", ExitCode: 137, OOMKilled: true}
+	if got := killed.StatusSummary(); got != "exited (exit code 137), OOM-killed" {
+		t.Fatalf("OOM-killed summary = %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// lifecycleActions are the container events StreamEvents reports
+var lifecycleActions = []events.Action{
+	events.ActionStart,
+	events.ActionRestart,
+	events.ActionPause,
+	events.ActionUnPause,
+	events.ActionKill,
+	events.ActionDie,
+	events.ActionOOM,
+	events.ActionHealthStatus,
+}
+
+// ContainerEvent is a lifecycle event of a container
+type ContainerEvent struct {
+	Container Container // built from the event's attributes; State is "running" for start events
+	Action    string    // "start", "die", "oom", "health_status", ...
+	Detail    string    // exit code, health status or signal, when the event has one
+	ExitCode  int       // for die events
+	Time      time.Time
+}
+
+// StreamEvents reports container lifecycle events from the Docker events API,
+// filtered to the given container IDs or names, or for every container when
+// none are given. Both channels close when ctx is cancelled or the event
+// stream fails; a failure is sent on the error channel first.
+func (c *Client) StreamEvents(ctx context.Context, containers ...string) (<-chan ContainerEvent, <-chan error) {
+	eventChan := make(chan ContainerEvent, 16)
+	errChan := make(chan error, 1)
+
+	args := filters.NewArgs(filters.Arg("type", string(events.ContainerEventType)))
+	for _, action := range lifecycleActions {
+		args.Add("event", string(action))
+	}
+	for _, id := range containers {
+		args.Add("container", id)
+	}
+
+	go func() {
+		defer close(eventChan)
+		defer close(errChan)
+
+		messages, errs := c.cli.Events(ctx, events.ListOptions{Filters: args})
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-errs:
+				if ctx.Err() == nil {
+					errChan <- fmt.Errorf("docker events: %w", err)
+				}
+				return
+			case msg := <-messages:
+				ev, ok := eventFromMessage(msg)
+				if !ok {
+					continue
+				}
+				select {
+				case eventChan <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return eventChan, errChan
+}
+
+// eventFromMessage converts a container event from the API. Container events
+// carry the container's name, image and labels as actor attributes.
+func eventFromMessage(msg events.Message) (ContainerEvent, bool) {
+	if msg.Type != events.ContainerEventType || len(msg.Actor.ID) < 12 {
+		return ContainerEvent{}, false
+	}
+	attrs := msg.Actor.Attributes
+	replica, _ := strconv.Atoi(attrs[LabelComposeReplica])
+	ev := ContainerEvent{
+		Container: Container{
+			ID:                msg.Actor.ID[:12],
+			Name:              attrs["name"],
+			ComposeProject:    attrs[LabelComposeProject],
+			ComposeService:    attrs[LabelComposeService],
+			ComposeConfigFile: attrs[LabelComposeConfigFile],
+			ComposeWorkingDir: attrs[LabelComposeWorkingDir],
+			ComposeReplica:    replica,
+			Image:             attrs["image"],
+		},
+		Action: string(msg.Action),
+		Time:   time.Unix(0, msg.TimeNano),
+	}
+
+	// Health events are sent as "health_status: healthy"
+	if action, status, ok := strings.Cut(ev.Action, ": "); ok {
+		ev.Action, ev.Detail = action, status
+	}
+	switch events.Action(ev.Action) {
+	case events.ActionStart:
+		ev.Container.State = "running"
+		ev.Container.Status = "Up"
+		ev.Container.Created = ev.Time
+	case events.ActionDie:
+		if code := attrs["exitCode"]; code != "" {
+			ev.ExitCode, _ = strconv.Atoi(code)
+			ev.Detail = "exit code " + code
+		}
+	case events.ActionKill:
+		ev.Detail = attrs["signal"]
+	}
+	return ev, true
+}
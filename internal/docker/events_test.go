@@ -0,0 +1,52 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/events"
+)
+
+func TestEventFromMessage(t *testing.T) {
+	msg := events.Message{
+		Type:   events.ContainerEventType,
+		Action: events.ActionStart,
+		Actor: events.Actor{
+			ID: "0123456789abcdef0123",
+			Attributes: map[string]string{
+				"name":                 "shop-api-2",
+				"image":                "shop-api:latest",
+				LabelComposeProject:    "shop",
+				LabelComposeService:    "api",
+				LabelComposeReplica:    "2",
+				LabelComposeWorkingDir: "/src/shop",
+			},
+		},
+	}
+
+	ev, ok := eventFromMessage(msg)
+	if !ok {
+		t.Fatalf("expected an event from a container start")
+	}
+	cont := ev.Container
+	if ev.Action != "start" || cont.ID != "0123456789ab" || cont.Name != "shop-api-2" || cont.State != "running" ||
+		cont.ComposeProject != "shop" || cont.ComposeService != "api" || cont.ComposeReplica != 2 ||
+		cont.ComposeWorkingDir != "/src/shop" || cont.Image != "shop-api:latest" {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+
+	msg.Action = events.ActionDie
+	msg.Actor.Attributes["exitCode"] = "137"
+	if ev, _ := eventFromMessage(msg); ev.Action != "die" || ev.Detail != "exit code 137" || ev.ExitCode != 137 || ev.Container.State != "" {
+		t.Fatalf("unexpected die event %+v", ev)
+	}
+
+	msg.Action = "health_status: unhealthy"
+	if ev, _ := eventFromMessage(msg); ev.Action != "health_status" || ev.Detail != "unhealthy" {
+		t.Fatalf("unexpected health event %+v", ev)
+	}
+
+	msg.Type = events.ImageEventType
+	if _, ok := eventFromMessage(msg); ok {
+		t.Fatalf("expected image events to be ignored")
+	}
+}
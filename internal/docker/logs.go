@@ -2,12 +2,18 @@ package docker
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
 )
 
 // LogLine represents a single log entry
@@ -16,10 +22,59 @@ type LogLine struct {
 	Timestamp   time.Time
 	Stream      string // "stdout", "stderr", or "system"
 	Content     string
+	Plain       string // Content without SGR sequences, filled in by the log view when the line is added
+}
+
+// LogStreamOptions controls how much history is fetched and whether the
+// stream keeps following new output
+type LogStreamOptions struct {
+	Tail       int       // Number of lines from the end to start with (0 = all)
+	Since      time.Time // Only return logs after this time (zero = no limit)
+	Timestamps bool      // Ask Docker to prefix lines with timestamps
+	Follow     bool      // Keep streaming new output (ignored for stopped containers)
+}
+
+// containerLogsOptions converts to the Docker API options
+func (o LogStreamOptions) containerLogsOptions() container.LogsOptions {
+	opts := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     o.Follow,
+		Tail:       "all",
+		Timestamps: o.Timestamps,
+	}
+	if o.Tail > 0 {
+		opts.Tail = strconv.Itoa(o.Tail)
+	}
+	if !o.Since.IsZero() {
+		opts.Since = o.Since.Format(time.RFC3339Nano)
+	}
+	return opts
+}
+
+// defaultLogStreamOptions returns the options StreamLogs uses: a short tail
+// that follows running containers, and a longer one for exited containers
+func defaultLogStreamOptions(running bool) LogStreamOptions {
+	if !running {
+		return LogStreamOptions{Tail: 50, Timestamps: true}
+	}
+	return LogStreamOptions{Tail: 10, Timestamps: true, Follow: true}
 }
 
 // StreamLogs starts streaming logs for a container and returns channels for log lines and errors
 func (c *Client) StreamLogs(ctx context.Context, containerID string) (<-chan LogLine, <-chan error) {
+	return c.streamLogs(ctx, containerID, nil)
+}
+
+// StreamLogsWithOptions is like StreamLogs but lets the caller choose the
+// tail size, start time, timestamps and follow mode
+func (c *Client) StreamLogsWithOptions(ctx context.Context, containerID string, opts LogStreamOptions) (<-chan LogLine, <-chan error) {
+	return c.streamLogs(ctx, containerID, &opts)
+}
+
+// streamLogs implements StreamLogs and StreamLogsWithOptions, picking the
+// default options from the container state when opts is nil
+func (c *Client) streamLogs(ctx context.Context, containerID string, opts *LogStreamOptions) (<-chan LogLine, <-chan error) {
 	logChan := make(chan LogLine, 100)
 	errChan := make(chan error, 1)
 
@@ -36,105 +91,134 @@ func (c *Client) StreamLogs(ctx context.Context, containerID string) (<-chan Log
 		isTTY := inspect.Config.Tty
 		isRunning := inspect.State.Running
 
-		// For exited containers, get more lines and don't follow
-		tail := "10"
-		follow := true
-		if !isRunning {
-			tail = "50"
-			follow = false
+		streamOpts := defaultLogStreamOptions(isRunning)
+		if opts != nil {
+			streamOpts = *opts
+			// Following a stopped container would end immediately anyway
+			if !isRunning {
+				streamOpts.Follow = false
+			}
 		}
 
-		reader, err := c.cli.ContainerLogs(ctx, containerID, container.LogsOptions{
-			ShowStdout: true,
-			ShowStderr: true,
-			Follow:     follow,
-			Tail:       tail,
-			Timestamps: true,
-		})
+		reader, err := c.cli.ContainerLogs(ctx, containerID, streamOpts.containerLogsOptions())
 		if err != nil {
 			errChan <- err
 			return
 		}
 		defer func() { _ = reader.Close() }()
 
-		if isTTY {
-			// TTY mode: logs come through directly without multiplexing
-			scanner := bufio.NewScanner(reader)
-			for scanner.Scan() {
-				select {
-				case <-ctx.Done():
-					return
-				default:
-				}
-				line := scanner.Text()
-				logLine := parseLine(containerID, "stdout", line)
-				select {
-				case <-ctx.Done():
-					return
-				case logChan <- logLine:
-				}
-			}
-			if err := scanner.Err(); err != nil && err != io.EOF {
-				errChan <- err
-			}
-		} else {
-			// Non-TTY mode: Docker multiplexes stdout and stderr with an 8-byte header
-			// Header format: [STREAM_TYPE, 0, 0, 0, SIZE1, SIZE2, SIZE3, SIZE4]
-			// STREAM_TYPE: 0=stdin, 1=stdout, 2=stderr
-			hdr := make([]byte, 8)
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				default:
-				}
-
-				// Read the header
-				_, err := io.ReadFull(reader, hdr)
-				if err != nil {
-					if err != io.EOF {
-						errChan <- err
-					}
-					return
-				}
-
-				// Get stream type
-				streamType := "stdout"
-				if hdr[0] == 2 {
-					streamType = "stderr"
-				}
-
-				// Get payload size
-				size := int64(hdr[4])<<24 | int64(hdr[5])<<16 | int64(hdr[6])<<8 | int64(hdr[7])
-
-				// Read the payload
-				payload := make([]byte, size)
-				_, err = io.ReadFull(reader, payload)
-				if err != nil {
-					if err != io.EOF {
-						errChan <- err
-					}
-					return
-				}
-
-				// Parse lines from payload
-				scanner := bufio.NewScanner(strings.NewReader(string(payload)))
-				for scanner.Scan() {
-					line := scanner.Text()
-					logLine := parseLine(containerID, streamType, line)
-					select {
-					case <-ctx.Done():
-						return
-					case logChan <- logLine:
-					}
-				}
-			}
+		if err := readLogStream(ctx, reader, isTTY, containerID, logChan); err != nil && ctx.Err() == nil {
+			errChan <- err
 		}
 	}()
 
 	return logChan, errChan
 }
 
+// exportProgressEvery is how often ExportLogs reports progress, in lines
+const exportProgressEvery = 5000
+
+// ExportLogs writes a container's complete log history to w, one line per
+// entry prefixed with its timestamp, and returns the number of lines written.
+// Unlike the pane buffer nothing is dropped. progress, if non-nil, is called
+// with the running line count as the export goes.
+func (c *Client) ExportLogs(ctx context.Context, containerID string, w io.Writer, progress func(lines int)) (int, error) {
+	logChan, errChan := c.StreamLogsWithOptions(ctx, containerID, LogStreamOptions{Timestamps: true})
+	lines, err := writeLogLines(w, logChan, errChan, progress)
+	if err == nil && ctx.Err() != nil {
+		// The stream ends quietly on cancel; don't report a partial export as complete
+		err = ctx.Err()
+	}
+	return lines, err
+}
+
+// writeLogLines drains a log stream into w
+func writeLogLines(w io.Writer, logChan <-chan LogLine, errChan <-chan error, progress func(lines int)) (int, error) {
+	bw := bufio.NewWriter(w)
+	lines := 0
+	var writeErr error
+	for line := range logChan {
+		if writeErr != nil {
+			continue // keep draining so the stream goroutine can exit
+		}
+		if _, writeErr = fmt.Fprintf(bw, "%s %s\n", line.Timestamp.Format(time.RFC3339Nano), line.Content); writeErr != nil {
+			continue
+		}
+		lines++
+		if progress != nil && lines%exportProgressEvery == 0 {
+			progress(lines)
+		}
+	}
+	if writeErr != nil {
+		return lines, writeErr
+	}
+	if err := <-errChan; err != nil {
+		return lines, err
+	}
+	return lines, bw.Flush()
+}
+
+// errStreamCancelled stops the demultiplexer once the context is cancelled
+var errStreamCancelled = errors.New("log stream cancelled")
+
+// readLogStream reads a container log stream and sends parsed lines to logChan.
+// Non-TTY streams are multiplexed with 8-byte stdcopy headers and are split
+// back into stdout and stderr; TTY streams are raw stdout.
+func readLogStream(ctx context.Context, reader io.Reader, isTTY bool, containerID string, logChan chan<- LogLine) error {
+	stdout := &lineWriter{ctx: ctx, containerID: containerID, stream: "stdout", out: logChan}
+	stderr := &lineWriter{ctx: ctx, containerID: containerID, stream: "stderr", out: logChan}
+
+	var err error
+	if isTTY {
+		_, err = io.Copy(stdout, reader)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, reader)
+	}
+	if errors.Is(err, errStreamCancelled) {
+		return nil
+	}
+
+	// Emit whatever is left of an unterminated last line
+	stdout.flush()
+	stderr.flush()
+	return err
+}
+
+// lineWriter is an io.Writer that assembles written chunks into lines for
+// one stream and sends them as LogLines
+type lineWriter struct {
+	ctx         context.Context
+	containerID string
+	stream      string
+	out         chan<- LogLine
+	assembler   lineAssembler
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	if !w.send(w.assembler.Write(p)) {
+		return 0, errStreamCancelled
+	}
+	return len(p), nil
+}
+
+func (w *lineWriter) flush() {
+	if line, ok := w.assembler.Flush(); ok {
+		w.send([]string{line})
+	}
+}
+
+// send delivers parsed lines, reporting false once the context is cancelled
+func (w *lineWriter) send(lines []string) bool {
+	for _, line := range lines {
+		select {
+		case <-w.ctx.Done():
+			return false
+		case w.out <- parseLine(w.containerID, w.stream, line):
+		}
+	}
+	return true
+}
+
 // parseLine parses a log line with optional timestamp
 func parseLine(containerID, stream, line string) LogLine {
 	logLine := LogLine{
@@ -144,13 +228,137 @@ func parseLine(containerID, stream, line string) LogLine {
 		Content:     line,
 	}
 
-	// Try to parse timestamp (format: 2024-01-15T10:30:45.123456789Z)
+	if ts, rest, ok := splitTimestamp(line); ok {
+		logLine.Timestamp = ts
+		logLine.Content = strings.TrimSpace(rest)
+	}
+
+	return logLine
+}
+
+// splitTimestamp splits a Docker timestamp prefix (2024-01-15T10:30:45.123456789Z)
+// from the rest of the line
+func splitTimestamp(line string) (time.Time, string, bool) {
 	if len(line) > 30 && line[4] == '-' && line[7] == '-' && line[10] == 'T' {
 		if ts, err := time.Parse(time.RFC3339Nano, line[:30]); err == nil {
-			logLine.Timestamp = ts
-			logLine.Content = strings.TrimSpace(line[31:])
+			return ts, line[31:], true
 		}
 	}
+	return time.Time{}, line, false
+}
 
-	return logLine
+// maxPendingLine caps how much of an unterminated line is buffered before it
+// is emitted anyway (matches Docker's 16K partial message size)
+const maxPendingLine = 16 * 1024
+
+// lineAssembler joins log output that arrives in arbitrary chunks into whole
+// lines. An unterminated tail is held until the rest arrives; if it grows
+// past maxPendingLine it is emitted early, but an incomplete escape sequence
+// or UTF-8 rune at the cut is held back so it isn't split across two lines.
+type lineAssembler struct {
+	pending []byte
+}
+
+// Write consumes a chunk and returns the complete lines it finished
+func (a *lineAssembler) Write(chunk []byte) []string {
+	// Docker prefixes every message with a timestamp when Timestamps is set,
+	// including continuations of a partial line - drop the repeated prefix
+	if len(a.pending) > 0 {
+		if _, rest, ok := splitTimestamp(string(chunk)); ok {
+			chunk = []byte(rest)
+		}
+	}
+
+	var lines []string
+	for len(chunk) > 0 {
+		idx := bytes.IndexByte(chunk, '\n')
+		if idx < 0 {
+			a.pending = append(a.pending, chunk...)
+			break
+		}
+		a.pending = append(a.pending, chunk[:idx]...)
+		lines = append(lines, string(a.pending))
+		a.pending = a.pending[:0]
+		chunk = chunk[idx+1:]
+	}
+
+	if len(a.pending) > maxPendingLine {
+		cut := safeCutPoint(a.pending)
+		if cut > 0 {
+			lines = append(lines, string(a.pending[:cut]))
+			a.pending = append(a.pending[:0], a.pending[cut:]...)
+		}
+	}
+
+	return lines
+}
+
+// Flush returns any buffered partial line (used when the stream ends)
+func (a *lineAssembler) Flush() (string, bool) {
+	if len(a.pending) == 0 {
+		return "", false
+	}
+	line := string(a.pending)
+	a.pending = a.pending[:0]
+	return line, true
+}
+
+// safeCutPoint returns the length of b that can be emitted without splitting
+// a trailing escape sequence or UTF-8 rune
+func safeCutPoint(b []byte) int {
+	cut := len(b)
+	if esc := incompleteEscapeStart(b); esc >= 0 {
+		cut = esc
+	}
+	// Back off to the start of a trailing partial rune
+	for i := cut - 1; i >= 0 && i >= cut-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:cut]) {
+				cut = i
+			}
+			break
+		}
+	}
+	return cut
+}
+
+// incompleteEscapeStart returns the index of an unterminated escape sequence
+// at the end of b, or -1 if b doesn't end inside one
+func incompleteEscapeStart(b []byte) int {
+	esc := bytes.LastIndexByte(b, 0x1b)
+	if esc < 0 {
+		return -1
+	}
+	seq := b[esc+1:]
+	if len(seq) == 0 {
+		return esc
+	}
+
+	switch seq[0] {
+	case '[':
+		// CSI: parameter bytes, intermediate bytes, then a final byte
+		for _, c := range seq[1:] {
+			if c >= 0x40 && c <= 0x7e {
+				return -1
+			}
+			if c < 0x20 || c > 0x3f {
+				// Not a valid CSI - nothing sensible to hold back
+				return -1
+			}
+		}
+		return esc
+	case ']':
+		// OSC ends with BEL (an ST terminator would itself be the last ESC)
+		if bytes.IndexByte(seq, 0x07) >= 0 {
+			return -1
+		}
+		return esc
+	case 'P', '_', '^', 'X':
+		// DCS/APC/PM/SOS end with ST, which would be the last ESC
+		return esc
+	case '\\':
+		// ST terminating an earlier string sequence
+		return -1
+	}
+	return -1
 }
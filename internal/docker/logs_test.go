@@ -0,0 +1,268 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// readAll runs readLogStream over data and collects the emitted lines
+func readAll(t *testing.T, data []byte, isTTY bool) []LogLine {
+	t.Helper()
+	logChan := make(chan LogLine, 100)
+	if err := readLogStream(context.Background(), bytes.NewReader(data), isTTY, "abc", logChan); err != nil {
+		t.Fatalf("readLogStream: %v", err)
+	}
+	close(logChan)
+
+	var lines []LogLine
+	for l := range logChan {
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+func TestReadLogStreamDemultiplexesStdoutAndStderr(t *testing.T) {
+	var buf bytes.Buffer
+	stdout := stdcopy.NewStdWriter(&buf, stdcopy.Stdout)
+	stderr := stdcopy.NewStdWriter(&buf, stdcopy.Stderr)
+
+	_, _ = stdout.Write([]byte("2024-01-15T10:30:45.123456789Z out one\n"))
+	_, _ = stderr.Write([]byte("2024-01-15T10:30:46.123456789Z err one\n"))
+	// A stdout line split over two frames with stderr interleaved
+	_, _ = stdout.Write([]byte("2024-01-15T10:30:47.123456789Z out "))
+	_, _ = stderr.Write([]byte("2024-01-15T10:30:48.123456789Z err two\n"))
+	_, _ = stdout.Write([]byte("two\n"))
+
+	lines := readAll(t, buf.Bytes(), false)
+	want := []struct{ stream, content string }{
+		{"stdout", "out one"},
+		{"stderr", "err one"},
+		{"stderr", "err two"},
+		{"stdout", "out two"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got %d: %+v", len(want), len(lines), lines)
+	}
+	for i, w := range want {
+		if lines[i].Stream != w.stream || lines[i].Content != w.content {
+			t.Fatalf("line %d: expected %s %q, got %s %q", i, w.stream, w.content, lines[i].Stream, lines[i].Content)
+		}
+		if lines[i].ContainerID != "abc" {
+			t.Fatalf("line %d: expected container ID to be set", i)
+		}
+		if strings.ContainsAny(lines[i].Content, "\x00\x01\x02") {
+			t.Fatalf("line %d: frame header leaked into content: %q", i, lines[i].Content)
+		}
+	}
+	if lines[0].Timestamp.Second() != 45 {
+		t.Fatalf("expected timestamp to be parsed, got %v", lines[0].Timestamp)
+	}
+}
+
+func TestReadLogStreamTTYIsRawStdout(t *testing.T) {
+	lines := readAll(t, []byte("first\nsecond\nno newline"), true)
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %+v", lines)
+	}
+	for _, l := range lines {
+		if l.Stream != "stdout" {
+			t.Fatalf("expected TTY output on stdout, got %q", l.Stream)
+		}
+	}
+	if lines[2].Content != "no newline" {
+		t.Fatalf("expected trailing partial line to be flushed, got %q", lines[2].Content)
+	}
+}
+
+func TestReadLogStreamStopsOnCancel(t *testing.T) {
+	var buf bytes.Buffer
+	w := stdcopy.NewStdWriter(&buf, stdcopy.Stdout)
+	_, _ = w.Write([]byte("one\ntwo\n"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// Unbuffered and never read: send must bail out on the cancelled context
+	if err := readLogStream(ctx, &buf, false, "abc", make(chan LogLine)); err != nil {
+		t.Fatalf("expected cancellation to end the stream cleanly, got %v", err)
+	}
+}
+
+func TestLogStreamOptionsConvertToDockerOptions(t *testing.T) {
+	since := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	opts := LogStreamOptions{Tail: 200, Since: since, Timestamps: true, Follow: true}.containerLogsOptions()
+
+	if opts.Tail != "200" || !opts.Follow || !opts.Timestamps {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+	if opts.Since != "2024-01-15T10:30:00Z" {
+		t.Fatalf("expected RFC3339 since, got %q", opts.Since)
+	}
+	if !opts.ShowStdout || !opts.ShowStderr {
+		t.Fatalf("expected both streams to be requested")
+	}
+
+	all := LogStreamOptions{}.containerLogsOptions()
+	if all.Tail != "all" || all.Since != "" || all.Follow {
+		t.Fatalf("expected zero options to fetch full history without following, got %+v", all)
+	}
+}
+
+func TestDefaultLogStreamOptionsDependOnState(t *testing.T) {
+	running := defaultLogStreamOptions(true)
+	if running.Tail != 10 || !running.Follow || !running.Timestamps {
+		t.Fatalf("unexpected running defaults: %+v", running)
+	}
+	exited := defaultLogStreamOptions(false)
+	if exited.Tail != 50 || exited.Follow || !exited.Timestamps {
+		t.Fatalf("unexpected exited defaults: %+v", exited)
+	}
+}
+
+func TestLineAssemblerJoinsLineSplitAcrossChunks(t *testing.T) {
+	var a lineAssembler
+
+	if lines := a.Write([]byte("hello \x1b[3")); len(lines) != 0 {
+		t.Fatalf("expected partial line to be held, got %q", lines)
+	}
+	lines := a.Write([]byte("2mworld\x1b[0m\nnext"))
+	if len(lines) != 1 || lines[0] != "hello \x1b[32mworld\x1b[0m" {
+		t.Fatalf("expected rejoined line, got %q", lines)
+	}
+
+	line, ok := a.Flush()
+	if !ok || line != "next" {
+		t.Fatalf("expected flush to return trailing partial line, got %q (%v)", line, ok)
+	}
+	if _, ok := a.Flush(); ok {
+		t.Fatalf("expected nothing left after flush")
+	}
+}
+
+func TestLineAssemblerSplitsMultipleLinesInOneChunk(t *testing.T) {
+	var a lineAssembler
+	lines := a.Write([]byte("one\ntwo\n\nthree\n"))
+	want := []string{"one", "two", "", "three"}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got %q", len(want), lines)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Fatalf("line %d: expected %q, got %q", i, want[i], lines[i])
+		}
+	}
+}
+
+func TestLineAssemblerDropsRepeatedTimestampOnContinuation(t *testing.T) {
+	var a lineAssembler
+	a.Write([]byte("2024-01-15T10:30:45.123456789Z first half "))
+	lines := a.Write([]byte("2024-01-15T10:30:45.223456789Z second half\n"))
+	if len(lines) != 1 {
+		t.Fatalf("expected one line, got %q", lines)
+	}
+
+	got := parseLine("id", "stdout", lines[0])
+	if got.Content != "first half second half" {
+		t.Fatalf("expected continuation timestamp to be dropped, got %q", got.Content)
+	}
+}
+
+func TestLineAssemblerHoldsBackEscapeWhenCappingLongLine(t *testing.T) {
+	var a lineAssembler
+	long := strings.Repeat("x", maxPendingLine) + "\x1b[38;5"
+	lines := a.Write([]byte(long))
+	if len(lines) != 1 {
+		t.Fatalf("expected overlong partial line to be emitted, got %d lines", len(lines))
+	}
+	if strings.Contains(lines[0], "\x1b") {
+		t.Fatalf("emitted chunk contains a split escape sequence")
+	}
+
+	lines = a.Write([]byte(";208mtail\n"))
+	if len(lines) != 1 || lines[0] != "\x1b[38;5;208mtail" {
+		t.Fatalf("expected held escape to lead the next line, got %q", lines)
+	}
+}
+
+func TestLineAssemblerHoldsBackPartialRuneWhenCappingLongLine(t *testing.T) {
+	var a lineAssembler
+	rune3 := []byte("世")
+	chunk := append([]byte(strings.Repeat("x", maxPendingLine)), rune3[:2]...)
+	lines := a.Write(chunk)
+	if len(lines) != 1 || !utf8.ValidString(lines[0]) {
+		t.Fatalf("expected valid UTF-8 chunk, got %d lines", len(lines))
+	}
+
+	lines = a.Write(append(rune3[2:], '\n'))
+	if len(lines) != 1 || lines[0] != "世" {
+		t.Fatalf("expected rune to be completed on the next line, got %q", lines)
+	}
+}
+
+func TestIncompleteEscapeStart(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"no escape", "hello", -1},
+		{"complete SGR", "a\x1b[0m", -1},
+		{"lone ESC", "ab\x1b", 2},
+		{"partial CSI", "ab\x1b[38;5", 2},
+		{"CSI with intermediate", "ab\x1b[2 ", 2},
+		{"partial OSC", "ab\x1b]0;title", 2},
+		{"OSC with BEL", "ab\x1b]0;title\x07", -1},
+		{"OSC with ST", "ab\x1b]0;title\x1b\\", -1},
+		{"partial DCS", "ab\x1bPq", 2},
+		{"single char escape", "ab\x1bM", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := incompleteEscapeStart([]byte(tt.in)); got != tt.want {
+				t.Fatalf("incompleteEscapeStart(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteLogLinesWritesEveryLineWithProgress(t *testing.T) {
+	logChan := make(chan LogLine, exportProgressEvery+1)
+	errChan := make(chan error, 1)
+	ts := time.Date(2024, 1, 15, 10, 30, 45, 0, time.UTC)
+	for i := 0; i < exportProgressEvery+1; i++ {
+		logChan <- LogLine{Timestamp: ts, Stream: "stdout", Content: "line"}
+	}
+	close(logChan)
+	close(errChan)
+
+	var buf bytes.Buffer
+	var reported []int
+	lines, err := writeLogLines(&buf, logChan, errChan, func(n int) { reported = append(reported, n) })
+	if err != nil || lines != exportProgressEvery+1 {
+		t.Fatalf("expected %d lines, got %d (%v)", exportProgressEvery+1, lines, err)
+	}
+	if len(reported) != 1 || reported[0] != exportProgressEvery {
+		t.Fatalf("unexpected progress reports %v", reported)
+	}
+	first, _, _ := strings.Cut(buf.String(), "\n")
+	if first != "2024-01-15T10:30:45Z line" {
+		t.Fatalf("unexpected line format %q", first)
+	}
+}
+
+func TestWriteLogLinesReportsStreamError(t *testing.T) {
+	logChan := make(chan LogLine)
+	errChan := make(chan error, 1)
+	close(logChan)
+	errChan <- context.DeadlineExceeded
+
+	if _, err := writeLogLines(&bytes.Buffer{}, logChan, errChan, nil); err != context.DeadlineExceeded {
+		t.Fatalf("expected stream error, got %v", err)
+	}
+}
@@ -51,6 +51,12 @@ func Initialize() {
 	}
 }
 
+// Reload re-reads the notification settings, e.g. after config.json changed
+func Reload() {
+	Close()
+	Initialize()
+}
+
 // detectTerminal attempts to identify the current terminal emulator
 func detectTerminal() string {
 	// Check TERM_PROGRAM first (most reliable)
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"cm/internal/config"
 	"cm/internal/docker"
 	"cm/internal/ui/common"
 	"cm/internal/ui/discovery"
@@ -27,6 +28,9 @@ type App struct {
 	dockerClient     *docker.Client
 	selectedConts    []docker.Container
 	startWithLogView bool
+	configErrors     []error
+	startupNotice    *common.ShowToastMsg
+	watcher          common.ConfigWatcher
 }
 
 // NewApp creates a new application model
@@ -38,24 +42,54 @@ func NewApp(dockerClient *docker.Client, initialContainers []docker.Container) A
 			dockerClient:     dockerClient,
 			selectedConts:    initialContainers,
 			startWithLogView: true,
+			configErrors:     config.ValidateFiles(),
+			watcher:          common.NewConfigWatcher(),
 		}
 	}
 	return App{
 		screen:       ScreenDiscovery,
 		discovery:    discovery.New(dockerClient, nil),
 		dockerClient: dockerClient,
+		configErrors: config.ValidateFiles(),
+		watcher:      common.NewConfigWatcher(),
 	}
 }
 
+// WithStartupNotice shows a toast once the first screen is up, for messages
+// from before the program starts that the alt screen would hide
+func (a App) WithStartupNotice(title, message string, typ common.ToastType) App {
+	a.startupNotice = &common.ShowToastMsg{Title: title, Message: message, Type: typ}
+	return a
+}
+
+// startupToast reports config files that couldn't be parsed and were
+// replaced by defaults, so a typo doesn't silently reset the user's settings.
+// Otherwise it shows the startup notice, if any.
+func (a App) startupToast() tea.Cmd {
+	if len(a.configErrors) == 0 {
+		if a.startupNotice == nil {
+			return nil
+		}
+		toast := *a.startupNotice
+		return func() tea.Msg { return toast }
+	}
+	toast := common.ShowToastMsg{
+		Title:   common.ConfigErrorTitle,
+		Message: common.FormatConfigErrors(a.configErrors),
+		Type:    common.ToastError,
+	}
+	return func() tea.Msg { return toast }
+}
+
 // Init initializes the application
 // Note: AltScreen and Mouse are already enabled via tea.NewProgram options in main.go
 func (a App) Init() tea.Cmd {
 	if a.startWithLogView {
 		// Initialize log view directly (no tutorial when starting directly in logview)
 		a.logview = logview.New(a.selectedConts, a.dockerClient, a.width, a.height, common.Tutorial{})
-		return a.logview.Init()
+		return tea.Batch(a.logview.Init(), a.watcher.Init())
 	}
-	return a.discovery.Init()
+	return tea.Batch(a.discovery.Init(), a.startupToast(), a.watcher.Init())
 }
 
 // Update handles messages
@@ -70,6 +104,11 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, tea.Quit
 		}
 
+	case common.ConfigWatchTickMsg, common.ConfigFileEventMsg:
+		var cmd tea.Cmd
+		a.watcher, cmd = a.watcher.Update(msg)
+		return a, cmd
+
 	case tea.WindowSizeMsg:
 		a.width = msg.Width
 		a.height = msg.Height
@@ -77,7 +116,8 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if a.startWithLogView && a.screen == ScreenLogView {
 			a.logview = logview.New(a.selectedConts, a.dockerClient, a.width, a.height, common.Tutorial{})
 			a.startWithLogView = false
-			return a, a.logview.Init()
+			// The toast waits for the log view to exist so it isn't sent to a zero model
+			return a, tea.Batch(a.logview.Init(), a.startupToast())
 		}
 
 	case discovery.ContainerSelectedMsg:
@@ -87,6 +127,13 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.screen = ScreenLogView
 		return a, a.logview.Init()
 
+	case logview.ComposeLogsMsg:
+		// Swap the panes for one following the whole project; going back
+		// still returns to discovery with the original selection
+		a.logview.Cleanup()
+		a.logview = logview.New([]docker.Container{docker.ComposeLogsContainer(msg.Container)}, a.dockerClient, a.width, a.height, a.logview.GetTutorial())
+		return a, a.logview.Init()
+
 	case logview.BackToDiscoveryMsg:
 		// Go back to discovery, preserving selection
 		a.logview.Cleanup()
@@ -113,6 +160,14 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+// Cleanup releases the current screen's resources and the config watcher before the program exits
+func (a App) Cleanup() {
+	if a.screen == ScreenLogView {
+		a.logview.Cleanup()
+	}
+	a.watcher.Close()
+}
+
 // View renders the application
 func (a App) View() string {
 	var content string
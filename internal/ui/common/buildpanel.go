@@ -2,14 +2,22 @@ package common
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"cm/internal/config"
+	"cm/internal/debug"
 	"cm/internal/docker"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // BuildPanelLogMsg is sent when a new log line is received
@@ -26,6 +34,11 @@ type BuildPanelCompleteMsg struct {
 // BuildPanelCloseMsg is sent when the build panel should close
 type BuildPanelCloseMsg struct{}
 
+// BuildPanelTickMsg re-renders the elapsed timer while an operation runs
+type BuildPanelTickMsg struct {
+	run int // Operation the tick belongs to, so restarts don't double the tick rate
+}
+
 // BuildPanel represents a build log panel component
 type BuildPanel struct {
 	visible     bool
@@ -35,42 +48,145 @@ type BuildPanel struct {
 	height      int
 	operation   string // "build", "up", "down"
 	serviceName string
+	project     string
 	status      string // "running", "success", "error"
 	autoClose   bool   // whether to auto-close after completion
 	closeTimer  *time.Timer
+	utc         bool // render timestamps in UTC
+	millis      bool // render timestamps with millisecond precision
+	persistLogs bool // write the full log to disk on completion
+	startedAt   time.Time
+	finishedAt  time.Time
+	run         int  // incremented by Start
+	follow      bool // keep the newest output in view; off while scrolled up
+	search      ModalSearch
+	stages      map[string]buildStage // build progress per Dockerfile stage, from BuildKit's plain output
+}
+
+// buildStage is how far the build of one Dockerfile stage has got
+type buildStage struct {
+	step, steps int
 }
 
+// buildStepPattern matches the BuildKit plain progress lines of Dockerfile
+// steps, like "#12 [api builder 3/5] RUN npm ci", capturing the stage, the
+// step and the stage's step count
+var buildStepPattern = regexp.MustCompile(`^#\d+ \[(?:(.*) )?(\d+)/(\d+)\]`)
+
 // NewBuildPanel creates a new build panel
 func NewBuildPanel() BuildPanel {
 	vp := viewport.New(40, 20)
 	vp.Style = lipgloss.NewStyle()
+	var display config.DisplaySettings
+	var builds config.BuildSettings
+	if cfg, err := config.Load(); err == nil {
+		display = cfg.GetDisplaySettings()
+		builds = cfg.GetBuildSettings()
+	}
 	return BuildPanel{
-		viewport: vp,
-		status:   "idle",
+		viewport:    vp,
+		status:      "idle",
+		utc:         display.UTCTimestamps,
+		millis:      display.MillisecondTimestamps,
+		persistLogs: builds.PersistLogs,
 	}
 }
 
-// Start starts showing build logs for an operation
-func (b *BuildPanel) Start(operation, serviceName string) {
+// ReloadConfig re-reads the timestamp and build log settings, e.g. after
+// config.json changed, and re-renders the log
+func (b *BuildPanel) ReloadConfig() {
+	cfg, _ := config.Load()
+	if cfg == nil {
+		return
+	}
+	display := cfg.GetDisplaySettings()
+	b.utc = display.UTCTimestamps
+	b.millis = display.MillisecondTimestamps
+	b.persistLogs = cfg.GetBuildSettings().PersistLogs
+	b.viewport.SetContent(b.renderLogs())
+}
+
+// Start starts showing build logs for an operation and returns the command
+// that drives the elapsed timer
+func (b *BuildPanel) Start(operation, project, serviceName string) tea.Cmd {
 	b.visible = true
 	b.logs = nil
 	b.operation = operation
+	b.project = project
 	b.serviceName = serviceName
 	b.status = "running"
 	b.autoClose = true
+	b.startedAt = time.Now()
+	b.finishedAt = time.Time{}
+	b.run++
+	b.follow = true
+	b.search.Clear()
+	b.stages = nil
 	b.viewport.SetContent("")
 	b.viewport.GotoTop()
+	return b.tick()
+}
+
+// tick schedules the next elapsed timer update
+func (b *BuildPanel) tick() tea.Cmd {
+	run := b.run
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return BuildPanelTickMsg{run: run}
+	})
+}
+
+// Elapsed returns how long the current operation has been running, or how
+// long it took once complete
+func (b *BuildPanel) Elapsed() time.Duration {
+	if b.startedAt.IsZero() {
+		return 0
+	}
+	if !b.finishedAt.IsZero() {
+		return b.finishedAt.Sub(b.startedAt)
+	}
+	return time.Since(b.startedAt)
 }
 
 // AddLog adds a log line to the panel
 func (b *BuildPanel) AddLog(log docker.OperationLog) {
 	b.logs = append(b.logs, log)
+	b.search.Add(len(b.logs)-1, log.Content)
+	b.trackProgress(log.Content)
 	b.viewport.SetContent(b.renderLogs())
-	b.viewport.GotoBottom()
+	if b.follow {
+		b.viewport.GotoBottom()
+	}
+}
+
+// trackProgress notes the Dockerfile step a BuildKit progress line reports
+func (b *BuildPanel) trackProgress(line string) {
+	m := buildStepPattern.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+	step, _ := strconv.Atoi(m[2])
+	steps, _ := strconv.Atoi(m[3])
+	if b.stages == nil {
+		b.stages = make(map[string]buildStage)
+	}
+	if stage := b.stages[m[1]]; step > stage.step || steps != stage.steps {
+		b.stages[m[1]] = buildStage{step: step, steps: steps}
+	}
+}
+
+// Progress returns the Dockerfile steps reached and the step count of the
+// stages seen so far, summed over stages and services
+func (b *BuildPanel) Progress() (step, steps int) {
+	for _, stage := range b.stages {
+		step += stage.step
+		steps += stage.steps
+	}
+	return step, steps
 }
 
 // Complete marks the operation as complete
 func (b *BuildPanel) Complete(success bool, err error) tea.Cmd {
+	b.finishedAt = time.Now()
 	if success {
 		b.status = "success"
 		b.AddLog(docker.OperationLog{
@@ -91,6 +207,18 @@ func (b *BuildPanel) Complete(success bool, err error) tea.Cmd {
 		})
 	}
 
+	if b.persistLogs {
+		if path, err := b.saveLog(); err != nil {
+			debug.For("buildpanel").Warn("failed to save log: %v", err)
+		} else {
+			b.AddLog(docker.OperationLog{
+				Timestamp: time.Now(),
+				Stream:    "system",
+				Content:   fmt.Sprintf("--- Log saved to %s ---", path),
+			})
+		}
+	}
+
 	// Auto-close after 2 seconds if successful
 	if b.autoClose && success {
 		return tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
@@ -105,6 +233,48 @@ func (b *BuildPanel) Close() {
 	b.visible = false
 	b.logs = nil
 	b.status = "idle"
+	b.search.Clear()
+}
+
+// CapturesKey reports whether the panel handles a key that would otherwise
+// close it (esc/q while typing a search, esc to clear an applied search)
+func (b *BuildPanel) CapturesKey(k string) bool {
+	return b.search.CapturesKey(k)
+}
+
+// SetSearch applies a search query, highlighting matches and jumping to the first
+func (b *BuildPanel) SetSearch(query string) int {
+	n := b.search.Set(query, b.logContents())
+	b.viewport.SetContent(b.renderLogs())
+	b.jumpToMatch(0)
+	return n
+}
+
+// logContents returns the content of every log line, for searching
+func (b *BuildPanel) logContents() []string {
+	contents := make([]string, len(b.logs))
+	for i, log := range b.logs {
+		contents[i] = log.Content
+	}
+	return contents
+}
+
+// jumpToMatch selects the match delta away from the current one, centers it
+// in the viewport and stops auto-scroll so streaming output doesn't pull the
+// view away from it
+func (b *BuildPanel) jumpToMatch(delta int) {
+	line, ok := b.search.Step(delta)
+	if !ok {
+		return
+	}
+	b.viewport.SetContent(b.renderLogs())
+	b.viewport.SetYOffset(scrollOffset(line, b.viewport.Height))
+	b.follow = b.viewport.AtBottom()
+}
+
+// containsFold reports whether s contains substr, ignoring case and ANSI styling
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(ansi.Strip(s)), strings.ToLower(substr))
 }
 
 // IsVisible returns whether the panel is visible
@@ -138,10 +308,34 @@ func (b BuildPanel) Update(msg tea.Msg) (BuildPanel, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if b.search.Typing() {
+			if b.search.Update(msg) {
+				b.SetSearch(b.search.Query())
+			}
+			return b, nil
+		}
 		switch msg.String() {
-		case "esc", "q":
+		case "esc":
+			if b.search.Query() != "" {
+				b.SetSearch("")
+				return b, nil
+			}
 			b.Close()
 			return b, nil
+		case "q":
+			b.Close()
+			return b, nil
+		case "y":
+			return b, b.copyLogs()
+		case "/":
+			b.search.Start()
+			return b, nil
+		case "n":
+			b.jumpToMatch(1)
+			return b, nil
+		case "N":
+			b.jumpToMatch(-1)
+			return b, nil
 		case "up", "k":
 			b.viewport.SetYOffset(b.viewport.YOffset - 1)
 		case "down", "j":
@@ -155,6 +349,8 @@ func (b BuildPanel) Update(msg tea.Msg) (BuildPanel, tea.Cmd) {
 		case "G":
 			b.viewport.GotoBottom()
 		}
+		// Scrolling up pauses auto-scroll; reaching the bottom resumes it
+		b.follow = b.viewport.AtBottom()
 	case tea.MouseMsg:
 		if msg.Action == tea.MouseActionPress {
 			switch msg.Button {
@@ -163,6 +359,7 @@ func (b BuildPanel) Update(msg tea.Msg) (BuildPanel, tea.Cmd) {
 			case tea.MouseButtonWheelDown:
 				b.viewport.SetYOffset(b.viewport.YOffset + 3)
 			}
+			b.follow = b.viewport.AtBottom()
 		}
 	case BuildPanelLogMsg:
 		b.AddLog(msg.Log)
@@ -170,11 +367,89 @@ func (b BuildPanel) Update(msg tea.Msg) (BuildPanel, tea.Cmd) {
 		return b, b.Complete(msg.Success, msg.Error)
 	case BuildPanelCloseMsg:
 		b.Close()
+	case BuildPanelTickMsg:
+		// Keep ticking only for the operation that is still running
+		if msg.run == b.run && b.status == "running" {
+			return b, b.tick()
+		}
 	}
 
 	return b, nil
 }
 
+// formatTimestamp formats a log timestamp using the display settings
+func (b *BuildPanel) formatTimestamp(t time.Time) string {
+	if b.utc {
+		t = t.UTC()
+	}
+	if b.millis {
+		return t.Format("15:04:05.000")
+	}
+	return t.Format("15:04:05")
+}
+
+// PlainText returns the accumulated output without styling, headed by the
+// operation and service name, for copying
+func (b *BuildPanel) PlainText() string {
+	var sb strings.Builder
+	header := fmt.Sprintf("%s %s", b.capitalizeOp(), b.serviceName)
+	if b.status != "running" && b.status != "idle" {
+		header += fmt.Sprintf(" (%s, %s)", b.status, formatElapsed(b.Elapsed()))
+	}
+	sb.WriteString(header + "\n\n")
+	for _, log := range b.logs {
+		sb.WriteString(fmt.Sprintf("%s %s\n", b.formatTimestamp(log.Timestamp), ansi.Strip(log.Content)))
+	}
+	return sb.String()
+}
+
+// saveLog writes the build output to
+// <config dir>/builds/<project>-<service>-<timestamp>.log and returns the path
+func (b *BuildPanel) saveLog() (string, error) {
+	dir := config.GetBuildLogsDir()
+	if dir == "" {
+		return "", fmt.Errorf("cannot determine home directory")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%s-%s-%s.log",
+		SanitizeFileName(b.project), SanitizeFileName(b.serviceName),
+		b.startedAt.Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(b.PlainText()), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// SanitizeFileName replaces characters that don't belong in a file name
+func SanitizeFileName(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '-'
+	}, s)
+}
+
+// copyLogs copies the build output to the clipboard and reports the result as a toast
+func (b *BuildPanel) copyLogs() tea.Cmd {
+	if len(b.logs) == 0 {
+		return nil
+	}
+	toast := ShowToastMsg{Title: "Copied", Message: fmt.Sprintf("%d lines", len(b.logs)), Type: ToastSuccess}
+	if err := clipboard.WriteAll(b.PlainText()); err != nil {
+		toast = ShowToastMsg{Title: "Copy failed", Message: err.Error(), Type: ToastError}
+	}
+	return func() tea.Msg { return toast }
+}
+
 // renderLogs renders the log content
 func (b *BuildPanel) renderLogs() string {
 	if len(b.logs) == 0 {
@@ -182,13 +457,14 @@ func (b *BuildPanel) renderLogs() string {
 	}
 
 	var sb strings.Builder
-	for _, log := range b.logs {
-		ts := TimestampStyle.Render(log.Timestamp.Format("15:04:05"))
-		var content string
-		switch log.Stream {
-		case "stderr":
+	for i, log := range b.logs {
+		ts := TimestampStyle.Render(b.formatTimestamp(log.Timestamp))
+		content, matched := b.search.Highlight(i, log.Content)
+		switch {
+		case matched:
+		case log.Stream == "stderr":
 			content = StderrStyle.Render(log.Content)
-		case "system":
+		case log.Stream == "system":
 			content = SubtitleStyle.Render(log.Content)
 		default:
 			content = log.Content
@@ -198,6 +474,37 @@ func (b *BuildPanel) renderLogs() string {
 	return sb.String()
 }
 
+// highlightFold highlights every case-insensitive occurrence of query in text,
+// using a distinct color for the current match
+func highlightFold(text, query string, current bool) string {
+	style := SearchMatchStyle
+	if current {
+		style = SearchCurrentMatchStyle
+	}
+	// Compare rune by rune so offsets stay valid for non-ASCII text
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 || len(lower) != len(runes) {
+		return text
+	}
+
+	var sb strings.Builder
+	last := 0
+	for i := 0; i+len(q) <= len(runes); {
+		if string(lower[i:i+len(q)]) != string(q) {
+			i++
+			continue
+		}
+		sb.WriteString(string(runes[last:i]))
+		sb.WriteString(style.Render(string(runes[i : i+len(q)])))
+		i += len(q)
+		last = i
+	}
+	sb.WriteString(string(runes[last:]))
+	return sb.String()
+}
+
 // View renders the build panel
 func (b BuildPanel) View() string {
 	if !b.visible {
@@ -224,9 +531,30 @@ func (b BuildPanel) View() string {
 	)
 	statusText := statusStyle.Render(statusIcon)
 	titleLine := title + statusText
+	if step, steps := b.Progress(); steps > 0 && b.status == "running" {
+		titleLine += MutedInlineStyle.Render(fmt.Sprintf(" step %d of %d", step, steps))
+	}
+	if elapsed := b.Elapsed(); elapsed > 0 {
+		titleLine += MutedInlineStyle.Render(" " + formatElapsed(elapsed))
+	}
 
 	// Help text
-	helpText := MutedInlineStyle.Render(" esc: close  ↑↓: scroll")
+	var helpText string
+	if b.search.Typing() {
+		helpText = " " + b.search.Prompt()
+	} else {
+		help := " esc: close  ↑↓: scroll  /: search  y: copy"
+		if b.search.Query() != "" {
+			help = " " + b.search.Status()
+		}
+		if !b.follow {
+			help += "  G: follow"
+		}
+		helpText = MutedInlineStyle.Render(help)
+		if !b.follow && b.status == "running" {
+			helpText += lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Render(" [scroll paused]")
+		}
+	}
 
 	// Viewport content
 	content := lipgloss.JoinVertical(lipgloss.Left,
@@ -245,6 +573,19 @@ func (b BuildPanel) View() string {
 	return borderStyle.Render(content)
 }
 
+// formatElapsed formats a duration as 42s, 4m12s or 1h02m
+func formatElapsed(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
 func (b *BuildPanel) capitalizeOp() string {
 	if b.operation == "" {
 		return "Build"
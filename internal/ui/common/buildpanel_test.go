@@ -0,0 +1,266 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cm/internal/config"
+	"cm/internal/docker"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newTestBuildPanel(t *testing.T) BuildPanel {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	b := NewBuildPanel()
+	b.SetSize(80, 20)
+	return b
+}
+
+func TestFormatElapsed(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{42*time.Second + 300*time.Millisecond, "42s"},
+		{4*time.Minute + 12*time.Second, "4m12s"},
+		{time.Hour + 2*time.Minute + 5*time.Second, "1h02m"},
+	}
+	for _, tt := range tests {
+		if got := formatElapsed(tt.d); got != tt.want {
+			t.Fatalf("formatElapsed(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestBuildPanelElapsedStopsOnComplete(t *testing.T) {
+	b := newTestBuildPanel(t)
+	if cmd := b.Start("build", "shop", "api"); cmd == nil {
+		t.Fatalf("expected Start to schedule the elapsed timer")
+	}
+	b.startedAt = time.Now().Add(-4*time.Minute - 12*time.Second)
+
+	if !strings.Contains(b.View(), "4m12s") {
+		t.Fatalf("expected running elapsed time in the title")
+	}
+
+	b.Complete(false, nil)
+	frozen := b.Elapsed()
+	time.Sleep(10 * time.Millisecond)
+	if b.Elapsed() != frozen {
+		t.Fatalf("expected elapsed time to stop once complete")
+	}
+
+	// The timer chain ends once the operation is no longer running
+	if _, cmd := b.Update(BuildPanelTickMsg{run: b.run}); cmd != nil {
+		t.Fatalf("expected no further ticks after completion")
+	}
+}
+
+func TestBuildPanelShowsBuildKitStepProgress(t *testing.T) {
+	b := newTestBuildPanel(t)
+	b.Start("build", "shop", "api")
+	for _, line := range []string{
+		"#1 [internal] load build definition from Dockerfile",
+		"#5 [api builder 1/3] FROM docker.io/library/golang:1.24",
+		"#6 [api builder 2/3] COPY . .",
+		"#9 [api stage-1 1/2] FROM docker.io/library/alpine",
+		"#6 [api builder 2/3] COPY . .",
+		"#6 DONE 0.3s",
+	} {
+		b.AddLog(docker.OperationLog{Content: line})
+	}
+
+	if step, steps := b.Progress(); step != 3 || steps != 5 {
+		t.Fatalf("Progress() = %d, %d; want 3, 5", step, steps)
+	}
+	if !strings.Contains(b.View(), "step 3 of 5") {
+		t.Fatalf("expected the step count in the title, got:\n%s", b.View())
+	}
+	if len(b.logs) != 6 {
+		t.Fatalf("expected every line kept in the log, got %d", len(b.logs))
+	}
+
+	b.Complete(true, nil)
+	if strings.Contains(b.View(), "step 3 of 5") {
+		t.Fatalf("expected the step count to go once the build is done")
+	}
+}
+
+func TestBuildPanelIgnoresTicksFromPreviousRun(t *testing.T) {
+	b := newTestBuildPanel(t)
+	b.Start("build", "shop", "api")
+	stale := b.run
+	b.Start("up", "shop", "api")
+
+	if _, cmd := b.Update(BuildPanelTickMsg{run: stale}); cmd != nil {
+		t.Fatalf("expected stale tick to be dropped")
+	}
+	if _, cmd := b.Update(BuildPanelTickMsg{run: b.run}); cmd == nil {
+		t.Fatalf("expected current tick to reschedule")
+	}
+}
+
+func TestBuildPanelPausesAutoScrollWhenScrolledUp(t *testing.T) {
+	b := newTestBuildPanel(t)
+	b.Start("build", "shop", "api")
+	for i := 0; i < 50; i++ {
+		b.AddLog(docker.OperationLog{Timestamp: time.Now(), Stream: "stdout", Content: "step"})
+	}
+	if !b.viewport.AtBottom() {
+		t.Fatalf("expected panel to follow output while streaming")
+	}
+
+	b, _ = b.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	offset := b.viewport.YOffset
+	b.AddLog(docker.OperationLog{Timestamp: time.Now(), Stream: "stdout", Content: "more"})
+	if b.viewport.YOffset != offset {
+		t.Fatalf("expected scroll position to hold after scrolling up, moved %d -> %d", offset, b.viewport.YOffset)
+	}
+	if !strings.Contains(b.View(), "scroll paused") {
+		t.Fatalf("expected paused indicator")
+	}
+
+	b, _ = b.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	b.AddLog(docker.OperationLog{Timestamp: time.Now(), Stream: "stdout", Content: "again"})
+	if !b.viewport.AtBottom() {
+		t.Fatalf("expected auto-scroll to resume at the bottom")
+	}
+}
+
+func TestBuildPanelSearchFindsAndCyclesMatches(t *testing.T) {
+	b := newTestBuildPanel(t)
+	b.Start("build", "shop", "api")
+	for i := 0; i < 40; i++ {
+		content := "step ok"
+		if i == 5 || i == 30 {
+			content = "\x1b[31mERROR\x1b[0m: failed to fetch"
+		}
+		b.AddLog(docker.OperationLog{Timestamp: time.Now(), Stream: "stdout", Content: content})
+	}
+
+	// Type "/error" and apply it
+	b, _ = b.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if !b.CapturesKey("q") {
+		t.Fatalf("expected search input to capture q")
+	}
+	b, _ = b.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("error")})
+	b, _ = b.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if len(b.search.matches) != 2 || b.search.matches[0] != 5 || b.search.matches[1] != 30 {
+		t.Fatalf("expected matches on lines 5 and 30, got %v", b.search.matches)
+	}
+	if b.search.current != 0 || b.follow {
+		t.Fatalf("expected first match selected with auto-scroll paused")
+	}
+
+	b, _ = b.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if b.search.current != 1 {
+		t.Fatalf("expected next match, got %d", b.search.current)
+	}
+	b, _ = b.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if b.search.current != 0 {
+		t.Fatalf("expected wrap to first match, got %d", b.search.current)
+	}
+
+	// New output matching the query is picked up while streaming
+	b.AddLog(docker.OperationLog{Timestamp: time.Now(), Stream: "stderr", Content: "another error"})
+	if len(b.search.matches) != 3 {
+		t.Fatalf("expected streamed match to be added, got %v", b.search.matches)
+	}
+
+	// esc clears the search instead of closing the panel
+	if !b.CapturesKey("esc") {
+		t.Fatalf("expected esc to be captured while a search is applied")
+	}
+	b, _ = b.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if !b.IsVisible() || b.search.query != "" || len(b.search.matches) != 0 {
+		t.Fatalf("expected esc to clear search and keep panel open")
+	}
+}
+
+func TestHighlightFold(t *testing.T) {
+	got := highlightFold("Error: ERROR été", "error", false)
+	if strings.Count(got, "Error") != 1 || strings.Count(got, "ERROR") != 1 || !strings.HasSuffix(got, " été") {
+		t.Fatalf("expected both matches highlighted with original casing kept, got %q", got)
+	}
+	if got := highlightFold("café", "fé", false); !strings.HasPrefix(got, "ca") {
+		t.Fatalf("expected non-ASCII offsets to be preserved, got %q", got)
+	}
+}
+
+func TestBuildPanelPlainTextHasHeaderAndNoStyling(t *testing.T) {
+	b := newTestBuildPanel(t)
+	b.Start("build", "shop", "api")
+	ts := time.Date(2024, 1, 15, 10, 30, 45, 0, time.Local)
+	b.AddLog(docker.OperationLog{Timestamp: ts, Stream: "stdout", Content: "\x1b[32mStep 1/3\x1b[0m"})
+	b.AddLog(docker.OperationLog{Timestamp: ts, Stream: "stderr", Content: "ERROR: boom"})
+	b.Complete(false, nil)
+
+	text := b.PlainText()
+	lines := strings.Split(text, "\n")
+	if !strings.HasPrefix(lines[0], "Build api (error, ") {
+		t.Fatalf("expected operation header, got %q", lines[0])
+	}
+	if lines[2] != "10:30:45 Step 1/3" || lines[3] != "10:30:45 ERROR: boom" {
+		t.Fatalf("unexpected body: %q", lines[2:4])
+	}
+	if strings.Contains(text, "\x1b") {
+		t.Fatalf("expected copied text to be free of escape sequences")
+	}
+}
+
+func TestBuildPanelPersistsLogOnComplete(t *testing.T) {
+	b := newTestBuildPanel(t)
+	b.persistLogs = true
+	b.Start("build", "shop", "2 services")
+	b.AddLog(docker.OperationLog{Timestamp: time.Now(), Stream: "stdout", Content: "Step 1/3"})
+	b.Complete(true, nil)
+
+	entries, err := os.ReadDir(config.GetBuildLogsDir())
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one persisted log, got %v (%v)", entries, err)
+	}
+	name := entries[0].Name()
+	if !strings.HasPrefix(name, "shop-2-services-") || !strings.HasSuffix(name, ".log") {
+		t.Fatalf("unexpected log file name %q", name)
+	}
+	data, _ := os.ReadFile(filepath.Join(config.GetBuildLogsDir(), name))
+	if !strings.Contains(string(data), "Step 1/3") || !strings.Contains(string(data), "completed successfully") {
+		t.Fatalf("expected full output in persisted log, got %q", data)
+	}
+}
+
+func TestBuildPanelDoesNotPersistByDefault(t *testing.T) {
+	b := newTestBuildPanel(t)
+	b.Start("build", "shop", "api")
+	b.Complete(true, nil)
+
+	if _, err := os.Stat(config.GetBuildLogsDir()); !os.IsNotExist(err) {
+		t.Fatalf("expected no builds directory without persist_logs, got %v", err)
+	}
+}
+
+func TestBuildPanelReloadConfigAppliesTimestampSettings(t *testing.T) {
+	b := newTestBuildPanel(t)
+	b.Start("build", "shop", "api")
+	b.AddLog(docker.OperationLog{Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 678e6, time.UTC), Content: "step"})
+
+	cfg := &config.Config{Display: &config.DisplaySettings{UTCTimestamps: true, MillisecondTimestamps: true}}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	b.ReloadConfig()
+	if !b.utc || !b.millis {
+		t.Fatalf("expected the reload to pick up the timestamp settings")
+	}
+	if !strings.Contains(b.View(), "03:04:05.678") {
+		t.Fatalf("expected the log re-rendered with UTC milliseconds, got:\n%s", b.View())
+	}
+}
@@ -0,0 +1,160 @@
+package common
+
+import (
+	"strings"
+
+	"cm/internal/config"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// BuildPromptConfirmedMsg is sent when the user confirms a build context
+type BuildPromptConfirmedMsg struct {
+	ContainerID   string
+	ContainerName string
+	BuildContext  config.BuildContext
+}
+
+// BuildPrompt asks for the build context and Dockerfile of a standalone
+// container before rebuilding its image
+type BuildPrompt struct {
+	visible       bool
+	containerID   string
+	containerName string
+	contextInput  textinput.Model
+	fileInput     textinput.Model
+	focusFile     bool // Dockerfile input has focus
+}
+
+// NewBuildPrompt creates a new build prompt
+func NewBuildPrompt() BuildPrompt {
+	ctxInput := textinput.New()
+	ctxInput.Placeholder = "path/to/context"
+	ctxInput.CharLimit = 256
+	ctxInput.Width = 30
+
+	fileInput := textinput.New()
+	fileInput.Placeholder = "Dockerfile (optional)"
+	fileInput.CharLimit = 256
+	fileInput.Width = 24
+
+	return BuildPrompt{
+		contextInput: ctxInput,
+		fileInput:    fileInput,
+	}
+}
+
+// Open shows the prompt for a container, prefilled with its remembered build context
+func (p *BuildPrompt) Open(containerID, containerName string, bc config.BuildContext) tea.Cmd {
+	p.visible = true
+	p.containerID = containerID
+	p.containerName = containerName
+	p.contextInput.SetValue(bc.Context)
+	p.fileInput.SetValue(bc.Dockerfile)
+	p.contextInput.CursorEnd()
+	p.fileInput.CursorEnd()
+	p.setFocus(false)
+	return textinput.Blink
+}
+
+// Close hides the prompt
+func (p *BuildPrompt) Close() {
+	p.visible = false
+	p.contextInput.Blur()
+	p.fileInput.Blur()
+}
+
+// IsVisible returns whether the prompt is visible
+func (p BuildPrompt) IsVisible() bool {
+	return p.visible
+}
+
+// setFocus moves focus between the context and Dockerfile inputs
+func (p *BuildPrompt) setFocus(file bool) {
+	p.focusFile = file
+	if file {
+		p.contextInput.Blur()
+		p.fileInput.Focus()
+	} else {
+		p.fileInput.Blur()
+		p.contextInput.Focus()
+	}
+}
+
+// Update handles messages for the prompt
+func (p BuildPrompt) Update(msg tea.Msg) (BuildPrompt, tea.Cmd) {
+	if !p.visible {
+		return p, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc":
+		p.Close()
+		return p, nil
+
+	case "tab", "shift+tab":
+		p.setFocus(!p.focusFile)
+		return p, nil
+
+	case "enter":
+		bc := config.BuildContext{
+			Context:    strings.TrimSpace(p.contextInput.Value()),
+			Dockerfile: strings.TrimSpace(p.fileInput.Value()),
+		}
+		if bc.Context == "" {
+			p.setFocus(false)
+			return p, nil
+		}
+		p.Close()
+		confirmed := BuildPromptConfirmedMsg{
+			ContainerID:   p.containerID,
+			ContainerName: p.containerName,
+			BuildContext:  bc,
+		}
+		return p, func() tea.Msg { return confirmed }
+	}
+
+	var cmd tea.Cmd
+	if p.focusFile {
+		p.fileInput, cmd = p.fileInput.Update(CleanPaste(keyMsg))
+	} else {
+		p.contextInput, cmd = p.contextInput.Update(CleanPaste(keyMsg))
+	}
+	return p, cmd
+}
+
+// View renders the prompt as a single bar
+func (p BuildPrompt) View(screenWidth int) string {
+	if !p.visible {
+		return ""
+	}
+
+	label := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("117")).
+		Bold(true).
+		Render("Build " + p.containerName)
+
+	parts := []string{
+		label,
+		MutedInlineStyle.Render("  context: "),
+		p.contextInput.View(),
+		MutedInlineStyle.Render("  file: "),
+		p.fileInput.View(),
+		MutedInlineStyle.Render("  tab:switch enter:build esc:cancel"),
+	}
+
+	barStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("236")).
+		Foreground(lipgloss.Color("252")).
+		Padding(0, 1).
+		Width(screenWidth)
+
+	return barStyle.Render(strings.Join(parts, ""))
+}
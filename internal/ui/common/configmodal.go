@@ -10,7 +10,6 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
 // ConfigModalClosedMsg is sent when the config modal is closed
@@ -33,6 +32,7 @@ const (
 	ItemNotificationMode ConfigModalItem = iota
 	ItemToastDuration
 	ItemToastPosition
+	ItemRecordKeyBindings
 	ItemEditKeyBindings
 	ItemResetKeyBindings
 	ItemResetAll
@@ -50,11 +50,13 @@ type ConfigModal struct {
 	originalCfg  config.Config // To detect changes
 
 	// Current values being edited
-	notifyMode       config.NotificationMode
-	toastDuration    int
-	toastPosition    config.ToastPosition
-	keyBindings      config.KeyBindings
-	keyBindingsReset bool // Track if key bindings were reset this session
+	notifyMode        config.NotificationMode
+	toastDuration     int
+	toastPosition     config.ToastPosition
+	keyBindings       config.KeyBindings
+	keyBindingsReset  bool // Track if key bindings were reset this session
+	keyBindingsEdited bool // Track if keys were recorded in the key editor
+	keyEditor         KeyEditor
 }
 
 // NewConfigModal creates a new config modal
@@ -74,7 +76,8 @@ func (m *ConfigModal) Open() tea.Cmd {
 
 	m.cfg = cfg
 	m.originalCfg = *cfg
-	settings := cfg.GetNotificationSettings()
+	// Env overrides are left out, so saving doesn't write them to config.json
+	settings := cfg.SavedNotificationSettings()
 	m.notifyMode = settings.Mode
 	m.toastDuration = settings.GetToastDuration()
 	m.toastPosition = settings.GetToastPosition()
@@ -82,6 +85,8 @@ func (m *ConfigModal) Open() tea.Cmd {
 	m.visible = true
 	m.selectedItem = ItemNotificationMode
 	m.keyBindingsReset = false
+	m.keyBindingsEdited = false
+	m.keyEditor.Close()
 
 	return nil
 }
@@ -100,6 +105,7 @@ func (m ConfigModal) IsVisible() bool {
 func (m *ConfigModal) SetSize(width, height int) {
 	m.width = width
 	m.height = height
+	m.keyEditor.SetHeight(height)
 }
 
 // Update handles messages for the modal
@@ -108,6 +114,16 @@ func (m ConfigModal) Update(msg tea.Msg) (ConfigModal, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.keyEditor.IsVisible() {
+		var cmd tea.Cmd
+		m.keyEditor, cmd = m.keyEditor.Update(msg)
+		if !m.keyEditor.IsVisible() && m.keyEditor.Changed() {
+			m.keyBindings = m.keyEditor.Bindings()
+			m.keyBindingsEdited = true
+		}
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
@@ -180,6 +196,9 @@ func (m *ConfigModal) handleSelect() (ConfigModal, tea.Cmd) {
 	case ItemToastPosition:
 		m.toastPosition = m.nextToastPosition()
 
+	case ItemRecordKeyBindings:
+		m.keyEditor.Open(m.keyBindings)
+
 	case ItemEditKeyBindings:
 		// Open keybindings file in editor
 		kbPath := config.GetKeybindingsPath()
@@ -198,17 +217,19 @@ func (m *ConfigModal) handleSelect() (ConfigModal, tea.Cmd) {
 		m.keyBindingsReset = true
 
 	case ItemSave:
+		unboundKeys := m.cfg.Notifications != nil && m.cfg.Notifications.UnboundKeys
 		m.cfg.Notifications = &config.NotificationSettings{
 			Mode:          m.notifyMode,
 			ToastDuration: m.toastDuration,
 			ToastPosition: m.toastPosition,
+			UnboundKeys:   unboundKeys,
 		}
 		// Save config
 		if err := m.cfg.Save(); err != nil {
 			return *m, nil
 		}
-		// Save keybindings if reset
-		if m.keyBindingsReset {
+		// Save keybindings if reset or recorded
+		if m.keyBindingsReset || m.keyBindingsEdited {
 			_ = config.SaveKeyBindings(m.keyBindings)
 		}
 		m.visible = false
@@ -349,6 +370,10 @@ func (m ConfigModal) View(screenWidth, screenHeight int) string {
 
 	var content strings.Builder
 
+	if m.keyEditor.IsVisible() {
+		return PlaceModal(ModalStyle.Render(m.keyEditor.View()), screenWidth, screenHeight)
+	}
+
 	// Title
 	content.WriteString(ModalTitleStyle.Render("Configuration"))
 	content.WriteString("\n\n")
@@ -405,8 +430,20 @@ func (m ConfigModal) View(screenWidth, screenHeight int) string {
 	content.WriteString(keyStyle.Render(kb.Quit) + descStyle.Render(":quit"))
 	content.WriteString("\n\n")
 
+	// Record Key Bindings
+	recordKeyLabel := "[Record Key Bindings]"
+	if m.keyBindingsEdited {
+		recordKeyLabel = "[Record Key Bindings] ✓"
+	}
+	if m.selectedItem == ItemRecordKeyBindings {
+		content.WriteString(ModalSelectedStyle.Render("  " + recordKeyLabel))
+	} else {
+		content.WriteString(MutedInlineStyle.Render("  " + recordKeyLabel))
+	}
+	content.WriteString("\n")
+
 	// Edit Key Bindings
-	editKeyLabel := "[Edit Key Bindings]"
+	editKeyLabel := "[Edit Key Bindings File]"
 	if m.selectedItem == ItemEditKeyBindings {
 		content.WriteString(ModalSelectedStyle.Render("  " + editKeyLabel))
 	} else {
@@ -457,31 +494,7 @@ func (m ConfigModal) View(screenWidth, screenHeight int) string {
 	// Help
 	content.WriteString(MutedInlineStyle.Render("  j/k: navigate  h/l: change  enter: select  esc: close"))
 
-	// Style the modal
-	modalContent := ModalStyle.Render(content.String())
-
-	// Get modal dimensions
-	modalWidth := lipgloss.Width(modalContent)
-	modalHeight := lipgloss.Height(modalContent)
-
-	// Center the modal
-	x := (screenWidth - modalWidth) / 2
-	y := (screenHeight - modalHeight) / 2
-
-	if x < 0 {
-		x = 0
-	}
-	if y < 0 {
-		y = 0
-	}
-
-	// Create positioned modal
-	positioned := lipgloss.NewStyle().
-		MarginLeft(x).
-		MarginTop(y).
-		Render(modalContent)
-
-	return positioned
+	return PlaceModal(ModalStyle.Render(content.String()), screenWidth, screenHeight)
 }
 
 func (m ConfigModal) renderSelectItem(b *strings.Builder, item ConfigModalItem, label, value string) {
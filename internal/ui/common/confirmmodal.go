@@ -0,0 +1,116 @@
+package common
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ConfirmedMsg is sent when the user confirms the action of a confirm modal
+type ConfirmedMsg struct {
+	Action string
+}
+
+// ConfirmModal asks the user to confirm a destructive action
+type ConfirmModal struct {
+	visible      bool
+	action       string
+	title        string
+	message      string
+	confirmLabel string
+	focusConfirm bool // Cancel has focus by default
+}
+
+// NewConfirmModal creates a new confirm modal
+func NewConfirmModal() ConfirmModal {
+	return ConfirmModal{}
+}
+
+// Open shows the modal. action is passed back in ConfirmedMsg.
+func (m *ConfirmModal) Open(action, title, message, confirmLabel string) {
+	m.visible = true
+	m.action = action
+	m.title = title
+	m.message = message
+	m.confirmLabel = confirmLabel
+	m.focusConfirm = false
+}
+
+// Close hides the modal
+func (m *ConfirmModal) Close() {
+	m.visible = false
+}
+
+// IsVisible returns whether the modal is visible
+func (m ConfirmModal) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the modal
+func (m ConfirmModal) Update(msg tea.Msg) (ConfirmModal, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "n", "q":
+		m.Close()
+	case "left", "right", "h", "l", "tab", "shift+tab":
+		m.focusConfirm = !m.focusConfirm
+	case "y":
+		return m.confirm()
+	case "enter":
+		if m.focusConfirm {
+			return m.confirm()
+		}
+		m.Close()
+	}
+	return m, nil
+}
+
+// confirm closes the modal and sends its action
+func (m ConfirmModal) confirm() (ConfirmModal, tea.Cmd) {
+	m.Close()
+	confirmed := ConfirmedMsg{Action: m.action}
+	return m, func() tea.Msg { return confirmed }
+}
+
+// View renders the modal
+func (m ConfirmModal) View(screenWidth, screenHeight int) string {
+	if !m.visible {
+		return ""
+	}
+
+	var content strings.Builder
+
+	content.WriteString(ModalTitleStyle.Render(m.title))
+	content.WriteString("\n\n")
+	for _, line := range strings.Split(m.message, "\n") {
+		content.WriteString("  " + line + "\n")
+	}
+	content.WriteString("\n")
+
+	// Buttons row
+	confirmBtn := "  " + m.confirmLabel + "  "
+	cancelBtn := "  Cancel  "
+	if m.focusConfirm {
+		content.WriteString(ModalDangerButtonStyle.Render(confirmBtn))
+		content.WriteString(ModalButtonStyle.Render(cancelBtn))
+	} else {
+		content.WriteString(ModalButtonStyle.Render(confirmBtn))
+		content.WriteString(ModalButtonActiveStyle.Render(cancelBtn))
+	}
+	content.WriteString("\n\n")
+
+	content.WriteString(MutedInlineStyle.Render("  h/l: choose  enter: select  y: confirm  esc: cancel"))
+
+	// Style the modal
+	modalContent := ModalStyle.Render(content.String())
+
+	return PlaceModal(modalContent, screenWidth, screenHeight)
+}
@@ -0,0 +1,27 @@
+package common
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestConfirmModalDefaultsToCancel(t *testing.T) {
+	m := NewConfirmModal()
+	m.Open("prune", "Prune Containers", "Remove all stopped containers?", "Remove")
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil || m.IsVisible() {
+		t.Fatalf("expected enter on the default button to cancel")
+	}
+
+	m.Open("prune", "Prune Containers", "Remove all stopped containers?", "Remove")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil || m.IsVisible() {
+		t.Fatalf("expected enter on the confirm button to confirm")
+	}
+	if got := cmd().(ConfirmedMsg); got.Action != "prune" {
+		t.Fatalf("expected the prune action, got %q", got.Action)
+	}
+}
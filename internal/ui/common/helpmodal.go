@@ -6,6 +6,7 @@ import (
 	"cm/internal/config"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -15,25 +16,29 @@ type HelpModalClosedMsg struct{}
 
 // HelpModal represents the help modal
 type HelpModal struct {
-	visible bool
-	width   int
-	height  int
-	scroll  int
-	kb      config.KeyBindings
+	visible  bool
+	width    int
+	height   int
+	viewport viewport.Model
+	kb       config.KeyBindings
+	search   ModalSearch
 }
 
 // NewHelpModal creates a new help modal
 func NewHelpModal() HelpModal {
 	return HelpModal{
-		visible: false,
+		visible:  false,
+		viewport: viewport.New(60, 20),
 	}
 }
 
 // Open opens the modal
 func (m *HelpModal) Open() tea.Cmd {
 	m.visible = true
-	m.scroll = 0
 	m.kb = config.LoadKeyBindings()
+	m.search.Clear()
+	m.refresh()
+	m.viewport.GotoTop()
 	return nil
 }
 
@@ -51,6 +56,29 @@ func (m HelpModal) IsVisible() bool {
 func (m *HelpModal) SetSize(width, height int) {
 	m.width = width
 	m.height = height
+	// Leave room for the border, padding, title and footer
+	if height > 0 {
+		m.viewport.Height = height - 10
+		if m.viewport.Height < 5 {
+			m.viewport.Height = 5
+		}
+	}
+	m.refresh()
+}
+
+// refresh renders the shortcuts into the viewport, highlighting search
+// matches, and fits the viewport to the widest line the screen allows
+func (m *HelpModal) refresh() {
+	lines := m.search.HighlightLines(m.lines())
+	width := 0
+	for _, line := range lines {
+		width = max(width, lipgloss.Width(line))
+	}
+	if m.width > 0 {
+		width = min(width, m.width-8)
+	}
+	m.viewport.Width = max(width, 20)
+	m.viewport.SetContent(strings.Join(lines, "\n"))
 }
 
 // Update handles messages for the modal
@@ -61,36 +89,61 @@ func (m HelpModal) Update(msg tea.Msg) (HelpModal, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.search.Typing() {
+			if m.search.Update(msg) {
+				m.search.Find(m.lines())
+				m.jumpToMatch(0)
+			}
+			m.refresh()
+			return m, nil
+		}
 		switch {
+		case key.Matches(msg, key.NewBinding(key.WithKeys("esc"))) && m.search.Query() != "":
+			m.search.Clear()
+			m.refresh()
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("/"))):
+			m.search.Start()
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("n"))):
+			m.jumpToMatch(1)
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("N"))):
+			m.jumpToMatch(-1)
+
 		case key.Matches(msg, key.NewBinding(key.WithKeys("esc", "?", "q"))):
 			m.visible = false
 			return m, func() tea.Msg { return HelpModalClosedMsg{} }
 
 		case key.Matches(msg, key.NewBinding(key.WithKeys("up", "k"))):
-			if m.scroll > 0 {
-				m.scroll--
-			}
+			m.viewport.SetYOffset(m.viewport.YOffset - 1)
 
 		case key.Matches(msg, key.NewBinding(key.WithKeys("down", "j"))):
-			m.scroll++
+			m.viewport.SetYOffset(m.viewport.YOffset + 1)
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+u"))):
+			m.viewport.SetYOffset(m.viewport.YOffset - 5)
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+d"))):
+			m.viewport.SetYOffset(m.viewport.YOffset + 5)
 		}
 	}
 
 	return m, nil
 }
 
-// View renders the modal
-func (m HelpModal) View(screenWidth, screenHeight int) string {
-	if !m.visible {
-		return ""
+// jumpToMatch selects the search match delta away from the current one and
+// scrolls it into view
+func (m *HelpModal) jumpToMatch(delta int) {
+	line, ok := m.search.Step(delta)
+	m.refresh()
+	if ok {
+		m.viewport.SetYOffset(scrollOffset(line, m.viewport.Height))
 	}
+}
 
-	var content strings.Builder
-
-	// Title
-	content.WriteString(ModalTitleStyle.Render("Keyboard Shortcuts"))
-	content.WriteString("\n\n")
-
+// lines renders the shortcut sections, one entry per line
+func (m HelpModal) lines() []string {
 	keyStyle := HelpKeyStyle
 	descStyle := MutedInlineStyle
 	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("117"))
@@ -98,23 +151,29 @@ func (m HelpModal) View(screenWidth, screenHeight int) string {
 	// Helper to format key names nicely
 	formatKey := func(k string) string {
 		replacements := map[string]string{
-			"up":        "↑",
-			"down":      "↓",
-			"left":      "←",
-			"right":     "→",
-			"space":     "space",
-			"enter":     "enter",
-			"esc":       "esc",
-			"tab":       "tab",
-			"shift+tab": "shift+tab",
-			"ctrl+u":    "ctrl+u",
-			"ctrl+d":    "ctrl+d",
-			"ctrl+r":    "ctrl+r",
-			"ctrl+l":    "ctrl+l",
-			"ctrl+c":    "ctrl+c",
-			"ctrl+g":    "ctrl+g",
-			"{":         "{",
-			"}":         "}",
+			"up":          "↑",
+			"down":        "↓",
+			"left":        "←",
+			"right":       "→",
+			"space":       "space",
+			"enter":       "enter",
+			"esc":         "esc",
+			"tab":         "tab",
+			"shift+tab":   "shift+tab",
+			"ctrl+u":      "ctrl+u",
+			"ctrl+d":      "ctrl+d",
+			"ctrl+r":      "ctrl+r",
+			"ctrl+l":      "ctrl+l",
+			"ctrl+c":      "ctrl+c",
+			"ctrl+g":      "ctrl+g",
+			"ctrl+o":      "ctrl+o",
+			"ctrl+y":      "ctrl+y",
+			"shift+left":  "shift+←",
+			"shift+right": "shift+→",
+			"shift+up":    "shift+↑",
+			"shift+down":  "shift+↓",
+			"{":           "{",
+			"}":           "}",
 		}
 		// Handle comma-separated keys (e.g., "up,k")
 		parts := strings.Split(k, ",")
@@ -139,7 +198,14 @@ func (m HelpModal) View(screenWidth, screenHeight int) string {
 				{formatKey(m.kb.ScrollUp) + "/" + formatKey(m.kb.ScrollDown), "Scroll viewport up/down"},
 				{formatKey(m.kb.Top) + "/" + formatKey(m.kb.Bottom), "Go to top/bottom"},
 				{formatKey(m.kb.NextPane) + "/" + formatKey(m.kb.PrevPane), "Next/previous pane"},
+				{formatKey(m.kb.NewestPane), "Jump to the pane with the newest log line"},
+				{formatKey(m.kb.SyncScroll), "Scroll all panes together (toggle)"},
+				{formatKey(m.kb.SyncTime), "Keep all panes at the same time as the scrolled one (toggle)"},
 				{"1-9", "Jump to pane 1-9"},
+				{"<count>" + formatKey(m.kb.Down), "Move/scroll count times (5j)"},
+				{formatKey(m.kb.SwapLeft) + "/" + formatKey(m.kb.SwapRight) + "/" + formatKey(m.kb.SwapUp) + "/" + formatKey(m.kb.SwapDown), "Move pane left/right/up/down"},
+				{formatKey(m.kb.ResizeLeft) + "/" + formatKey(m.kb.ResizeRight) + "/" + formatKey(m.kb.ResizeUp) + "/" + formatKey(m.kb.ResizeDown), "Resize focused pane's column/row"},
+				{formatKey(m.kb.ResizeReset), "Balance the grid (undo resizes)"},
 			},
 		},
 		{
@@ -149,6 +215,11 @@ func (m HelpModal) View(screenWidth, screenHeight int) string {
 				{formatKey(m.kb.SelectAll), "Select all containers"},
 				{formatKey(m.kb.ClearAll), "Clear all selections"},
 				{formatKey(m.kb.Confirm), "Confirm and start monitoring"},
+				{formatKey(m.kb.GroupToggle), "Group by compose project / image"},
+				{formatKey(m.kb.LocalFilter), "Only the current directory's project / all"},
+				{formatKey(m.kb.CopyCommand), "Copy a cm command that opens the selection"},
+				{formatKey(m.kb.Prune), "Remove all stopped containers"},
+				{formatKey(m.kb.SystemMenu), "System menu (prune images / volumes)"},
 			},
 		},
 		{
@@ -156,20 +227,26 @@ func (m HelpModal) View(screenWidth, screenHeight int) string {
 			items: []struct{ key, desc string }{
 				{formatKey(m.kb.Restart), "Restart container"},
 				{formatKey(m.kb.Kill), "Kill container (force stop)"},
+				{formatKey(m.kb.Signal), "Send a signal (e.g. SIGHUP) to container"},
 				{formatKey(m.kb.Remove), "Remove container"},
 				{formatKey(m.kb.Start), "Start stopped container"},
 				{formatKey(m.kb.Stop), "Stop running container"},
+				{formatKey(m.kb.Pause), "Pause/unpause container (freeze its processes)"},
 				{formatKey(m.kb.Exec), "Open shell in container"},
-				{formatKey(m.kb.Inspect), "Inspect container details"},
+				{formatKey(m.kb.Inspect), "Inspect container details (J: raw JSON, u/s/r: start/stop/restart)"},
+				{formatKey(m.kb.Rename), "Rename container (also from inspect)"},
+				{formatKey(m.kb.Reconnect), "Reconnect disconnected log stream"},
+				{formatKey(m.kb.ReconnectAll), "Reconnect every disconnected pane"},
 			},
 		},
 		{
 			title: "Compose Actions",
 			items: []struct{ key, desc string }{
 				{formatKey(m.kb.ComposeRestart), "Compose down/up"},
-				{formatKey(m.kb.ComposeBuild), "Build (no-cache) and start"},
+				{formatKey(m.kb.ComposeBuild), "Build and start (standalone: build + recreate)"},
 				{formatKey(m.kb.ComposeUp), "Compose up"},
 				{formatKey(m.kb.ComposeDown), "Compose down"},
+				{formatKey(m.kb.ComposeLogs), "Follow docker compose logs for the whole project"},
 			},
 		},
 		{
@@ -180,7 +257,18 @@ func (m HelpModal) View(screenWidth, screenHeight int) string {
 				{"Right-click", "Copy selected text"},
 				{formatKey(m.kb.CopySelection), "Copy selected text"},
 				{formatKey(m.kb.CopyLogs), "Copy all logs to clipboard"},
+				{formatKey(m.kb.CopyLogsANSI), "Copy all logs with their ANSI colors"},
+				{formatKey(m.kb.ExportLogs), "Export full log history to a file"},
+				{formatKey(m.kb.OpenPager), "Open the pane's logs in $PAGER (less)"},
+				{formatKey(m.kb.CopyID), "Copy container ID"},
+				{formatKey(m.kb.CopyName), "Copy container name"},
+				{formatKey(m.kb.CopyExec), "Copy a docker exec command for a separate terminal"},
 				{formatKey(m.kb.WordWrap), "Toggle word wrap"},
+				{formatKey(m.kb.UTCToggle), "Toggle UTC/local timestamps"},
+				{formatKey(m.kb.CopyStamps), "Toggle timestamps in copied text"},
+				{formatKey(m.kb.StreamFilter), "Show both / stdout / stderr"},
+				{formatKey(m.kb.FreezeLayout), "Freeze layout (keep exited panes in place)"},
+				{formatKey(m.kb.DismissPane), "Dismiss exited pane"},
 				{formatKey(m.kb.Search), "Search/filter logs"},
 			},
 		},
@@ -188,12 +276,15 @@ func (m HelpModal) View(screenWidth, screenHeight int) string {
 			title: "General",
 			items: []struct{ key, desc string }{
 				{formatKey(m.kb.Confirm), "Toggle maximize pane"},
+				{"1-6 / [ ]", "Switch tabs while maximized (Logs ... Events)"},
 				{formatKey(m.kb.Back), "Un-maximize / go back"},
 				{formatKey(m.kb.Config), "Open configuration"},
+				{formatKey(m.kb.ReloadConfig), "Reload config files"},
 				{formatKey(m.kb.SavedProjects), "Saved projects"},
 				{formatKey(m.kb.Help), "Show this help"},
 				{formatKey(m.kb.Refresh), "Refresh container list"},
 				{formatKey(m.kb.DebugToggle), "Toggle debug logging"},
+				{formatKey(m.kb.DebugOverlay), "Toggle debug overlay (debug logging on)"},
 				{formatKey(m.kb.Quit), "Quit"},
 			},
 		},
@@ -210,59 +301,42 @@ func (m HelpModal) View(screenWidth, screenHeight int) string {
 		}
 		lines = append(lines, "")
 	}
+	return lines
+}
 
-	// Apply scroll offset
-	maxScroll := len(lines) - 15 // Show about 15 lines
-	if maxScroll < 0 {
-		maxScroll = 0
-	}
-	if m.scroll > maxScroll {
-		m.scroll = maxScroll
+// View renders the modal
+func (m HelpModal) View(screenWidth, screenHeight int) string {
+	if !m.visible {
+		return ""
 	}
 
-	visibleLines := lines
-	if m.scroll < len(lines) {
-		visibleLines = lines[m.scroll:]
-	}
-	if len(visibleLines) > 20 {
-		visibleLines = visibleLines[:20]
-	}
+	var content strings.Builder
+
+	// Title
+	content.WriteString(ModalTitleStyle.Render("Keyboard Shortcuts"))
+	content.WriteString("\n\n")
 
-	content.WriteString(strings.Join(visibleLines, "\n"))
+	content.WriteString(m.viewport.View())
 	content.WriteString("\n\n")
 
-	// Scroll indicator
-	if len(lines) > 20 {
-		scrollInfo := MutedInlineStyle.Render("  j/k: scroll  ")
-		content.WriteString(scrollInfo)
-	}
+	switch {
+	case m.search.Typing():
+		content.WriteString("  " + m.search.Prompt())
+	case m.search.Query() != "":
+		content.WriteString(MutedInlineStyle.Render("  " + m.search.Status()))
+	default:
+		// Scroll indicator
+		if m.viewport.TotalLineCount() > m.viewport.Height {
+			scrollInfo := MutedInlineStyle.Render("  j/k: scroll  ")
+			content.WriteString(scrollInfo)
+		}
 
-	// Close hint
-	content.WriteString(MutedInlineStyle.Render("esc/?/q: close"))
+		// Close hint
+		content.WriteString(MutedInlineStyle.Render("/: search  esc/?/q: close"))
+	}
 
 	// Style the modal
 	modalContent := ModalStyle.Render(content.String())
 
-	// Get modal dimensions
-	modalWidth := lipgloss.Width(modalContent)
-	modalHeight := lipgloss.Height(modalContent)
-
-	// Center the modal
-	x := (screenWidth - modalWidth) / 2
-	y := (screenHeight - modalHeight) / 2
-
-	if x < 0 {
-		x = 0
-	}
-	if y < 0 {
-		y = 0
-	}
-
-	// Create positioned modal
-	positioned := lipgloss.NewStyle().
-		MarginLeft(x).
-		MarginTop(y).
-		Render(modalContent)
-
-	return positioned
+	return PlaceModal(modalContent, screenWidth, screenHeight)
 }
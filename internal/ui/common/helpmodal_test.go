@@ -0,0 +1,46 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestHelpModalSearchScrollsToShortcut(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := NewHelpModal()
+	m.Open()
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("quit")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if len(m.search.Matches()) == 0 || m.viewport.YOffset == 0 {
+		t.Fatalf("expected the Quit shortcut to be found and scrolled to, got %v at %d", m.search.Matches(), m.viewport.YOffset)
+	}
+	if view := m.View(120, 40); !strings.Contains(view, "Quit") {
+		t.Fatalf("expected the Quit shortcut in view, got:\n%s", view)
+	}
+	if m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); m.IsVisible() {
+		t.Fatalf("expected q to close the modal once the search is applied")
+	}
+}
+
+func TestHelpModalScrollsToTheLastShortcutOnSmallScreens(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := NewHelpModal()
+	m.SetSize(80, 20)
+	m.Open()
+
+	if view := m.View(80, 20); lipgloss.Height(view) > 20 {
+		t.Fatalf("expected the modal to fit a 20-row screen, got %d rows", lipgloss.Height(view))
+	}
+	for i := 0; i < 500; i++ {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	}
+	if view := m.View(80, 20); !strings.Contains(view, "Quit") {
+		t.Fatalf("expected scrolling to reach the last shortcut, got:\n%s", view)
+	}
+}
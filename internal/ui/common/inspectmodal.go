@@ -6,6 +6,7 @@ import (
 
 	"cm/internal/docker"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -21,6 +22,32 @@ type ContainerDetailsMsg struct {
 	Err     error
 }
 
+// Container actions the inspect modal can ask for
+const (
+	InspectStart   = "start"
+	InspectStop    = "stop"
+	InspectRestart = "restart"
+)
+
+// InspectActionMsg asks the parent to start, stop or restart the inspected
+// container; the modal stays open and shows the new state once re-inspected
+type InspectActionMsg struct {
+	ContainerID string
+	Action      string // InspectStart, InspectStop or InspectRestart
+}
+
+// InspectJSONRequestMsg asks for the full `docker inspect` JSON of the inspected container
+type InspectJSONRequestMsg struct {
+	ContainerID string
+}
+
+// InspectJSONMsg is sent when a container's `docker inspect` JSON is fetched
+type InspectJSONMsg struct {
+	ContainerID string
+	JSON        []byte
+	Err         error
+}
+
 // InspectModal represents the container inspect modal
 type InspectModal struct {
 	visible     bool
@@ -31,6 +58,16 @@ type InspectModal struct {
 	err         error
 	viewport    viewport.Model
 	containerID string
+	keys        KeyMap // the user's start/stop/restart keys act on the container
+
+	// Raw `docker inspect` JSON, fetched the first time it's shown
+	raw        bool
+	rawJSON    string
+	rawLoading bool
+	rawErr     error
+
+	content string // viewport content before search highlighting
+	search  ModalSearch
 }
 
 // NewInspectModal creates a new inspect modal
@@ -47,6 +84,13 @@ func (m *InspectModal) Open(containerID string) tea.Cmd {
 	m.details = nil
 	m.err = nil
 	m.containerID = containerID
+	m.keys = DefaultKeyMap()
+	m.raw = false
+	m.rawJSON = ""
+	m.rawLoading = false
+	m.rawErr = nil
+	m.content = ""
+	m.search.Clear()
 	m.viewport = viewport.New(60, 20)
 	return nil
 }
@@ -56,9 +100,101 @@ func (m *InspectModal) SetDetails(details *docker.ContainerDetails, err error) {
 	m.loading = false
 	m.details = details
 	m.err = err
-	if details != nil {
-		m.viewport.SetContent(m.renderDetails())
+	if details != nil && !m.raw {
+		m.setContent(m.renderDetails())
+	}
+}
+
+// SetJSON sets the raw inspect JSON, ignoring results for a container no longer shown
+func (m *InspectModal) SetJSON(containerID string, data []byte, err error) {
+	if !m.visible || containerID != m.containerID {
+		return
+	}
+	m.rawLoading = false
+	m.rawJSON = string(data)
+	m.rawErr = err
+	if m.raw {
+		m.setContent(m.rawJSON)
+	}
+}
+
+// setContent shows new content, finding the applied search query in it
+func (m *InspectModal) setContent(content string) {
+	m.content = content
+	m.viewport.GotoTop()
+	if m.search.Query() != "" {
+		m.search.Find(strings.Split(content, "\n"))
+		m.jumpToMatch(0)
+		return
+	}
+	m.viewport.SetContent(content)
+}
+
+// jumpToMatch selects the search match delta away from the current one and
+// centers it in the viewport
+func (m *InspectModal) jumpToMatch(delta int) {
+	line, ok := m.search.Step(delta)
+	m.viewport.SetContent(strings.Join(m.search.HighlightLines(strings.Split(m.content, "\n")), "\n"))
+	if ok {
+		m.viewport.SetYOffset(scrollOffset(line, m.viewport.Height))
+	}
+}
+
+// IsSearching returns whether a search query is being typed
+func (m InspectModal) IsSearching() bool {
+	return m.search.Typing()
+}
+
+// IsRaw returns whether the modal shows the raw inspect JSON
+func (m InspectModal) IsRaw() bool {
+	return m.raw
+}
+
+// toggleRaw switches between the details and the raw JSON, requesting the
+// JSON the first time it's shown
+func (m *InspectModal) toggleRaw() tea.Cmd {
+	m.raw = !m.raw
+	if !m.raw {
+		m.setContent(m.renderDetails())
+		return nil
+	}
+	m.setContent(m.rawJSON)
+	if m.rawJSON != "" || m.rawLoading {
+		return nil
+	}
+	m.rawLoading = true
+	m.rawErr = nil
+	id := m.containerID
+	return func() tea.Msg { return InspectJSONRequestMsg{ContainerID: id} }
+}
+
+// action asks the parent to act on the inspected container
+func (m InspectModal) action(action string) tea.Cmd {
+	id := m.containerID
+	return func() tea.Msg { return InspectActionMsg{ContainerID: id, Action: action} }
+}
+
+// actionHelp lists the container action keys for the footer
+func (m InspectModal) actionHelp() string {
+	first := func(b key.Binding) string {
+		if keys := b.Keys(); len(keys) > 0 {
+			return keys[0]
+		}
+		return "?"
+	}
+	return fmt.Sprintf("%s/%s/%s: start/stop/restart  ", first(m.keys.Start), first(m.keys.Stop), first(m.keys.Restart))
+}
+
+// copyJSON copies the raw inspect JSON to the clipboard and reports the result as a toast
+func (m InspectModal) copyJSON() tea.Cmd {
+	if m.rawJSON == "" {
+		return nil
+	}
+	toast := ShowToastMsg{Title: "Copied", Message: "docker inspect JSON", Type: ToastSuccess}
+	if err := clipboard.WriteAll(m.rawJSON); err != nil {
+		toast = ShowToastMsg{Title: "Copy failed", Message: err.Error(), Type: ToastError}
 	}
+	return func() tea.Msg { return toast }
 }
 
 // Close closes the modal
@@ -74,6 +210,11 @@ func (m InspectModal) IsVisible() bool {
 	return m.visible
 }
 
+// ContainerID returns the ID of the container being inspected
+func (m InspectModal) ContainerID() string {
+	return m.containerID
+}
+
 // SetSize sets the modal dimensions
 func (m *InspectModal) SetSize(width, height int) {
 	m.width = width
@@ -109,11 +250,38 @@ func (m InspectModal) Update(msg tea.Msg) (InspectModal, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.search.Typing() {
+			if m.search.Update(msg) {
+				m.setContent(m.content)
+			}
+			return m, nil
+		}
 		switch {
+		case key.Matches(msg, key.NewBinding(key.WithKeys("esc"))) && m.search.Query() != "":
+			m.search.Clear()
+			m.viewport.SetContent(m.content)
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("/"))):
+			m.search.Start()
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("n"))):
+			m.jumpToMatch(1)
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("N"))):
+			m.jumpToMatch(-1)
+
 		case key.Matches(msg, key.NewBinding(key.WithKeys("esc", "i", "q"))):
 			m.visible = false
 			return m, func() tea.Msg { return InspectModalClosedMsg{} }
 
+		case key.Matches(msg, key.NewBinding(key.WithKeys("J"))):
+			return m, m.toggleRaw()
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("y"))):
+			if m.raw {
+				return m, m.copyJSON()
+			}
+
 		case key.Matches(msg, key.NewBinding(key.WithKeys("up", "k"))):
 			m.viewport.SetYOffset(m.viewport.YOffset - 1)
 
@@ -125,6 +293,15 @@ func (m InspectModal) Update(msg tea.Msg) (InspectModal, tea.Cmd) {
 
 		case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+d"))):
 			m.viewport.SetYOffset(m.viewport.YOffset + 5)
+
+		case key.Matches(msg, m.keys.Start):
+			return m, m.action(InspectStart)
+
+		case key.Matches(msg, m.keys.Stop):
+			return m, m.action(InspectStop)
+
+		case key.Matches(msg, m.keys.Restart):
+			return m, m.action(InspectRestart)
 		}
 	}
 
@@ -159,7 +336,7 @@ func (m *InspectModal) renderDetails() string {
 	writeField("ID", d.ID)
 	writeField("Name", d.Name)
 	writeField("Image", d.Image)
-	writeField("Status", d.Status)
+	writeField("Status", d.StatusSummary())
 	if !d.Created.IsZero() {
 		writeField("Created", d.Created.Format("2006-01-02 15:04:05"))
 	}
@@ -271,10 +448,23 @@ func (m InspectModal) View(screenWidth, screenHeight int) string {
 	var content strings.Builder
 
 	// Title
-	content.WriteString(ModalTitleStyle.Render("Container Details"))
+	title := "Container Details"
+	if m.raw {
+		title = "docker inspect"
+	}
+	content.WriteString(ModalTitleStyle.Render(title))
 	content.WriteString("\n\n")
 
-	if m.loading {
+	if m.raw {
+		switch {
+		case m.rawLoading:
+			content.WriteString(MutedInlineStyle.Render("  Loading..."))
+		case m.rawErr != nil:
+			content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("  Error: " + m.rawErr.Error()))
+		default:
+			content.WriteString(m.viewport.View())
+		}
+	} else if m.loading {
 		content.WriteString(MutedInlineStyle.Render("  Loading..."))
 	} else if m.err != nil {
 		content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("  Error: " + m.err.Error()))
@@ -284,11 +474,29 @@ func (m InspectModal) View(screenWidth, screenHeight int) string {
 
 	content.WriteString("\n\n")
 
-	// Scroll indicator
-	if m.details != nil && m.viewport.TotalLineCount() > m.viewport.Height {
-		content.WriteString(MutedInlineStyle.Render("  j/k: scroll  "))
+	// Footer: the search being typed or applied, or the key hints
+	switch {
+	case m.search.Typing():
+		content.WriteString("  " + m.search.Prompt())
+	case m.search.Query() != "":
+		content.WriteString(MutedInlineStyle.Render("  " + m.search.Status()))
+	default:
+		// Scroll indicator
+		scrollable := m.details != nil
+		if m.raw {
+			scrollable = m.rawJSON != ""
+		}
+		if scrollable && m.viewport.TotalLineCount() > m.viewport.Height {
+			content.WriteString(MutedInlineStyle.Render("  j/k: scroll  "))
+		}
+		if m.raw {
+			content.WriteString(MutedInlineStyle.Render("J: details  y: copy  "))
+		} else {
+			content.WriteString(MutedInlineStyle.Render("J: raw JSON  "))
+		}
+		content.WriteString(MutedInlineStyle.Render(m.actionHelp()))
+		content.WriteString(MutedInlineStyle.Render("/: search  esc/i/q: close"))
 	}
-	content.WriteString(MutedInlineStyle.Render("esc/i/q: close"))
 
 	// Style the modal (no background fill; border-only overlay)
 	modalStyle := lipgloss.NewStyle().
@@ -297,26 +505,5 @@ func (m InspectModal) View(screenWidth, screenHeight int) string {
 		Padding(1, 2)
 	modalContent := modalStyle.Render(content.String())
 
-	// Get modal dimensions
-	modalWidth := lipgloss.Width(modalContent)
-	modalHeight := lipgloss.Height(modalContent)
-
-	// Center the modal
-	x := (screenWidth - modalWidth) / 2
-	y := (screenHeight - modalHeight) / 2
-
-	if x < 0 {
-		x = 0
-	}
-	if y < 0 {
-		y = 0
-	}
-
-	// Create positioned modal
-	positioned := lipgloss.NewStyle().
-		MarginLeft(x).
-		MarginTop(y).
-		Render(modalContent)
-
-	return positioned
+	return PlaceModal(modalContent, screenWidth, screenHeight)
 }
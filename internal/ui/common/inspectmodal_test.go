@@ -0,0 +1,91 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"cm/internal/docker"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestInspectModalFetchesRawJSONOnce(t *testing.T) {
+	m := NewInspectModal()
+	m.SetSize(120, 40)
+	m.Open("abc123")
+	m.SetDetails(&docker.ContainerDetails{ID: "abc123", Name: "web"}, nil)
+
+	raw := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("J")}
+	m, cmd := m.Update(raw)
+	if cmd == nil {
+		t.Fatalf("expected the first switch to raw JSON to request it")
+	}
+	if got := cmd().(InspectJSONRequestMsg); got.ContainerID != "abc123" {
+		t.Fatalf("requested JSON for %q, want abc123", got.ContainerID)
+	}
+
+	// A late result for another container is dropped
+	m.SetJSON("other", []byte(`{"Id": "other"}`), nil)
+	m.SetJSON("abc123", []byte("{\n  \"Id\": \"abc123\"\n}"), nil)
+	if view := m.View(120, 40); !strings.Contains(view, `"Id": "abc123"`) {
+		t.Fatalf("expected the raw JSON in the view, got:\n%s", view)
+	}
+
+	m, _ = m.Update(raw)
+	if m.IsRaw() || !strings.Contains(m.View(120, 40), "Container Details") {
+		t.Fatalf("expected J to switch back to the details")
+	}
+	if m, cmd = m.Update(raw); cmd != nil || !m.IsRaw() {
+		t.Fatalf("expected the fetched JSON to be reused")
+	}
+}
+
+func TestInspectModalSearchJumpsToMatches(t *testing.T) {
+	m := NewInspectModal()
+	m.SetSize(120, 30)
+	m.Open("abc123")
+	var env []string
+	for i := 0; i < 60; i++ {
+		env = append(env, "VAR="+string(rune('a'+i%26)))
+	}
+	env[45] = "DATABASE_URL=postgres://db"
+	m.SetDetails(&docker.ContainerDetails{ID: "abc123", Name: "web", Env: env}, nil)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	// Typed keys go to the query, not the modal's own bindings
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("database_url")})
+	if !m.IsVisible() || m.IsRaw() {
+		t.Fatalf("expected typing to stay in the search input")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if len(m.search.Matches()) != 1 || m.viewport.YOffset == 0 {
+		t.Fatalf("expected one match scrolled into view, got %v at offset %d", m.search.Matches(), m.viewport.YOffset)
+	}
+	if view := m.View(120, 30); !strings.Contains(view, "DATABASE_URL") || !strings.Contains(view, "1/1") {
+		t.Fatalf("expected the match and its position in the view, got:\n%s", view)
+	}
+
+	// esc clears the search before it closes the modal
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if !m.IsVisible() || m.search.Query() != "" {
+		t.Fatalf("expected esc to clear the search and keep the modal open")
+	}
+}
+
+func TestInspectModalActionKeys(t *testing.T) {
+	m := NewInspectModal()
+	m.SetSize(120, 40)
+	m.Open("abc123")
+	m.SetDetails(&docker.ContainerDetails{ID: "abc123", Name: "web"}, nil)
+
+	for k, want := range map[string]string{"u": InspectStart, "s": InspectStop, "r": InspectRestart} {
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		if cmd == nil {
+			t.Fatalf("expected %s to send an action", k)
+		}
+		if got := cmd().(InspectActionMsg); got.ContainerID != "abc123" || got.Action != want {
+			t.Fatalf("%s sent %+v, want %s", k, got, want)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"cm/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyEditorRows is how many actions the editor lists at once, screen height permitting
+const keyEditorRows = 14
+
+// keyAction is one rebindable field of config.KeyBindings
+type keyAction struct {
+	name  string // json name, as it appears in keybindings.json
+	field int    // struct field index
+}
+
+// keyActions lists every action in config.KeyBindings in declaration order
+var keyActions = func() []keyAction {
+	t := reflect.TypeOf(config.KeyBindings{})
+	actions := make([]keyAction, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || t.Field(i).Type.Kind() != reflect.String {
+			continue
+		}
+		actions = append(actions, keyAction{name: name, field: i})
+	}
+	return actions
+}()
+
+// KeyEditor lets the user rebind actions by pressing the new key
+type KeyEditor struct {
+	visible   bool
+	cursor    int
+	offset    int
+	capturing bool // waiting for the next keypress
+	appending bool // the captured key is added rather than replacing
+	changed   bool
+	bindings  config.KeyBindings
+	height    int // screen height, 0 if unknown
+}
+
+// NewKeyEditor creates a new key editor
+func NewKeyEditor() KeyEditor {
+	return KeyEditor{}
+}
+
+// SetHeight fits the list of actions to the screen height
+func (e *KeyEditor) SetHeight(height int) {
+	e.height = height
+	e.scrollToCursor()
+}
+
+// rows returns how many actions fit on screen around the editor's other lines
+func (e KeyEditor) rows() int {
+	if e.height <= 0 {
+		return keyEditorRows
+	}
+	return max(min(keyEditorRows, e.height-10), 3)
+}
+
+// scrollToCursor keeps the cursor within the listed actions
+func (e *KeyEditor) scrollToCursor() {
+	if e.cursor < e.offset {
+		e.offset = e.cursor
+	}
+	if e.cursor >= e.offset+e.rows() {
+		e.offset = e.cursor - e.rows() + 1
+	}
+}
+
+// Open shows the editor for a copy of the given bindings
+func (e *KeyEditor) Open(kb config.KeyBindings) {
+	e.visible = true
+	e.cursor = 0
+	e.offset = 0
+	e.capturing = false
+	e.changed = false
+	e.bindings = kb
+}
+
+// Close hides the editor
+func (e *KeyEditor) Close() {
+	e.visible = false
+	e.capturing = false
+}
+
+// IsVisible returns whether the editor is visible
+func (e KeyEditor) IsVisible() bool {
+	return e.visible
+}
+
+// Bindings returns the bindings including any keys recorded so far
+func (e KeyEditor) Bindings() config.KeyBindings {
+	return e.bindings
+}
+
+// Changed returns whether any key was recorded or restored
+func (e KeyEditor) Changed() bool {
+	return e.changed
+}
+
+func (e KeyEditor) get(a keyAction) string {
+	return reflect.ValueOf(e.bindings).Field(a.field).String()
+}
+
+func (e *KeyEditor) set(a keyAction, keys string) {
+	reflect.ValueOf(&e.bindings).Elem().Field(a.field).SetString(keys)
+	e.changed = true
+}
+
+// keyName maps a keypress to the string keybindings.json uses for it
+func keyName(msg tea.KeyMsg) string {
+	if msg.String() == " " {
+		return "space"
+	}
+	return msg.String()
+}
+
+// conflicts returns the other actions already bound to any of keys
+func (e KeyEditor) conflicts(current keyAction, keys string) []string {
+	var names []string
+	for _, a := range keyActions {
+		if a.field == current.field {
+			continue
+		}
+		for _, bound := range strings.Split(e.get(a), ",") {
+			for _, k := range strings.Split(keys, ",") {
+				if strings.TrimSpace(bound) == k {
+					names = append(names, a.name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// Update handles messages for the editor
+func (e KeyEditor) Update(msg tea.Msg) (KeyEditor, tea.Cmd) {
+	if !e.visible {
+		return e, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return e, nil
+	}
+
+	action := keyActions[e.cursor]
+
+	if e.capturing {
+		// A paste isn't a key; keep waiting for one
+		if keyMsg.Paste {
+			return e, nil
+		}
+		e.capturing = false
+		if keyMsg.String() == "esc" {
+			return e, nil
+		}
+		keys := keyName(keyMsg)
+		if e.appending && e.get(action) != "" {
+			keys = e.get(action) + "," + keys
+		}
+		e.set(action, keys)
+		return e, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q":
+		e.Close()
+	case "up", "k":
+		if e.cursor > 0 {
+			e.cursor--
+		}
+	case "down", "j":
+		if e.cursor < len(keyActions)-1 {
+			e.cursor++
+		}
+	case "enter":
+		e.capturing = true
+		e.appending = false
+	case "a":
+		e.capturing = true
+		e.appending = true
+	case "d":
+		defaults := KeyEditor{bindings: config.DefaultKeyBindings()}
+		e.set(action, defaults.get(action))
+	}
+
+	e.scrollToCursor()
+	return e, nil
+}
+
+// View renders the editor body for the config modal
+func (e KeyEditor) View() string {
+	var content strings.Builder
+
+	content.WriteString(ModalTitleStyle.Render("Key Bindings"))
+	content.WriteString("\n\n")
+
+	end := e.offset + e.rows()
+	if end > len(keyActions) {
+		end = len(keyActions)
+	}
+	for i := e.offset; i < end; i++ {
+		a := keyActions[i]
+		line := fmt.Sprintf("  %-20s %s", a.name, e.get(a))
+		if i == e.cursor {
+			if e.capturing {
+				line = fmt.Sprintf("  %-20s press a key…", a.name)
+			}
+			content.WriteString(ModalSelectedStyle.Render(line))
+		} else {
+			content.WriteString(line)
+		}
+		content.WriteString("\n")
+	}
+	content.WriteString(MutedInlineStyle.Render(fmt.Sprintf("  %d/%d", e.cursor+1, len(keyActions))))
+	content.WriteString("\n")
+
+	action := keyActions[e.cursor]
+	if others := e.conflicts(action, e.get(action)); len(others) > 0 {
+		content.WriteString(PausedStyle.Render("  also bound to " + strings.Join(others, ", ")))
+	}
+	content.WriteString("\n\n")
+
+	if e.capturing {
+		content.WriteString(MutedInlineStyle.Render("  press the new key  esc: cancel"))
+	} else {
+		content.WriteString(MutedInlineStyle.Render("  enter: record  a: add key  d: default  esc: back"))
+	}
+
+	return content.String()
+}
@@ -0,0 +1,89 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"cm/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestConfigModalRecordsAndSavesKeyBinding(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	m := NewConfigModal()
+	m.Open()
+	for m.selectedItem != ItemRecordKeyBindings {
+		m, _ = m.Update(down)
+	}
+	m, _ = m.Update(enter)
+	if !m.keyEditor.IsVisible() {
+		t.Fatalf("expected the key editor to open")
+	}
+
+	for keyActions[m.keyEditor.cursor].name != "stop" {
+		m, _ = m.Update(down)
+	}
+	m, _ = m.Update(enter)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	if got := m.keyEditor.Bindings().Stop; got != "ctrl+x" {
+		t.Fatalf("expected stop to be recorded as ctrl+x, got %q", got)
+	}
+
+	// a adds an alternative, esc while capturing leaves the binding alone
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	m, _ = m.Update(enter)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if got := m.keyEditor.Bindings().Stop; got != "ctrl+x,space" {
+		t.Fatalf("expected ctrl+x,space, got %q", got)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.keyEditor.IsVisible() || !m.IsVisible() {
+		t.Fatalf("expected esc to return to the config modal")
+	}
+	m.selectedItem = ItemSave
+	if _, cmd := m.Update(enter); cmd == nil {
+		t.Fatalf("expected save to close the modal")
+	}
+	if got := config.LoadKeyBindings().Stop; got != "ctrl+x,space" {
+		t.Fatalf("expected the recorded key to be saved, got %q", got)
+	}
+}
+
+func TestKeyEditorRestoresDefault(t *testing.T) {
+	kb := config.DefaultKeyBindings()
+	kb.Quit = "ctrl+q"
+
+	e := NewKeyEditor()
+	e.Open(kb)
+	for keyActions[e.cursor].name != "quit" {
+		e, _ = e.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	e, _ = e.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if got, want := e.Bindings().Quit, config.DefaultKeyBindings().Quit; got != want {
+		t.Fatalf("expected quit to be restored to %q, got %q", want, got)
+	}
+}
+
+func TestKeyEditorFitsShortScreens(t *testing.T) {
+	e := NewKeyEditor()
+	e.Open(config.DefaultKeyBindings())
+	for i := 0; i < 20; i++ {
+		e, _ = e.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+
+	// Shrinking the screen keeps the cursor's action in the shorter list
+	e.SetHeight(16)
+	if e.rows() != 6 || e.cursor >= e.offset+e.rows() {
+		t.Fatalf("expected 6 rows with the cursor in view, got %d rows from %d for cursor %d", e.rows(), e.offset, e.cursor)
+	}
+	if view := e.View(); !strings.Contains(view, keyActions[e.cursor].name) {
+		t.Fatalf("expected the selected action in view, got:\n%s", view)
+	}
+}
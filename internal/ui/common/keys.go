@@ -6,6 +6,7 @@ import (
 	"cm/internal/config"
 
 	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // KeyMap defines the key bindings for the application
@@ -21,6 +22,9 @@ type KeyMap struct {
 	Bottom     key.Binding
 	NextPane   key.Binding
 	PrevPane   key.Binding
+	NewestPane key.Binding
+	SyncScroll key.Binding
+	SyncTime   key.Binding
 
 	// Selection
 	Select    key.Binding
@@ -30,33 +34,57 @@ type KeyMap struct {
 	Back      key.Binding
 
 	// Container actions
-	Start   key.Binding
-	Stop    key.Binding
-	Restart key.Binding
-	Kill    key.Binding
-	Remove  key.Binding
-	Exec    key.Binding
-	Inspect key.Binding
+	Start        key.Binding
+	Stop         key.Binding
+	Restart      key.Binding
+	Kill         key.Binding
+	Remove       key.Binding
+	Exec         key.Binding
+	Inspect      key.Binding
+	Reconnect    key.Binding
+	ReconnectAll key.Binding
+	Pause        key.Binding
+	Signal       key.Binding
+	Rename       key.Binding
+	Prune        key.Binding
 
 	// Compose actions
 	ComposeUp      key.Binding
 	ComposeDown    key.Binding
 	ComposeRestart key.Binding
 	ComposeBuild   key.Binding
+	ComposeLogs    key.Binding
 
 	// General
 	Refresh       key.Binding
 	Search        key.Binding
 	Help          key.Binding
 	Config        key.Binding
+	ReloadConfig  key.Binding
+	SystemMenu    key.Binding
 	SavedProjects key.Binding
 	Quit          key.Binding
 	CopyLogs      key.Binding
+	CopyLogsANSI  key.Binding
 	CopySelection key.Binding
+	CopyID        key.Binding
+	CopyName      key.Binding
+	CopyExec      key.Binding
+	CopyCommand   key.Binding
 	WordWrap      key.Binding
 	DebugToggle   key.Binding
+	DebugOverlay  key.Binding
 	ClearLogs     key.Binding
 	PauseLogs     key.Binding
+	UTCToggle     key.Binding
+	CopyStamps    key.Binding
+	GroupToggle   key.Binding
+	LocalFilter   key.Binding
+	FreezeLayout  key.Binding
+	DismissPane   key.Binding
+	ExportLogs    key.Binding
+	OpenPager     key.Binding
+	StreamFilter  key.Binding
 
 	// Pane shortcuts
 	Pane1 key.Binding
@@ -75,6 +103,47 @@ type KeyMap struct {
 	ResizeUp    key.Binding
 	ResizeDown  key.Binding
 	ResizeReset key.Binding
+
+	// Pane reorder
+	SwapLeft  key.Binding
+	SwapRight key.Binding
+	SwapUp    key.Binding
+	SwapDown  key.Binding
+}
+
+// maxCount caps a count prefix so a long run of digits can't overflow
+const maxCount = 9999
+
+// AppendCount adds a digit keypress to a pending vi-style count prefix such
+// as the 5 in 5j. It returns false for keys that aren't part of a count; a 0
+// only extends a count that has already started.
+func AppendCount(count int, msg tea.KeyMsg) (int, bool) {
+	if msg.Type != tea.KeyRunes || msg.Alt || msg.Paste || len(msg.Runes) != 1 {
+		return count, false
+	}
+	r := msg.Runes[0]
+	if r < '0' || r > '9' || (r == '0' && count == 0) {
+		return count, false
+	}
+	count = count*10 + int(r-'0')
+	if count > maxCount {
+		count = maxCount
+	}
+	return count, true
+}
+
+// CleanPaste flattens a bracketed paste for a single-line input: a trailing
+// newline is dropped and inner newlines and tabs become spaces, so pasting a
+// copied log line or a multi-line string lands in the input as typed text.
+// Other keys are returned unchanged.
+func CleanPaste(msg tea.KeyMsg) tea.KeyMsg {
+	if !msg.Paste {
+		return msg
+	}
+	text := strings.TrimRight(string(msg.Runes), "\r\n")
+	text = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ").Replace(text)
+	msg.Runes = []rune(text)
+	return msg
 }
 
 // parseKeys splits a comma-separated key string into a slice
@@ -139,6 +208,18 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys(parseKeys(bindings.PrevPane)...),
 			key.WithHelp("{", "prev pane"),
 		),
+		NewestPane: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.NewestPane)...),
+			key.WithHelp("N", "pane with newest logs"),
+		),
+		SyncScroll: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.SyncScroll)...),
+			key.WithHelp("alt+s", "scroll panes together"),
+		),
+		SyncTime: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.SyncTime)...),
+			key.WithHelp("ctrl+t", "align panes by time"),
+		),
 
 		// Selection
 		Select: key.NewBinding(
@@ -191,6 +272,30 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys(parseKeys(bindings.Inspect)...),
 			key.WithHelp("i", "inspect"),
 		),
+		Reconnect: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.Reconnect)...),
+			key.WithHelp("L", "reconnect logs"),
+		),
+		ReconnectAll: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.ReconnectAll)...),
+			key.WithHelp("alt+l", "reconnect all disconnected panes"),
+		),
+		Pause: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.Pause)...),
+			key.WithHelp("z", "pause/unpause container"),
+		),
+		Signal: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.Signal)...),
+			key.WithHelp("ctrl+k", "send signal"),
+		),
+		Rename: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.Rename)...),
+			key.WithHelp("ctrl+n", "rename container"),
+		),
+		Prune: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.Prune)...),
+			key.WithHelp("X", "prune stopped containers"),
+		),
 
 		// Compose actions
 		ComposeUp: key.NewBinding(
@@ -209,6 +314,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys(parseKeys(bindings.ComposeBuild)...),
 			key.WithHelp("b", "build"),
 		),
+		ComposeLogs: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.ComposeLogs)...),
+			key.WithHelp("l", "compose logs"),
+		),
 
 		// General
 		Refresh: key.NewBinding(
@@ -227,6 +336,14 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys(parseKeys(bindings.Config)...),
 			key.WithHelp("c", "config"),
 		),
+		SystemMenu: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.SystemMenu)...),
+			key.WithHelp("M", "system menu"),
+		),
+		ReloadConfig: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.ReloadConfig)...),
+			key.WithHelp("C", "reload config"),
+		),
 		SavedProjects: key.NewBinding(
 			key.WithKeys(parseKeys(bindings.SavedProjects)...),
 			key.WithHelp("p", "projects"),
@@ -239,10 +356,30 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys(parseKeys(bindings.CopyLogs)...),
 			key.WithHelp("y", "copy logs"),
 		),
+		CopyLogsANSI: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.CopyLogsANSI)...),
+			key.WithHelp("alt+y", "copy logs with colors"),
+		),
 		CopySelection: key.NewBinding(
 			key.WithKeys(parseKeys(bindings.CopySelection)...),
 			key.WithHelp("ctrl+shift+c", "copy selection"),
 		),
+		CopyID: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.CopyID)...),
+			key.WithHelp("Y", "copy container ID"),
+		),
+		CopyName: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.CopyName)...),
+			key.WithHelp("ctrl+y", "copy container name"),
+		),
+		CopyExec: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.CopyExec)...),
+			key.WithHelp("ctrl+e", "copy docker exec command"),
+		),
+		CopyCommand: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.CopyCommand)...),
+			key.WithHelp("alt+c", "copy cm command"),
+		),
 		WordWrap: key.NewBinding(
 			key.WithKeys(parseKeys(bindings.WordWrap)...),
 			key.WithHelp("w", "word wrap"),
@@ -251,6 +388,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys(parseKeys(bindings.DebugToggle)...),
 			key.WithHelp("ctrl+g", "debug logs"),
 		),
+		DebugOverlay: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.DebugOverlay)...),
+			key.WithHelp("ctrl+o", "debug overlay"),
+		),
 		ClearLogs: key.NewBinding(
 			key.WithKeys(parseKeys(bindings.ClearLogs)...),
 			key.WithHelp("ctrl+l", "clear logs"),
@@ -259,6 +400,42 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys(parseKeys(bindings.PauseLogs)...),
 			key.WithHelp("P", "pause logs"),
 		),
+		UTCToggle: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.UTCToggle)...),
+			key.WithHelp("T", "utc timestamps"),
+		),
+		CopyStamps: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.CopyStamps)...),
+			key.WithHelp("alt+t", "timestamps in copies"),
+		),
+		GroupToggle: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.GroupToggle)...),
+			key.WithHelp("I", "group by project/image"),
+		),
+		LocalFilter: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.LocalFilter)...),
+			key.WithHelp(".", "local project/all"),
+		),
+		FreezeLayout: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.FreezeLayout)...),
+			key.WithHelp("F", "freeze layout"),
+		),
+		DismissPane: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.DismissPane)...),
+			key.WithHelp("x", "dismiss exited pane"),
+		),
+		ExportLogs: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.ExportLogs)...),
+			key.WithHelp("E", "export full log history"),
+		),
+		OpenPager: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.OpenPager)...),
+			key.WithHelp("v", "open logs in pager"),
+		),
+		StreamFilter: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.StreamFilter)...),
+			key.WithHelp("o", "cycle stdout/stderr filter"),
+		),
 
 		// Pane shortcuts
 		Pane1: key.NewBinding(
@@ -319,5 +496,23 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys(parseKeys(bindings.ResizeReset)...),
 			key.WithHelp("=", "reset size"),
 		),
+
+		// Pane reorder
+		SwapLeft: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.SwapLeft)...),
+			key.WithHelp("shift+←", "move pane left"),
+		),
+		SwapRight: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.SwapRight)...),
+			key.WithHelp("shift+→", "move pane right"),
+		),
+		SwapUp: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.SwapUp)...),
+			key.WithHelp("shift+↑", "move pane up"),
+		),
+		SwapDown: key.NewBinding(
+			key.WithKeys(parseKeys(bindings.SwapDown)...),
+			key.WithHelp("shift+↓", "move pane down"),
+		),
 	}
 }
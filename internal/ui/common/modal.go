@@ -0,0 +1,32 @@
+package common
+
+import "github.com/charmbracelet/lipgloss"
+
+// PlaceModal centers a rendered modal on the screen. A modal larger than the
+// screen is pinned to the top-left corner and clipped, so it never pushes the
+// view past the terminal's edges.
+func PlaceModal(modalContent string, screenWidth, screenHeight int) string {
+	// Get modal dimensions
+	modalWidth := lipgloss.Width(modalContent)
+	modalHeight := lipgloss.Height(modalContent)
+
+	// Center the modal
+	x := (screenWidth - modalWidth) / 2
+	y := (screenHeight - modalHeight) / 2
+
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+
+	// Create positioned modal
+	style := lipgloss.NewStyle().
+		MarginLeft(x).
+		MarginTop(y)
+	if screenWidth > 0 && screenHeight > 0 {
+		style = style.MaxWidth(screenWidth).MaxHeight(screenHeight)
+	}
+	return style.Render(modalContent)
+}
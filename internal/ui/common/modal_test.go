@@ -0,0 +1,22 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestPlaceModalCentersAndClipsToScreen(t *testing.T) {
+	modal := ModalStyle.Render(strings.Repeat("line\n", 9) + "line")
+
+	placed := PlaceModal(modal, 80, 40)
+	if got, want := lipgloss.Height(placed), (40-lipgloss.Height(modal))/2+lipgloss.Height(modal); got != want {
+		t.Fatalf("centered modal is %d rows, want %d", got, want)
+	}
+
+	// On a screen shorter than the modal it's clipped instead of overflowing
+	if got := lipgloss.Height(PlaceModal(modal, 80, 8)); got != 8 {
+		t.Fatalf("clipped modal is %d rows, want 8", got)
+	}
+}
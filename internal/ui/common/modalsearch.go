@@ -0,0 +1,168 @@
+package common
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ModalSearch is the "/" search shared by the build panel and the scrollable
+// modals: the query being typed, the applied query and the lines matching it.
+// The owner supplies its rendered lines and scrolls to the matches.
+type ModalSearch struct {
+	typing  bool   // typing a query
+	input   string // query being typed
+	query   string // applied query
+	matches []int  // indices of the lines that contain the query
+	current int    // index into matches
+}
+
+// Start starts typing a query, beginning with the applied one
+func (s *ModalSearch) Start() {
+	s.typing = true
+	s.input = s.query
+}
+
+// Typing reports whether a query is being typed
+func (s ModalSearch) Typing() bool {
+	return s.typing
+}
+
+// Query returns the applied query
+func (s ModalSearch) Query() string {
+	return s.query
+}
+
+// Matches returns the indices of the lines that contain the query
+func (s ModalSearch) Matches() []int {
+	return s.matches
+}
+
+// CapturesKey reports whether the search handles a key that would otherwise
+// close its modal (any key while typing, esc to clear an applied search)
+func (s ModalSearch) CapturesKey(k string) bool {
+	if s.typing {
+		return true
+	}
+	return k == "esc" && s.query != ""
+}
+
+// Update handles a key while a query is being typed. It returns true when
+// the query is submitted; the owner then calls Find with its lines.
+func (s *ModalSearch) Update(msg tea.KeyMsg) bool {
+	msg = CleanPaste(msg)
+	switch msg.Type {
+	case tea.KeyEsc:
+		s.typing = false
+		s.input = ""
+	case tea.KeyEnter:
+		s.typing = false
+		s.query = s.input
+		return true
+	case tea.KeyBackspace:
+		if r := []rune(s.input); len(r) > 0 {
+			s.input = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		s.input += " "
+	case tea.KeyRunes:
+		s.input += string(msg.Runes)
+	}
+	return false
+}
+
+// Set applies a query and finds its matches in lines
+func (s *ModalSearch) Set(query string, lines []string) int {
+	s.query = query
+	return s.Find(lines)
+}
+
+// Find finds the applied query in lines, selecting the first match
+func (s *ModalSearch) Find(lines []string) int {
+	s.matches = nil
+	s.current = 0
+	if s.query == "" {
+		return 0
+	}
+	for i, line := range lines {
+		if containsFold(line, s.query) {
+			s.matches = append(s.matches, i)
+		}
+	}
+	return len(s.matches)
+}
+
+// Add checks a line appended at index i against the applied query
+func (s *ModalSearch) Add(i int, line string) {
+	if s.query != "" && containsFold(line, s.query) {
+		s.matches = append(s.matches, i)
+	}
+}
+
+// Clear drops the query and its matches
+func (s *ModalSearch) Clear() {
+	*s = ModalSearch{}
+}
+
+// Current returns the line of the selected match
+func (s ModalSearch) Current() (int, bool) {
+	if s.current >= len(s.matches) {
+		return 0, false
+	}
+	return s.matches[s.current], true
+}
+
+// Step selects the match delta away from the current one, wrapping around,
+// and returns its line
+func (s *ModalSearch) Step(delta int) (int, bool) {
+	if len(s.matches) == 0 {
+		return 0, false
+	}
+	s.current = ((s.current+delta)%len(s.matches) + len(s.matches)) % len(s.matches)
+	return s.Current()
+}
+
+// Highlight highlights the query in line i, dropping the line's own styling.
+// It returns false if the line doesn't match.
+func (s ModalSearch) Highlight(i int, line string) (string, bool) {
+	if s.query == "" || !containsFold(line, s.query) {
+		return line, false
+	}
+	current, _ := s.Current()
+	return highlightFold(ansi.Strip(line), s.query, len(s.matches) > 0 && i == current), true
+}
+
+// HighlightLines highlights the query in every matching line
+func (s ModalSearch) HighlightLines(lines []string) []string {
+	if s.query == "" {
+		return lines
+	}
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i], _ = s.Highlight(i, line)
+	}
+	return out
+}
+
+// Prompt renders the query being typed
+func (s ModalSearch) Prompt() string {
+	return "/" + s.input + "█"
+}
+
+// Status renders the applied query with the position of the selected match
+func (s ModalSearch) Status() string {
+	current := 0
+	if len(s.matches) > 0 {
+		current = s.current + 1
+	}
+	return fmt.Sprintf("%q %d/%d  n/N: next/prev  esc: clear", s.query, current, len(s.matches))
+}
+
+// scrollOffset returns the viewport offset that centers a line
+func scrollOffset(line, height int) int {
+	if offset := line - height/2; offset > 0 {
+		return offset
+	}
+	return 0
+}
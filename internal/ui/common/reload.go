@@ -0,0 +1,191 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cm/internal/config"
+	"cm/internal/debug"
+	"cm/internal/docker"
+	"cm/internal/notify"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+// ConfigErrorTitle is the toast title for config files that couldn't be parsed
+const ConfigErrorTitle = "Config error, using defaults"
+
+// ReloadConfig re-reads the config files for the settings that are otherwise
+// only read at startup: it drops the docker package's cached config and
+// reapplies notification and debug log settings. It returns an error for each
+// file that couldn't be parsed. Screens rebuild their key maps and toast
+// settings themselves.
+func ReloadConfig() []error {
+	docker.InvalidateConfigCache()
+	notify.Reload()
+	if cfg, err := config.Load(); err == nil {
+		debug.Configure(cfg.GetDebugSettings())
+	}
+	return config.ValidateFiles()
+}
+
+// FormatConfigErrors renders config file errors one per line for a toast
+func FormatConfigErrors(errs []error) string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// ShowReloadResult shows a toast for the outcome of a config reload
+func ShowReloadResult(t *Toast, errs []error, message string) tea.Cmd {
+	if len(errs) > 0 {
+		return t.Show(ConfigErrorTitle, FormatConfigErrors(errs), ToastError)
+	}
+	return t.Show("Config", message, ToastSuccess)
+}
+
+// configSettleDelay is how long a changed config file must go unchanged
+// before it's reloaded
+const configSettleDelay = time.Second
+
+// ConfigFileEventMsg is a change fsnotify reported in the config directory
+type ConfigFileEventMsg struct {
+	path string
+}
+
+// ConfigWatchTickMsg ends the wait for the changed files to settle
+type ConfigWatchTickMsg struct {
+	gen int
+}
+
+// ConfigChangedMsg is sent when a config file was changed on disk by
+// something other than cm and has been stable for configSettleDelay
+type ConfigChangedMsg struct{}
+
+// ConfigWatcher watches config.json, keybindings.json and projects.json with
+// fsnotify when reload.watch is enabled. A change is only reported once the
+// file stops changing, so an editor's partial writes don't trigger a reload
+// of a half-written file, and not at all when the file is as cm itself last
+// wrote it (saving projects, the config modal).
+type ConfigWatcher struct {
+	enabled bool
+	paths   map[string]bool
+	fsw     *fsnotify.Watcher
+	changed map[string]bool // files changed since the last report
+	gen     int             // counts changes; a tick from an older one is stale
+}
+
+// NewConfigWatcher creates a watcher, enabled if reload.watch is set
+func NewConfigWatcher() ConfigWatcher {
+	w := ConfigWatcher{paths: make(map[string]bool), changed: make(map[string]bool)}
+	if cfg, err := config.Load(); err != nil || !cfg.GetReloadSettings().Watch {
+		return w
+	}
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		debug.For("reload").Warn("Not watching the config files: %v", err)
+		return w
+	}
+	// Directories are watched, since saves replace the files by renaming
+	dirs := make(map[string]bool)
+	for _, path := range []string{config.GetConfigPath(), config.GetKeybindingsPath(), config.GetProjectsPath()} {
+		if path == "" {
+			continue
+		}
+		w.paths[filepath.Clean(path)] = true
+		dirs[filepath.Dir(path)] = true
+	}
+	for dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err == nil {
+			err = fsw.Add(dir)
+		}
+		if err != nil {
+			debug.For("reload").Warn("Not watching %s: %v", dir, err)
+		}
+	}
+	w.fsw = fsw
+	w.enabled = true
+	return w
+}
+
+// Init starts waiting for changes if the watcher is enabled
+func (w ConfigWatcher) Init() tea.Cmd {
+	if !w.enabled {
+		return nil
+	}
+	return w.waitForEvent()
+}
+
+// Close stops watching
+func (w ConfigWatcher) Close() {
+	if w.fsw != nil {
+		w.fsw.Close()
+	}
+}
+
+// Update notes changed files and returns ConfigChangedMsg once a change has
+// settled
+func (w ConfigWatcher) Update(msg tea.Msg) (ConfigWatcher, tea.Cmd) {
+	if !w.enabled {
+		return w, nil
+	}
+	switch msg := msg.(type) {
+	case ConfigFileEventMsg:
+		if !w.paths[msg.path] {
+			return w, w.waitForEvent()
+		}
+		// Wait for a quiet interval before reloading
+		w.changed[msg.path] = true
+		w.gen++
+		gen := w.gen
+		return w, tea.Batch(w.waitForEvent(), tea.Tick(configSettleDelay, func(time.Time) tea.Msg {
+			return ConfigWatchTickMsg{gen: gen}
+		}))
+
+	case ConfigWatchTickMsg:
+		if msg.gen != w.gen || len(w.changed) == 0 {
+			return w, nil
+		}
+		external := false
+		for path := range w.changed {
+			if !config.WrittenByCM(path) {
+				external = true
+			}
+		}
+		w.changed = make(map[string]bool)
+		if external {
+			return w, func() tea.Msg { return ConfigChangedMsg{} }
+		}
+	}
+	return w, nil
+}
+
+// waitForEvent waits for the next change in the watched directories
+func (w ConfigWatcher) waitForEvent() tea.Cmd {
+	fsw := w.fsw
+	return func() tea.Msg {
+		for {
+			select {
+			case ev, ok := <-fsw.Events:
+				if !ok {
+					return nil
+				}
+				// A chmod alone doesn't change the contents
+				if ev.Op == fsnotify.Chmod {
+					continue
+				}
+				return ConfigFileEventMsg{path: filepath.Clean(ev.Name)}
+			case err, ok := <-fsw.Errors:
+				if !ok {
+					return nil
+				}
+				debug.For("reload").Warn("Config watcher: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cm/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// immediateMsgs runs cmd and returns the messages that arrive without waiting
+// for a tick
+func immediateMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	results := make(chan tea.Msg, 1)
+	go func() { results <- cmd() }()
+	var msg tea.Msg
+	select {
+	case msg = <-results:
+	case <-time.After(100 * time.Millisecond):
+		return nil
+	}
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, immediateMsgs(c)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+func hasConfigChanged(msgs []tea.Msg) bool {
+	for _, msg := range msgs {
+		if _, ok := msg.(ConfigChangedMsg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func TestConfigWatcherReportsSettledChanges(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	// Disabled unless reload.watch is set
+	if w := NewConfigWatcher(); w.Init() != nil {
+		t.Fatalf("expected the watcher to be off by default")
+	}
+
+	cfg := &config.Config{Reload: &config.ReloadSettings{Watch: true}}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	w := NewConfigWatcher()
+	defer w.Close()
+	cmd := w.Init()
+	if cmd == nil {
+		t.Fatalf("expected the watcher to start when reload.watch is set")
+	}
+
+	// fsnotify reports an edit of the file
+	path := filepath.Clean(config.GetConfigPath())
+	os.WriteFile(path, []byte(`{"reload": {"watch": true}, "display": {`), 0644)
+	var ev ConfigFileEventMsg
+	for ev.path != path {
+		msgs := make(chan tea.Msg, 1)
+		go func() { msgs <- cmd() }()
+		select {
+		case msg := <-msgs:
+			ev, _ = msg.(ConfigFileEventMsg)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected fsnotify to report the edit")
+		}
+	}
+
+	// An edit is reported only after a quiet interval with no further writes
+	w, _ = w.Update(ev)
+	stale := w.gen
+	os.WriteFile(path, []byte(`{"reload": {"watch": true}, "display": {"utc_timestamps": true}}`), 0644)
+	w, _ = w.Update(ev)
+	if _, cmd = w.Update(ConfigWatchTickMsg{gen: stale}); hasConfigChanged(immediateMsgs(cmd)) {
+		t.Fatalf("expected a second write to restart the wait")
+	}
+	w, cmd = w.Update(ConfigWatchTickMsg{gen: w.gen})
+	if !hasConfigChanged(immediateMsgs(cmd)) {
+		t.Fatalf("expected the settled change to be reported")
+	}
+	w, cmd = w.Update(ConfigWatchTickMsg{gen: w.gen})
+	if hasConfigChanged(immediateMsgs(cmd)) {
+		t.Fatalf("expected the change to be reported once")
+	}
+
+	// cm's own saves and other files in the directory are ignored
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	w, _ = w.Update(ConfigFileEventMsg{path: path})
+	w, _ = w.Update(ConfigFileEventMsg{path: filepath.Join(filepath.Dir(path), "notes.txt")})
+	if _, cmd = w.Update(ConfigWatchTickMsg{gen: w.gen}); hasConfigChanged(immediateMsgs(cmd)) {
+		t.Fatalf("expected cm's own save not to be reported")
+	}
+}
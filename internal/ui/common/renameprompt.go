@@ -0,0 +1,119 @@
+package common
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RenamePromptConfirmedMsg is sent when the user confirms a new container name
+type RenamePromptConfirmedMsg struct {
+	ContainerID string
+	OldName     string
+	NewName     string
+}
+
+// RenamePrompt asks for a new name for a container
+type RenamePrompt struct {
+	visible     bool
+	containerID string
+	oldName     string
+	input       textinput.Model
+}
+
+// NewRenamePrompt creates a new rename prompt
+func NewRenamePrompt() RenamePrompt {
+	input := textinput.New()
+	input.Placeholder = "new-name"
+	input.CharLimit = 128
+	input.Width = 30
+
+	return RenamePrompt{input: input}
+}
+
+// Open shows the prompt for a container, prefilled with its current name
+func (p *RenamePrompt) Open(containerID, name string) tea.Cmd {
+	p.visible = true
+	p.containerID = containerID
+	p.oldName = name
+	p.input.SetValue(name)
+	p.input.CursorEnd()
+	p.input.Focus()
+	return textinput.Blink
+}
+
+// Close hides the prompt
+func (p *RenamePrompt) Close() {
+	p.visible = false
+	p.input.Blur()
+}
+
+// IsVisible returns whether the prompt is visible
+func (p RenamePrompt) IsVisible() bool {
+	return p.visible
+}
+
+// Update handles messages for the prompt
+func (p RenamePrompt) Update(msg tea.Msg) (RenamePrompt, tea.Cmd) {
+	if !p.visible {
+		return p, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc":
+		p.Close()
+		return p, nil
+
+	case "enter":
+		name := strings.TrimSpace(p.input.Value())
+		if name == "" || name == p.oldName {
+			p.Close()
+			return p, nil
+		}
+		p.Close()
+		confirmed := RenamePromptConfirmedMsg{
+			ContainerID: p.containerID,
+			OldName:     p.oldName,
+			NewName:     name,
+		}
+		return p, func() tea.Msg { return confirmed }
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(CleanPaste(keyMsg))
+	return p, cmd
+}
+
+// View renders the prompt as a single bar
+func (p RenamePrompt) View(screenWidth int) string {
+	if !p.visible {
+		return ""
+	}
+
+	label := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("117")).
+		Bold(true).
+		Render("Rename " + p.oldName)
+
+	parts := []string{
+		label,
+		MutedInlineStyle.Render("  name: "),
+		p.input.View(),
+		MutedInlineStyle.Render("  enter:rename esc:cancel"),
+	}
+
+	barStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("236")).
+		Foreground(lipgloss.Color("252")).
+		Padding(0, 1).
+		Width(screenWidth)
+
+	return barStyle.Render(strings.Join(parts, ""))
+}
@@ -2,14 +2,17 @@ package common
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"cm/internal/config"
+	"cm/internal/docker"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
 // SavedProjectsClosedMsg is sent when the saved projects modal is closed
@@ -17,6 +20,20 @@ type SavedProjectsClosedMsg struct {
 	Changed bool
 }
 
+// SavedProjectLaunchMsg is sent to open the log view for a saved project
+type SavedProjectLaunchMsg struct {
+	Name string
+}
+
+// projectResolvedMsg carries the result of checking a compose file entered
+// to add a project
+type projectResolvedMsg struct {
+	name        string
+	configFiles []string
+	workingDir  string
+	err         error
+}
+
 // SavedProjectsModal represents the saved projects management modal
 type SavedProjectsModal struct {
 	visible  bool
@@ -26,23 +43,67 @@ type SavedProjectsModal struct {
 	projects []savedProject
 	cursor   int
 	selected map[string]bool
+	views    map[string]config.SavedView // current log view per project, when opened from the log view
+	status   string
+	// launch enables opening the project under the cursor, for screens
+	// that handle SavedProjectLaunchMsg
+	launch bool
+	// adding is set while the compose file path input is open
+	adding   bool
+	addInput textinput.Model
+	// editing is set while the paths of the project under the cursor are
+	// edited; editInputs holds the compose file, working dir and env file
+	editing    bool
+	editInputs [3]textinput.Model
+	editFocus  int
 }
 
 type savedProject struct {
-	name       string
-	workingDir string
+	name        string
+	configFiles []string
+	workingDir  string
+	envFile     string
+	view        *config.SavedView
+	missing     bool // working dir, a compose file or the env file no longer exists
 }
 
 // NewSavedProjectsModal creates a new saved projects modal
 func NewSavedProjectsModal() SavedProjectsModal {
-	return SavedProjectsModal{
+	input := textinput.New()
+	input.Placeholder = "path/to/compose.yaml or project dir"
+	input.CharLimit = 512
+	input.Width = 40
+
+	m := SavedProjectsModal{
 		visible:  false,
 		selected: make(map[string]bool),
+		addInput: input,
+	}
+	for i := range m.editInputs {
+		m.editInputs[i] = textinput.New()
+		m.editInputs[i].CharLimit = 512
+		m.editInputs[i].Width = 40
 	}
+	m.editInputs[2].Placeholder = "none"
+	return m
 }
 
 // Open opens the modal and loads saved projects
 func (m *SavedProjectsModal) Open() tea.Cmd {
+	m.loadProjects()
+	m.visible = true
+	m.cursor = 0
+	m.selected = make(map[string]bool)
+	m.status = ""
+	m.adding = false
+	m.addInput.Blur()
+	m.editing = false
+
+	return nil
+}
+
+// loadProjects reads the saved projects from disk into the list
+func (m *SavedProjectsModal) loadProjects() {
 	m.proj = config.LoadProjects()
 	m.projects = make([]savedProject, 0, len(m.proj.SavedProjects))
 
@@ -56,18 +117,252 @@ func (m *SavedProjectsModal) Open() tea.Cmd {
 	for _, name := range names {
 		proj := m.proj.SavedProjects[name]
 		m.projects = append(m.projects, savedProject{
-			name:       name,
-			workingDir: proj.WorkingDir,
+			name:        name,
+			configFiles: proj.ConfigFiles,
+			workingDir:  proj.WorkingDir,
+			envFile:     proj.EnvFile,
+			view:        proj.View,
+			missing:     pathsMissing(proj),
 		})
 	}
+}
 
-	m.visible = true
-	m.cursor = 0
-	m.selected = make(map[string]bool)
+// pathsMissing reports whether the working dir, env file or any compose
+// file of a project no longer exists
+func pathsMissing(proj config.SavedProject) bool {
+	for _, path := range append([]string{proj.WorkingDir, proj.EnvFile}, proj.ConfigFiles...) {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// removeMissing removes every project flagged as missing
+func (m *SavedProjectsModal) removeMissing() {
+	removed := 0
+	for _, p := range m.projects {
+		if p.missing {
+			m.proj.RemoveProject(p.name)
+			delete(m.selected, p.name)
+			removed++
+		}
+	}
+	if removed == 0 {
+		m.status = "No missing projects"
+		return
+	}
+	if err := m.proj.Save(); err != nil {
+		m.status = "Save failed: " + err.Error()
+		return
+	}
+	docker.InvalidateConfigCache()
+	m.loadProjects()
+	if m.cursor >= len(m.projects) {
+		m.cursor = max(len(m.projects)-1, 0)
+	}
+	m.status = fmt.Sprintf("Removed %d missing projects", removed)
+}
+
+// resolveProject checks the entered compose file path in the background,
+// since naming the project may run docker compose
+func resolveProject(path string) tea.Cmd {
+	return func() tea.Msg {
+		name, configFiles, workingDir, err := docker.ResolveComposeProject(path)
+		return projectResolvedMsg{name: name, configFiles: configFiles, workingDir: workingDir, err: err}
+	}
+}
+
+// addProject saves a project checked by resolveProject and moves the cursor to it
+func (m *SavedProjectsModal) addProject(msg projectResolvedMsg) {
+	if msg.err != nil {
+		m.status = "Not added: " + msg.err.Error()
+		return
+	}
+	proj := m.proj.SavedProjects[msg.name]
+	proj.ConfigFiles = msg.configFiles
+	proj.WorkingDir = msg.workingDir
+	m.proj.SavedProjects[msg.name] = proj
+	if err := m.proj.Save(); err != nil {
+		m.status = "Save failed: " + err.Error()
+		return
+	}
+	docker.InvalidateConfigCache()
+
+	m.loadProjects()
+	for i, p := range m.projects {
+		if p.name == msg.name {
+			m.cursor = i
+		}
+	}
+	m.status = "Added " + msg.name
+}
+
+// updateAdding handles keys while the compose file path input is open
+func (m SavedProjectsModal) updateAdding(msg tea.KeyMsg) (SavedProjectsModal, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.adding = false
+		m.addInput.Blur()
+		return m, nil
+	case "enter":
+		path := strings.TrimSpace(m.addInput.Value())
+		if path == "" {
+			return m, nil
+		}
+		m.adding = false
+		m.addInput.Blur()
+		m.status = "Checking " + path + "..."
+		return m, resolveProject(path)
+	}
+	var cmd tea.Cmd
+	m.addInput, cmd = m.addInput.Update(CleanPaste(msg))
+	return m, cmd
+}
+
+// startEdit opens the path inputs for the project under the cursor
+func (m *SavedProjectsModal) startEdit() tea.Cmd {
+	proj := m.projects[m.cursor]
+	m.editing = true
+	m.status = ""
+	var configFile string
+	if len(proj.configFiles) > 0 {
+		configFile = proj.configFiles[0]
+	}
+	m.editInputs[0].SetValue(configFile)
+	m.editInputs[1].SetValue(proj.workingDir)
+	m.editInputs[2].SetValue(proj.envFile)
+	m.editFocus = 0
+	for i := range m.editInputs {
+		m.editInputs[i].Blur()
+	}
+	m.editInputs[0].Focus()
+	return textinput.Blink
+}
+
+// updateEditing handles keys while the path inputs are open
+func (m SavedProjectsModal) updateEditing(msg tea.KeyMsg) (SavedProjectsModal, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.editing = false
+		return m, nil
+	case "tab", "shift+tab", "up", "down":
+		m.editInputs[m.editFocus].Blur()
+		step := 1
+		if msg.String() == "shift+tab" || msg.String() == "up" {
+			step = len(m.editInputs) - 1
+		}
+		m.editFocus = (m.editFocus + step) % len(m.editInputs)
+		return m, m.editInputs[m.editFocus].Focus()
+	case "enter":
+		if err := m.saveEdit(); err != nil {
+			m.status = "Not saved: " + err.Error()
+			return m, nil
+		}
+		m.editing = false
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.editInputs[m.editFocus], cmd = m.editInputs[m.editFocus].Update(CleanPaste(msg))
+	return m, cmd
+}
+
+// saveEdit checks the edited paths exist and stores them for the project
+// under the cursor. An empty working dir defaults to the compose file's dir,
+// and a relative env file is taken from the working dir. The edited file
+// replaces the project's first compose file; override files after it are kept.
+func (m *SavedProjectsModal) saveEdit() error {
+	configFile, err := docker.ExpandPath(strings.TrimSpace(m.editInputs[0].Value()))
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(configFile); err != nil {
+		return err
+	} else if info.IsDir() {
+		return fmt.Errorf("%s is a directory", configFile)
+	}
+	workingDir := filepath.Dir(configFile)
+	if dir := strings.TrimSpace(m.editInputs[1].Value()); dir != "" {
+		if workingDir, err = docker.ExpandPath(dir); err != nil {
+			return err
+		}
+		if info, err := os.Stat(workingDir); err != nil {
+			return err
+		} else if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", workingDir)
+		}
+	}
+	var envFile string
+	if path := strings.TrimSpace(m.editInputs[2].Value()); path != "" {
+		if !filepath.IsAbs(path) && !strings.HasPrefix(path, "~/") {
+			path = filepath.Join(workingDir, path)
+		}
+		if envFile, err = docker.ExpandPath(path); err != nil {
+			return err
+		}
+		if info, err := os.Stat(envFile); err != nil {
+			return err
+		} else if info.IsDir() {
+			return fmt.Errorf("%s is a directory", envFile)
+		}
+	}
 
+	name := m.projects[m.cursor].name
+	proj := m.proj.SavedProjects[name]
+	configFiles := []string{configFile}
+	if len(proj.ConfigFiles) > 1 {
+		configFiles = append(configFiles, proj.ConfigFiles[1:]...)
+	}
+	proj.ConfigFiles = configFiles
+	proj.WorkingDir = workingDir
+	proj.EnvFile = envFile
+	m.proj.SavedProjects[name] = proj
+	if err := m.proj.Save(); err != nil {
+		return err
+	}
+	docker.InvalidateConfigCache()
+	m.projects[m.cursor].configFiles = configFiles
+	m.projects[m.cursor].workingDir = workingDir
+	m.projects[m.cursor].envFile = envFile
+	m.projects[m.cursor].missing = pathsMissing(proj)
+	m.status = "Updated " + name
 	return nil
 }
 
+// EnableLaunch lets the modal open the project under the cursor with "o"
+func (m *SavedProjectsModal) EnableLaunch() {
+	m.launch = true
+}
+
+// SetCurrentViews sets the views the log view currently shows, keyed by
+// project, so they can be saved from the modal. Call before Open.
+func (m *SavedProjectsModal) SetCurrentViews(views map[string]config.SavedView) {
+	m.views = views
+}
+
+// saveView stores (or with nil, clears) the view of the project under the cursor
+func (m *SavedProjectsModal) saveView(view *config.SavedView) {
+	name := m.projects[m.cursor].name
+	if !m.proj.SetView(name, view) {
+		return
+	}
+	if err := m.proj.Save(); err != nil {
+		m.status = "Save failed: " + err.Error()
+		return
+	}
+	// The docker package caches projects and would otherwise write back a stale copy
+	docker.InvalidateConfigCache()
+	m.projects[m.cursor].view = view
+	if view != nil {
+		m.status = fmt.Sprintf("Saved view for %s (%d services)", name, len(view.Services))
+	} else {
+		m.status = "Cleared view for " + name
+	}
+}
+
 // Close closes the modal
 func (m *SavedProjectsModal) Close() {
 	m.visible = false
@@ -84,6 +379,15 @@ func (m *SavedProjectsModal) SetSize(width, height int) {
 	m.height = height
 }
 
+// visibleRows returns how many projects the list shows at once: up to 10,
+// fewer when the screen is too short to fit them around the modal's other lines
+func (m SavedProjectsModal) visibleRows() int {
+	if m.height <= 0 {
+		return 10
+	}
+	return max(min(10, m.height-17), 3)
+}
+
 // Update handles messages for the modal
 func (m SavedProjectsModal) Update(msg tea.Msg) (SavedProjectsModal, tea.Cmd) {
 	if !m.visible {
@@ -91,12 +395,35 @@ func (m SavedProjectsModal) Update(msg tea.Msg) (SavedProjectsModal, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
+	case projectResolvedMsg:
+		m.addProject(msg)
+
 	case tea.KeyMsg:
+		if m.adding {
+			return m.updateAdding(msg)
+		}
+		if m.editing {
+			return m.updateEditing(msg)
+		}
 		switch {
 		case key.Matches(msg, key.NewBinding(key.WithKeys("esc"))):
 			m.visible = false
 			return m, func() tea.Msg { return SavedProjectsClosedMsg{Changed: false} }
 
+		case key.Matches(msg, key.NewBinding(key.WithKeys("n"))):
+			// Register a project from its compose file
+			m.adding = true
+			m.status = ""
+			m.addInput.SetValue("")
+			m.addInput.Focus()
+			return m, textinput.Blink
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("e"))):
+			// Fix the paths of a project that has moved
+			if m.cursor < len(m.projects) {
+				return m, m.startEdit()
+			}
+
 		case key.Matches(msg, key.NewBinding(key.WithKeys("up", "k"))):
 			if m.cursor > 0 {
 				m.cursor--
@@ -128,6 +455,36 @@ func (m SavedProjectsModal) Update(msg tea.Msg) (SavedProjectsModal, tea.Cmd) {
 			// Clear selection
 			m.selected = make(map[string]bool)
 
+		case key.Matches(msg, key.NewBinding(key.WithKeys("o"))):
+			// Open the project under the cursor in the log view
+			if m.launch && m.cursor < len(m.projects) {
+				name := m.projects[m.cursor].name
+				m.visible = false
+				return m, func() tea.Msg { return SavedProjectLaunchMsg{Name: name} }
+			}
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("v"))):
+			// Save the current log view for the project under the cursor
+			if m.cursor < len(m.projects) {
+				if view, ok := m.views[m.projects[m.cursor].name]; ok {
+					m.saveView(&view)
+				} else if m.views == nil {
+					m.status = "Open from the log view to save a view"
+				} else {
+					m.status = "No panes from " + m.projects[m.cursor].name + " are open"
+				}
+			}
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("x"))):
+			// Forget the saved view of the project under the cursor
+			if m.cursor < len(m.projects) && m.projects[m.cursor].view != nil {
+				m.saveView(nil)
+			}
+
+		case key.Matches(msg, key.NewBinding(key.WithKeys("D"))):
+			// Clean up projects whose repos were moved or deleted
+			m.removeMissing()
+
 		case key.Matches(msg, key.NewBinding(key.WithKeys("d", "backspace", "delete"))):
 			// Remove selected projects
 			if len(m.selected) > 0 {
@@ -135,6 +492,7 @@ func (m SavedProjectsModal) Update(msg tea.Msg) (SavedProjectsModal, tea.Cmd) {
 					m.proj.RemoveProject(name)
 				}
 				if err := m.proj.Save(); err == nil {
+					docker.InvalidateConfigCache()
 					m.visible = false
 					return m, func() tea.Msg { return SavedProjectsClosedMsg{Changed: true} }
 				}
@@ -146,6 +504,7 @@ func (m SavedProjectsModal) Update(msg tea.Msg) (SavedProjectsModal, tea.Cmd) {
 				name := m.projects[m.cursor].name
 				m.proj.RemoveProject(name)
 				if err := m.proj.Save(); err == nil {
+					docker.InvalidateConfigCache()
 					m.visible = false
 					return m, func() tea.Msg { return SavedProjectsClosedMsg{Changed: true} }
 				}
@@ -155,6 +514,7 @@ func (m SavedProjectsModal) Update(msg tea.Msg) (SavedProjectsModal, tea.Cmd) {
 					m.proj.RemoveProject(name)
 				}
 				if err := m.proj.Save(); err == nil {
+					docker.InvalidateConfigCache()
 					m.visible = false
 					return m, func() tea.Msg { return SavedProjectsClosedMsg{Changed: true} }
 				}
@@ -188,7 +548,7 @@ func (m SavedProjectsModal) View(screenWidth, screenHeight int) string {
 		}
 
 		// List projects
-		maxVisible := 10
+		maxVisible := m.visibleRows()
 		start := 0
 		if m.cursor >= maxVisible {
 			start = m.cursor - maxVisible + 1
@@ -215,12 +575,21 @@ func (m SavedProjectsModal) View(screenWidth, screenHeight int) string {
 			}
 
 			line := fmt.Sprintf("%s%s %s", cursor, checkbox, proj.name)
+			if proj.view != nil {
+				line += fmt.Sprintf(" ◆ %d services", len(proj.view.Services))
+			}
 			if i == m.cursor {
 				line = ModalSelectedStyle.Render(line)
-				content.WriteString(line)
+			}
+			if proj.missing {
+				line += " " + StoppedStyle.Render("(missing)")
+			}
+			content.WriteString(line)
+			if i == m.cursor {
 				content.WriteString(MutedInlineStyle.Render(fmt.Sprintf("\n       %s", dir)))
-			} else {
-				content.WriteString(line)
+				if proj.envFile != "" {
+					content.WriteString(MutedInlineStyle.Render(fmt.Sprintf("\n       env: %s", filepath.Base(proj.envFile))))
+				}
 			}
 			content.WriteString("\n")
 		}
@@ -230,34 +599,49 @@ func (m SavedProjectsModal) View(screenWidth, screenHeight int) string {
 		}
 	}
 
+	if m.adding {
+		content.WriteString("\n  Compose file: ")
+		content.WriteString(m.addInput.View())
+		content.WriteString("\n")
+	}
+	if m.editing {
+		content.WriteString("\n  Compose file: ")
+		content.WriteString(m.editInputs[0].View())
+		if n := len(m.projects[m.cursor].configFiles) - 1; n > 0 {
+			content.WriteString(MutedInlineStyle.Render(fmt.Sprintf("\n                %d override file(s) kept", n)))
+		}
+		content.WriteString("\n  Working dir:  ")
+		content.WriteString(m.editInputs[1].View())
+		content.WriteString("\n  Env file:     ")
+		content.WriteString(m.editInputs[2].View())
+		content.WriteString("\n")
+	}
+	if m.status != "" {
+		content.WriteString(HelpKeyStyle.Render("  " + m.status))
+		content.WriteString("\n")
+	}
 	content.WriteString("\n")
 
 	// Help
-	content.WriteString(MutedInlineStyle.Render("  j/k:nav  space:sel  a/A:all/clr  d/⏎:remove  esc:close"))
+	if m.adding {
+		content.WriteString(MutedInlineStyle.Render("  enter:add  esc:cancel"))
+	} else if m.editing {
+		content.WriteString(MutedInlineStyle.Render("  tab:next field  enter:save  esc:cancel"))
+	} else {
+		content.WriteString(MutedInlineStyle.Render("  j/k:nav  space:sel  a/A:all/clr  d/⏎:remove  n:add  e:edit  esc:close"))
+		content.WriteString("\n")
+		viewHelp := "  x:clear view  D:remove missing"
+		if m.views != nil {
+			viewHelp = "  v:save current view  x:clear view  D:remove missing"
+		}
+		if m.launch {
+			viewHelp = "  o:open" + viewHelp
+		}
+		content.WriteString(MutedInlineStyle.Render(viewHelp))
+	}
 
 	// Style the modal
 	modalContent := ModalStyle.Render(content.String())
 
-	// Get modal dimensions
-	modalWidth := lipgloss.Width(modalContent)
-	modalHeight := lipgloss.Height(modalContent)
-
-	// Center the modal
-	x := (screenWidth - modalWidth) / 2
-	y := (screenHeight - modalHeight) / 2
-
-	if x < 0 {
-		x = 0
-	}
-	if y < 0 {
-		y = 0
-	}
-
-	// Create positioned modal
-	positioned := lipgloss.NewStyle().
-		MarginLeft(x).
-		MarginTop(y).
-		Render(modalContent)
-
-	return positioned
+	return PlaceModal(modalContent, screenWidth, screenHeight)
 }
@@ -0,0 +1,155 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"cm/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSavedProjectsModalLaunchesProjectUnderCursor(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	projects := &config.Projects{SavedProjects: map[string]config.SavedProject{
+		"blog": {WorkingDir: "/src/blog"},
+		"shop": {WorkingDir: "/src/shop"},
+	}}
+	if err := projects.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	openKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")}
+
+	m := NewSavedProjectsModal()
+	m.Open()
+	if _, cmd := m.Update(openKey); cmd != nil {
+		t.Fatalf("expected no launch unless the screen enables it")
+	}
+
+	m.EnableLaunch()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, cmd := m.Update(openKey)
+	if cmd == nil {
+		t.Fatalf("expected a launch command")
+	}
+	if msg, ok := cmd().(SavedProjectLaunchMsg); !ok || msg.Name != "shop" {
+		t.Fatalf("expected to launch shop, got %#v", cmd())
+	}
+	if m.IsVisible() {
+		t.Fatalf("expected the modal to close on launch")
+	}
+}
+
+func TestSavedProjectsModalAddsProjectFromComposeFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "docker-compose.yml"), []byte("name: blog\nservices:\n  web:\n    image: nginx\n"), 0644)
+
+	m := NewSavedProjectsModal()
+	m.Open()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if !m.adding {
+		t.Fatalf("expected n to open the path input")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(dir)})
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatalf("expected enter to check the path")
+	}
+	m, _ = m.Update(cmd())
+
+	p, ok := config.LoadProjects().SavedProjects["blog"]
+	if !ok || p.WorkingDir != dir || !slices.Equal(p.ConfigFiles, []string{filepath.Join(dir, "docker-compose.yml")}) {
+		t.Fatalf("expected blog to be saved, got %+v (status %q)", p, m.status)
+	}
+	if m.projects[m.cursor].name != "blog" {
+		t.Fatalf("expected the cursor on the new project")
+	}
+
+	// A path that isn't a compose file is reported and not saved
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(filepath.Join(dir, "nope.yml"))})
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.Update(cmd())
+	if !strings.HasPrefix(m.status, "Not added") || len(config.LoadProjects().SavedProjects) != 1 {
+		t.Fatalf("expected a missing file to be rejected, got status %q", m.status)
+	}
+}
+
+func TestSavedProjectsModalEditsMovedProject(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	projects := &config.Projects{SavedProjects: map[string]config.SavedProject{
+		"shop": {ConfigFiles: []string{"/old/shop/compose.yaml", "/old/shop/compose.override.yaml"}, WorkingDir: "/old/shop", View: &config.SavedView{Services: []string{"api"}}},
+	}}
+	if err := projects.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	dir := t.TempDir()
+	composeFile := filepath.Join(dir, "compose.yaml")
+	os.WriteFile(composeFile, []byte("services:\n  api:\n    image: nginx\n"), 0644)
+
+	m := NewSavedProjectsModal()
+	m.Open()
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	if !m.editing || m.editInputs[0].Value() != "/old/shop/compose.yaml" {
+		t.Fatalf("expected e to edit the stored paths")
+	}
+
+	// A compose file that doesn't exist is rejected
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.editing || !strings.HasPrefix(m.status, "Not saved") {
+		t.Fatalf("expected the stale path to be rejected, got status %q", m.status)
+	}
+
+	// Clearing the working dir defaults it to the compose file's dir, and a
+	// relative env file is found in it
+	os.WriteFile(filepath.Join(dir, "dev.env"), []byte("PORT=8080\n"), 0644)
+	m.editInputs[0].SetValue(composeFile)
+	m.editInputs[1].SetValue("")
+	m.editInputs[2].SetValue("dev.env")
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.editing {
+		t.Fatalf("expected the edit to be saved, got status %q", m.status)
+	}
+	p := config.LoadProjects().SavedProjects["shop"]
+	if !slices.Equal(p.ConfigFiles, []string{composeFile, "/old/shop/compose.override.yaml"}) || p.WorkingDir != dir || p.View == nil {
+		t.Fatalf("expected the new paths to be saved with the override file and view kept, got %+v", p)
+	}
+	if p.EnvFile != filepath.Join(dir, "dev.env") {
+		t.Fatalf("expected the env file to be saved, got %q", p.EnvFile)
+	}
+}
+
+func TestSavedProjectsModalFlagsAndRemovesMissingProjects(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	dir := t.TempDir()
+	projects := &config.Projects{SavedProjects: map[string]config.SavedProject{
+		"blog": {WorkingDir: dir},
+		"shop": {ConfigFiles: []string{filepath.Join(dir, "gone.yaml")}, WorkingDir: dir},
+	}}
+	if err := projects.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	m := NewSavedProjectsModal()
+	m.Open()
+	if m.projects[0].missing || !m.projects[1].missing {
+		t.Fatalf("expected only shop to be flagged, got %+v", m.projects)
+	}
+	if !strings.Contains(m.View(120, 40), "(missing)") {
+		t.Fatalf("expected the missing tag to be rendered")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+	saved := config.LoadProjects().SavedProjects
+	if _, ok := saved["shop"]; ok || len(saved) != 1 || len(m.projects) != 1 {
+		t.Fatalf("expected only the missing project to be removed, got %+v", saved)
+	}
+}
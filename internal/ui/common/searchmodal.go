@@ -119,7 +119,7 @@ func (m SearchModal) Update(msg tea.Msg) (SearchModal, tea.Cmd) {
 		default:
 			// Update the text input
 			var cmd tea.Cmd
-			m.input, cmd = m.input.Update(msg)
+			m.input, cmd = m.input.Update(CleanPaste(msg))
 			cmds = append(cmds, cmd)
 			// Send search update
 			query := m.input.Value()
@@ -0,0 +1,31 @@
+package common
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSearchModalTakesPastesAsText(t *testing.T) {
+	m := NewSearchModal()
+	m.Open()
+
+	// A copied line with its newline, tab and key-like characters
+	paste := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("req-42\tq\n"), Paste: true}
+	m, _ = m.Update(paste)
+	if !m.IsVisible() || m.GetQuery() != "req-42 q" {
+		t.Fatalf("query after paste = %q (visible %v), want %q", m.GetQuery(), m.IsVisible(), "req-42 q")
+	}
+
+	// A pasted digit doesn't start a count
+	if _, ok := AppendCount(0, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5"), Paste: true}); ok {
+		t.Fatalf("expected a pasted digit not to start a count")
+	}
+
+	var s ModalSearch
+	s.Start()
+	s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a\r\nb\n"), Paste: true})
+	if s.Update(tea.KeyMsg{Type: tea.KeyEnter}); s.Query() != "a b" {
+		t.Fatalf("modal search query after paste = %q, want %q", s.Query(), "a b")
+	}
+}
@@ -0,0 +1,143 @@
+package common
+
+import (
+	"strings"
+
+	"cm/internal/docker"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SignalPromptConfirmedMsg is sent when the user picks a signal to send
+type SignalPromptConfirmedMsg struct {
+	ContainerID   string
+	ContainerName string
+	Signal        string // validated, e.g. SIGHUP
+}
+
+// commonSignals are cycled through with tab
+var commonSignals = []string{"SIGHUP", "SIGUSR1", "SIGUSR2", "SIGINT", "SIGTERM", "SIGQUIT"}
+
+// SignalPrompt asks which signal to send to a container
+type SignalPrompt struct {
+	visible       bool
+	containerID   string
+	containerName string
+	input         textinput.Model
+	preset        int    // index into commonSignals shown in the input
+	err           string // why the entered signal was rejected
+}
+
+// NewSignalPrompt creates a new signal prompt
+func NewSignalPrompt() SignalPrompt {
+	input := textinput.New()
+	input.Placeholder = "SIGHUP"
+	input.CharLimit = 16
+	input.Width = 12
+
+	return SignalPrompt{input: input}
+}
+
+// Open shows the prompt for a container, prefilled with SIGHUP
+func (p *SignalPrompt) Open(containerID, containerName string) tea.Cmd {
+	p.visible = true
+	p.containerID = containerID
+	p.containerName = containerName
+	p.preset = 0
+	p.err = ""
+	p.input.SetValue(commonSignals[0])
+	p.input.CursorEnd()
+	p.input.Focus()
+	return textinput.Blink
+}
+
+// Close hides the prompt
+func (p *SignalPrompt) Close() {
+	p.visible = false
+	p.input.Blur()
+}
+
+// IsVisible returns whether the prompt is visible
+func (p SignalPrompt) IsVisible() bool {
+	return p.visible
+}
+
+// Update handles messages for the prompt
+func (p SignalPrompt) Update(msg tea.Msg) (SignalPrompt, tea.Cmd) {
+	if !p.visible {
+		return p, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc":
+		p.Close()
+		return p, nil
+
+	case "tab", "shift+tab":
+		step := 1
+		if keyMsg.String() == "shift+tab" {
+			step = len(commonSignals) - 1
+		}
+		p.preset = (p.preset + step) % len(commonSignals)
+		p.input.SetValue(commonSignals[p.preset])
+		p.input.CursorEnd()
+		p.err = ""
+		return p, nil
+
+	case "enter":
+		signal, err := docker.ParseSignal(p.input.Value())
+		if err != nil {
+			p.err = err.Error()
+			return p, nil
+		}
+		p.Close()
+		confirmed := SignalPromptConfirmedMsg{
+			ContainerID:   p.containerID,
+			ContainerName: p.containerName,
+			Signal:        signal,
+		}
+		return p, func() tea.Msg { return confirmed }
+	}
+
+	p.err = ""
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(CleanPaste(keyMsg))
+	return p, cmd
+}
+
+// View renders the prompt as a single bar
+func (p SignalPrompt) View(screenWidth int) string {
+	if !p.visible {
+		return ""
+	}
+
+	label := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("117")).
+		Bold(true).
+		Render("Signal " + p.containerName)
+
+	parts := []string{
+		label,
+		MutedInlineStyle.Render("  signal: "),
+		p.input.View(),
+	}
+	if p.err != "" {
+		parts = append(parts, StoppedStyle.Render("  "+p.err))
+	}
+	parts = append(parts, MutedInlineStyle.Render("  tab:common signals enter:send esc:cancel"))
+
+	barStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("236")).
+		Foreground(lipgloss.Color("252")).
+		Padding(0, 1).
+		Width(screenWidth)
+
+	return barStyle.Render(strings.Join(parts, ""))
+}
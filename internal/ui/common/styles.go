@@ -45,6 +45,9 @@ var (
 	StoppedStyle = lipgloss.NewStyle().
 			Foreground(errorColor)
 
+	PausedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214")) // Orange
+
 	// Pane styles
 	PaneBorderStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
@@ -95,6 +98,17 @@ var (
 	MutedInlineStyle = lipgloss.NewStyle().
 				Foreground(mutedColor)
 
+	// Search highlight styles
+	SearchMatchStyle = lipgloss.NewStyle().
+				Background(lipgloss.Color("226")).
+				Foreground(lipgloss.Color("0")).
+				Bold(true)
+
+	SearchCurrentMatchStyle = lipgloss.NewStyle().
+				Background(lipgloss.Color("208")).
+				Foreground(lipgloss.Color("0")).
+				Bold(true)
+
 	// Modal styles
 	ModalOverlayStyle = lipgloss.NewStyle().
 				Background(lipgloss.Color("0"))
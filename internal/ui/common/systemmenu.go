@@ -0,0 +1,118 @@
+package common
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SystemActionMsg is sent when an action is chosen from the system menu
+type SystemActionMsg struct {
+	Action string
+}
+
+// System menu actions
+const (
+	SystemPruneContainers = "prune_containers"
+	SystemPruneImages     = "prune_images"
+	SystemPruneVolumes    = "prune_volumes"
+)
+
+type systemMenuItem struct {
+	action string
+	label  string
+	desc   string
+}
+
+var systemMenuItems = []systemMenuItem{
+	{SystemPruneContainers, "Prune stopped containers", "docker container prune"},
+	{SystemPruneImages, "Prune dangling images", "docker image prune"},
+	{SystemPruneVolumes, "Prune unused volumes", "docker volume prune"},
+}
+
+// SystemMenu lists Docker maintenance actions that aren't tied to a container
+type SystemMenu struct {
+	visible bool
+	cursor  int
+}
+
+// NewSystemMenu creates a new system menu
+func NewSystemMenu() SystemMenu {
+	return SystemMenu{}
+}
+
+// Open shows the menu
+func (m *SystemMenu) Open() {
+	m.visible = true
+	m.cursor = 0
+}
+
+// Close hides the menu
+func (m *SystemMenu) Close() {
+	m.visible = false
+}
+
+// IsVisible returns whether the menu is visible
+func (m SystemMenu) IsVisible() bool {
+	return m.visible
+}
+
+// Update handles messages for the menu
+func (m SystemMenu) Update(msg tea.Msg) (SystemMenu, tea.Cmd) {
+	if !m.visible {
+		return m, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q":
+		m.Close()
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(systemMenuItems)-1 {
+			m.cursor++
+		}
+	case "enter":
+		m.Close()
+		action := SystemActionMsg{Action: systemMenuItems[m.cursor].action}
+		return m, func() tea.Msg { return action }
+	}
+	return m, nil
+}
+
+// View renders the menu
+func (m SystemMenu) View(screenWidth, screenHeight int) string {
+	if !m.visible {
+		return ""
+	}
+
+	var content strings.Builder
+
+	content.WriteString(ModalTitleStyle.Render("System"))
+	content.WriteString("\n\n")
+
+	for i, item := range systemMenuItems {
+		if i == m.cursor {
+			content.WriteString(ModalSelectedStyle.Render("> " + item.label))
+		} else {
+			content.WriteString("  " + item.label)
+		}
+		content.WriteString(MutedInlineStyle.Render("  " + item.desc))
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
+
+	content.WriteString(MutedInlineStyle.Render("  j/k: navigate  enter: select  esc: close"))
+
+	// Style the modal
+	modalContent := ModalStyle.Render(content.String())
+
+	return PlaceModal(modalContent, screenWidth, screenHeight)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"cm/internal/config"
+	"cm/internal/debug"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -39,6 +40,7 @@ type Toast struct {
 	id       int
 	duration time.Duration
 	position config.ToastPosition
+	unbound  bool // show a toast for keys that aren't bound to anything
 }
 
 // NewToast creates a new toast manager
@@ -59,6 +61,7 @@ func NewToast() Toast {
 		id:       0,
 		duration: duration,
 		position: position,
+		unbound:  cfg != nil && cfg.GetNotificationSettings().UnboundKeys,
 	}
 }
 
@@ -85,9 +88,20 @@ func (t *Toast) ReloadConfig() {
 		settings := cfg.GetNotificationSettings()
 		t.duration = time.Duration(settings.GetToastDuration()) * time.Second
 		t.position = settings.GetToastPosition()
+		t.unbound = settings.UnboundKeys
 	}
 }
 
+// ShowUnboundKey reports a key that didn't match any binding: always in the
+// debug log, and as a toast when notifications.show_unbound_keys is set
+func (t *Toast) ShowUnboundKey(msg tea.KeyMsg) tea.Cmd {
+	debug.For("keys").Debug("Unbound key: %s", msg.String())
+	if !t.unbound {
+		return nil
+	}
+	return t.Show("Unbound", msg.String(), ToastInfo)
+}
+
 // Show displays a toast and returns a command to hide it after duration
 func (t *Toast) Show(title, message string, typ ToastType) tea.Cmd {
 	t.visible = true
@@ -2,23 +2,33 @@ package discovery
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"cm/internal/config"
 	"cm/internal/debug"
 	"cm/internal/docker"
+	"cm/internal/notify"
 	"cm/internal/ui/common"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// logger tags the discovery screen's debug log messages
+var logger = debug.For("discovery")
+
 // Messages
 type ContainersLoadedMsg struct {
-	Groups []docker.ContainerGroup
+	Containers   []docker.Container
+	LocalProject string
+	ConfigFiles  map[string][]string // compose file(s) per project
 }
 
 type ContainerSelectedMsg struct {
@@ -31,6 +41,11 @@ type LoadErrorMsg struct {
 
 type autoRefreshTickMsg struct{}
 
+type statsSampledMsg struct {
+	stats map[string]docker.ContainerStats
+	err   error
+}
+
 type bulkActionCompleteMsg struct {
 	action    string
 	succeeded int
@@ -42,6 +57,13 @@ type actionStartedMsg struct {
 	action string
 }
 
+type pruneCompleteMsg struct {
+	what      string // containers, images or volumes
+	removed   int
+	reclaimed uint64
+	err       error
+}
+
 // Build streaming messages
 type buildLogMsg struct {
 	log docker.OperationLog
@@ -59,11 +81,27 @@ type buildStreamStartedMsg struct {
 	op           string
 }
 
+// groupMode selects how the container list is grouped
+type groupMode int
+
+const (
+	groupByProject groupMode = iota
+	groupByImage
+)
+
 // Model represents the container discovery screen
 type Model struct {
+	containers         []docker.Container
+	localProject       string
+	showAll            bool // list every project even when cm runs in one's directory
+	configFiles        map[string][]string
+	projectConflicts   map[string][]string // project name -> working dirs using it
+	warnedConflicts    map[string]bool
+	groupMode          groupMode
 	groups             []docker.ContainerGroup
 	flatList           []listItem
 	cursor             int
+	count              int // pending count prefix, e.g. the 5 in 5j
 	selected           map[string]bool
 	width, height      int
 	ready              bool
@@ -74,18 +112,40 @@ type Model struct {
 	actionRunning      bool
 	configModal        common.ConfigModal
 	savedProjectsModal common.SavedProjectsModal
+	confirmModal       common.ConfirmModal
+	systemMenu         common.SystemMenu
 	toast              common.Toast
 	tutorial           common.Tutorial
 	buildPanel         common.BuildPanel
 	buildStream        *docker.StreamingResult
 	buildTargets       []docker.Container
+	stats              map[string][]docker.ContainerStats // recent samples per container ID, oldest first
+	statsSampling      bool
+	statsUnavailable   bool      // the last sample failed, e.g. no docker CLI
+	statsRetryAt       time.Time // no sampling before then, after a failed sample
 }
 
+// statsSamples is how many recent samples a row's sparkline shows
+const statsSamples = 8
+
+// statsRetryInterval is how long sampling pauses after a failed sample
+const statsRetryInterval = 30 * time.Second
+
 type listItem struct {
 	isGroup     bool
 	isSeparator bool
 	groupName   string
 	container   docker.Container
+	replicas    []docker.Container // every container of a scaled service, when there's more than one
+}
+
+// containers returns the containers the row stands for: all replicas of a
+// scaled service, or just its container
+func (item listItem) containers() []docker.Container {
+	if len(item.replicas) > 0 {
+		return item.replicas
+	}
+	return []docker.Container{item.container}
 }
 
 // selectionKey returns a stable key for selecting a container
@@ -103,16 +163,24 @@ func New(dockerClient *docker.Client, initialSelection []docker.Container) Model
 	for _, c := range initialSelection {
 		selected[selectionKey(c)] = true
 	}
-	return Model{
+	m := Model{
 		selected:           selected,
+		warnedConflicts:    make(map[string]bool),
 		keys:               common.DefaultKeyMap(),
 		dockerClient:       dockerClient,
 		configModal:        common.NewConfigModal(),
 		savedProjectsModal: common.NewSavedProjectsModal(),
+		confirmModal:       common.NewConfirmModal(),
+		systemMenu:         common.NewSystemMenu(),
 		toast:              common.NewToast(),
 		tutorial:           common.NewTutorial(),
 		buildPanel:         common.NewBuildPanel(),
 	}
+	m.savedProjectsModal.EnableLaunch()
+	if cfg, err := config.Load(); err == nil {
+		m.showAll = cfg.GetDiscoverySettings().ShowAll
+	}
+	return m
 }
 
 // Init initializes the model
@@ -127,18 +195,160 @@ func (m Model) loadContainers() tea.Cmd {
 			return LoadErrorMsg{Err: err}
 		}
 		localProject := docker.DetectLocalComposeProject()
-		groups := docker.GroupByComposeProject(containers, localProject)
-		return ContainersLoadedMsg{Groups: groups}
+		return ContainersLoadedMsg{Containers: containers, LocalProject: localProject, ConfigFiles: docker.ProjectConfigFiles()}
+	}
+}
+
+// sampleStats takes one batch stats sample for the list, unless one is still
+// running or no container is running
+func (m *Model) sampleStats() tea.Cmd {
+	if m.statsSampling || time.Now().Before(m.statsRetryAt) {
+		return nil
+	}
+	var running []string
+	for _, c := range m.containers {
+		if c.State == "running" {
+			running = append(running, c.ID)
+		}
+	}
+	if len(running) == 0 {
+		return nil
+	}
+	m.statsSampling = true
+	client := m.dockerClient
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		stats, err := client.StatsSnapshots(ctx, running)
+		return statsSampledMsg{stats: stats, err: err}
+	}
+}
+
+// applyStatsSample adds a finished stats sample to each container's history
+func (m *Model) applyStatsSample(msg statsSampledMsg) {
+	m.statsSampling = false
+	if msg.err != nil {
+		if !m.statsUnavailable {
+			logger.Warn("Container stats unavailable, retrying every %s: %v", statsRetryInterval, msg.err)
+		}
+		m.statsUnavailable = true
+		m.statsRetryAt = time.Now().Add(statsRetryInterval)
+		return
+	}
+	if m.statsUnavailable {
+		logger.Info("Container stats available again")
+		m.statsUnavailable = false
+	}
+	history := make(map[string][]docker.ContainerStats, len(msg.stats))
+	for id, s := range msg.stats {
+		samples := append(m.stats[id], s)
+		if len(samples) > statsSamples {
+			samples = samples[len(samples)-statsSamples:]
+		}
+		history[id] = samples
+	}
+	m.stats = history
+}
+
+// rowStats sums the sampled CPU history and latest memory of a row's
+// containers (all replicas of a scaled service), newest sample last
+func (m Model) rowStats(item listItem) (cpu []float64, mem float64, ok bool) {
+	for _, c := range item.containers() {
+		samples := m.stats[c.ID]
+		if len(samples) == 0 {
+			continue
+		}
+		ok = true
+		for len(cpu) < len(samples) {
+			cpu = append([]float64{0}, cpu...)
+		}
+		for k := 1; k <= len(samples); k++ {
+			cpu[len(cpu)-k] += samples[len(samples)-k].CPUPercent
+		}
+		mem += samples[len(samples)-1].MemoryPercent
+	}
+	return cpu, mem, ok
+}
+
+// sparkBlocks draws a sparkline from low to high
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders percentages as bars scaled to 100%
+func sparkline(values []float64) string {
+	var b strings.Builder
+	for _, v := range values {
+		i := int(v / 100 * float64(len(sparkBlocks)))
+		b.WriteRune(sparkBlocks[min(max(i, 0), len(sparkBlocks)-1)])
+	}
+	return b.String()
+}
+
+// localOnly reports whether the list is narrowed to the current directory's
+// project: one was detected, it has containers, and show all is off
+func (m Model) localOnly() bool {
+	if m.showAll || m.localProject == "" {
+		return false
+	}
+	for _, c := range m.containers {
+		if c.ComposeProject == m.localProject {
+			return true
+		}
 	}
+	return false
+}
+
+// listedContainers returns the loaded containers the list shows
+func (m Model) listedContainers() []docker.Container {
+	if !m.localOnly() {
+		return m.containers
+	}
+	var local []docker.Container
+	for _, c := range m.containers {
+		if c.ComposeProject == m.localProject {
+			local = append(local, c)
+		}
+	}
+	return local
+}
+
+// groupContainers groups the listed containers according to the group mode
+func (m Model) groupContainers() []docker.ContainerGroup {
+	containers := m.listedContainers()
+	if m.groupMode == groupByImage {
+		return docker.GroupByImage(containers)
+	}
+	return docker.GroupByComposeProject(containers, m.localProject)
 }
 
 // Update handles messages
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	// Handle window resize even when a modal or the build panel is open
+	if sizeMsg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = sizeMsg.Width
+		m.height = sizeMsg.Height
+		m.configModal.SetSize(sizeMsg.Width, sizeMsg.Height)
+		m.savedProjectsModal.SetSize(sizeMsg.Width, sizeMsg.Height)
+		m.buildPanel.SetSize(buildPanelWidth(sizeMsg.Width), sizeMsg.Height-2)
+		return m, nil
+	}
+
+	// Config files changed on disk (reload.watch); applies even with a modal open
+	if _, ok := msg.(common.ConfigChangedMsg); ok {
+		return m, tea.Batch(common.ShowReloadResult(&m.toast, m.reloadConfig(), "changed on disk, reloaded"), m.loadContainers())
+	}
+
+	// A sample finishing while a modal is open would otherwise leave
+	// sampling marked as running for good
+	if sampled, ok := msg.(statsSampledMsg); ok {
+		m.applyStatsSample(sampled)
+		return m, nil
+	}
+
 	// Handle build panel messages first
 	if m.buildPanel.IsVisible() {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
-			if msg.String() == "esc" || msg.String() == "q" {
+			if (msg.String() == "esc" || msg.String() == "q") && !m.buildPanel.CapturesKey(msg.String()) {
 				m.buildPanel.Close()
 				m.buildStream = nil
 				m.actionRunning = false
@@ -164,6 +374,16 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				m.actionStatus = fmt.Sprintf("%s completed", m.buildPanel.GetStatus())
 			} else {
 				m.actionStatus = fmt.Sprintf("%s failed", m.buildPanel.GetStatus())
+				// Say whether the services are still up, so it's clear if they need recovering
+				var failed *docker.BuildFailedError
+				if errors.As(msg.err, &failed) && len(failed.Services) > 0 {
+					report := failed.Report()
+					m.actionStatus += ": " + report
+					cmd = tea.Batch(cmd, func() tea.Msg {
+						notify.Error("Build failed: " + report)
+						return nil
+					})
+				}
 			}
 			return m, tea.Batch(cmd, m.loadContainers())
 		case common.BuildPanelCloseMsg:
@@ -171,16 +391,14 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			m.buildStream = nil
 			m.actionStatus = ""
 			return m, m.loadContainers()
-		case tea.WindowSizeMsg:
-			m.width = msg.Width
-			m.height = msg.Height
-			// Build panel takes 40% of width
-			panelWidth := msg.Width * 40 / 100
-			if panelWidth < 40 {
-				panelWidth = 40
-			}
-			m.buildPanel.SetSize(panelWidth, msg.Height-2)
-			return m, nil
+		case common.BuildPanelTickMsg:
+			var cmd tea.Cmd
+			m.buildPanel, cmd = m.buildPanel.Update(msg)
+			return m, cmd
+		case common.ShowToastMsg, common.ToastExpiredMsg:
+			var cmd tea.Cmd
+			m.toast, cmd = m.toast.Update(msg)
+			return m, cmd
 		}
 		return m, nil
 	}
@@ -189,15 +407,15 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	if streamMsg, ok := msg.(buildStreamStartedMsg); ok {
 		m.buildStream = &streamMsg.stream
 		m.buildTargets = streamMsg.targets
-		m.buildPanel.Start(streamMsg.op, streamMsg.serviceNames)
-		// Set initial panel size
-		panelWidth := m.width * 40 / 100
-		if panelWidth < 40 {
-			panelWidth = 40
+		var project string
+		if len(streamMsg.targets) > 0 {
+			project = streamMsg.targets[0].ComposeProject
 		}
-		m.buildPanel.SetSize(panelWidth, m.height-2)
+		tickCmd := m.buildPanel.Start(streamMsg.op, project, streamMsg.serviceNames)
+		// Set initial panel size
+		m.buildPanel.SetSize(buildPanelWidth(m.width), m.height-2)
 		// Start listening for stream output
-		return m, m.waitForBuildStream(streamMsg.stream)
+		return m, tea.Batch(m.waitForBuildStream(streamMsg.stream), tickCmd)
 	}
 
 	// Handle saved projects modal messages first
@@ -214,12 +432,31 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	// Handle system menu and confirm modal messages
+	if m.systemMenu.IsVisible() {
+		var cmd tea.Cmd
+		m.systemMenu, cmd = m.systemMenu.Update(msg)
+		return m, cmd
+	}
+	if m.confirmModal.IsVisible() {
+		var cmd tea.Cmd
+		m.confirmModal, cmd = m.confirmModal.Update(msg)
+		return m, cmd
+	}
+	if action, ok := msg.(common.SystemActionMsg); ok {
+		m.confirmPrune(action.Action)
+		return m, nil
+	}
+	if confirmed, ok := msg.(common.ConfirmedMsg); ok {
+		return m, m.prune(confirmed.Action)
+	}
+
 	// Handle modal closed messages
 	if closed, ok := msg.(common.ConfigModalClosedMsg); ok {
 		// Reload key bindings and toast settings in case they changed
 		m.keys = common.DefaultKeyMap()
 		if closed.ConfigChanged {
-			m.toast.ReloadConfig()
+			m.reloadConfig()
 		}
 		return m, nil
 	}
@@ -229,6 +466,11 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Launch a saved project: select its services and confirm
+	if launch, ok := msg.(common.SavedProjectLaunchMsg); ok {
+		return m, m.launchProject(launch.Name)
+	}
+
 	// Handle open saved projects modal message
 	if _, ok := msg.(common.OpenSavedProjectsMsg); ok {
 		return m, m.savedProjectsModal.Open()
@@ -246,14 +488,12 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	}
 
 	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-		m.configModal.SetSize(msg.Width, msg.Height)
-		m.savedProjectsModal.SetSize(msg.Width, msg.Height)
-
 	case ContainersLoadedMsg:
-		m.groups = msg.Groups
+		m.containers = msg.Containers
+		m.localProject = msg.LocalProject
+		m.configFiles = msg.ConfigFiles
+		m.projectConflicts = docker.ProjectNameConflicts(msg.Containers)
+		m.groups = m.groupContainers()
 		m.flatList = m.buildFlatList()
 		m.ready = true
 		if m.cursor == 0 || m.cursor >= len(m.flatList) {
@@ -266,9 +506,10 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		}
 		// Start tutorial if there are containers
 		m.tutorial.StartIfReady(len(m.flatList) > 0)
-		return m, tea.Tick(5*time.Second, func(t time.Time) tea.Msg {
+		tick := tea.Tick(5*time.Second, func(t time.Time) tea.Msg {
 			return autoRefreshTickMsg{}
 		})
+		return m, tea.Batch(tick, m.warnProjectConflicts(), m.sampleStats())
 
 	case autoRefreshTickMsg:
 		if !m.actionRunning {
@@ -287,11 +528,25 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			toastCmd = m.toast.Show(capitalize(msg.action), fmt.Sprintf("%d containers", msg.succeeded), common.ToastSuccess)
 		} else {
 			m.actionStatus = fmt.Sprintf("%s: %d succeeded, %d failed", msg.action, msg.succeeded, msg.failed)
+			logger.Warn("%s failed for %d containers: %s", msg.action, msg.failed, strings.Join(msg.errors, "; "))
 			toastCmd = m.toast.Show(capitalize(msg.action), fmt.Sprintf("%d failed", msg.failed), common.ToastError)
 		}
 		return m, tea.Batch(toastCmd, m.loadContainers())
 
+	case pruneCompleteMsg:
+		m.actionRunning = false
+		if msg.err != nil {
+			logger.Warn("Prune %s failed: %v", msg.what, msg.err)
+			m.actionStatus = "Prune failed"
+			return m, tea.Batch(m.toast.Show("Prune", msg.err.Error(), common.ToastError), m.loadContainers())
+		}
+		logger.Info("Pruned %d %s, %d bytes reclaimed", msg.removed, msg.what, msg.reclaimed)
+		m.actionStatus = fmt.Sprintf("Prune completed (%d %s removed)", msg.removed, msg.what)
+		result := fmt.Sprintf("%d %s removed, %s reclaimed", msg.removed, msg.what, common.FormatBytes(msg.reclaimed))
+		return m, tea.Batch(m.toast.Show("Prune", result, common.ToastSuccess), m.loadContainers())
+
 	case LoadErrorMsg:
+		logger.Warn("Failed to load containers: %v", msg.Err)
 		m.err = msg.Err
 		m.ready = true
 
@@ -327,16 +582,28 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Digits build a count for the next move; any other key clears it
+		if count, ok := common.AppendCount(m.count, msg); ok {
+			m.count = count
+			return m, nil
+		}
+		count := max(m.count, 1)
+		m.count = 0
+
 		switch {
 		case key.Matches(msg, m.keys.Up):
-			m.moveCursor(-1)
+			for range count {
+				m.moveCursor(-1)
+			}
 			// Advance tutorial if on navigate step
 			if m.tutorial.Active && m.tutorial.Step == common.TutorialStepNavigate {
 				m.tutorial.Advance()
 			}
 
 		case key.Matches(msg, m.keys.Down):
-			m.moveCursor(1)
+			for range count {
+				m.moveCursor(1)
+			}
 			// Advance tutorial if on navigate step
 			if m.tutorial.Active && m.tutorial.Step == common.TutorialStepNavigate {
 				m.tutorial.Advance()
@@ -373,6 +640,50 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				return m, m.confirmSelection()
 			}
 
+		case key.Matches(msg, m.keys.GroupToggle):
+			mode := "by image"
+			if m.groupMode == groupByImage {
+				m.groupMode = groupByProject
+				mode = "by project"
+			} else {
+				m.groupMode = groupByImage
+			}
+			m.regroup()
+			return m, m.toast.Show("Grouping", mode, common.ToastInfo)
+
+		case key.Matches(msg, m.keys.CopyCommand):
+			if len(m.selected) == 0 {
+				return m, m.toast.Show("Nothing selected", "Select containers to copy their command", common.ToastInfo)
+			}
+			text, extra := m.selectionCommand()
+			if text == "" {
+				return m, m.toast.Show("Nothing to copy", "Stopped services can't be opened by name", common.ToastInfo)
+			}
+			if err := clipboard.WriteAll(text); err != nil {
+				return m, m.toast.Show("Copy failed", err.Error(), common.ToastError)
+			}
+			if extra > 0 {
+				return m, m.toast.Show("Copied command", fmt.Sprintf("%s (also opens %d unselected)", text, extra), common.ToastInfo)
+			}
+			return m, m.toast.Show("Copied command", text, common.ToastSuccess)
+
+		case key.Matches(msg, m.keys.LocalFilter):
+			if m.localProject == "" {
+				return m, m.toast.Show("No local project", "No compose file in the current directory", common.ToastInfo)
+			}
+			m.showAll = !m.showAll
+			m.regroup()
+			// Saved so the choice survives discovery being rebuilt
+			if cfg, err := config.Load(); err == nil {
+				if err := cfg.SetShowAll(m.showAll); err != nil {
+					return m, m.toast.Show("Save failed", err.Error(), common.ToastError)
+				}
+			}
+			if m.localOnly() {
+				return m, m.toast.Show("Showing", m.localProject+" only", common.ToastInfo)
+			}
+			return m, m.toast.Show("Showing", "all projects", common.ToastInfo)
+
 		case key.Matches(msg, m.keys.Refresh):
 			m.ready = false
 			m.actionStatus = ""
@@ -388,12 +699,27 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.Restart):
 			return m, m.doAction("restart", m.getActionTargets(), m.dockerClient.ComposeDownUp)
 
+		case key.Matches(msg, m.keys.Pause):
+			return m, m.togglePause(m.withReplicas(m.getActionTargets()))
+
 		case key.Matches(msg, m.keys.ComposeBuild):
 			return m, m.doStreamingBuild("build", m.getActionTargets())
 
+		case key.Matches(msg, m.keys.ComposeLogs):
+			return m, m.openComposeLogs()
+
+		case key.Matches(msg, m.keys.Prune):
+			m.confirmPrune(common.SystemPruneContainers)
+
+		case key.Matches(msg, m.keys.SystemMenu):
+			m.systemMenu.Open()
+
 		case key.Matches(msg, m.keys.Config):
 			return m, m.configModal.Open()
 
+		case key.Matches(msg, m.keys.ReloadConfig):
+			return m, tea.Batch(common.ShowReloadResult(&m.toast, m.reloadConfig(), "reloaded"), m.loadContainers())
+
 		case key.Matches(msg, m.keys.SavedProjects):
 			return m, m.savedProjectsModal.Open()
 
@@ -407,12 +733,69 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
+
+		default:
+			return m, m.toast.ShowUnboundKey(msg)
 		}
 	}
 
 	return m, nil
 }
 
+// reloadConfig re-reads the config files and applies the key bindings and
+// toast settings. It returns an error for each file that couldn't be parsed.
+func (m *Model) reloadConfig() []error {
+	errs := common.ReloadConfig()
+	m.keys = common.DefaultKeyMap()
+	m.toast.ReloadConfig()
+	m.buildPanel.ReloadConfig()
+	logger.Info("Config reloaded (%d file errors)", len(errs))
+	return errs
+}
+
+// confirmPrune asks before running one of the system menu prune actions
+func (m *Model) confirmPrune(action string) {
+	switch action {
+	case common.SystemPruneContainers:
+		// Count the stopped containers in the list. Compose services without
+		// a container aren't real containers and are left alone.
+		count := 0
+		for _, c := range m.containers {
+			if c.State == "exited" || c.State == "dead" || c.State == "created" {
+				count++
+			}
+		}
+		m.confirmModal.Open(action, "Prune Containers", fmt.Sprintf("Remove all stopped containers (%d listed here)?\nExited containers hidden by the exited window are removed too.", count), "Remove")
+	case common.SystemPruneImages:
+		m.confirmModal.Open(action, "Prune Images", "Remove all dangling (untagged) images\nnot used by a container?", "Remove")
+	case common.SystemPruneVolumes:
+		m.confirmModal.Open(action, "Prune Volumes", "Remove all volumes not used by a container?\nTheir data is deleted.", "Remove")
+	}
+}
+
+// prune runs a confirmed prune action in the background
+func (m Model) prune(action string) tea.Cmd {
+	var what string
+	var run func(context.Context) (int, uint64, error)
+	switch action {
+	case common.SystemPruneContainers:
+		what, run = "containers", m.dockerClient.PruneStoppedContainers
+	case common.SystemPruneImages:
+		what, run = "images", m.dockerClient.PruneDanglingImages
+	case common.SystemPruneVolumes:
+		what, run = "volumes", m.dockerClient.PruneUnusedVolumes
+	default:
+		return nil
+	}
+	return tea.Batch(
+		func() tea.Msg { return actionStartedMsg{action: "Pruning " + what + "..."} },
+		func() tea.Msg {
+			removed, reclaimed, err := run(context.Background())
+			return pruneCompleteMsg{what: what, removed: removed, reclaimed: reclaimed, err: err}
+		},
+	)
+}
+
 // getActionTargets returns selected containers, or the focused container if none selected
 func (m *Model) getActionTargets() []docker.Container {
 	var targets []docker.Container
@@ -441,6 +824,54 @@ func (m *Model) getActionTargets() []docker.Container {
 
 type composeAction func(context.Context, docker.Container) error
 
+// togglePause pauses the running targets, or unpauses them when the first
+// target is already paused. Targets in any other state are skipped.
+func (m Model) togglePause(targets []docker.Container) tea.Cmd {
+	if len(targets) == 0 {
+		return nil
+	}
+	from, name, action := "running", "pause", m.dockerClient.PauseContainer
+	if targets[0].State == "paused" {
+		from, name, action = "paused", "unpause", m.dockerClient.UnpauseContainer
+	}
+
+	var matching []docker.Container
+	for _, c := range targets {
+		if c.State == from {
+			matching = append(matching, c)
+		}
+	}
+	if len(matching) == 0 {
+		return m.toast.Show("Cannot pause", "Container not running", common.ToastError)
+	}
+	return m.doAction(name, matching, func(ctx context.Context, c docker.Container) error {
+		return action(ctx, c.ID)
+	})
+}
+
+// warnProjectConflicts shows a toast the first time a project name is seen
+// in use by more than one directory
+func (m *Model) warnProjectConflicts() tea.Cmd {
+	for project, dirs := range m.projectConflicts {
+		if m.warnedConflicts[project] {
+			continue
+		}
+		m.warnedConflicts[project] = true
+		return m.toast.Show("Project name clash", fmt.Sprintf("%s is used by %d directories", project, len(dirs)), common.ToastError)
+	}
+	return nil
+}
+
+// truncateLeft shortens s to at most n characters, keeping the end, which
+// is the informative part of a path
+func truncateLeft(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return "…" + string(r[len(r)-n+1:])
+}
+
 // capitalize returns a string with the first letter capitalized
 func capitalize(s string) string {
 	if s == "" {
@@ -517,7 +948,8 @@ func (m Model) doStreamingBuild(op string, targets []docker.Container) tea.Cmd {
 	// Validate all targets are compose services
 	for _, target := range targets {
 		if target.ComposeProject == "" || target.ComposeService == "" {
-			return m.toast.Show("Cannot build", "Not a compose service", common.ToastError)
+			// Standalone images are rebuilt from the log view, which prompts for the build context
+			return m.toast.Show("Cannot build", "Not a compose service (open it to rebuild its image)", common.ToastError)
 		}
 	}
 
@@ -603,6 +1035,67 @@ func (m *Model) toggleSelect() {
 	}
 }
 
+// selectionCommand returns a cm command that opens the selected containers.
+// A service or container is given by name when cm would match just the
+// selection's containers with it. Otherwise the name is given anyway, and
+// extra counts the containers outside the selection the command also opens.
+// The command is empty when nothing selected can be named, as for stopped
+// services.
+func (m Model) selectionCommand() (command string, extra int) {
+	args := []string{"cm"}
+	own := make(map[string]bool)
+	for _, item := range m.flatList {
+		if item.isGroup || item.isSeparator || !m.selected[selectionKey(item.container)] {
+			continue
+		}
+		for _, c := range item.containers() {
+			own[c.ID] = true
+		}
+	}
+	for _, item := range m.flatList {
+		if item.isGroup || item.isSeparator || !m.selected[selectionKey(item.container)] {
+			continue
+		}
+		if service := item.container.ComposeService; service != "" && m.matchesOnly(strings.ToLower(service), own) {
+			args = append(args, service)
+			continue
+		}
+		for _, c := range item.containers() {
+			// Stopped services have no container to name
+			if c.State != "stopped" {
+				args = append(args, c.Name)
+			}
+		}
+	}
+
+	if len(args) == 1 {
+		return "", 0
+	}
+
+	// Count what the names open beyond the selection
+	opened := make(map[string]bool)
+	for _, name := range args[1:] {
+		for _, c := range m.containers {
+			if c.MatchesName(strings.ToLower(name)) && !own[c.ID] && !opened[c.ID] {
+				opened[c.ID] = true
+				extra++
+			}
+		}
+	}
+	return strings.Join(args, " "), extra
+}
+
+// matchesOnly reports whether every loaded container cm would match with
+// name is one of ids
+func (m Model) matchesOnly(name string, ids map[string]bool) bool {
+	for _, c := range m.containers {
+		if c.MatchesName(name) && !ids[c.ID] {
+			return false
+		}
+	}
+	return true
+}
+
 func (m *Model) selectAll() {
 	for _, item := range m.flatList {
 		if !item.isGroup && !item.isSeparator {
@@ -666,6 +1159,36 @@ func (m *Model) moveCursor(delta int) {
 	}
 }
 
+// regroup rebuilds the list after the group mode changed, keeping the
+// cursor on the same container
+func (m *Model) regroup() {
+	var current string
+	if m.cursor >= 0 && m.cursor < len(m.flatList) && !m.flatList[m.cursor].isGroup {
+		current = selectionKey(m.flatList[m.cursor].container)
+	}
+
+	m.groups = m.groupContainers()
+	m.flatList = m.buildFlatList()
+
+	m.cursor = 0
+	first := -1
+	for i, item := range m.flatList {
+		if item.isGroup || item.isSeparator {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		if selectionKey(item.container) == current {
+			m.cursor = i
+			return
+		}
+	}
+	if first >= 0 {
+		m.cursor = first
+	}
+}
+
 func (m Model) buildFlatList() []listItem {
 	var items []listItem
 	for _, group := range m.groups {
@@ -674,32 +1197,123 @@ func (m Model) buildFlatList() []listItem {
 			groupName: group.ProjectName,
 		})
 
+		// Replicas of a scaled service share a selection key and get one row
+		rows := make(map[string]int)
 		for _, c := range group.Containers {
+			key := selectionKey(c)
+			if i, ok := rows[key]; ok && c.ComposeService != "" {
+				if len(items[i].replicas) == 0 {
+					items[i].replicas = []docker.Container{items[i].container}
+				}
+				items[i].replicas = append(items[i].replicas, c)
+				continue
+			}
+			rows[key] = len(items)
 			items = append(items, listItem{
 				isGroup:   false,
 				container: c,
 			})
 		}
 	}
+
+	for i := range items {
+		if len(items[i].replicas) == 0 {
+			continue
+		}
+		replicas := items[i].replicas
+		sort.SliceStable(replicas, func(a, b int) bool {
+			return replicas[a].ComposeReplica < replicas[b].ComposeReplica
+		})
+		// The row shows the first running replica's state and ID
+		items[i].container = replicas[0]
+		for _, c := range replicas {
+			if c.State == "running" {
+				items[i].container = c
+				break
+			}
+		}
+	}
 	return items
 }
 
+// withReplicas adds the other replicas of scaled services to targets
+func (m Model) withReplicas(targets []docker.Container) []docker.Container {
+	keys := make(map[string]bool, len(targets))
+	for _, c := range targets {
+		keys[selectionKey(c)] = true
+	}
+	var all []docker.Container
+	for _, c := range m.containers {
+		if keys[selectionKey(c)] {
+			all = append(all, c)
+		}
+	}
+	return all
+}
+
+// launchProject selects the running services of a saved project, limited to
+// its saved view if it has one, and opens them in the log view
+func (m *Model) launchProject(name string) tea.Cmd {
+	var services map[string]bool
+	if proj, ok := config.LoadProjects().SavedProjects[name]; ok && proj.View != nil {
+		services = make(map[string]bool)
+		for _, svc := range proj.View.Services {
+			services[svc] = true
+		}
+	}
+
+	selected := make(map[string]bool)
+	for _, c := range m.containers {
+		if c.ComposeProject != name || c.State == "stopped" {
+			continue
+		}
+		if services != nil && !services[c.ComposeService] {
+			continue
+		}
+		selected[selectionKey(c)] = true
+	}
+	if len(selected) == 0 {
+		return m.toast.Show("Nothing to open", "No running containers for "+name, common.ToastError)
+	}
+
+	m.selected = selected
+	logger.Info("Launching saved project %s (%d containers)", name, len(selected))
+	return m.confirmSelection()
+}
+
 func (m Model) confirmSelection() tea.Cmd {
 	return func() tea.Msg {
 		var containers []docker.Container
 		for _, item := range m.flatList {
 			if !item.isGroup && m.selected[selectionKey(item.container)] {
-				// Skip stopped containers - they don't exist yet and have no logs
-				if item.container.State == "stopped" {
-					continue
+				// A pane per replica; skip stopped containers - they don't
+				// exist yet and have no logs
+				for _, c := range item.containers() {
+					if c.State != "stopped" {
+						containers = append(containers, c)
+					}
 				}
-				containers = append(containers, item.container)
 			}
 		}
 		return ContainerSelectedMsg{Containers: containers}
 	}
 }
 
+// openComposeLogs opens the log view with one pane following docker compose
+// logs for the project of the container under the cursor
+func (m *Model) openComposeLogs() tea.Cmd {
+	if m.cursor < 0 || m.cursor >= len(m.flatList) || m.flatList[m.cursor].isGroup || m.flatList[m.cursor].isSeparator {
+		return nil
+	}
+	cont := m.flatList[m.cursor].container
+	if cont.ComposeProject == "" {
+		return m.toast.Show("Compose logs", cont.DisplayName()+" is not part of a compose project", common.ToastError)
+	}
+	return func() tea.Msg {
+		return ContainerSelectedMsg{Containers: []docker.Container{docker.ComposeLogsContainer(cont)}}
+	}
+}
+
 // View renders the model
 func (m Model) View() string {
 	if !m.ready {
@@ -737,11 +1351,22 @@ func (m Model) View() string {
 	b.WriteString("\n")
 	b.WriteString(common.SubtitleStyle.Render("   docker logs, beautifully"))
 	b.WriteString("\n\n")
+	if m.localOnly() {
+		b.WriteString(common.MutedInlineStyle.Render(fmt.Sprintf("  Only %s, from the current directory · %s: all projects", m.localProject, m.keys.LocalFilter.Help().Key)))
+		b.WriteString("\n\n")
+	}
 
 	// List
 	for i, item := range m.flatList {
 		if item.isGroup {
 			b.WriteString(common.GroupHeaderStyle.Render(fmt.Sprintf("  %s", item.groupName)))
+			// Show where a compose project came from, projects can share a name
+			if dirs := m.projectConflicts[item.groupName]; len(dirs) > 1 && m.groupMode == groupByProject {
+				b.WriteString(common.StoppedStyle.Render(fmt.Sprintf("  ⚠ same name in %d dirs", len(dirs))))
+			} else if files, ok := m.configFiles[item.groupName]; ok && m.groupMode == groupByProject {
+				maxLen := m.width - lipgloss.Width(item.groupName) - 8
+				b.WriteString(common.MutedInlineStyle.Render("  " + truncateLeft(strings.Join(files, ", "), max(maxLen, 20))))
+			}
 			b.WriteString("\n")
 			continue
 		}
@@ -758,16 +1383,25 @@ func (m Model) View() string {
 		}
 
 		name := item.container.DisplayName()
+		if m.groupMode == groupByImage {
+			// Image groups mix projects, so show which project each service is from
+			name = item.container.QualifiedName()
+		}
 		isRunning := item.container.State == "running"
 		isStopped := item.container.State == "stopped"
 
 		status := common.StoppedStyle.Render("○")
 		if isRunning {
 			status = common.RunningStyle.Render("●")
+		} else if item.container.State == "paused" {
+			status = common.PausedStyle.Render("‖")
 		} else if isStopped {
 			status = common.MutedInlineStyle.Render("◌")
 		}
 
+		if n := len(item.replicas); n > 0 {
+			name += fmt.Sprintf(" (%d replicas)", n)
+		}
 		line := fmt.Sprintf("%s%s %s %s", cursor, checkbox, status, name)
 		if i == m.cursor {
 			line = common.SelectedItemStyle.Render(line)
@@ -776,6 +1410,14 @@ func (m Model) View() string {
 		b.WriteString("  ")
 		b.WriteString(line)
 
+		if cpu, mem, ok := m.rowStats(item); ok && isRunning {
+			style := common.MutedInlineStyle
+			if cpu[len(cpu)-1] >= 80 || mem >= 80 {
+				style = common.PausedStyle
+			}
+			b.WriteString(style.Render(fmt.Sprintf("  %s cpu %.0f%% mem %.0f%%", sparkline(cpu), cpu[len(cpu)-1], mem)))
+		}
+
 		if i == m.cursor {
 			if isStopped {
 				b.WriteString(common.MutedInlineStyle.Render(" (not started)"))
@@ -784,6 +1426,19 @@ func (m Model) View() string {
 				if !isRunning {
 					info = fmt.Sprintf("%s - %s", item.container.ID, item.container.Status)
 				}
+				if len(item.replicas) > 0 {
+					running := 0
+					for _, c := range item.replicas {
+						if c.State == "running" {
+							running++
+						}
+					}
+					info = fmt.Sprintf("%d/%d running", running, len(item.replicas))
+				}
+				if len(m.projectConflicts[item.container.ComposeProject]) > 1 {
+					// Tell apart services of same-named projects
+					info += " · " + item.container.ComposeWorkingDir
+				}
 				b.WriteString(common.MutedInlineStyle.Render(fmt.Sprintf(" (%s)", info)))
 			}
 		}
@@ -881,6 +1536,28 @@ func (m Model) View() string {
 		return m.overlayAtPosition(base, modalView, 0, 0)
 	}
 
+	// Overlay system menu if visible
+	if m.systemMenu.IsVisible() {
+		modalView := m.systemMenu.View(width, height)
+		base := lipgloss.Place(width, height,
+			lipgloss.Left, lipgloss.Top,
+			content,
+			lipgloss.WithWhitespaceChars(" "),
+		)
+		return m.overlayAtPosition(base, modalView, 0, 0)
+	}
+
+	// Overlay confirm modal if visible
+	if m.confirmModal.IsVisible() {
+		modalView := m.confirmModal.View(width, height)
+		base := lipgloss.Place(width, height,
+			lipgloss.Left, lipgloss.Top,
+			content,
+			lipgloss.WithWhitespaceChars(" "),
+		)
+		return m.overlayAtPosition(base, modalView, 0, 0)
+	}
+
 	// Overlay tutorial intro modal if at intro step
 	if m.tutorial.IsIntroStep() {
 		return m.tutorial.ViewIntroModal(width, height)
@@ -905,6 +1582,8 @@ func (m Model) renderHelpBar() string {
 		k("spc") + d(":sel ") +
 		k("a") + d("/") + k("A") + d(":all/clr ") +
 		k("⏎") + d(":logs ") +
+		k("I") + d(":group ") +
+		k(".") + d(":local/all ") +
 		k("u") + d("/") + k("s") + d("/") + k("r") + d(":up/stop/restart ") +
 		k("b") + d(":build ") +
 		k("p") + d(":projects ") +
@@ -1140,6 +1819,12 @@ func truncateWithAnsi(s string, width int) string {
 	return result.String()
 }
 
+// buildPanelWidth returns the build panel's share of the screen width: 40%,
+// and at least 40 columns
+func buildPanelWidth(width int) int {
+	return max(width-width*60/100, 40)
+}
+
 // renderWithBuildPanel renders the discovery view with the build panel on the right
 func (m Model) renderWithBuildPanel(mainContent string) string {
 	width := m.width
@@ -1152,16 +1837,17 @@ func (m Model) renderWithBuildPanel(mainContent string) string {
 	}
 
 	// Calculate widths: 60% for list, 40% for build panel
-	listWidth := width * 60 / 100
-	panelWidth := width - listWidth
-	if panelWidth < 40 {
-		panelWidth = 40
-		listWidth = width - panelWidth
-	}
+	panelWidth := buildPanelWidth(width)
+	listWidth := width - panelWidth
 
-	// Help bar at the bottom
+	// Help bar at the bottom, with the toast (if any) above it
 	helpBar := m.renderHelpBar()
 	helpBarHeight := 1
+	var toastLine string
+	if m.toast.IsVisible() {
+		toastLine = m.renderInlineToast()
+		helpBarHeight += lipgloss.Height(toastLine)
+	}
 
 	// Available height for content
 	contentHeight := height - helpBarHeight
@@ -1185,5 +1871,8 @@ func (m Model) renderWithBuildPanel(mainContent string) string {
 	contentArea := lipgloss.JoinHorizontal(lipgloss.Top, listContent, panelContent)
 
 	// Join with help bar
+	if toastLine != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, contentArea, toastLine, helpBar)
+	}
 	return lipgloss.JoinVertical(lipgloss.Left, contentArea, helpBar)
 }
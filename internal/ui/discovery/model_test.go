@@ -0,0 +1,125 @@
+package discovery
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cm/internal/config"
+	"cm/internal/docker"
+	"cm/internal/ui/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSelectionCommandNamesOnlyWhatIsSelected(t *testing.T) {
+	containers := []docker.Container{
+		{ID: "w1", Name: "shop-web-1", ComposeProject: "shop", ComposeService: "web", State: "running"},
+		{ID: "w2", Name: "shop-web2-1", ComposeProject: "shop", ComposeService: "web2", State: "running"},
+		{ID: "stopped:shop:api", Name: "api", ComposeProject: "shop", ComposeService: "api", State: "stopped"},
+		{ID: "stopped:shop:api-worker", Name: "api-worker", ComposeProject: "shop", ComposeService: "api-worker", State: "stopped"},
+		{ID: "c1", Name: "cache", State: "running"},
+		{ID: "c2", Name: "cache-old", State: "exited"},
+	}
+	m := Model{containers: containers}
+	for _, c := range containers {
+		m.flatList = append(m.flatList, listItem{container: c})
+	}
+	command := func(selected ...int) (string, int) {
+		m.selected = make(map[string]bool)
+		for _, i := range selected {
+			m.selected[selectionKey(containers[i])] = true
+		}
+		return m.selectionCommand()
+	}
+
+	tests := []struct {
+		name     string
+		selected []int
+		want     string
+		extra    int
+	}{
+		{"unambiguous service", []int{1}, "cm web2", 0},
+		// "web" would also open web2, so the container is named instead
+		{"service matching another", []int{0}, "cm shop-web-1", 0},
+		{"both services", []int{0, 1}, "cm web web2", 0},
+		// A stopped service has nothing to name, so there's no command
+		{"stopped service matching another", []int{2}, "", 0},
+		{"ambiguous container name", []int{4}, "cm cache", 1},
+	}
+	for _, tt := range tests {
+		got, extra := command(tt.selected...)
+		if got != tt.want || extra != tt.extra {
+			t.Fatalf("%s: selectionCommand() = %q, %d; want %q, %d", tt.name, got, extra, tt.want, tt.extra)
+		}
+	}
+}
+
+func TestStatsSamplingPausesAfterAFailureAndRecovers(t *testing.T) {
+	m := Model{containers: []docker.Container{{ID: "w1", State: "running"}}, stats: map[string][]docker.ContainerStats{}}
+
+	m, _ = m.Update(statsSampledMsg{err: errors.New("timeout")})
+	if !m.statsUnavailable || m.sampleStats() != nil {
+		t.Fatalf("expected sampling to pause after a failed sample")
+	}
+
+	m.statsRetryAt = time.Now().Add(-time.Second)
+	if m.sampleStats() == nil {
+		t.Fatalf("expected sampling to resume after the retry interval")
+	}
+	m, _ = m.Update(statsSampledMsg{stats: map[string]docker.ContainerStats{"w1": {CPUPercent: 5}}})
+	if m.statsUnavailable || len(m.stats["w1"]) != 1 {
+		t.Fatalf("expected a successful sample to clear the failure")
+	}
+}
+
+func TestStatsSampleFinishingUnderAModalIsApplied(t *testing.T) {
+	m := Model{containers: []docker.Container{{ID: "w1", State: "running"}}, stats: map[string][]docker.ContainerStats{}, confirmModal: common.NewConfirmModal()}
+	if m.sampleStats() == nil {
+		t.Fatalf("expected a sample to start")
+	}
+
+	m.confirmModal.Open("stop", "Stop", "Stop w1?", "Stop")
+	m, _ = m.Update(statsSampledMsg{stats: map[string]docker.ContainerStats{"w1": {CPUPercent: 5}}})
+	if m.statsSampling || len(m.stats["w1"]) != 1 {
+		t.Fatalf("expected the sample to be applied with the modal open")
+	}
+	if m.sampleStats() == nil {
+		t.Fatalf("expected the next sample to start")
+	}
+}
+
+func TestLocalFilterListsTheLocalProjectAndRemembersTheToggle(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	cfg := &config.Config{Tutorial: &config.TutorialSettings{Completed: true}}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded := ContainersLoadedMsg{LocalProject: "shop", Containers: []docker.Container{
+		{ID: "w1", Name: "shop-web-1", ComposeProject: "shop", ComposeService: "web", State: "running"},
+		{ID: "b1", Name: "blog-web-1", ComposeProject: "blog", ComposeService: "web", State: "running"},
+	}}
+	open := func() Model {
+		m, _ := New(nil, nil).Update(loaded)
+		return m
+	}
+
+	m := open()
+	if listed := m.listedContainers(); len(listed) != 1 || listed[0].ID != "w1" {
+		t.Fatalf("expected only the local project by default, got %v", listed)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(".")})
+	if len(m.listedContainers()) != 2 {
+		t.Fatalf("expected . to list every project")
+	}
+	if saved, err := config.Load(); err != nil || !saved.GetDiscoverySettings().ShowAll {
+		t.Fatalf("expected the toggle to be saved as discovery.show_all, got %v", err)
+	}
+
+	// A rebuilt discovery starts from the saved choice
+	if len(open().listedContainers()) != 2 {
+		t.Fatalf("expected discovery.show_all to start with every project")
+	}
+}
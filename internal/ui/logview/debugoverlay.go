@@ -0,0 +1,108 @@
+package logview
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"cm/internal/debug"
+	"cm/internal/ui/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// debugOverlayLines is how many recent debug log lines the overlay shows
+	debugOverlayLines = 8
+	// debugOverlayInterval is how often the overlay re-reads the debug log
+	debugOverlayInterval = 500 * time.Millisecond
+	// debugOverlayMaxWidth caps the overlay width on wide terminals
+	debugOverlayMaxWidth = 72
+)
+
+// debugOverlayTickMsg refreshes the debug overlay's tail of the debug log
+type debugOverlayTickMsg struct{}
+
+// debugOverlayVisible reports whether the overlay should be drawn. It only
+// shows while debug logging is on, so turning debug off hides it too.
+func (m Model) debugOverlayVisible() bool {
+	return m.debugOverlay && debug.IsEnabled()
+}
+
+// scheduleDebugOverlayTick schedules the next overlay refresh unless one is already pending
+func (m *Model) scheduleDebugOverlayTick() tea.Cmd {
+	if m.debugOverlayTicking {
+		return nil
+	}
+	m.debugOverlayTicking = true
+	return tea.Tick(debugOverlayInterval, func(time.Time) tea.Msg {
+		return debugOverlayTickMsg{}
+	})
+}
+
+// renderDebugOverlay renders the model's layout and stream state with the
+// most recent debug log lines
+func (m Model) renderDebugOverlay() string {
+	width := m.width - 4
+	if width > debugOverlayMaxWidth {
+		width = debugOverlayMaxWidth
+	}
+	if width < 20 {
+		width = 20
+	}
+
+	labelStyle := common.MutedInlineStyle
+	row := func(label, value string) string {
+		return labelStyle.Render(fmt.Sprintf("%-10s", label)) + value
+	}
+
+	grid := fmt.Sprintf("%dx%d", m.layout.Rows, m.layout.Cols)
+	if m.layout.ColumnRatios != nil || m.layout.RowRatios != nil {
+		grid += fmt.Sprintf(" cols %s rows %s", formatRatios(m.layout.ColumnRatios), formatRatios(m.layout.RowRatios))
+	}
+
+	lines := []string{
+		common.ModalTitleStyle.Render("Debug"),
+		row("screen", fmt.Sprintf("%dx%d", m.width, m.height)),
+		row("panes", fmt.Sprintf("%d  focused %d  maximized %d", len(m.panes), m.focusedPane, m.maximizedPane)),
+		row("grid", grid),
+		row("streams", fmt.Sprintf("%d  builds %d", len(m.streams), len(m.buildStreams))),
+	}
+	if m.focusedPane >= 0 && m.focusedPane < len(m.panes) {
+		p := &m.panes[m.focusedPane]
+		lines = append(lines, row("viewport", fmt.Sprintf("%dx%d  y %d  x %d  lines %d",
+			p.Viewport.Width, p.Viewport.Height, p.Viewport.YOffset, p.xOffset, p.LogLines.Len())))
+	}
+	if m.selection.PaneIdx >= 0 {
+		startLine, startCol, endLine, endCol := m.selection.GetNormalizedRange()
+		lines = append(lines, row("selection", fmt.Sprintf("pane %d  %d:%d-%d:%d", m.selection.PaneIdx, startLine, startCol, endLine, endCol)))
+	}
+
+	lines = append(lines, "")
+	if len(m.debugTail) == 0 {
+		lines = append(lines, labelStyle.Render("(no debug output yet)"))
+	}
+	for _, l := range m.debugTail {
+		lines = append(lines, truncateWithAnsi(l, width))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("208")).
+		Padding(0, 1).
+		Width(width).
+		Render(strings.Join(lines, "\n"))
+}
+
+// formatRatios renders layout ratios compactly, or "=" for equal sizes
+func formatRatios(ratios []float64) string {
+	if ratios == nil {
+		return "="
+	}
+	parts := make([]string, len(ratios))
+	for i, r := range ratios {
+		parts[i] = fmt.Sprintf("%.2f", r)
+	}
+	return strings.Join(parts, "/")
+}
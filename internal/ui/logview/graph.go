@@ -164,26 +164,6 @@ func renderSingleBar(label string, value float64, suffix string, color lipgloss.
 	return strings.Join(lines, "\n")
 }
 
-// FormatBytes formats bytes into a human-readable string
-func FormatBytes(bytes uint64) string {
-	const (
-		KB = 1024
-		MB = KB * 1024
-		GB = MB * 1024
-	)
-
-	switch {
-	case bytes >= GB:
-		return fmt.Sprintf("%.1fG", float64(bytes)/float64(GB))
-	case bytes >= MB:
-		return fmt.Sprintf("%.1fM", float64(bytes)/float64(MB))
-	case bytes >= KB:
-		return fmt.Sprintf("%.1fK", float64(bytes)/float64(KB))
-	default:
-		return fmt.Sprintf("%dB", bytes)
-	}
-}
-
 // FormatPercent formats a percentage with one decimal place
 func FormatPercent(percent float64) string {
 	return fmt.Sprintf("%.1f%%", percent)
@@ -17,7 +17,59 @@ const MinPaneRatio = 0.1
 // ResizeStep is the amount to change ratio per keyboard press
 const ResizeStep = 0.05
 
-// CalculateLayout determines the optimal grid layout for N panes
+// Smallest tiled pane that still shows a useful amount of log
+const (
+	MinReadablePaneWidth  = 24
+	MinReadablePaneHeight = 5 // Border, title and two log lines
+)
+
+// LayoutBias picks how CalculateLayoutFor shapes the grid
+type LayoutBias int
+
+const (
+	LayoutWide     LayoutBias = iota // Fewer, wider columns, since log lines run horizontally
+	LayoutBalanced                   // A near-square grid whatever the screen size
+)
+
+// ParseLayoutBias converts a config value ("wide", "balanced") to a
+// LayoutBias, defaulting to wide
+func ParseLayoutBias(s string) LayoutBias {
+	if s == "balanced" {
+		return LayoutBalanced
+	}
+	return LayoutWide
+}
+
+const (
+	// wideColumnWidth is the narrowest column the wide layout splits the screen into
+	wideColumnWidth = 100
+	// wideRowHeight is the shortest row the wide layout stacks before adding a column
+	wideRowHeight = 8
+)
+
+// CalculateLayoutFor determines the grid layout for N panes on a screen of
+// the given size. The wide bias uses one column until the screen fits two of
+// wideColumnWidth, and so on, adding columns early only when the rows would
+// get shorter than wideRowHeight. It never uses more columns than the
+// balanced grid.
+func CalculateLayoutFor(numPanes, width, height int, bias LayoutBias) Layout {
+	balanced := CalculateLayout(numPanes)
+	if bias != LayoutWide || numPanes <= 1 || width <= 0 || height <= 0 {
+		return balanced
+	}
+
+	cols := min(max(width/wideColumnWidth, 1), balanced.Cols)
+	for cols < balanced.Cols && height/ceilDiv(numPanes, cols) < wideRowHeight {
+		cols++
+	}
+	return gridLayout(numPanes, cols)
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+// CalculateLayout determines the balanced grid layout for N panes
 func CalculateLayout(numPanes int) Layout {
 	if numPanes == 0 {
 		return Layout{Rows: 0, Cols: 0}
@@ -36,7 +88,12 @@ func CalculateLayout(numPanes int) Layout {
 	// Calculate optimal grid dimensions
 	// Goal: minimize empty cells while keeping aspect ratios reasonable
 	cols := int(math.Ceil(math.Sqrt(float64(numPanes))))
-	rows := int(math.Ceil(float64(numPanes) / float64(cols)))
+	return gridLayout(numPanes, cols)
+}
+
+// gridLayout fills a grid of the given column count row by row
+func gridLayout(numPanes, cols int) Layout {
+	rows := ceilDiv(numPanes, cols)
 
 	// Build pane map
 	paneMap := make([][]int, rows)
@@ -194,6 +251,25 @@ func (l *Layout) GetRowHeights(totalHeight int) []int {
 	return heights
 }
 
+// TooSmall reports whether any cell of the grid would fall below the
+// readable pane size at the given screen size
+func (l *Layout) TooSmall(totalWidth, totalHeight int) bool {
+	if l.Cols <= 0 || l.Rows <= 0 {
+		return false
+	}
+	for _, w := range l.GetColumnWidths(totalWidth) {
+		if w < MinReadablePaneWidth {
+			return true
+		}
+	}
+	for _, h := range l.GetRowHeights(totalHeight) {
+		if h < MinReadablePaneHeight {
+			return true
+		}
+	}
+	return false
+}
+
 // GetColumnBorders returns x positions of vertical borders between columns
 func (l *Layout) GetColumnBorders(totalWidth int) []int {
 	widths := l.GetColumnWidths(totalWidth)
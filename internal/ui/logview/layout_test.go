@@ -0,0 +1,48 @@
+package logview
+
+import "testing"
+
+func TestCalculateLayoutForPrefersWideColumns(t *testing.T) {
+	type grid struct{ rows, cols int }
+	cases := []struct {
+		name          string
+		width, height int
+		bias          LayoutBias
+		want          []grid // for 1..9 panes
+	}{
+		{"small terminal", 80, 23, LayoutWide, []grid{{1, 1}, {2, 1}, {2, 2}, {2, 2}, {2, 3}, {2, 3}, {3, 3}, {3, 3}, {3, 3}}},
+		{"medium terminal", 120, 39, LayoutWide, []grid{{1, 1}, {2, 1}, {3, 1}, {4, 1}, {3, 2}, {3, 2}, {4, 2}, {4, 2}, {3, 3}}},
+		{"wide terminal", 220, 59, LayoutWide, []grid{{1, 1}, {1, 2}, {2, 2}, {2, 2}, {3, 2}, {3, 2}, {4, 2}, {4, 2}, {5, 2}}},
+		{"balanced", 220, 59, LayoutBalanced, []grid{{1, 1}, {1, 2}, {2, 2}, {2, 2}, {2, 3}, {2, 3}, {3, 3}, {3, 3}, {3, 3}}},
+	}
+	for _, tc := range cases {
+		for n := 1; n <= 9; n++ {
+			l := CalculateLayoutFor(n, tc.width, tc.height, tc.bias)
+			if got := (grid{l.Rows, l.Cols}); got != tc.want[n-1] {
+				t.Fatalf("%s, %d panes: expected %dx%d, got %dx%d", tc.name, n, tc.want[n-1].rows, tc.want[n-1].cols, got.rows, got.cols)
+			}
+			placed := 0
+			for _, row := range l.PaneMap {
+				for _, idx := range row {
+					if idx >= 0 {
+						placed++
+					}
+				}
+			}
+			if placed != n {
+				t.Fatalf("%s, %d panes: expected every pane in the grid, placed %d", tc.name, n, placed)
+			}
+		}
+	}
+}
+
+func TestParseLayoutBiasDefaultsToWide(t *testing.T) {
+	if ParseLayoutBias("balanced") != LayoutBalanced {
+		t.Fatalf("expected balanced to parse")
+	}
+	for _, s := range []string{"", "wide", "bogus"} {
+		if ParseLayoutBias(s) != LayoutWide {
+			t.Fatalf("expected %q to mean wide", s)
+		}
+	}
+}
@@ -2,13 +2,21 @@ package logview
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
+	"cm/internal/alert"
+	"cm/internal/config"
 	"cm/internal/debug"
 	"cm/internal/docker"
+	"cm/internal/notify"
 	"cm/internal/ui/common"
 
 	"github.com/atotto/clipboard"
@@ -17,26 +25,53 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// logger tags the log view's debug log messages
+var logger = debug.For("logview")
+
 const (
 	doubleClickThreshold = 400 * time.Millisecond
 	resizeDebounceDelay  = 50 * time.Millisecond
+	renderInterval       = 50 * time.Millisecond
+	// How long quitting waits for cancelled exports to close their files
+	exportCloseTimeout = 2 * time.Second
 )
 
 type resizeTickMsg struct{}
 
+// paneFlashDuration is how long a pane's border stays highlighted after
+// jumping to it
+const paneFlashDuration = 600 * time.Millisecond
+
+// flashDoneMsg redraws the grid once a pane's border flash is over
+type flashDoneMsg struct{}
+
+// noOutputTickMsg re-renders a pane still waiting for its first line, so its
+// placeholder can say the container may not log at all
+type noOutputTickMsg struct {
+	ContainerID string
+}
+
 // Messages
 type LogLineMsg struct {
 	ContainerID string
 	Line        docker.LogLine
+	source      <-chan docker.LogLine // stream the line came from
 }
 
 type LogErrorMsg struct {
 	ContainerID string
 	Err         error
+	source      <-chan docker.LogLine
 }
 
 type BackToDiscoveryMsg struct{}
 
+// ComposeLogsMsg asks to replace the log view with a single pane following
+// docker compose logs for the container's project
+type ComposeLogsMsg struct {
+	Container docker.Container
+}
+
 type ContainerActionMsg struct {
 	ContainerID string
 	Action      string
@@ -49,6 +84,28 @@ type ContainerRemovedMsg struct {
 	Err         error
 }
 
+// ContainerSignalMsg is sent when a signal has been sent to a container
+type ContainerSignalMsg struct {
+	ContainerID string
+	Signal      string
+	Err         error
+}
+
+// ContainerRenamedMsg is sent when a container has been renamed
+type ContainerRenamedMsg struct {
+	ContainerID string
+	OldName     string
+	NewName     string
+	Err         error
+}
+
+// ContainerPausedMsg is sent when a container has been paused or unpaused
+type ContainerPausedMsg struct {
+	ContainerID string
+	Paused      bool
+	Err         error
+}
+
 type ContainerActionStartMsg struct {
 	ContainerID string
 	Action      string
@@ -57,6 +114,7 @@ type ContainerActionStartMsg struct {
 // StreamClosedMsg is sent when a log stream channel closes (container stopped/restarted)
 type StreamClosedMsg struct {
 	ContainerID string
+	source      <-chan docker.LogLine
 }
 
 // Build streaming messages
@@ -82,12 +140,82 @@ type returnToLogsMsg struct {
 	ContainerID string
 }
 
+// renderTickMsg flushes lines added since the last render to the pane viewports
+type renderTickMsg struct{}
+
+// countTimeoutMsg applies a count prefix's digit as a shortcut when no
+// motion followed it
+type countTimeoutMsg struct {
+	gen int
+}
+
+// countTimeout is how long a count prefix waits for its motion
+const countTimeout = 500 * time.Millisecond
+
+// rateTickMsg re-renders pane titles so log rates decay once output stops
+type rateTickMsg struct{}
+
+// totalsTickMsg asks for a stats sample of every monitored container, for the
+// resource totals in the help bar
+type totalsTickMsg struct{}
+
+// totalsSampledMsg carries the sample totalsTickMsg asked for
+type totalsSampledMsg struct {
+	stats map[string]docker.ContainerStats
+	err   error
+}
+
+// totalsInterval is how often every pane's container is sampled for the totals
+const totalsInterval = 5 * time.Second
+
+// totalsMaxRetryDelay caps the backoff between failed totals samples
+const totalsMaxRetryDelay = time.Minute
+
+// totalsRetryDelay is the wait before the next totals sample after `failures`
+// failed samples in a row, doubling from totalsInterval up to totalsMaxRetryDelay
+func totalsRetryDelay(failures int) time.Duration {
+	delay := totalsInterval
+	for i := 0; i < failures && delay < totalsMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, totalsMaxRetryDelay)
+}
+
+// logExport tracks a running full-history export
+type logExport struct {
+	name     string
+	progress chan int
+	done     chan exportDoneMsg
+}
+
+// exportProgressMsg reports how many lines an export has written so far
+type exportProgressMsg struct {
+	export *logExport
+	lines  int
+}
+
+// exportDoneMsg is sent when a full-history export finishes
+type exportDoneMsg struct {
+	name  string
+	path  string
+	lines int
+	err   error
+}
+
 // StatsUpdateMsg is sent when new container stats are received
 type StatsUpdateMsg struct {
 	ContainerID string
 	Stats       docker.ContainerStats
 }
 
+// statsSnapshotMsg carries the one-off sample that paints the Stats tab
+// while its stream warms up
+type statsSnapshotMsg struct {
+	ContainerID string
+	Stats       docker.ContainerStats
+	Err         error
+}
+
 // StatsErrorMsg is sent when stats streaming encounters an error
 type StatsErrorMsg struct {
 	ContainerID string
@@ -101,6 +229,11 @@ type TopUpdateMsg struct {
 	Err         error
 }
 
+// startStreamMsg asks Update to open the initial log stream for a pane
+type startStreamMsg struct {
+	ContainerID string
+}
+
 // topTickMsg triggers a process list refresh
 type topTickMsg struct {
 	ContainerID string
@@ -110,6 +243,7 @@ type topTickMsg struct {
 type streamInfo struct {
 	logChan <-chan docker.LogLine
 	errChan <-chan error
+	cancel  context.CancelFunc
 }
 
 // ResizeMode indicates what type of border is being dragged
@@ -124,7 +258,12 @@ const (
 // Model represents the log view screen
 type Model struct {
 	panes         []Pane
-	streams       map[string]streamInfo // containerID -> stream channels
+	streams       map[string]streamInfo // containerID -> stream channels (only mutated in Update)
+	streamLogs    func(ctx context.Context, containerID string) (<-chan docker.LogLine, <-chan error)
+	composeLogs   func(ctx context.Context, cont docker.Container) (<-chan docker.LogLine, <-chan error)
+	exportLogs    func(ctx context.Context, containerID string, w io.Writer, progress func(lines int)) (int, error)
+	streamEvents  func(ctx context.Context, containers ...string) (<-chan docker.ContainerEvent, <-chan error)
+	exports       *sync.WaitGroup // running exports, waited on by Cleanup
 	layout        Layout
 	focusedPane   int
 	maximizedPane int // -1 if none maximized
@@ -134,6 +273,13 @@ type Model struct {
 	ctx           context.Context
 	cancel        context.CancelFunc
 
+	// Pending count prefix (the 5 in 5j). Its last pane digit is also a pane
+	// or tab shortcut, applied when no motion follows within countTimeout.
+	count         int
+	countKey      tea.KeyMsg
+	countGen      int  // counts typed digits; a timeout for an older one is stale
+	countFlushing bool // applying countKey as a shortcut
+
 	// For double-click detection
 	lastClickTime   time.Time
 	lastClickPaneID string
@@ -153,6 +299,11 @@ type Model struct {
 	// Config modal
 	configModal common.ConfigModal
 
+	// Saved projects modal
+	savedProjectsModal common.SavedProjectsModal
+	// Tab the saved view shows on the maximized pane, switched to at startup
+	restoreTab TabType
+
 	// Help modal
 	helpModal common.HelpModal
 
@@ -163,6 +314,13 @@ type Model struct {
 	searchModal   common.SearchModal
 	searchPaneIdx int // tracks which pane we're navigating in during search (tiled view)
 
+	// Build context prompt for standalone containers
+	buildPrompt common.BuildPrompt
+
+	// Signal picker and rename input for the focused container
+	signalPrompt common.SignalPrompt
+	renamePrompt common.RenamePrompt
+
 	// Toast notifications
 	toast common.Toast
 
@@ -172,6 +330,30 @@ type Model struct {
 	// Word wrap toggle
 	wordWrap bool
 
+	// Timestamp display preferences
+	utcTimestamps bool
+	msTimestamps  bool
+	// Leave timestamps out of copied text
+	copyNoTimestamps bool
+	// Stream filter new panes start with
+	streamFilter StreamFilter
+	// Log lines each pane keeps
+	logBuffer int
+	// Keep removed/dead panes as placeholders instead of reflowing the grid
+	freezeLayout bool
+	// Scroll every pane's logs together with the one being scrolled, by
+	// the same number of lines or to the same time
+	syncScroll bool
+	syncTime   bool
+	// How the grid is shaped for the screen size
+	layoutBias LayoutBias
+
+	// Automatic reconnect schedule
+	reconnect config.ReconnectSettings
+
+	// Alert rules checked against incoming stats and log lines
+	alerts *alert.Engine
+
 	// Tutorial state
 	tutorial common.Tutorial
 
@@ -184,15 +366,33 @@ type Model struct {
 	statsStreaming   bool
 	statsChan        <-chan docker.ContainerStats
 	statsContainerID string
+	totalsFailures   int // totals samples failed in a row
+	eventsFailures   int // event streams failed in a row
 
 	// Top polling state
 	topPolling bool
+
+	// Set while rateTickMsg is scheduled
+	rateTicking bool
+	// Set while renderTickMsg is scheduled
+	renderTicking bool
+
+	// Debug overlay (only drawn while debug logging is on)
+	debugOverlay        bool
+	debugOverlayTicking bool
+	debugTail           []string // recent debug log lines
 }
 
 // New creates a new log view model
 func New(containers []docker.Container, dockerClient *docker.Client, width, height int, tutorial common.Tutorial) Model {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	// A project's saved view decides the pane order
+	view := savedViewFor(containers)
+	if view != nil {
+		containers = orderByServices(containers, view.Services)
+	}
+
 	// If tutorial is at pane nav step but there's only one pane, skip to maximize step
 	if tutorial.Active && tutorial.ShouldSkipPaneNav(len(containers)) {
 		tutorial.Advance()
@@ -207,6 +407,11 @@ func New(containers []docker.Container, dockerClient *docker.Client, width, heig
 		height:        height,
 		keys:          common.DefaultKeyMap(),
 		dockerClient:  dockerClient,
+		streamLogs:    dockerClient.StreamLogs,
+		composeLogs:   dockerClient.StreamComposeLogs,
+		exportLogs:    dockerClient.ExportLogs,
+		streamEvents:  dockerClient.StreamEvents,
+		exports:       &sync.WaitGroup{},
 		ctx:           ctx,
 		cancel:        cancel,
 		lastWidth:     width,
@@ -215,14 +420,33 @@ func New(containers []docker.Container, dockerClient *docker.Client, width, heig
 		helpModal:     common.NewHelpModal(),
 		inspectModal:  common.NewInspectModal(),
 		searchModal:   common.NewSearchModal(),
+		buildPrompt:   common.NewBuildPrompt(),
+		signalPrompt:  common.NewSignalPrompt(),
+		renamePrompt:  common.NewRenamePrompt(),
 		toast:         common.NewToast(),
 		selection:     NewSelection(),
 		tutorial:      tutorial,
 		buildStreams:  make(map[string]*docker.StreamingResult),
+		reconnect:     config.DefaultReconnectSettings(),
+	}
+	m.savedProjectsModal = common.NewSavedProjectsModal()
+
+	// Apply display and reconnect preferences from config
+	if cfg, err := config.Load(); err == nil {
+		display := cfg.GetDisplaySettings()
+		m.utcTimestamps = display.UTCTimestamps
+		m.msTimestamps = display.MillisecondTimestamps
+		m.copyNoTimestamps = display.CopyWithoutTimestamps
+		m.freezeLayout = display.FreezeLayout
+		m.layoutBias = ParseLayoutBias(display.Layout)
+		m.streamFilter = ParseStreamFilter(display.StreamFilter)
+		m.logBuffer = display.GetLogBuffer()
+		m.reconnect = cfg.GetReconnectSettings()
+		m.alerts = loadAlerts(cfg)
 	}
 
 	// Calculate layout
-	m.layout = CalculateLayout(len(containers))
+	m.layout = CalculateLayoutFor(len(containers), width, height-1, m.layoutBias)
 
 	// Early return if no containers to avoid divide by zero
 	if len(containers) == 0 {
@@ -253,10 +477,17 @@ func New(containers []docker.Container, dockerClient *docker.Client, width, heig
 			}
 
 			m.panes[paneIdx] = NewPane(containers[paneIdx], paneWidth, paneHeight)
+			m.panes[paneIdx].utcTimestamps = m.utcTimestamps
+			m.panes[paneIdx].msTimestamps = m.msTimestamps
+			m.panes[paneIdx].copyNoTimestamps = m.copyNoTimestamps
+			m.panes[paneIdx].streamFilter = m.streamFilter
+			m.panes[paneIdx].SetBufferSize(m.logBuffer)
 			paneIdx++
 		}
 	}
 
+	m.refreshPaneNames()
+
 	if len(m.panes) > 0 {
 		m.panes[0].Active = true
 		if len(m.panes) == 1 {
@@ -265,47 +496,133 @@ func New(containers []docker.Container, dockerClient *docker.Client, width, heig
 			m.panes[0].SetActiveTab(TabLogs)
 		}
 	}
+	if view != nil {
+		m.applySavedView(view)
+	}
 
 	return m
 }
 
-// Init initializes the model and starts log streaming
+// Init initializes the model and starts log streaming.
+// Streams are opened in Update (via startStreamMsg) so that the model which
+// owns the streams map is the one Bubble Tea keeps, not Init's copy.
 func (m Model) Init() tea.Cmd {
 	var cmds []tea.Cmd
 
 	for _, pane := range m.panes {
-		logChan, errChan := m.dockerClient.StreamLogs(m.ctx, pane.Container.ID)
-		m.streams[pane.ID] = streamInfo{logChan: logChan, errChan: errChan}
-		cmds = append(cmds, m.waitForLog(pane.ID, logChan))
-		cmds = append(cmds, m.waitForError(pane.ID, errChan))
+		containerID := pane.ID
+		cmds = append(cmds, func() tea.Msg {
+			return startStreamMsg{ContainerID: containerID}
+		})
+	}
+
+	cmds = append(cmds, m.restoreTabCmd())
+	if m.dockerClient != nil {
+		cmds = append(cmds, scheduleTotalsTick(), func() tea.Msg { return watchEventsMsg{} })
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// startStream opens a log stream for a container, replacing (and cancelling)
+// any existing stream for the same ID. Must only be called from Update.
+func (m *Model) startStream(containerID string) tea.Cmd {
+	m.stopStream(containerID)
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	var logChan <-chan docker.LogLine
+	var errChan <-chan error
+	if cont, ok := m.composeLogsPane(containerID); ok {
+		logChan, errChan = m.composeLogs(ctx, cont)
+	} else {
+		logChan, errChan = m.streamLogs(ctx, containerID)
 	}
+	stream := streamInfo{logChan: logChan, errChan: errChan, cancel: cancel}
+	m.streams[containerID] = stream
 
+	cmds := []tea.Cmd{m.waitForLog(containerID, logChan), m.waitForError(containerID, stream)}
+	for i := range m.panes {
+		if m.panes[i].ID == containerID && m.panes[i].LogLines.Len() == 0 && m.panes[i].waitingSince.IsZero() {
+			m.panes[i].waitingSince = time.Now()
+			cmds = append(cmds, tea.Tick(noOutputHintDelay, func(time.Time) tea.Msg {
+				return noOutputTickMsg{ContainerID: containerID}
+			}))
+		}
+	}
 	return tea.Batch(cmds...)
 }
 
+// composeLogsPane returns the container of a pane streaming docker compose
+// logs for a whole project
+func (m *Model) composeLogsPane(containerID string) (docker.Container, bool) {
+	for i := range m.panes {
+		if m.panes[i].ID == containerID && m.panes[i].Container.IsComposeLogs() {
+			return m.panes[i].Container, true
+		}
+	}
+	return docker.Container{}, false
+}
+
+// stopStream cancels and forgets the stream for a container.
+// Must only be called from Update.
+func (m *Model) stopStream(containerID string) {
+	if stream, ok := m.streams[containerID]; ok {
+		if stream.cancel != nil {
+			stream.cancel()
+		}
+		delete(m.streams, containerID)
+	}
+}
+
+// isCurrentStream reports whether a message came from the container's active stream.
+// Messages from replaced streams are dropped so they can't re-arm readers or
+// tear down the stream that superseded them.
+func (m *Model) isCurrentStream(containerID string, source <-chan docker.LogLine) bool {
+	stream, ok := m.streams[containerID]
+	return ok && stream.logChan == source
+}
+
 func (m Model) waitForLog(containerID string, logChan <-chan docker.LogLine) tea.Cmd {
 	return func() tea.Msg {
 		line, ok := <-logChan
 		if !ok {
 			// Channel closed - container likely stopped or restarted
-			return StreamClosedMsg{ContainerID: containerID}
+			return StreamClosedMsg{ContainerID: containerID, source: logChan}
 		}
-		return LogLineMsg{ContainerID: containerID, Line: line}
+		return LogLineMsg{ContainerID: containerID, Line: line, source: logChan}
 	}
 }
 
-func (m Model) waitForError(containerID string, errChan <-chan error) tea.Cmd {
+func (m Model) waitForError(containerID string, stream streamInfo) tea.Cmd {
 	return func() tea.Msg {
-		err, ok := <-errChan
+		err, ok := <-stream.errChan
 		if !ok {
 			return nil
 		}
-		return LogErrorMsg{ContainerID: containerID, Err: err}
+		return LogErrorMsg{ContainerID: containerID, Err: err, source: stream.logChan}
 	}
 }
 
 // Update handles messages
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	// Coalesce new log lines into at most one re-render per renderInterval
+	if !m.renderTicking {
+		for i := range m.panes {
+			if m.panes[i].renderPending {
+				m.renderTicking = true
+				cmd = tea.Batch(cmd, tea.Tick(renderInterval, func(time.Time) tea.Msg {
+					return renderTickMsg{}
+				}))
+				break
+			}
+		}
+	}
+	return m, cmd
+}
+
+// update handles a single message for Update
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	// Handle window resize even when any modal is open
@@ -313,6 +630,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.width = sizeMsg.Width
 		m.height = sizeMsg.Height
 		m.configModal.SetSize(sizeMsg.Width, sizeMsg.Height)
+		m.savedProjectsModal.SetSize(sizeMsg.Width, sizeMsg.Height)
 		m.helpModal.SetSize(sizeMsg.Width, sizeMsg.Height)
 		m.inspectModal.SetSize(sizeMsg.Width, sizeMsg.Height)
 		m.searchModal.SetSize(sizeMsg.Width, sizeMsg.Height)
@@ -326,11 +644,30 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		}
 	}
 
+	// Config files changed on disk (reload.watch); applies even with a modal open
+	if _, ok := msg.(common.ConfigChangedMsg); ok {
+		return m, common.ShowReloadResult(&m.toast, m.reloadConfig(), "changed on disk, reloaded")
+	}
+
 	// Handle ContainerDetailsMsg even when inspect modal is visible
 	if detailsMsg, ok := msg.(common.ContainerDetailsMsg); ok {
 		m.inspectModal.SetDetails(detailsMsg.Details, detailsMsg.Err)
 		return m, nil
 	}
+	switch inspectMsg := msg.(type) {
+	case common.InspectJSONRequestMsg:
+		return m, m.inspectContainerJSON(inspectMsg.ContainerID)
+	case common.InspectJSONMsg:
+		m.inspectModal.SetJSON(inspectMsg.ContainerID, inspectMsg.JSON, inspectMsg.Err)
+		return m, nil
+	case common.InspectActionMsg:
+		for i := range m.panes {
+			if m.panes[i].ID == inspectMsg.ContainerID {
+				return m, m.paneAction(i, inspectMsg.Action)
+			}
+		}
+		return m, nil
+	}
 
 	// Handle search-related messages even when search modal is visible
 	switch searchMsg := msg.(type) {
@@ -341,7 +678,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				matchCount := m.panes[m.maximizedPane].SetSearch(searchMsg.Query)
 				if searchMsg.Query != "" {
 					m.searchModal.SetMatchInfo(1, matchCount)
-					debug.Log("Search '%s' in maximized pane %d: %d matches", searchMsg.Query, m.maximizedPane, matchCount)
+					logger.Debug("Search '%s' in maximized pane %d: %d matches", searchMsg.Query, m.maximizedPane, matchCount)
 				}
 			}
 		} else {
@@ -350,13 +687,13 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			for i := range m.panes {
 				matchCount := m.panes[i].SetSearch(searchMsg.Query)
 				if searchMsg.Query != "" && matchCount > 0 {
-					debug.Log("Search '%s' in pane %d (%s): %d matches", searchMsg.Query, i, m.panes[i].Container.DisplayName(), matchCount)
+					logger.Debug("Search '%s' in pane %d (%s): %d matches", searchMsg.Query, i, m.panes[i].Container.DisplayName(), matchCount)
 				}
 				totalMatches += matchCount
 			}
 			if searchMsg.Query != "" {
 				m.searchModal.SetMatchInfo(1, totalMatches)
-				debug.Log("Search '%s' total: %d matches across %d panes", searchMsg.Query, totalMatches, len(m.panes))
+				logger.Debug("Search '%s' total: %d matches across %d panes", searchMsg.Query, totalMatches, len(m.panes))
 			}
 		}
 		return m, nil
@@ -394,52 +731,103 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			// Maximized view: clear only the maximized pane
 			if m.maximizedPane >= 0 && m.maximizedPane < len(m.panes) {
 				m.panes[m.maximizedPane].ClearSearch()
-				debug.Log("Search cleared in maximized pane %d", m.maximizedPane)
+				logger.Debug("Search cleared in maximized pane %d", m.maximizedPane)
 			}
 		} else {
 			// Tiled view: clear all panes
 			for i := range m.panes {
 				m.panes[i].ClearSearch()
 			}
-			debug.Log("Search cleared in all %d panes", len(m.panes))
+			logger.Debug("Search cleared in all %d panes", len(m.panes))
 		}
 		return m, nil
 	}
 
+	// Log stream and export messages must reach the main switch even while a
+	// modal is open, otherwise their readers are never re-armed and they stall
+	var streamMsg bool
+	switch msg.(type) {
+	case startStreamMsg, LogLineMsg, LogErrorMsg, StreamClosedMsg, restartStreamMsg, reconnectFailedMsg, reconnectAllMsg,
+		exportProgressMsg, exportDoneMsg, rateTickMsg, renderTickMsg, debugOverlayTickMsg,
+		totalsTickMsg, totalsSampledMsg, noOutputTickMsg, watchEventsMsg, containerEventMsg, eventsClosedMsg, containerExitedMsg,
+		ContainerActionMsg:
+		streamMsg = true
+	}
+
+	// Handle build prompt input
+	if m.buildPrompt.IsVisible() && !streamMsg {
+		var cmd tea.Cmd
+		m.buildPrompt, cmd = m.buildPrompt.Update(msg)
+		return m, cmd
+	}
+
+	// Handle signal prompt input
+	if m.signalPrompt.IsVisible() && !streamMsg {
+		var cmd tea.Cmd
+		m.signalPrompt, cmd = m.signalPrompt.Update(msg)
+		return m, cmd
+	}
+
+	// Handle rename prompt input
+	if m.renamePrompt.IsVisible() && !streamMsg {
+		var cmd tea.Cmd
+		m.renamePrompt, cmd = m.renamePrompt.Update(msg)
+		return m, cmd
+	}
+
 	// Handle search modal input
-	if m.searchModal.IsVisible() {
+	if m.searchModal.IsVisible() && !streamMsg {
 		var cmd tea.Cmd
 		m.searchModal, cmd = m.searchModal.Update(msg)
 		return m, cmd
 	}
 
 	// Handle inspect modal messages first
-	if m.inspectModal.IsVisible() {
+	if m.inspectModal.IsVisible() && !streamMsg {
+		// The inspected container can be renamed without closing the modal first
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, m.keys.Rename) && !m.inspectModal.IsSearching() {
+			for i := range m.panes {
+				if m.panes[i].ID == m.inspectModal.ContainerID() {
+					m.inspectModal.Close()
+					return m, m.renamePrompt.Open(m.panes[i].ID, m.panes[i].Container.Name)
+				}
+			}
+		}
 		var cmd tea.Cmd
 		m.inspectModal, cmd = m.inspectModal.Update(msg)
 		return m, cmd
 	}
 
 	// Handle help modal messages first
-	if m.helpModal.IsVisible() {
+	if m.helpModal.IsVisible() && !streamMsg {
 		var cmd tea.Cmd
 		m.helpModal, cmd = m.helpModal.Update(msg)
 		return m, cmd
 	}
 
 	// Handle config modal messages first
-	if m.configModal.IsVisible() {
+	if m.configModal.IsVisible() && !streamMsg {
 		var cmd tea.Cmd
 		m.configModal, cmd = m.configModal.Update(msg)
 		return m, cmd
 	}
 
+	// Handle saved projects modal messages first
+	if m.savedProjectsModal.IsVisible() && !streamMsg {
+		var cmd tea.Cmd
+		m.savedProjectsModal, cmd = m.savedProjectsModal.Update(msg)
+		return m, cmd
+	}
+	if _, ok := msg.(common.SavedProjectsClosedMsg); ok {
+		return m, nil
+	}
+
 	// Handle modal closed message
 	if closed, ok := msg.(common.ConfigModalClosedMsg); ok {
-		// Reload key bindings and toast settings in case they changed
+		// Reload key bindings and settings in case they changed
 		m.keys = common.DefaultKeyMap()
 		if closed.ConfigChanged {
-			m.toast.ReloadConfig()
+			m.reloadConfig()
 		}
 		return m, nil
 	}
@@ -465,20 +853,40 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			m.recalculateLayout()
 		}
 
+	case startStreamMsg:
+		for i := range m.panes {
+			if m.panes[i].ID == msg.ContainerID {
+				cmds = append(cmds, m.startStream(msg.ContainerID))
+				break
+			}
+		}
+
 	case LogLineMsg:
+		if !m.isCurrentStream(msg.ContainerID, msg.source) {
+			// Line from a stream that has since been replaced - stop reading it
+			break
+		}
 		for i := range m.panes {
 			if m.panes[i].ID == msg.ContainerID {
-				debug.Log("LogLine received: container=%s stream=%s len=%d", msg.ContainerID[:12], msg.Line.Stream, len(msg.Line.Content))
+				logger.Debug("LogLine received: container=%s stream=%s len=%d", msg.ContainerID[:12], msg.Line.Stream, len(msg.Line.Content))
 				m.panes[i].AddLogLine(msg.Line)
 				// Continue listening on the SAME channel
-				if stream, ok := m.streams[msg.ContainerID]; ok {
-					cmds = append(cmds, m.waitForLog(msg.ContainerID, stream.logChan))
+				cmds = append(cmds, m.waitForLog(msg.ContainerID, msg.source), m.scheduleRateTick())
+				if m.alerts.Len() > 0 {
+					line := msg.Line
+					line.Plain = stripANSI(line.Content)
+					cmds = append(cmds, notifyAlerts(m.alerts.Log(m.panes[i].Container, line)))
 				}
 				break
 			}
 		}
 
 	case LogErrorMsg:
+		if !m.isCurrentStream(msg.ContainerID, msg.source) {
+			break
+		}
+		// Drop the failed stream so its close doesn't trigger a second reconnect
+		m.stopStream(msg.ContainerID)
 		for i := range m.panes {
 			if m.panes[i].ID == msg.ContainerID {
 				m.panes[i].Connected = false
@@ -495,6 +903,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					Content:     "--- Waiting for container to restart... ---",
 				})
 				// Try to reconnect in case container was restarted externally
+				m.panes[i].reconnecting = true
 				cmds = append(cmds, m.tryReconnect(m.panes[i].Container))
 				break
 			}
@@ -502,6 +911,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 	case StreamClosedMsg:
 		// Log stream channel closed - container likely stopped or restarted externally
+		if !m.isCurrentStream(msg.ContainerID, msg.source) {
+			break
+		}
 		for i := range m.panes {
 			if m.panes[i].ID == msg.ContainerID {
 				// Only try to reconnect if we haven't already started
@@ -520,9 +932,10 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 						Content:     "--- Waiting for container to restart... ---",
 					})
 					// Clean up old stream reference
-					delete(m.streams, msg.ContainerID)
+					m.stopStream(msg.ContainerID)
 					// Try to reconnect
-					cmds = append(cmds, m.tryReconnect(m.panes[i].Container))
+					m.panes[i].reconnecting = true
+					cmds = append(cmds, m.tryReconnect(m.panes[i].Container), m.inspectExit(m.panes[i].Container))
 				}
 				break
 			}
@@ -541,6 +954,42 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Digits build a count for the next scroll. They're also pane and tab
+		// shortcuts: when anything but a scroll follows, or nothing does
+		// within countTimeout, the last one is applied as its shortcut. The
+		// focus only moves then, so the pane a count is typed over is left as
+		// it was (its unseen stderr badge included).
+		count := 1
+		if next, ok := common.AppendCount(m.count, msg); ok && !m.countFlushing {
+			m.count = next
+			if key.Matches(msg, m.keys.Pane1, m.keys.Pane2, m.keys.Pane3, m.keys.Pane4, m.keys.Pane5,
+				m.keys.Pane6, m.keys.Pane7, m.keys.Pane8, m.keys.Pane9) {
+				m.countKey = msg
+			}
+			m.countGen++
+			gen := m.countGen
+			return m, tea.Tick(countTimeout, func(time.Time) tea.Msg { return countTimeoutMsg{gen: gen} })
+		} else if m.count > 0 {
+			if key.Matches(msg, m.keys.Up, m.keys.Down, m.keys.ScrollUp, m.keys.ScrollDown) {
+				count = m.count
+				m.count = 0
+				m.countKey = tea.KeyMsg{}
+			} else {
+				var cmd tea.Cmd
+				m, cmd = m.flushCount()
+				cmds = append(cmds, cmd)
+			}
+		}
+
+		// A compose logs pane has no single container to act on
+		if m.focusedPane >= 0 && m.focusedPane < len(m.panes) && m.panes[m.focusedPane].Container.IsComposeLogs() &&
+			key.Matches(msg, m.keys.Start, m.keys.Stop, m.keys.Restart, m.keys.Kill, m.keys.Remove, m.keys.Exec,
+				m.keys.Inspect, m.keys.Pause, m.keys.Signal, m.keys.Rename, m.keys.ComposeUp, m.keys.ComposeDown,
+				m.keys.ComposeRestart, m.keys.ComposeBuild, m.keys.ComposeLogs, m.keys.ExportLogs, m.keys.CopyID,
+				m.keys.CopyExec) {
+			return m, m.toast.Show("Compose logs", "Not available in a compose logs pane", common.ToastInfo)
+		}
+
 		switch {
 		case key.Matches(msg, m.keys.Back):
 			// Single-pane sessions should leave log view immediately.
@@ -562,7 +1011,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			return m, func() tea.Msg { return BackToDiscoveryMsg{} }
 
 		case key.Matches(msg, m.keys.Quit):
-			m.cancel()
+			m.Cleanup()
 			return m, tea.Quit
 
 		case key.Matches(msg, m.keys.NextPane):
@@ -573,6 +1022,19 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				m.recalculateLayout()
 			}
 
+		case key.Matches(msg, m.keys.NewestPane):
+			idx := m.newestPane()
+			if idx < 0 {
+				return m, m.toast.Show("No activity", "No pane has received a log line yet", common.ToastInfo)
+			}
+			m.setFocus(idx)
+			if m.maximizedPane != -1 {
+				m.maximizedPane = m.focusedPane
+				m.recalculateLayout()
+			}
+			m.panes[idx].flashUntil = time.Now().Add(paneFlashDuration)
+			return m, tea.Tick(paneFlashDuration, func(time.Time) tea.Msg { return flashDoneMsg{} })
+
 		case key.Matches(msg, m.keys.PrevPane):
 			m.focusPrevPane()
 			// If maximized, show the newly focused pane
@@ -613,13 +1075,15 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				if m.maximizedPane >= 0 && m.maximizedPane < len(m.panes) {
 					pane := &m.panes[m.maximizedPane]
 					if pane.GetActiveTab() == TabLogs {
-						pane.Viewport.SetYOffset(pane.Viewport.YOffset - 1)
+						m.scrollLogs(m.maximizedPane, -count)
 					} else {
-						pane.ScrollTabUp(1)
+						pane.ScrollTabUp(count)
 					}
 				}
 			} else {
-				m.focusUp()
+				for range count {
+					m.focusUp()
+				}
 			}
 
 		case key.Matches(msg, m.keys.Down):
@@ -628,13 +1092,15 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				if m.maximizedPane >= 0 && m.maximizedPane < len(m.panes) {
 					pane := &m.panes[m.maximizedPane]
 					if pane.GetActiveTab() == TabLogs {
-						pane.Viewport.SetYOffset(pane.Viewport.YOffset + 1)
+						m.scrollLogs(m.maximizedPane, count)
 					} else {
-						pane.ScrollTabDown(1)
+						pane.ScrollTabDown(count)
 					}
 				}
 			} else {
-				m.focusDown()
+				for range count {
+					m.focusDown()
+				}
 			}
 
 		case key.Matches(msg, m.keys.Left):
@@ -678,7 +1144,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				paneIdx = m.maximizedPane
 			}
 			if paneIdx >= 0 && paneIdx < len(m.panes) {
-				m.panes[paneIdx].Viewport.SetYOffset(m.panes[paneIdx].Viewport.YOffset - 3)
+				m.scrollLogs(paneIdx, -3*count)
 			}
 
 		case key.Matches(msg, m.keys.ScrollDown):
@@ -687,28 +1153,30 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				paneIdx = m.maximizedPane
 			}
 			if paneIdx >= 0 && paneIdx < len(m.panes) {
-				m.panes[paneIdx].Viewport.SetYOffset(m.panes[paneIdx].Viewport.YOffset + 3)
+				m.scrollLogs(paneIdx, 3*count)
 			}
 
 		// Container actions
 		case key.Matches(msg, m.keys.Restart):
-			if m.focusedPane >= 0 && m.focusedPane < len(m.panes) {
-				pane := &m.panes[m.focusedPane]
-				debug.Log("Restart requested for container: %s", pane.Container.DisplayName())
-				pane.AddLogLine(docker.LogLine{
-					ContainerID: pane.ID,
-					Timestamp:   time.Now(),
-					Stream:      "system",
-					Content:     "--- Restarting container... ---",
-				})
-				cmds = append(cmds, m.restartContainer(pane.Container))
+			cmds = append(cmds, m.paneAction(m.focusedPane, common.InspectRestart))
+
+		case key.Matches(msg, m.keys.Start):
+			cmds = append(cmds, m.paneAction(m.focusedPane, common.InspectStart))
+
+		case key.Matches(msg, m.keys.Stop):
+			// s also shows redacted env vars on the Env tab of a maximized pane
+			if m.maximizedPane >= 0 && m.maximizedPane < len(m.panes) && msg.String() == "s" &&
+				m.panes[m.maximizedPane].GetActiveTab() == TabEnv {
+				m.panes[m.maximizedPane].ToggleRedactedEnv()
+				break
 			}
+			cmds = append(cmds, m.paneAction(m.focusedPane, common.InspectStop))
 
 		case key.Matches(msg, m.keys.Kill):
 			if m.focusedPane >= 0 && m.focusedPane < len(m.panes) {
 				pane := &m.panes[m.focusedPane]
 				if pane.Container.State == "running" {
-					debug.Log("Kill requested for container: %s", pane.Container.DisplayName())
+					logger.Info("Kill requested for container: %s", pane.Container.DisplayName())
 					pane.AddLogLine(docker.LogLine{
 						ContainerID: pane.ID,
 						Timestamp:   time.Now(),
@@ -721,10 +1189,53 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				}
 			}
 
+		case key.Matches(msg, m.keys.Signal):
+			// Pick a signal to send to the focused container
+			paneIdx := m.focusedPane
+			if m.maximizedPane != -1 {
+				paneIdx = m.maximizedPane
+			}
+			if paneIdx >= 0 && paneIdx < len(m.panes) {
+				pane := &m.panes[paneIdx]
+				if pane.Container.State == "running" {
+					cmds = append(cmds, m.signalPrompt.Open(pane.ID, pane.Container.DisplayName()))
+				} else {
+					cmds = append(cmds, m.toast.Show("Cannot signal", "Container not running", common.ToastError))
+				}
+			}
+
+		case key.Matches(msg, m.keys.Rename):
+			paneIdx := m.focusedPane
+			if m.maximizedPane != -1 {
+				paneIdx = m.maximizedPane
+			}
+			if paneIdx >= 0 && paneIdx < len(m.panes) && !m.panes[paneIdx].exited {
+				pane := &m.panes[paneIdx]
+				cmds = append(cmds, m.renamePrompt.Open(pane.ID, pane.Container.Name))
+			}
+
+		case key.Matches(msg, m.keys.Pause):
+			// Freeze or resume the focused container's processes
+			paneIdx := m.focusedPane
+			if m.maximizedPane != -1 {
+				paneIdx = m.maximizedPane
+			}
+			if paneIdx >= 0 && paneIdx < len(m.panes) {
+				pane := &m.panes[paneIdx]
+				switch pane.Container.State {
+				case "running", "paused":
+					pause := pane.Container.State == "running"
+					logger.Info("Pause requested for container: %s (pause=%v)", pane.Container.DisplayName(), pause)
+					cmds = append(cmds, m.pauseContainer(pane.Container, pause))
+				default:
+					cmds = append(cmds, m.toast.Show("Cannot pause", "Container not running", common.ToastError))
+				}
+			}
+
 		case key.Matches(msg, m.keys.Remove):
 			if m.focusedPane >= 0 && m.focusedPane < len(m.panes) {
 				pane := &m.panes[m.focusedPane]
-				debug.Log("Remove requested for container: %s", pane.Container.DisplayName())
+				logger.Info("Remove requested for container: %s", pane.Container.DisplayName())
 				pane.AddLogLine(docker.LogLine{
 					ContainerID: pane.ID,
 					Timestamp:   time.Now(),
@@ -746,11 +1257,26 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				cmds = append(cmds, m.composeDownUp(pane.Container))
 			}
 
+		case key.Matches(msg, m.keys.ComposeLogs):
+			if m.focusedPane >= 0 && m.focusedPane < len(m.panes) {
+				cont := m.panes[m.focusedPane].Container
+				if cont.ComposeProject == "" {
+					cmds = append(cmds, m.toast.Show("Compose logs", cont.DisplayName()+" is not part of a compose project", common.ToastError))
+					break
+				}
+				return m, func() tea.Msg { return ComposeLogsMsg{Container: cont} }
+			}
+
 		case key.Matches(msg, m.keys.ComposeBuild):
 			if m.focusedPane >= 0 && m.focusedPane < len(m.panes) {
 				pane := &m.panes[m.focusedPane]
 				if pane.Container.ComposeProject == "" || pane.Container.ComposeService == "" {
-					cmds = append(cmds, m.toast.Show("Cannot build", "Not a compose service", common.ToastError))
+					// Standalone container: ask for the build context, prefilled with the remembered one
+					var bc config.BuildContext
+					if cfg, err := config.Load(); err == nil {
+						bc, _ = cfg.GetBuildContext(pane.Container.Name)
+					}
+					cmds = append(cmds, m.buildPrompt.Open(pane.ID, pane.Container.Name, bc))
 				} else {
 					// Start streaming build
 					pane.SetBuildMode("build")
@@ -785,6 +1311,13 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.Config):
 			return m, m.configModal.Open()
 
+		case key.Matches(msg, m.keys.SavedProjects):
+			m.savedProjectsModal.SetCurrentViews(m.currentViews())
+			return m, m.savedProjectsModal.Open()
+
+		case key.Matches(msg, m.keys.ReloadConfig):
+			cmds = append(cmds, common.ShowReloadResult(&m.toast, m.reloadConfig(), "reloaded"))
+
 		case key.Matches(msg, m.keys.Help):
 			return m, m.helpModal.Open()
 
@@ -799,6 +1332,56 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				cmds = append(cmds, m.inspectContainer(pane.Container))
 			}
 
+		case key.Matches(msg, m.keys.Reconnect):
+			// Manually reconnect a disconnected log stream
+			paneIdx := m.focusedPane
+			if m.maximizedPane != -1 {
+				paneIdx = m.maximizedPane
+			}
+			if paneIdx >= 0 && paneIdx < len(m.panes) {
+				pane := &m.panes[paneIdx]
+				switch {
+				case pane.Connected:
+					cmds = append(cmds, m.toast.Show("Already connected", pane.Container.DisplayName(), common.ToastInfo))
+				case pane.reconnecting:
+					cmds = append(cmds, m.toast.Show("Reconnect in progress", pane.Container.DisplayName(), common.ToastInfo))
+				default:
+					logger.Info("Manual reconnect requested for container: %s", pane.Container.DisplayName())
+					pane.reconnecting = true
+					pane.AddLogLine(docker.LogLine{
+						ContainerID: pane.ID,
+						Timestamp:   time.Now(),
+						Stream:      "system",
+						Content:     "--- Reconnecting... ---",
+					})
+					cmds = append(cmds, m.reconnectNow(pane.Container))
+				}
+			}
+
+		case key.Matches(msg, m.keys.ReconnectAll):
+			// Panes already reconnecting are left to it, and compose logs
+			// panes to their own restart, so none gets two at once
+			var conts []docker.Container
+			for i := range m.panes {
+				if m.panes[i].Connected || m.panes[i].reconnecting || m.panes[i].Container.IsComposeLogs() {
+					continue
+				}
+				m.panes[i].reconnecting = true
+				m.panes[i].AddLogLine(docker.LogLine{
+					ContainerID: m.panes[i].ID,
+					Timestamp:   time.Now(),
+					Stream:      "system",
+					Content:     "--- Reconnecting... ---",
+				})
+				conts = append(conts, m.panes[i].Container)
+			}
+			if len(conts) == 0 {
+				cmds = append(cmds, m.toast.Show("All panes connected", "Nothing to reconnect", common.ToastInfo))
+				break
+			}
+			logger.Info("Reconnect requested for %d disconnected panes", len(conts))
+			cmds = append(cmds, m.reconnectAll(conts))
+
 		case key.Matches(msg, m.keys.Search):
 			return m, m.searchModal.Open()
 
@@ -809,25 +1392,97 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				text := pane.GetPlainTextLogs()
 				if text != "" {
 					if err := clipboard.WriteAll(text); err == nil {
-						lineCount := len(pane.LogLines)
-						debug.Log("Copied %d lines (%d chars) from %s", lineCount, len(text), pane.Container.DisplayName())
+						lineCount := pane.LogLines.Len()
+						logger.Debug("Copied %d lines (%d chars) from %s", lineCount, len(text), pane.Container.DisplayName())
 						cmds = append(cmds, m.toast.Show("Copied", fmt.Sprintf("%d lines", lineCount), common.ToastSuccess))
 					}
 				}
 			}
 
+		case key.Matches(msg, m.keys.CopyLogsANSI):
+			// Like CopyLogs, keeping color codes for ANSI-aware terminals
+			if m.focusedPane >= 0 && m.focusedPane < len(m.panes) {
+				pane := &m.panes[m.focusedPane]
+				text := pane.GetANSILogs()
+				if text != "" {
+					if err := clipboard.WriteAll(text); err == nil {
+						cmds = append(cmds, m.toast.Show("Copied with colors", fmt.Sprintf("%d lines", pane.LogLines.Len()), common.ToastSuccess))
+					}
+				}
+			}
+
+		case key.Matches(msg, m.keys.ExportLogs):
+			// Export the complete log history, not just what the pane buffer holds
+			paneIdx := m.focusedPane
+			if m.maximizedPane != -1 {
+				paneIdx = m.maximizedPane
+			}
+			if paneIdx >= 0 && paneIdx < len(m.panes) {
+				cmds = append(cmds, m.startExport(m.panes[paneIdx].Container))
+			}
+
+		case key.Matches(msg, m.keys.OpenPager):
+			paneIdx := m.focusedPane
+			if m.maximizedPane != -1 {
+				paneIdx = m.maximizedPane
+			}
+			if paneIdx >= 0 && paneIdx < len(m.panes) {
+				pane := &m.panes[paneIdx]
+				cmd, err := openPager(pane.GetPlainTextLogs(), pane.Container.DisplayName())
+				if err != nil {
+					cmds = append(cmds, m.toast.Show("Pager failed", err.Error(), common.ToastError))
+				} else {
+					cmds = append(cmds, cmd)
+				}
+			}
+
 		case key.Matches(msg, m.keys.CopySelection):
 			cmd := m.copySelectedRange()
 			if cmd != nil {
 				cmds = append(cmds, cmd)
 			}
 
-		case key.Matches(msg, m.keys.WordWrap):
-			// Toggle word wrap
-			m.wordWrap = !m.wordWrap
-			debug.Log("Word wrap toggled: %v", m.wordWrap)
-			// Re-render all panes with new wrap setting
-			for i := range m.panes {
+		case key.Matches(msg, m.keys.CopyID), key.Matches(msg, m.keys.CopyName):
+			// Copy the focused container's full ID or display name
+			paneIdx := m.focusedPane
+			if m.maximizedPane != -1 {
+				paneIdx = m.maximizedPane
+			}
+			if paneIdx >= 0 && paneIdx < len(m.panes) {
+				pane := &m.panes[paneIdx]
+				label, text := "Copied name", pane.Container.DisplayName()
+				if key.Matches(msg, m.keys.CopyID) {
+					label, text = "Copied ID", pane.Container.ID
+				}
+				if err := clipboard.WriteAll(text); err != nil {
+					cmds = append(cmds, m.toast.Show("Copy failed", err.Error(), common.ToastError))
+				} else {
+					logger.Debug("%s for %s: %s", label, pane.Container.DisplayName(), text)
+					cmds = append(cmds, m.toast.Show(label, text, common.ToastSuccess))
+				}
+			}
+
+		case key.Matches(msg, m.keys.CopyExec):
+			// Copy a shell command for the focused container, to run in another terminal
+			paneIdx := m.focusedPane
+			if m.maximizedPane != -1 {
+				paneIdx = m.maximizedPane
+			}
+			if paneIdx >= 0 && paneIdx < len(m.panes) {
+				text := execCommand(m.panes[paneIdx].Container)
+				if err := clipboard.WriteAll(text); err != nil {
+					cmds = append(cmds, m.toast.Show("Copy failed", err.Error(), common.ToastError))
+				} else {
+					cmds = append(cmds, m.toast.Show("Copied command", text, common.ToastSuccess))
+				}
+			}
+
+		case key.Matches(msg, m.keys.WordWrap):
+			// Toggle word wrap
+			m.wordWrap = !m.wordWrap
+			logger.Debug("Word wrap toggled: %v", m.wordWrap)
+			// Re-render all panes with new wrap setting
+			for i := range m.panes {
 				m.panes[i].SetWordWrap(m.wordWrap)
 			}
 			status := "off"
@@ -836,16 +1491,120 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			}
 			cmds = append(cmds, m.toast.Show("Word Wrap", status, common.ToastSuccess))
 
+		case key.Matches(msg, m.keys.UTCToggle):
+			// Toggle UTC/local timestamps
+			m.utcTimestamps = !m.utcTimestamps
+			logger.Debug("UTC timestamps toggled: %v", m.utcTimestamps)
+			for i := range m.panes {
+				m.panes[i].SetUTCTimestamps(m.utcTimestamps)
+			}
+			zone := "local"
+			if m.utcTimestamps {
+				zone = "UTC"
+			}
+			cmds = append(cmds, m.toast.Show("Timestamps", zone, common.ToastSuccess))
+
+		case key.Matches(msg, m.keys.CopyStamps):
+			// Toggle whether copied text keeps the timestamp column
+			m.copyNoTimestamps = !m.copyNoTimestamps
+			for i := range m.panes {
+				m.panes[i].copyNoTimestamps = m.copyNoTimestamps
+			}
+			status := "included"
+			if m.copyNoTimestamps {
+				status = "left out"
+			}
+			cmds = append(cmds, m.toast.Show("Timestamps in copies", status, common.ToastSuccess))
+
+		case key.Matches(msg, m.keys.StreamFilter):
+			// Cycle the focused pane through both / stdout / stderr
+			paneIdx := m.focusedPane
+			if m.maximizedPane != -1 {
+				paneIdx = m.maximizedPane
+			}
+			if paneIdx >= 0 && paneIdx < len(m.panes) {
+				pane := &m.panes[paneIdx]
+				m.selection.Clear()
+				pane.ClearSelection()
+				pane.SetStreamFilter(pane.StreamFilter().Next())
+				logger.Debug("Stream filter for %s: %s", pane.Container.DisplayName(), pane.StreamFilter())
+				cmds = append(cmds, m.toast.Show("Streams", pane.StreamFilter().String(), common.ToastSuccess))
+			}
+
+		case key.Matches(msg, m.keys.SyncScroll):
+			m.syncScroll = !m.syncScroll
+			m.syncTime = false
+			status := "off"
+			if m.syncScroll {
+				status = "on: panes scroll together"
+			}
+			cmds = append(cmds, m.toast.Show("Sync scroll", status, common.ToastSuccess))
+
+		case key.Matches(msg, m.keys.SyncTime):
+			m.syncTime = !m.syncTime
+			m.syncScroll = false
+			status := "off"
+			if m.syncTime {
+				status = "on: panes follow the scrolled pane's time"
+				paneIdx := m.focusedPane
+				if m.maximizedPane != -1 {
+					paneIdx = m.maximizedPane
+				}
+				m.scrollLogs(paneIdx, 0)
+			}
+			cmds = append(cmds, m.toast.Show("Sync time", status, common.ToastSuccess))
+
+		case key.Matches(msg, m.keys.FreezeLayout):
+			m.freezeLayout = !m.freezeLayout
+			logger.Debug("Freeze layout toggled: %v", m.freezeLayout)
+			status := "frozen"
+			if !m.freezeLayout {
+				// Placeholders only make sense while frozen
+				for i := len(m.panes) - 1; i >= 0; i-- {
+					if m.panes[i].exited {
+						m.removePane(i)
+					}
+				}
+				status = "unfrozen"
+			}
+			cmds = append(cmds, m.toast.Show("Layout", status, common.ToastSuccess))
+
+		case key.Matches(msg, m.keys.DismissPane):
+			paneIdx := m.focusedPane
+			if m.maximizedPane != -1 {
+				paneIdx = m.maximizedPane
+			}
+			if paneIdx >= 0 && paneIdx < len(m.panes) {
+				if !m.panes[paneIdx].exited {
+					cmds = append(cmds, m.toast.Show("Not exited", "Only exited panes can be dismissed", common.ToastInfo))
+				} else {
+					name := m.panes[paneIdx].displayName()
+					m.removePane(paneIdx)
+					cmds = append(cmds, m.toast.Show("Dismissed", name, common.ToastSuccess))
+				}
+			}
+
 		case key.Matches(msg, m.keys.DebugToggle):
 			// Toggle debug logging
 			enabled := debug.Toggle()
 			status := "off"
 			if enabled {
 				status = "on"
-				debug.Log("Debug toggled on from logview")
+				logger.Info("Debug toggled on from logview")
 			}
 			cmds = append(cmds, m.toast.Show("Debug Log", status, common.ToastSuccess))
 
+		case key.Matches(msg, m.keys.DebugOverlay):
+			if !debug.IsEnabled() {
+				cmds = append(cmds, m.toast.Show("Debug Overlay", "Turn on debug logging first", common.ToastInfo))
+				break
+			}
+			m.debugOverlay = !m.debugOverlay
+			if m.debugOverlay {
+				m.debugTail = debug.Tail(debugOverlayLines)
+				cmds = append(cmds, m.scheduleDebugOverlayTick())
+			}
+
 		case key.Matches(msg, m.keys.Exec):
 			// Complete tutorial if on shell step
 			if m.tutorial.Active && m.tutorial.Step == common.TutorialStepShell {
@@ -859,7 +1618,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			if paneIdx >= 0 && paneIdx < len(m.panes) {
 				pane := &m.panes[paneIdx]
 				if pane.Container.State == "running" {
-					debug.Log("Opening shell in container: %s", pane.Container.DisplayName())
+					logger.Info("Opening shell in container: %s", pane.Container.DisplayName())
 					return m, m.execShell(pane.Container)
 				} else {
 					cmds = append(cmds, m.toast.Show("Cannot exec", "Container not running", common.ToastError))
@@ -892,7 +1651,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				cmds = append(cmds, m.toast.Show("Logs "+status, m.panes[paneIdx].Container.DisplayName(), common.ToastSuccess))
 			}
 
-		// Pane number shortcuts (1-9) - but in maximized mode, 1-5 switch tabs instead
+		// Pane number shortcuts (1-9) - but in maximized mode, 1-6 switch tabs instead
 		case key.Matches(msg, m.keys.Pane1):
 			if m.maximizedPane != -1 && m.maximizedPane < len(m.panes) {
 				// In maximized mode: switch to Logs tab
@@ -949,12 +1708,15 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 				m.setFocus(4)
 			}
 		case key.Matches(msg, m.keys.Pane6):
-			if len(m.panes) >= 6 {
-				m.setFocus(5)
-				if m.maximizedPane != -1 {
-					m.maximizedPane = 5
-					m.recalculateLayout()
+			if m.maximizedPane != -1 && m.maximizedPane < len(m.panes) {
+				// In maximized mode: switch to Events tab
+				pane := &m.panes[m.maximizedPane]
+				cmd := m.switchToTab(pane, TabEvents)
+				if cmd != nil {
+					cmds = append(cmds, cmd)
 				}
+			} else if len(m.panes) >= 6 {
+				m.setFocus(5)
 			}
 		case key.Matches(msg, m.keys.Pane7):
 			if len(m.panes) >= 7 {
@@ -998,18 +1760,27 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			if m.maximizedPane == -1 && len(m.panes) > 1 {
 				m.resizeFocusedPaneHeight(ResizeStep)
 			}
+		case key.Matches(msg, m.keys.SwapLeft):
+			m.swapFocusedPane(0, -1)
+		case key.Matches(msg, m.keys.SwapRight):
+			m.swapFocusedPane(0, 1)
+		case key.Matches(msg, m.keys.SwapUp):
+			m.swapFocusedPane(-1, 0)
+		case key.Matches(msg, m.keys.SwapDown):
+			m.swapFocusedPane(1, 0)
 		case key.Matches(msg, m.keys.ResizeReset):
-			if m.maximizedPane == -1 {
-				m.layout.ResetRatios()
-				m.recalculateLayout()
-				cmds = append(cmds, m.toast.Show("Layout", "Reset to equal", common.ToastSuccess))
-			}
+			// Also works while maximized, so the grid is even when restored
+			m.layout.ResetRatios()
+			m.recalculateLayout()
+			cmds = append(cmds, m.toast.Show("Layout", "Reset to equal", common.ToastSuccess))
 
 		// Tab cycling with [ and ], and 'r' for redacted toggle (only in maximized mode)
 		default:
+			handled := false
 			if m.maximizedPane != -1 && m.maximizedPane >= 0 && m.maximizedPane < len(m.panes) {
 				pane := &m.panes[m.maximizedPane]
 				keyStr := msg.String()
+				handled = keyStr == "[" || keyStr == "]" || (keyStr == "s" && pane.GetActiveTab() == TabEnv)
 
 				if keyStr == "[" {
 					oldTab := pane.GetActiveTab()
@@ -1036,6 +1807,11 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					pane.ToggleRedactedEnv()
 				}
 			}
+			if !handled {
+				if cmd := m.toast.ShowUnboundKey(msg); cmd != nil {
+					cmds = append(cmds, cmd)
+				}
+			}
 		}
 
 	case StatsUpdateMsg:
@@ -1043,6 +1819,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		for i := range m.panes {
 			if m.panes[i].ID == msg.ContainerID {
 				m.panes[i].AddStats(msg.Stats)
+				cmds = append(cmds, notifyAlerts(m.alerts.Stats(m.panes[i].Container, msg.Stats)))
 				// Continue listening for more stats if still streaming
 				if m.statsStreaming && m.statsChan != nil {
 					cmds = append(cmds, m.waitForStats(msg.ContainerID, m.statsChan))
@@ -1051,6 +1828,18 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			}
 		}
 
+	case containerExitedMsg:
+		// Events may have reported this already; inspect covers a missed one
+		for i := range m.panes {
+			if m.panes[i].ID == msg.ContainerID && !m.panes[i].Connected {
+				if msg.OOMKilled {
+					cmds = append(cmds, m.markOOMKilled(i))
+				}
+				m.panes[i].markExitCode(msg.ExitCode, time.Now())
+				break
+			}
+		}
+
 	case containerDetailsLoadedMsg:
 		// Handle container details loaded for Env/Config tabs
 		for i := range m.panes {
@@ -1060,10 +1849,22 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			}
 		}
 
+	case statsSnapshotMsg:
+		// Only useful until the stream has a CPU reading of its own
+		if msg.Err != nil || !m.statsStreaming || m.statsContainerID != msg.ContainerID {
+			break
+		}
+		for i := range m.panes {
+			if m.panes[i].ID == msg.ContainerID && m.panes[i].statsHistory != nil && m.panes[i].statsHistory.Len() < 2 {
+				m.panes[i].AddStats(msg.Stats)
+				break
+			}
+		}
+
 	case StatsErrorMsg:
 		// Handle stats error - stop streaming
 		m.stopStatsStreaming()
-		debug.Log("Stats streaming error for %s: %v", msg.ContainerID, msg.Err)
+		logger.Warn("Stats streaming error for %s: %v", msg.ContainerID, msg.Err)
 
 	case TopUpdateMsg:
 		// Handle process list update
@@ -1110,6 +1911,41 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					// Restart log stream for this container
 					cmds = append(cmds, m.restartLogStream(m.panes[i].Container))
 				}
+				// An action taken from the inspect modal shows its result there
+				if m.inspectModal.IsVisible() && m.inspectModal.ContainerID() == msg.ContainerID {
+					cmds = append(cmds, m.inspectContainer(m.panes[i].Container))
+				}
+				break
+			}
+		}
+
+	case ContainerPausedMsg:
+		action := "Unpause"
+		if msg.Paused {
+			action = "Pause"
+		}
+		for i := range m.panes {
+			if m.panes[i].ID == msg.ContainerID {
+				serviceName := m.panes[i].Container.DisplayName()
+				if msg.Err != nil {
+					logger.Warn("%s failed for %s: %v", action, serviceName, msg.Err)
+					cmds = append(cmds, m.toast.Show(action+" Failed", msg.Err.Error(), common.ToastError))
+					break
+				}
+				// The log stream stays open while paused, it just goes quiet
+				content := "--- Container paused (processes frozen) ---"
+				m.panes[i].Container.State = "paused"
+				if !msg.Paused {
+					content = "--- Container unpaused ---"
+					m.panes[i].Container.State = "running"
+				}
+				m.panes[i].AddLogLine(docker.LogLine{
+					ContainerID: msg.ContainerID,
+					Timestamp:   time.Now(),
+					Stream:      "system",
+					Content:     content,
+				})
+				cmds = append(cmds, m.toast.Show(action+" Complete", serviceName, common.ToastSuccess))
 				break
 			}
 		}
@@ -1138,33 +1974,17 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					containerName = m.panes[i].Container.DisplayName()
 					paneIdx = i
 					// Clean up stream reference
-					delete(m.streams, msg.ContainerID)
+					m.stopStream(msg.ContainerID)
 					break
 				}
 			}
 			if paneIdx >= 0 {
-				// Remove pane from slice
-				m.panes = append(m.panes[:paneIdx], m.panes[paneIdx+1:]...)
-				// Recalculate layout
-				m.layout = CalculateLayout(len(m.panes))
-				// Adjust focused pane if needed
-				if m.focusedPane >= len(m.panes) {
-					m.focusedPane = len(m.panes) - 1
-				}
-				if m.focusedPane < 0 {
-					m.focusedPane = 0
-				}
-				// Reset maximized pane if it was the removed one
-				if m.maximizedPane == paneIdx {
-					m.maximizedPane = -1
-				} else if m.maximizedPane > paneIdx {
-					m.maximizedPane--
-				}
-				// Update focus states
-				for i := range m.panes {
-					m.panes[i].Active = (i == m.focusedPane)
+				if m.freezeLayout {
+					// Keep the slot so the grid doesn't reflow
+					m.markPaneExited(paneIdx, "--- Container removed ---")
+				} else {
+					m.removePane(paneIdx)
 				}
-				m.recalculateLayout()
 				cmds = append(cmds, m.toast.Show("Removed", containerName, common.ToastSuccess))
 			}
 		}
@@ -1173,16 +1993,30 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		// All reconnection attempts failed
 		for i := range m.panes {
 			if m.panes[i].ID == msg.ContainerID {
+				m.panes[i].reconnecting = false
+				if msg.Manual {
+					cmds = append(cmds, m.toast.Show("Reconnect failed", "Container not running", common.ToastError))
+					break
+				}
+				if m.freezeLayout {
+					m.markPaneExited(i, "--- Could not reconnect. Container has exited"+m.panes[i].oomNote()+". ---")
+					break
+				}
 				m.panes[i].AddLogLine(docker.LogLine{
 					ContainerID: msg.ContainerID,
 					Timestamp:   time.Now(),
 					Stream:      "system",
-					Content:     "--- Could not reconnect. Container may have stopped. ---",
+					Content:     "--- Could not reconnect. Container may have stopped" + m.panes[i].oomNote() + ". ---",
 				})
 				break
 			}
 		}
 
+	case pagerExitMsg:
+		if msg.Err != nil {
+			cmds = append(cmds, m.toast.Show("Pager exited", msg.Err.Error(), common.ToastError))
+		}
+
 	case shellExitMsg:
 		// Shell session ended, show toast
 		for i := range m.panes {
@@ -1196,6 +2030,80 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			}
 		}
 
+	case common.SignalPromptConfirmedMsg:
+		for i := range m.panes {
+			if m.panes[i].ID == msg.ContainerID {
+				logger.Info("Sending %s to container: %s", msg.Signal, msg.ContainerName)
+				cmds = append(cmds, m.signalContainer(m.panes[i].Container, msg.Signal))
+				break
+			}
+		}
+
+	case ContainerSignalMsg:
+		for i := range m.panes {
+			if m.panes[i].ID == msg.ContainerID {
+				serviceName := m.panes[i].Container.DisplayName()
+				if msg.Err != nil {
+					m.panes[i].AddLogLine(docker.LogLine{
+						ContainerID: msg.ContainerID,
+						Timestamp:   time.Now(),
+						Stream:      "stderr",
+						Content:     fmt.Sprintf("--- Sending %s failed: %v ---", msg.Signal, msg.Err),
+					})
+					cmds = append(cmds, m.toast.Show("Signal Failed", serviceName, common.ToastError))
+				} else {
+					m.panes[i].AddLogLine(docker.LogLine{
+						ContainerID: msg.ContainerID,
+						Timestamp:   time.Now(),
+						Stream:      "system",
+						Content:     fmt.Sprintf("--- Sent %s ---", msg.Signal),
+					})
+					cmds = append(cmds, m.toast.Show("Sent "+msg.Signal, serviceName, common.ToastSuccess))
+				}
+				break
+			}
+		}
+
+	case common.RenamePromptConfirmedMsg:
+		logger.Info("Renaming container %s to %s", msg.OldName, msg.NewName)
+		cmds = append(cmds, m.renameContainer(msg.ContainerID, msg.OldName, msg.NewName))
+
+	case ContainerRenamedMsg:
+		if msg.Err != nil {
+			logger.Warn("Rename of %s failed: %v", msg.OldName, msg.Err)
+			cmds = append(cmds, m.toast.Show("Rename Failed", msg.Err.Error(), common.ToastError))
+			break
+		}
+		for i := range m.panes {
+			if m.panes[i].ID == msg.ContainerID {
+				// Reconnects look the container up by name, so keep it current
+				m.panes[i].Container.Name = msg.NewName
+				m.panes[i].AddLogLine(docker.LogLine{
+					ContainerID: msg.ContainerID,
+					Timestamp:   time.Now(),
+					Stream:      "system",
+					Content:     fmt.Sprintf("--- Renamed %s to %s ---", msg.OldName, msg.NewName),
+				})
+				break
+			}
+		}
+		m.refreshPaneNames()
+		cmds = append(cmds, m.toast.Show("Renamed", msg.OldName+" → "+msg.NewName, common.ToastSuccess))
+
+	case common.BuildPromptConfirmedMsg:
+		if cfg, err := config.Load(); err == nil {
+			if err := cfg.SetBuildContext(msg.ContainerName, msg.BuildContext); err != nil {
+				logger.Error("Failed to save build context for %s: %v", msg.ContainerName, err)
+			}
+		}
+		for i := range m.panes {
+			if m.panes[i].ID == msg.ContainerID {
+				m.panes[i].SetBuildMode("build")
+				cmds = append(cmds, m.startImageBuildStream(m.panes[i].Container, msg.BuildContext))
+				break
+			}
+		}
+
 	case BuildStreamStartedMsg:
 		// Store the stream and start listening
 		m.buildStreams[msg.ContainerID] = &msg.Stream
@@ -1230,6 +2138,16 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 					if msg.Error != nil {
 						errMsg = msg.Error.Error()
 					}
+					// Say whether the services are still up, so it's clear if they need recovering
+					var failed *docker.BuildFailedError
+					if errors.As(msg.Error, &failed) && len(failed.Services) > 0 {
+						errMsg = failed.Report()
+						operation := msg.Operation
+						cmds = append(cmds, func() tea.Msg {
+							notify.Error(operation + " failed: " + errMsg)
+							return nil
+						})
+					}
 					cmds = append(cmds, m.toast.Show(msg.Operation+" Failed", errMsg, common.ToastError))
 				}
 
@@ -1243,6 +2161,93 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			}
 		}
 
+	case exportProgressMsg:
+		status := "Reading log history..."
+		if msg.lines > 0 {
+			status = fmt.Sprintf("%d lines...", msg.lines)
+		}
+		cmds = append(cmds, m.toast.Show("Exporting "+msg.export.name, status, common.ToastInfo))
+		cmds = append(cmds, m.waitForExport(msg.export))
+
+	case exportDoneMsg:
+		if msg.err != nil {
+			logger.Error("Export of %s failed after %d lines: %v", msg.name, msg.lines, msg.err)
+			cmds = append(cmds, m.toast.Show("Export failed", msg.err.Error(), common.ToastError))
+		} else {
+			logger.Info("Exported %d lines from %s to %s", msg.lines, msg.name, msg.path)
+			cmds = append(cmds, m.toast.Show("Exported", fmt.Sprintf("%d lines to %s", msg.lines, msg.path), common.ToastSuccess))
+		}
+
+	case countTimeoutMsg:
+		if msg.gen == m.countGen && m.count > 0 {
+			var cmd tea.Cmd
+			m, cmd = m.flushCount()
+			cmds = append(cmds, cmd)
+		}
+
+	case renderTickMsg:
+		m.renderTicking = false
+		for i := range m.panes {
+			m.panes[i].FlushRender()
+		}
+
+	case restoreTabMsg:
+		if m.maximizedPane >= 0 && m.maximizedPane < len(m.panes) {
+			cmds = append(cmds, m.switchToTab(&m.panes[m.maximizedPane], m.restoreTab))
+		}
+
+	case debugOverlayTickMsg:
+		m.debugOverlayTicking = false
+		if m.debugOverlayVisible() {
+			m.debugTail = debug.Tail(debugOverlayLines)
+			cmds = append(cmds, m.scheduleDebugOverlayTick())
+		}
+
+	case rateTickMsg:
+		m.rateTicking = false
+		for i := range m.panes {
+			if m.panes[i].LogRate() > 0 {
+				cmds = append(cmds, m.scheduleRateTick())
+				break
+			}
+		}
+
+	case noOutputTickMsg:
+		for i := range m.panes {
+			if m.panes[i].ID == msg.ContainerID && m.panes[i].LogLines.Len() == 0 {
+				m.panes[i].Viewport.SetContent(m.panes[i].renderLogsWithSearch())
+				break
+			}
+		}
+
+	case totalsTickMsg:
+		cmds = append(cmds, m.sampleTotals())
+
+	case totalsSampledMsg:
+		if msg.err != nil {
+			// A timeout or daemon hiccup; retry, less often the longer it lasts
+			if m.totalsFailures == 0 {
+				logger.Warn("Container stats unavailable, retrying with backoff: %v", msg.err)
+			}
+			m.totalsFailures++
+			cmds = append(cmds, tea.Tick(totalsRetryDelay(m.totalsFailures), func(time.Time) tea.Msg {
+				return totalsTickMsg{}
+			}))
+			break
+		}
+		if m.totalsFailures > 0 {
+			logger.Info("Container stats available again after %d failed samples", m.totalsFailures)
+			m.totalsFailures = 0
+		}
+		for i := range m.panes {
+			// The Stats tab's stream already feeds its pane
+			if s, ok := msg.stats[m.panes[i].ID]; ok && !(m.statsStreaming && m.statsContainerID == m.panes[i].ID) {
+				m.panes[i].AddStats(s)
+				cmds = append(cmds, notifyAlerts(m.alerts.Stats(m.panes[i].Container, s)))
+			}
+		}
+		cmds = append(cmds, scheduleTotalsTick())
+
 	case returnToLogsMsg:
 		// Return pane to normal log view
 		for i := range m.panes {
@@ -1253,44 +2258,206 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		}
 
 	case restartStreamMsg:
-		// Update pane with new container info and restart log stream
+		cmds = append(cmds, m.restartPaneStream(msg))
+
+	case watchEventsMsg:
+		// Every container, not just the panes', so replacements are seen starting
+		events, errs := m.streamEvents(m.ctx)
+		cmds = append(cmds, waitForEvent(events, errs))
+
+	case containerEventMsg:
+		ev := msg.Event
+		if m.eventsFailures > 0 {
+			logger.Info("Container events available again after %d failed attempts", m.eventsFailures)
+			m.eventsFailures = 0
+		}
 		for i := range m.panes {
-			if m.panes[i].ID == msg.OldContainerID {
-				// Clean up old stream reference
-				if msg.OldContainerID != msg.NewContainer.ID {
-					delete(m.streams, msg.OldContainerID)
+			if m.panes[i].ID == ev.Container.ID {
+				m.panes[i].AddEvent(ev)
+				switch ev.Action {
+				case "oom":
+					// The OOM killer may have killed any process in the container;
+					// it was the container only if it dies before it starts again
+					m.panes[i].oomSeen = true
+				case "die":
+					if m.panes[i].oomSeen {
+						m.panes[i].oomSeen = false
+						cmds = append(cmds, m.markOOMKilled(i))
+					}
+					m.panes[i].markExitCode(ev.ExitCode, ev.Time)
+				case "start":
+					m.panes[i].oomSeen = false
 				}
+			}
+		}
+		if ev.Action == "start" {
+			// Follow a replacement container right away instead of waiting
+			// out the reconnect backoff
+			for i := range m.panes {
+				if m.panes[i].Connected {
+					continue
+				}
+				if c, ok := findReconnectTarget([]docker.Container{ev.Container}, m.panes[i].Container); ok {
+					logger.Info("Container %s started, reconnecting pane %s", c.ID, m.panes[i].Container.DisplayName())
+					if m.panes[i].ID != c.ID {
+						m.panes[i].AddEvent(ev) // recorded above when the ID didn't change
+					}
+					cmds = append(cmds, m.restartPaneStream(restartStreamMsg{OldContainerID: m.panes[i].ID, NewContainer: c}))
+				}
+			}
+		}
+		cmds = append(cmds, waitForEvent(msg.source, msg.errs))
 
-				// Update container info (ID might have changed)
-				m.panes[i].ID = msg.NewContainer.ID
-				m.panes[i].Container = msg.NewContainer
-				m.panes[i].Connected = true
+	case eventsClosedMsg:
+		if m.ctx.Err() != nil {
+			break
+		}
+		if m.eventsFailures == 0 {
+			logger.Warn("Container events unavailable, retrying with backoff: %v", msg.err)
+		}
+		m.eventsFailures++
+		cmds = append(cmds, tea.Tick(eventsRetryDelay(m.eventsFailures), func(time.Time) tea.Msg { return watchEventsMsg{} }))
 
-				// Clear old logs and reset viewport
-				m.panes[i].LogLines = make([]docker.LogLine, 0, maxLogLines)
-				m.panes[i].Viewport.SetContent("")
-				m.panes[i].Viewport.GotoTop()
+	case reconnectAllMsg:
+		for _, restart := range msg.restarts {
+			cmds = append(cmds, m.restartPaneStream(restart))
+		}
+		for _, id := range msg.failed {
+			for i := range m.panes {
+				if m.panes[i].ID == id {
+					m.panes[i].reconnecting = false
+				}
+			}
+		}
+		switch {
+		case msg.err != nil:
+			cmds = append(cmds, m.toast.Show("Reconnect failed", msg.err.Error(), common.ToastError))
+		case len(msg.failed) == 0:
+			cmds = append(cmds, m.toast.Show("Reconnected", fmt.Sprintf("%d panes", len(msg.restarts)), common.ToastSuccess))
+		default:
+			cmds = append(cmds, m.toast.Show("Reconnected",
+				fmt.Sprintf("%d of %d panes, %d not running", len(msg.restarts), len(msg.restarts)+len(msg.failed), len(msg.failed)),
+				common.ToastInfo))
+		}
+	}
 
-				// Add a system message indicating restart
-				m.panes[i].AddLogLine(docker.LogLine{
-					ContainerID: msg.NewContainer.ID,
-					Timestamp:   time.Now(),
-					Stream:      "system",
-					Content:     "--- Container restarted, streaming logs... ---",
-				})
+	return m, tea.Batch(cmds...)
+}
 
-				// Start new log stream
-				logChan, errChan := m.dockerClient.StreamLogs(m.ctx, msg.NewContainer.ID)
-				m.streams[msg.NewContainer.ID] = streamInfo{logChan: logChan, errChan: errChan}
+// restartPaneStream points a pane at the container that replaced its old one
+// and restarts its log stream
+func (m *Model) restartPaneStream(msg restartStreamMsg) tea.Cmd {
+	for i := range m.panes {
+		if m.panes[i].ID != msg.OldContainerID {
+			continue
+		}
+		if msg.reconnect && m.panes[i].Connected {
+			// Another path (e.g. a container start event) got there first
+			return nil
+		}
+		// Clean up old stream reference (startStream replaces a same-ID stream)
+		if msg.OldContainerID != msg.NewContainer.ID {
+			m.stopStream(msg.OldContainerID)
+		}
 
-				cmds = append(cmds, m.waitForLog(msg.NewContainer.ID, logChan))
-				cmds = append(cmds, m.waitForError(msg.NewContainer.ID, errChan))
-				break
-			}
+		// Update container info (ID might have changed)
+		m.panes[i].ID = msg.NewContainer.ID
+		m.panes[i].Container = msg.NewContainer
+		m.refreshPaneNames()
+		m.panes[i].exited = false
+		m.panes[i].Connected = true
+		m.panes[i].reconnecting = false
+
+		// Clear old logs and reset viewport
+		m.panes[i].resetLogLines()
+		m.panes[i].Viewport.SetContent("")
+		m.panes[i].Viewport.GotoTop()
+
+		// Add a system message indicating restart
+		content := "--- Container restarted, streaming logs... ---"
+		if m.panes[i].oomKilled {
+			content = "--- Container restarted after being OOM-killed, streaming logs... ---"
+			m.panes[i].oomKilled = false
 		}
+		m.panes[i].exitKnown = false
+		m.panes[i].AddLogLine(docker.LogLine{
+			ContainerID: msg.NewContainer.ID,
+			Timestamp:   time.Now(),
+			Stream:      "system",
+			Content:     content,
+		})
+
+		// Start new log stream
+		return m.startStream(msg.NewContainer.ID)
 	}
+	return nil
+}
 
-	return m, tea.Batch(cmds...)
+// removePane drops a pane from the grid and fixes up focus and maximize state
+func (m *Model) removePane(paneIdx int) {
+	m.panes = append(m.panes[:paneIdx], m.panes[paneIdx+1:]...)
+	m.refreshPaneNames()
+	// Recalculate layout
+	m.layout = m.calculateLayout()
+	// Adjust focused pane if needed
+	if m.focusedPane >= len(m.panes) {
+		m.focusedPane = len(m.panes) - 1
+	}
+	if m.focusedPane < 0 {
+		m.focusedPane = 0
+	}
+	// Reset maximized pane if it was the removed one
+	if m.maximizedPane == paneIdx {
+		m.maximizedPane = -1
+	} else if m.maximizedPane > paneIdx {
+		m.maximizedPane--
+	}
+	// Update focus states
+	m.setFocus(m.focusedPane)
+	m.recalculateLayout()
+}
+
+// markPaneExited turns a pane into a greyed placeholder that keeps its slot
+// in the grid until dismissed
+func (m *Model) markPaneExited(paneIdx int, reason string) {
+	pane := &m.panes[paneIdx]
+	m.stopStream(pane.ID)
+	pane.exited = true
+	pane.Connected = false
+	pane.reconnecting = false
+	pane.AddLogLine(docker.LogLine{
+		ContainerID: pane.ID,
+		Timestamp:   time.Now(),
+		Stream:      "system",
+		Content:     reason,
+	})
+}
+
+// refreshPaneNames qualifies pane titles with their compose project when two
+// panes would otherwise show the same service name
+func (m *Model) refreshPaneNames() {
+	counts := make(map[string]int, len(m.panes))
+	qualified := make(map[string]int, len(m.panes))
+	for _, p := range m.panes {
+		counts[p.Container.DisplayName()]++
+		qualified[p.Container.QualifiedName()]++
+	}
+	for i := range m.panes {
+		// Even project/service is ambiguous for replicas of a scaled service
+		m.panes[i].qualifyName = counts[m.panes[i].Container.DisplayName()] > qualified[m.panes[i].Container.QualifiedName()]
+		m.panes[i].showReplica = qualified[m.panes[i].Container.QualifiedName()] > 1
+	}
+}
+
+// calculateLayout returns the grid for the current panes and screen size
+func (m *Model) calculateLayout() Layout {
+	return CalculateLayoutFor(len(m.panes), m.width, m.height-1, m.layoutBias)
+}
+
+// stacked reports whether tiling would leave panes too small to read. The
+// focused pane then fills the screen under a one-line list of all panes.
+func (m *Model) stacked() bool {
+	return m.maximizedPane == -1 && len(m.panes) > 1 && m.layout.TooSmall(m.width, m.height-1)
 }
 
 // getPanePosition returns the top-left corner coordinates of a pane by its index
@@ -1299,6 +2466,10 @@ func (m *Model) getPanePosition(paneIdx int) (x, y int) {
 	if m.maximizedPane >= 0 {
 		return 0, 0
 	}
+	// Stacked, the focused pane sits under the pane list
+	if m.stacked() {
+		return 0, 1
+	}
 
 	// Find the pane's row and column
 	row, col := m.getPaneGridPosition(paneIdx)
@@ -1331,6 +2502,12 @@ func (m *Model) getPaneAtPosition(x, y int) int {
 	if m.maximizedPane >= 0 {
 		return m.maximizedPane
 	}
+	if m.stacked() {
+		if y < 1 {
+			return -1
+		}
+		return m.focusedPane
+	}
 
 	// Reserve 1 line for help bar
 	availableHeight := m.height - 1
@@ -1378,8 +2555,8 @@ const borderProximity = 1
 // getBorderAtPosition checks if the mouse is near a resizable border
 // Returns the resize mode and border index, or ResizeNone if not on a border
 func (m *Model) getBorderAtPosition(x, y int) (ResizeMode, int) {
-	// Don't allow border resize when maximized
-	if m.maximizedPane >= 0 {
+	// Don't allow border resize when maximized or stacked
+	if m.maximizedPane >= 0 || m.stacked() {
 		return ResizeNone, -1
 	}
 
@@ -1413,13 +2590,13 @@ func (m *Model) handleMouse(msg tea.MouseMsg) tea.Cmd {
 			// Find which pane the mouse is over using grid position
 			paneIdx := m.getPaneAtPosition(msg.X, msg.Y)
 			if paneIdx >= 0 {
-				m.panes[paneIdx].Viewport.SetYOffset(m.panes[paneIdx].Viewport.YOffset - 3)
+				m.scrollLogs(paneIdx, -3)
 			}
 			return nil
 		case tea.MouseButtonWheelDown:
 			paneIdx := m.getPaneAtPosition(msg.X, msg.Y)
 			if paneIdx >= 0 {
-				m.panes[paneIdx].Viewport.SetYOffset(m.panes[paneIdx].Viewport.YOffset + 3)
+				m.scrollLogs(paneIdx, 3)
 			}
 			return nil
 		case tea.MouseButtonLeft:
@@ -1446,6 +2623,7 @@ func (m *Model) handleMouse(msg tea.MouseMsg) tea.Cmd {
 
 		// Update selection while dragging
 		if m.selection.Selecting {
+			m.autoScrollSelection(msg.Y)
 			m.selection.Update(msg.X, msg.Y)
 			// Update visual selection in the pane with character-level precision
 			paneIdx := m.selection.PaneIdx
@@ -1477,6 +2655,24 @@ func (m *Model) handleMouse(msg tea.MouseMsg) tea.Cmd {
 	return nil
 }
 
+// autoScrollSelection scrolls the pane being selected in by a line when the
+// drag reaches its top or bottom row, so a selection can extend past one screen
+func (m *Model) autoScrollSelection(y int) {
+	paneIdx := m.selection.PaneIdx
+	if paneIdx < 0 || paneIdx >= len(m.panes) {
+		return
+	}
+	pane := &m.panes[paneIdx]
+	top := m.selection.ContentTop()
+	bottom := top + pane.Viewport.Height - 1
+	if y <= top {
+		pane.Viewport.SetYOffset(pane.Viewport.YOffset - 1)
+	} else if y >= bottom {
+		pane.Viewport.SetYOffset(pane.Viewport.YOffset + 1)
+	}
+	m.selection.YOffset = pane.Viewport.YOffset
+}
+
 // handleResizeDrag processes mouse drag during border resize
 func (m *Model) handleResizeDrag(x, y int) {
 	if m.resizeMode == ResizeColumn {
@@ -1547,6 +2743,8 @@ func (m *Model) handleMouseClick(msg tea.MouseMsg) tea.Cmd {
 
 	// Start selection for potential drag
 	paneX, paneY := m.getPanePosition(paneIdx)
+	m.selection.YOffset = pane.Viewport.YOffset
+	m.selection.HeaderRows, m.selection.Width, m.selection.Height = pane.contentArea(m.maximizedPane == paneIdx)
 	m.selection.Start(msg.X, msg.Y, paneIdx, paneX, paneY)
 
 	return nil
@@ -1571,17 +2769,48 @@ func (m *Model) copySelectedRange() tea.Cmd {
 		return m.toast.Show("Copy failed", err.Error(), common.ToastError)
 	}
 	charCount := len([]rune(text))
-	debug.Log("Copied %d chars from selected range in %s", charCount, pane.Container.DisplayName())
+	logger.Debug("Copied %d chars from selected range in %s", charCount, pane.Container.DisplayName())
 	return m.toast.Show("Copied", fmt.Sprintf("%d chars", charCount), common.ToastSuccess)
 }
 
+// newestPane returns the index of the pane that most recently received a
+// log line, or -1 when none has
+func (m *Model) newestPane() int {
+	newest := -1
+	for i := range m.panes {
+		if m.panes[i].lastActivity.IsZero() {
+			continue
+		}
+		if newest < 0 || m.panes[i].lastActivity.After(m.panes[newest].lastActivity) {
+			newest = i
+		}
+	}
+	return newest
+}
+
 func (m *Model) setFocus(index int) {
 	if index >= 0 && index < len(m.panes) {
 		for i := range m.panes {
 			m.panes[i].Active = (i == index)
 		}
 		m.focusedPane = index
+		m.panes[index].unseenStderr = false
+	}
+}
+
+// flushCount drops the pending count, applying its last pane digit as the
+// pane or tab shortcut it also is
+func (m Model) flushCount() (Model, tea.Cmd) {
+	jump := m.countKey
+	m.count = 0
+	m.countKey = tea.KeyMsg{}
+	if len(jump.Runes) == 0 {
+		return m, nil
 	}
+	m.countFlushing = true
+	m, cmd := m.update(jump)
+	m.countFlushing = false
+	return m, cmd
 }
 
 func (m *Model) focusNextPane() {
@@ -1614,6 +2843,37 @@ func (m *Model) getPaneGridPosition(paneIdx int) (row, col int) {
 	return 0, 0
 }
 
+// swapFocusedPane swaps the focused pane with its grid neighbour in the given
+// direction; focus follows the moved pane. Does nothing at the grid edge or
+// while a pane is maximized.
+func (m *Model) swapFocusedPane(dRow, dCol int) bool {
+	if m.maximizedPane != -1 || len(m.panes) < 2 {
+		return false
+	}
+	row, col := m.getPaneGridPosition(m.focusedPane)
+	row, col = row+dRow, col+dCol
+	if row < 0 || row >= m.layout.Rows || col < 0 || col >= m.layout.Cols {
+		return false
+	}
+	other := m.layout.PaneMap[row][col]
+	if other < 0 || other >= len(m.panes) || other == m.focusedPane {
+		return false
+	}
+
+	// Selection and search positions are pane indices - drop them rather than
+	// have them point at the wrong pane
+	if m.selection.PaneIdx >= 0 && m.selection.PaneIdx < len(m.panes) {
+		m.panes[m.selection.PaneIdx].ClearSelection()
+	}
+	m.selection.Clear()
+	m.searchPaneIdx = 0
+
+	m.panes[m.focusedPane], m.panes[other] = m.panes[other], m.panes[m.focusedPane]
+	m.focusedPane = other
+	m.recalculateLayout()
+	return true
+}
+
 // resizeFocusedPaneWidth changes the width of the focused pane
 // delta > 0 grows the pane, delta < 0 shrinks it
 func (m *Model) resizeFocusedPaneWidth(delta float64) {
@@ -1779,7 +3039,7 @@ func (m *Model) recalculateLayout() {
 		m.panes[m.maximizedPane].SetSize(m.width, availableHeight)
 	} else {
 		// Tiled mode - calculate layout and set pane sizes using ratios
-		newLayout := CalculateLayout(len(m.panes))
+		newLayout := m.calculateLayout()
 
 		// Safety check for layout
 		if newLayout.Cols <= 0 || newLayout.Rows <= 0 {
@@ -1794,6 +3054,15 @@ func (m *Model) recalculateLayout() {
 		m.layout = newLayout
 		m.layout.EnsureRatios()
 
+		// Too small to tile: every pane gets the space under the pane list,
+		// so whichever is focused can be shown
+		if m.stacked() {
+			for i := range m.panes {
+				m.panes[i].SetSize(m.width, max(availableHeight-1, 3))
+			}
+			return
+		}
+
 		// Get widths and heights from ratios
 		colWidths := m.layout.GetColumnWidths(m.width)
 		rowHeights := m.layout.GetRowHeights(availableHeight)
@@ -1829,6 +3098,12 @@ func (m Model) View() (result string) {
 	var searchBar string
 	if m.searchModal.IsVisible() {
 		searchBar = m.searchModal.View(m.width, m.height)
+	} else if m.buildPrompt.IsVisible() {
+		searchBar = m.buildPrompt.View(m.width)
+	} else if m.signalPrompt.IsVisible() {
+		searchBar = m.signalPrompt.View(m.width)
+	} else if m.renamePrompt.IsVisible() {
+		searchBar = m.renamePrompt.View(m.width)
 	}
 
 	// Create tutorial hint bar if active
@@ -1889,6 +3164,22 @@ func (m Model) View() (result string) {
 		return m.overlayAtPosition(content, modalView, 0, 0)
 	}
 
+	// Overlay saved projects modal if visible
+	if m.savedProjectsModal.IsVisible() {
+		modalView := m.savedProjectsModal.View(m.width, m.height)
+		return m.overlayAtPosition(content, modalView, 0, 0)
+	}
+
+	// Overlay debug state in the top-right corner
+	if m.debugOverlayVisible() {
+		overlay := m.renderDebugOverlay()
+		x := m.width - lipgloss.Width(overlay) - 1
+		if x < 0 {
+			x = 0
+		}
+		content = m.overlayAtPosition(content, overlay, x, 1)
+	}
+
 	// Overlay toast notification if visible
 	if m.toast.IsVisible() {
 		content = m.overlayToast(content)
@@ -2165,6 +3456,29 @@ func (m Model) renderTiledViewWithSearch(searchBar string) string {
 		rows = append(rows, searchBar)
 	}
 
+	if m.stacked() && m.focusedPane >= 0 && m.focusedPane < len(m.panes) {
+		pane := m.panes[m.focusedPane]
+		rows = append(rows, m.renderPaneList(), pane.View(m.width, max(availableHeight-1, 3), true))
+	} else {
+		rows = append(rows, m.renderGrid(colWidths, rowHeights))
+	}
+
+	// Add tutorial bar if active
+	if hasTutorialBar {
+		tutorialBar := m.tutorial.View(m.width)
+		rows = append(rows, tutorialBar)
+	}
+
+	// Add help bar
+	helpBar := m.renderHelpBar()
+	rows = append(rows, helpBar)
+
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// renderGrid renders the panes tiled by the layout
+func (m Model) renderGrid(colWidths, rowHeights []int) string {
+	var rows []string
 	for rowIdx := 0; rowIdx < m.layout.Rows; rowIdx++ {
 		var cols []string
 		for colIdx := 0; colIdx < m.layout.Cols; colIdx++ {
@@ -2184,18 +3498,23 @@ func (m Model) renderTiledViewWithSearch(searchBar string) string {
 		}
 		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cols...))
 	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
 
-	// Add tutorial bar if active
-	if hasTutorialBar {
-		tutorialBar := m.tutorial.View(m.width)
-		rows = append(rows, tutorialBar)
+// renderPaneList renders the line above the focused pane when the panes are
+// too small to tile: every pane's number and name, the focused one highlighted
+func (m Model) renderPaneList() string {
+	parts := []string{common.PausedStyle.Render(" too small to tile ")}
+	for i := range m.panes {
+		label := fmt.Sprintf("%d %s", i+1, m.panes[i].displayName())
+		if i == m.focusedPane {
+			parts = append(parts, common.HelpKeyStyle.Render("["+label+"]"))
+		} else {
+			parts = append(parts, common.MutedInlineStyle.Render(" "+label+" "))
+		}
 	}
-
-	// Add help bar
-	helpBar := m.renderHelpBar()
-	rows = append(rows, helpBar)
-
-	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+	parts = append(parts, common.MutedInlineStyle.Render("  "+m.keys.NextPane.Help().Key+":next pane"))
+	return lipgloss.NewStyle().Width(m.width).MaxWidth(m.width).Render(strings.Join(parts, " "))
 }
 
 func (m Model) renderHelpBar() string {
@@ -2207,6 +3526,7 @@ func (m Model) renderHelpBar() string {
 		// Maximized pane view
 		help = " " + key("b") + desc(":build") +
 			desc("  ") + key("r") + desc(":restart") +
+			desc("  ") + key("s") + desc(":stop") +
 			desc("  ") + key("R") + desc(":down/up") +
 			desc("  ") + key("e") + desc(":shell") +
 			desc("  ") + key("i") + desc(":inspect") +
@@ -2221,6 +3541,7 @@ func (m Model) renderHelpBar() string {
 		// Tiled panes view
 		help = " " + key("b") + desc(":build") +
 			desc("  ") + key("r") + desc(":restart") +
+			desc("  ") + key("s") + desc(":stop") +
 			desc("  ") + key("R") + desc(":down/up") +
 			desc("  ") + key("e") + desc(":shell") +
 			desc("  ") + key("i") + desc(":inspect") +
@@ -2233,27 +3554,9 @@ func (m Model) renderHelpBar() string {
 			desc("  ") + key("q") + desc(":quit")
 	}
 
-	// Debug indicator on the right
-	var debugIndicator string
-	if debug.IsEnabled() {
-		debugIndicator = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("208")).
-			Bold(true).
-			Render("[DEBUG]")
-	}
-
-	// If debug is enabled, add it to the right side
-	if debugIndicator != "" {
-		helpWidth := lipgloss.Width(help)
-		debugWidth := lipgloss.Width(debugIndicator)
-		padding := m.width - helpWidth - debugWidth - 1
-		if padding > 0 {
-			help = help + strings.Repeat(" ", padding) + debugIndicator
-		} else {
-			help = help + " " + debugIndicator
-		}
-	}
-
+	// Resource totals and debug indicator on the right
+	help = m.withStatusIndicators(help)
+
 	return common.HelpBarStyle.Width(m.width).Render(help)
 }
 
@@ -2262,10 +3565,11 @@ func (m Model) renderHelpBarMaximized() string {
 	key := common.HelpKeyStyle.Render
 	desc := common.HelpDescStyle.Render
 
-	help := " " + key("1-5") + desc(":tabs") +
+	help := " " + key("1-6") + desc(":tabs") +
 		desc("  ") + key("[]") + desc(":cycle") +
 		desc("  ") + key("b") + desc(":build") +
 		desc("  ") + key("r") + desc(":restart") +
+		desc("  ") + key("s") + desc(":stop") +
 		desc("  ") + key("e") + desc(":shell") +
 		desc("  ") + key("i") + desc(":inspect") +
 		desc("  ") + key("↑↓") + desc(":scroll") +
@@ -2273,26 +3577,8 @@ func (m Model) renderHelpBarMaximized() string {
 		desc("  ") + key("esc") + desc(":min") +
 		desc("  ") + key("q") + desc(":quit")
 
-	// Debug indicator on the right
-	var debugIndicator string
-	if debug.IsEnabled() {
-		debugIndicator = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("208")).
-			Bold(true).
-			Render("[DEBUG]")
-	}
-
-	// If debug is enabled, add it to the right side
-	if debugIndicator != "" {
-		helpWidth := lipgloss.Width(help)
-		debugWidth := lipgloss.Width(debugIndicator)
-		padding := m.width - helpWidth - debugWidth - 1
-		if padding > 0 {
-			help = help + strings.Repeat(" ", padding) + debugIndicator
-		} else {
-			help = help + " " + debugIndicator
-		}
-	}
+	// Resource totals and debug indicator on the right
+	help = m.withStatusIndicators(help)
 
 	return common.HelpBarStyle.Width(m.width).Render(help)
 }
@@ -2322,7 +3608,7 @@ func (m *Model) searchNextAcrossPanes() (current, total int) {
 	if m.panes[m.searchPaneIdx].HasMatches() && !m.panes[m.searchPaneIdx].IsAtLastMatch() {
 		m.panes[m.searchPaneIdx].NextMatch()
 		pos := m.calculateGlobalMatchPosition()
-		debug.Log("Search next: pane %d (%s), match %d/%d", m.searchPaneIdx, m.panes[m.searchPaneIdx].Container.DisplayName(), pos, total)
+		logger.Debug("Search next: pane %d (%s), match %d/%d", m.searchPaneIdx, m.panes[m.searchPaneIdx].Container.DisplayName(), pos, total)
 		return pos, total
 	}
 
@@ -2334,7 +3620,7 @@ func (m *Model) searchNextAcrossPanes() (current, total int) {
 			m.searchPaneIdx = nextPane
 			m.panes[nextPane].JumpToFirstMatch()
 			pos := m.calculateGlobalMatchPosition()
-			debug.Log("Search next: jumped to pane %d (%s), match %d/%d", nextPane, m.panes[nextPane].Container.DisplayName(), pos, total)
+			logger.Debug("Search next: jumped to pane %d (%s), match %d/%d", nextPane, m.panes[nextPane].Container.DisplayName(), pos, total)
 			return pos, total
 		}
 	}
@@ -2367,7 +3653,7 @@ func (m *Model) searchPrevAcrossPanes() (current, total int) {
 	if m.panes[m.searchPaneIdx].HasMatches() && !m.panes[m.searchPaneIdx].IsAtFirstMatch() {
 		m.panes[m.searchPaneIdx].PrevMatch()
 		pos := m.calculateGlobalMatchPosition()
-		debug.Log("Search prev: pane %d (%s), match %d/%d", m.searchPaneIdx, m.panes[m.searchPaneIdx].Container.DisplayName(), pos, total)
+		logger.Debug("Search prev: pane %d (%s), match %d/%d", m.searchPaneIdx, m.panes[m.searchPaneIdx].Container.DisplayName(), pos, total)
 		return pos, total
 	}
 
@@ -2379,7 +3665,7 @@ func (m *Model) searchPrevAcrossPanes() (current, total int) {
 			m.searchPaneIdx = prevPane
 			m.panes[prevPane].JumpToLastMatch()
 			pos := m.calculateGlobalMatchPosition()
-			debug.Log("Search prev: jumped to pane %d (%s), match %d/%d", prevPane, m.panes[prevPane].Container.DisplayName(), pos, total)
+			logger.Debug("Search prev: jumped to pane %d (%s), match %d/%d", prevPane, m.panes[prevPane].Container.DisplayName(), pos, total)
 			return pos, total
 		}
 	}
@@ -2399,11 +3685,137 @@ func (m *Model) calculateGlobalMatchPosition() int {
 	return position
 }
 
-// Cleanup cancels any running goroutines
+// reloadConfig re-reads the config files and applies them without a restart:
+// key bindings, toast and notification settings, timestamp display and the
+// reconnect schedule. It returns an error for each file that couldn't be parsed.
+func (m *Model) reloadConfig() []error {
+	errs := common.ReloadConfig()
+	m.keys = common.DefaultKeyMap()
+	m.toast.ReloadConfig()
+
+	if cfg, err := config.Load(); err == nil {
+		display := cfg.GetDisplaySettings()
+		m.utcTimestamps = display.UTCTimestamps
+		m.msTimestamps = display.MillisecondTimestamps
+		m.copyNoTimestamps = display.CopyWithoutTimestamps
+		m.layoutBias = ParseLayoutBias(display.Layout)
+		m.recalculateLayout()
+		for i := range m.panes {
+			m.panes[i].SetUTCTimestamps(m.utcTimestamps)
+			m.panes[i].SetMillisecondTimestamps(m.msTimestamps)
+			m.panes[i].copyNoTimestamps = m.copyNoTimestamps
+		}
+		m.reconnect = cfg.GetReconnectSettings()
+		m.alerts = loadAlerts(cfg)
+	}
+	logger.Info("Config reloaded (%d file errors)", len(errs))
+	return errs
+}
+
+// loadAlerts builds the alert engine from the configured rules, logging the
+// rules that were skipped
+func loadAlerts(cfg *config.Config) *alert.Engine {
+	engine, errs := alert.New(cfg.GetAlertRules())
+	for _, err := range errs {
+		logger.Warn("Skipping %v", err)
+	}
+	return engine
+}
+
+// notifyAlerts sends a notification for each alert
+func notifyAlerts(alerts []alert.Alert) tea.Cmd {
+	if len(alerts) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		for _, a := range alerts {
+			logger.Info("Alert: %s: %s", a.Title, a.Message)
+			notify.Toast(a.Title, a.Message)
+		}
+		return nil
+	}
+}
+
+// Cleanup cancels any running goroutines, moves lines held back by paused
+// panes into their buffers and waits for exports to close their files. It is
+// safe to call more than once.
 func (m *Model) Cleanup() {
 	if m.cancel != nil {
 		m.cancel()
 	}
+
+	for i := range m.panes {
+		if n := m.panes[i].flushPaused(); n > 0 {
+			logger.Debug("Flushed %d paused lines for %s", n, m.panes[i].Container.DisplayName())
+		}
+	}
+
+	// Cancelled exports stop at the next line; don't let a stuck one block quitting
+	if m.exports != nil {
+		done := make(chan struct{})
+		go func() {
+			m.exports.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(exportCloseTimeout):
+			logger.Warn("Timed out waiting for exports to finish")
+		}
+	}
+}
+
+// GetTutorial returns the current tutorial state
+func (m Model) GetTutorial() common.Tutorial {
+	return m.tutorial
+}
+
+// paneAction starts, stops or restarts the container in pane i, noting it
+// in the pane's logs
+func (m *Model) paneAction(i int, action string) tea.Cmd {
+	if i < 0 || i >= len(m.panes) {
+		return nil
+	}
+	pane := &m.panes[i]
+	running := pane.Container.State == "running" || pane.Container.State == "paused"
+	var note string
+	var cmd tea.Cmd
+	switch action {
+	case common.InspectStart:
+		if running {
+			return m.toast.Show("Cannot start", "Container already running", common.ToastError)
+		}
+		note, cmd = "--- Starting container... ---", m.startContainer(pane.Container)
+	case common.InspectStop:
+		if !running {
+			return m.toast.Show("Cannot stop", "Container not running", common.ToastError)
+		}
+		note, cmd = "--- Stopping container... ---", m.stopContainer(pane.Container)
+	case common.InspectRestart:
+		note, cmd = "--- Restarting container... ---", m.restartContainer(pane.Container)
+	default:
+		return nil
+	}
+	logger.Info("%s requested for container: %s", action, pane.Container.DisplayName())
+	pane.AddLogLine(docker.LogLine{
+		ContainerID: pane.ID,
+		Timestamp:   time.Now(),
+		Stream:      "system",
+		Content:     note,
+	})
+	return cmd
+}
+
+// startContainer starts a stopped container
+func (m Model) startContainer(cont docker.Container) tea.Cmd {
+	return func() tea.Msg {
+		err := m.dockerClient.StartContainer(m.ctx, cont.ID)
+		return ContainerActionMsg{
+			ContainerID: cont.ID,
+			Action:      "Start",
+			Err:         err,
+		}
+	}
 }
 
 // restartContainer restarts a container
@@ -2418,6 +3830,18 @@ func (m Model) restartContainer(cont docker.Container) tea.Cmd {
 	}
 }
 
+// stopContainer stops a container gracefully
+func (m Model) stopContainer(cont docker.Container) tea.Cmd {
+	return func() tea.Msg {
+		err := m.dockerClient.StopContainer(m.ctx, cont.ID)
+		return ContainerActionMsg{
+			ContainerID: cont.ID,
+			Action:      "Stop",
+			Err:         err,
+		}
+	}
+}
+
 // killContainer forcefully kills a container
 func (m Model) killContainer(cont docker.Container) tea.Cmd {
 	return func() tea.Msg {
@@ -2430,6 +3854,35 @@ func (m Model) killContainer(cont docker.Container) tea.Cmd {
 	}
 }
 
+// signalContainer sends a signal to a container
+func (m Model) signalContainer(cont docker.Container, signal string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.dockerClient.SignalContainer(m.ctx, cont.ID, signal)
+		return ContainerSignalMsg{ContainerID: cont.ID, Signal: signal, Err: err}
+	}
+}
+
+// renameContainer renames a container
+func (m Model) renameContainer(containerID, oldName, newName string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.dockerClient.RenameContainer(m.ctx, containerID, newName)
+		return ContainerRenamedMsg{ContainerID: containerID, OldName: oldName, NewName: newName, Err: err}
+	}
+}
+
+// pauseContainer pauses, or with pause false unpauses, a container
+func (m Model) pauseContainer(cont docker.Container, pause bool) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if pause {
+			err = m.dockerClient.PauseContainer(m.ctx, cont.ID)
+		} else {
+			err = m.dockerClient.UnpauseContainer(m.ctx, cont.ID)
+		}
+		return ContainerPausedMsg{ContainerID: cont.ID, Paused: pause, Err: err}
+	}
+}
+
 // removeContainer removes a container
 func (m Model) removeContainer(cont docker.Container) tea.Cmd {
 	return func() tea.Msg {
@@ -2452,6 +3905,14 @@ func (m Model) inspectContainer(cont docker.Container) tea.Cmd {
 	}
 }
 
+// inspectContainerJSON fetches a container's full `docker inspect` JSON
+func (m Model) inspectContainerJSON(containerID string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := m.dockerClient.InspectContainerJSON(m.ctx, containerID)
+		return common.InspectJSONMsg{ContainerID: containerID, JSON: data, Err: err}
+	}
+}
+
 // composeDownUp runs compose down/up for a container
 func (m Model) composeDownUp(cont docker.Container) tea.Cmd {
 	return func() tea.Msg {
@@ -2490,7 +3951,8 @@ func (m Model) restartLogStream(cont docker.Container) tea.Cmd {
 
 		// Find the container by compose service name or original name
 		for _, c := range containers {
-			if c.ComposeProject == cont.ComposeProject && c.ComposeService == cont.ComposeService {
+			if cont.ComposeService != "" && c.ComposeProject == cont.ComposeProject && c.ComposeService == cont.ComposeService &&
+				(cont.ComposeReplica == 0 || c.ComposeReplica == cont.ComposeReplica) {
 				return restartStreamMsg{
 					OldContainerID: cont.ID,
 					NewContainer:   c,
@@ -2510,67 +3972,193 @@ func (m Model) restartLogStream(cont docker.Container) tea.Cmd {
 type restartStreamMsg struct {
 	OldContainerID string
 	NewContainer   docker.Container
+	reconnect      bool // from a reconnect attempt, stale once the pane is connected again
+}
+
+// eventsRetryInterval is how long to wait before watching container events
+// again after the event stream first failed
+const eventsRetryInterval = 5 * time.Second
+
+// eventsMaxRetryDelay caps the backoff between failed event streams
+const eventsMaxRetryDelay = time.Minute
+
+// eventsRetryDelay returns how long to wait before watching events again once
+// the stream has failed `failures` times without delivering an event. The
+// first retry comes after eventsRetryInterval, each later one waits twice as
+// long, and none waits more than eventsMaxRetryDelay.
+func eventsRetryDelay(failures int) time.Duration {
+	delay := eventsRetryInterval
+	for i := 1; i < failures && delay < eventsMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, eventsMaxRetryDelay)
+}
+
+// watchEventsMsg (re)starts watching container lifecycle events
+type watchEventsMsg struct{}
+
+// containerEventMsg carries a container lifecycle event. Events of a pane's
+// container go to its Events tab; start events also let disconnected panes
+// follow their replacement at once.
+type containerEventMsg struct {
+	Event  docker.ContainerEvent
+	source <-chan docker.ContainerEvent
+	errs   <-chan error
+}
+
+// eventsClosedMsg is sent when the container event stream ends
+type eventsClosedMsg struct {
+	err error
+}
+
+// waitForEvent waits for the next container event
+func waitForEvent(source <-chan docker.ContainerEvent, errs <-chan error) tea.Cmd {
+	return func() tea.Msg {
+		ev, ok := <-source
+		if !ok {
+			return eventsClosedMsg{err: <-errs}
+		}
+		return containerEventMsg{Event: ev, source: source, errs: errs}
+	}
 }
 
 // tryReconnect attempts to reconnect to a container after the stream ends
 // This handles external restarts (docker compose down/up from another terminal)
 func (m Model) tryReconnect(cont docker.Container) tea.Cmd {
 	return func() tea.Msg {
-		// Retry a few times with increasing delays
-		delays := []time.Duration{1 * time.Second, 2 * time.Second, 3 * time.Second, 5 * time.Second}
+		// Retry with exponential backoff, bounded by the total timeout
+		delays := m.reconnect.Delays()
+		deadline := time.Now().Add(m.reconnect.GetTotalTimeout())
 
 		for attempt, delay := range delays {
-			time.Sleep(delay)
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				logger.Warn("Reconnect timeout reached for %s after %d attempts", cont.DisplayName(), attempt)
+				break
+			}
+			if delay > remaining {
+				delay = remaining
+			}
 
-			// Check if context was cancelled
+			// Wait, bailing out early if the context is cancelled
 			select {
 			case <-m.ctx.Done():
 				return nil
-			default:
+			case <-time.After(delay):
 			}
 
 			containers, err := m.dockerClient.ListContainers(m.ctx)
 			if err != nil {
-				debug.Log("Reconnect attempt %d failed to list containers: %v", attempt+1, err)
+				logger.Warn("Reconnect attempt %d failed to list containers: %v", attempt+1, err)
 				continue
 			}
 
-			// First try: match by compose project + service (most reliable for compose)
-			for _, c := range containers {
-				if cont.ComposeProject != "" && cont.ComposeService != "" &&
-					c.ComposeProject == cont.ComposeProject &&
-					c.ComposeService == cont.ComposeService &&
-					c.State == "running" {
-					debug.Log("Reconnecting to container %s (was %s, now %s)", c.DisplayName(), cont.ID[:12], c.ID[:12])
-					return restartStreamMsg{
-						OldContainerID: cont.ID,
-						NewContainer:   c,
-					}
+			if c, ok := findReconnectTarget(containers, cont); ok {
+				logger.Info("Reconnecting to container %s (was %s, now %s)", c.DisplayName(), cont.ID[:12], c.ID[:12])
+				return restartStreamMsg{
+					OldContainerID: cont.ID,
+					NewContainer:   c,
+					reconnect:      true,
 				}
 			}
 
-			// Second try: match by container name
-			for _, c := range containers {
-				if c.Name == cont.Name && c.State == "running" {
-					debug.Log("Reconnecting to container %s by name (was %s, now %s)", c.Name, cont.ID[:12], c.ID[:12])
-					return restartStreamMsg{
-						OldContainerID: cont.ID,
-						NewContainer:   c,
-					}
-				}
+			logger.Debug("Reconnect attempt %d: container %s not found or not running", attempt+1, cont.DisplayName())
+		}
+
+		logger.Error("Giving up reconnection attempts for %s", cont.DisplayName())
+		return reconnectFailedMsg{ContainerID: cont.ID}
+	}
+}
+
+// reconnectNow makes a single immediate reconnection attempt (user-triggered)
+func (m Model) reconnectNow(cont docker.Container) tea.Cmd {
+	return func() tea.Msg {
+		containers, err := m.dockerClient.ListContainers(m.ctx)
+		if err != nil {
+			logger.Warn("Manual reconnect failed to list containers: %v", err)
+			return reconnectFailedMsg{ContainerID: cont.ID, Manual: true}
+		}
+		if c, ok := findReconnectTarget(containers, cont); ok {
+			logger.Info("Manually reconnecting to container %s (now %s)", c.DisplayName(), c.ID[:12])
+			return restartStreamMsg{
+				OldContainerID: cont.ID,
+				NewContainer:   c,
+				reconnect:      true,
 			}
+		}
+		return reconnectFailedMsg{ContainerID: cont.ID, Manual: true}
+	}
+}
+
+// reconnectAllMsg carries the outcome of reconnecting several panes at once
+type reconnectAllMsg struct {
+	restarts []restartStreamMsg
+	failed   []string // IDs of panes whose container isn't running
+	err      error    // listing containers failed
+}
 
-			debug.Log("Reconnect attempt %d: container %s not found or not running", attempt+1, cont.DisplayName())
+// reconnectAll reconnects every given container immediately, listing the
+// running containers once for all of them
+func (m Model) reconnectAll(conts []docker.Container) tea.Cmd {
+	return func() tea.Msg {
+		var msg reconnectAllMsg
+		containers, err := m.dockerClient.ListContainers(m.ctx)
+		if err != nil {
+			logger.Warn("Reconnect all failed to list containers: %v", err)
+			msg.err = err
+		}
+		for _, cont := range conts {
+			if c, ok := findReconnectTarget(containers, cont); ok && err == nil {
+				msg.restarts = append(msg.restarts, restartStreamMsg{OldContainerID: cont.ID, NewContainer: c, reconnect: true})
+			} else {
+				msg.failed = append(msg.failed, cont.ID)
+			}
 		}
+		return msg
+	}
+}
 
-		debug.Log("Giving up reconnection attempts for %s", cont.DisplayName())
-		return reconnectFailedMsg{ContainerID: cont.ID}
+// findReconnectTarget finds the running container that replaces cont,
+// matching by compose project + service first, then by container name
+func findReconnectTarget(containers []docker.Container, cont docker.Container) (docker.Container, bool) {
+	// A compose logs pane reconnects once any service of its project is back
+	if cont.IsComposeLogs() {
+		for _, c := range containers {
+			if c.ComposeProject == cont.ComposeProject && c.State == "running" &&
+				(cont.ComposeWorkingDir == "" || c.ComposeWorkingDir == cont.ComposeWorkingDir) {
+				return cont, true
+			}
+		}
+		return docker.Container{}, false
+	}
+
+	// First try: match by compose project + service (most reliable for compose),
+	// and replica so panes of a scaled service don't all follow the same one
+	if cont.ComposeProject != "" && cont.ComposeService != "" {
+		for _, c := range containers {
+			if c.ComposeProject == cont.ComposeProject &&
+				c.ComposeService == cont.ComposeService &&
+				(cont.ComposeReplica == 0 || c.ComposeReplica == cont.ComposeReplica) &&
+				c.State == "running" {
+				return c, true
+			}
+		}
 	}
+
+	// Second try: match by container name
+	for _, c := range containers {
+		if c.Name == cont.Name && c.State == "running" {
+			return c, true
+		}
+	}
+
+	return docker.Container{}, false
 }
 
 // reconnectFailedMsg is sent when all reconnection attempts have failed
 type reconnectFailedMsg struct {
 	ContainerID string
+	Manual      bool // true when triggered by the reconnect key
 }
 
 // shellExitMsg is sent when an exec shell session ends
@@ -2579,6 +4167,21 @@ type shellExitMsg struct {
 	Err         error
 }
 
+// pagerExitMsg is sent when the pager opened on a pane's logs exits
+type pagerExitMsg struct {
+	Err error
+}
+
+// execCommand returns a docker exec command that opens a shell in a container:
+// bash where the image has it, else sh, which nearly every image has
+func execCommand(container docker.Container) string {
+	shortID := container.ID
+	if len(container.ID) > 12 {
+		shortID = container.ID[:12]
+	}
+	return "docker exec -it " + shortID + ` sh -c 'command -v bash >/dev/null && exec bash || exec sh'`
+}
+
 // execShell opens an interactive shell in the container
 func (m Model) execShell(container docker.Container) tea.Cmd {
 	shortID := container.ID
@@ -2633,6 +4236,38 @@ fi
 	})
 }
 
+// openPager writes logs to a temp file and opens it in $PAGER, or less
+// starting at the end, handing the terminal over until the pager exits. The
+// file is removed afterwards.
+func openPager(logs, name string) (tea.Cmd, error) {
+	if logs == "" {
+		return nil, fmt.Errorf("no logs in %s", name)
+	}
+	f, err := os.CreateTemp("", "cm-*.log")
+	if err != nil {
+		return nil, err
+	}
+	_, err = f.WriteString(logs)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	// Split the pager command into parts (handles "less -R" etc.)
+	parts := strings.Fields(os.Getenv("PAGER"))
+	if len(parts) == 0 {
+		parts = []string{"less", "+G"}
+	}
+	c := exec.Command(parts[0], append(parts[1:], f.Name())...)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		os.Remove(f.Name())
+		return pagerExitMsg{Err: err}
+	}), nil
+}
+
 // startBuildStream starts a streaming build/up/down operation
 func (m Model) startBuildStream(cont docker.Container, op string) tea.Cmd {
 	return func() tea.Msg {
@@ -2655,6 +4290,74 @@ func (m Model) startBuildStream(cont docker.Container, op string) tea.Cmd {
 	}
 }
 
+// startImageBuildStream rebuilds a standalone container's image and recreates the container from it
+func (m Model) startImageBuildStream(cont docker.Container, bc config.BuildContext) tea.Cmd {
+	return func() tea.Msg {
+		return BuildStreamStartedMsg{
+			ContainerID: cont.ID,
+			Stream:      m.dockerClient.ImageBuildRestartStream(m.ctx, cont, bc),
+			Operation:   "build",
+		}
+	}
+}
+
+// startExport writes a container's full log history to
+// <config dir>/exports/<name>-<timestamp>.log in the background
+func (m Model) startExport(cont docker.Container) tea.Cmd {
+	name := cont.DisplayName()
+	exportLogs := m.exportLogs
+	exports := m.exports
+	ctx := m.ctx
+	return func() tea.Msg {
+		dir := config.GetExportsDir()
+		if dir == "" {
+			return exportDoneMsg{name: name, err: fmt.Errorf("cannot determine home directory")}
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return exportDoneMsg{name: name, err: err}
+		}
+		path := filepath.Join(dir, fmt.Sprintf("%s-%s.log", common.SanitizeFileName(name), time.Now().Format("20060102-150405")))
+		f, err := os.Create(path)
+		if err != nil {
+			return exportDoneMsg{name: name, err: err}
+		}
+
+		export := &logExport{
+			name:     name,
+			progress: make(chan int, 1),
+			done:     make(chan exportDoneMsg, 1),
+		}
+		exports.Add(1)
+		go func() {
+			defer exports.Done()
+			lines, err := exportLogs(ctx, cont.ID, f, func(n int) {
+				// Only the latest count matters; drop it if the UI hasn't caught up
+				select {
+				case export.progress <- n:
+				default:
+				}
+			})
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			export.done <- exportDoneMsg{name: name, path: path, lines: lines, err: err}
+		}()
+		return exportProgressMsg{export: export}
+	}
+}
+
+// waitForExport waits for the next progress report or the end of an export
+func (m Model) waitForExport(export *logExport) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case n := <-export.progress:
+			return exportProgressMsg{export: export, lines: n}
+		case done := <-export.done:
+			return done
+		}
+	}
+}
+
 // waitForBuildStream waits for output from a build stream
 func (m Model) waitForBuildStream(containerID string, stream docker.StreamingResult) tea.Cmd {
 	return func() tea.Msg {
@@ -2698,6 +4401,120 @@ func (m Model) waitForBuildStream(containerID string, stream docker.StreamingRes
 	}
 }
 
+// scheduleTotalsTick schedules the next stats sample for the resource totals
+func scheduleTotalsTick() tea.Cmd {
+	return tea.Tick(totalsInterval, func(time.Time) tea.Msg {
+		return totalsTickMsg{}
+	})
+}
+
+// sampleTotals samples every running pane's container in one batch, or just
+// schedules the next tick when none is running
+func (m Model) sampleTotals() tea.Cmd {
+	var ids []string
+	for _, p := range m.panes {
+		if p.Container.State == "running" && !p.exited && !p.Container.IsComposeLogs() {
+			ids = append(ids, p.ID)
+		}
+	}
+	if len(ids) == 0 || m.dockerClient == nil {
+		return scheduleTotalsTick()
+	}
+	client := m.dockerClient
+	ctx := m.ctx
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		stats, err := client.StatsSnapshots(ctx, ids)
+		return totalsSampledMsg{stats: stats, err: err}
+	}
+}
+
+// resourceTotals sums the latest CPU and memory sample of every pane whose
+// container is running, ignoring samples too old to reflect it any more
+func (m Model) resourceTotals() (cpu float64, mem uint64, ok bool) {
+	for _, p := range m.panes {
+		if p.Container.State != "running" || p.exited || p.statsHistory == nil {
+			continue
+		}
+		latest := p.statsHistory.Latest()
+		if latest == nil || time.Since(latest.Timestamp) > 3*totalsInterval {
+			continue
+		}
+		cpu += latest.CPUPercent
+		mem += latest.MemoryUsage
+		ok = true
+	}
+	return cpu, mem, ok
+}
+
+// withStatusIndicators puts the resource totals and the debug indicator at
+// the right end of a help bar
+func (m Model) withStatusIndicators(help string) string {
+	var right []string
+	if cpu, mem, ok := m.resourceTotals(); ok {
+		right = append(right, common.MutedInlineStyle.Render(fmt.Sprintf("Σ cpu %s mem %s", FormatPercent(cpu), common.FormatBytes(mem))))
+	}
+	if m.syncScroll {
+		right = append(right, common.HelpKeyStyle.Render("[sync scroll]"))
+	}
+	if m.syncTime {
+		right = append(right, common.HelpKeyStyle.Render("[sync time]"))
+	}
+	if debug.IsEnabled() {
+		right = append(right, lipgloss.NewStyle().
+			Foreground(lipgloss.Color("208")).
+			Bold(true).
+			Render("[DEBUG]"))
+	}
+	if len(right) == 0 {
+		return help
+	}
+
+	indicators := strings.Join(right, " ")
+	padding := m.width - lipgloss.Width(help) - lipgloss.Width(indicators) - 1
+	if padding > 0 {
+		return help + strings.Repeat(" ", padding) + indicators
+	}
+	return help + " " + indicators
+}
+
+// scrollLogs scrolls a pane's logs by delta lines. With sync scroll on, every
+// other pane showing its logs scrolls by the same delta; with sync time on,
+// they scroll to the time now at the top of the pane.
+func (m *Model) scrollLogs(paneIdx, delta int) {
+	if paneIdx < 0 || paneIdx >= len(m.panes) {
+		return
+	}
+	m.panes[paneIdx].Viewport.SetYOffset(m.panes[paneIdx].Viewport.YOffset + delta)
+	if !m.syncScroll && !m.syncTime {
+		return
+	}
+	at, ok := m.panes[paneIdx].TopLineTime()
+	for i := range m.panes {
+		if i == paneIdx || m.panes[i].GetActiveTab() != TabLogs {
+			continue
+		}
+		switch {
+		case m.syncScroll:
+			m.panes[i].Viewport.SetYOffset(m.panes[i].Viewport.YOffset + delta)
+		case m.syncTime && ok:
+			m.panes[i].JumpToTime(at)
+		}
+	}
+}
+
+// scheduleRateTick schedules the next rate refresh unless one is already pending
+func (m *Model) scheduleRateTick() tea.Cmd {
+	if m.rateTicking {
+		return nil
+	}
+	m.rateTicking = true
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return rateTickMsg{}
+	})
+}
+
 // scheduleReturnToLogs schedules a return to log view after build completion
 func (m Model) scheduleReturnToLogs(containerID string) tea.Cmd {
 	return tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
@@ -2714,6 +4531,9 @@ func (m *Model) switchToTab(pane *Pane, newTab TabType) tea.Cmd {
 		return nil
 	}
 	pane.SetActiveTab(newTab)
+	if newTab = pane.GetActiveTab(); newTab == oldTab {
+		return nil // compose logs panes only have the Logs tab
+	}
 	return m.handleTabSwitch(pane, oldTab, newTab)
 }
 
@@ -2764,11 +4584,23 @@ func (m *Model) startStatsStreaming(cont docker.Container) tea.Cmd {
 	statsChan, errChan := m.dockerClient.StreamStats(m.statsCtx, cont.ID)
 	m.statsChan = statsChan
 	return tea.Batch(
+		m.fetchStatsSnapshot(cont.ID),
 		m.waitForStats(cont.ID, statsChan),
 		m.waitForStatsError(cont.ID, errChan),
 	)
 }
 
+// fetchStatsSnapshot takes a single stats sample. A stream's first sample
+// has no CPU reading to compare against, so this gives the tab a real figure
+// about a second sooner.
+func (m Model) fetchStatsSnapshot(containerID string) tea.Cmd {
+	ctx := m.statsCtx
+	return func() tea.Msg {
+		stats, err := m.dockerClient.GetStatsSnapshot(ctx, containerID)
+		return statsSnapshotMsg{ContainerID: containerID, Stats: stats, Err: err}
+	}
+}
+
 // stopStatsStreaming stops the current stats streaming
 func (m *Model) stopStatsStreaming() {
 	if m.statsCancel != nil {
@@ -2849,6 +4681,55 @@ func (m Model) fetchContainerDetails(cont docker.Container) tea.Cmd {
 	}
 }
 
+// containerExitedMsg reports how a container whose stream ended exited
+type containerExitedMsg struct {
+	ContainerID string
+	ExitCode    int
+	OOMKilled   bool
+}
+
+// inspectExit inspects a container whose stream ended for its exit code and
+// OOM kill, in case the events saying so were missed (e.g. the event stream
+// was down)
+func (m Model) inspectExit(cont docker.Container) tea.Cmd {
+	if m.dockerClient == nil || cont.IsComposeLogs() {
+		return nil
+	}
+	return func() tea.Msg {
+		details, err := m.dockerClient.InspectContainer(m.ctx, cont.ID)
+		if err != nil || (details.State != "exited" && details.State != "dead") {
+			return nil
+		}
+		return containerExitedMsg{ContainerID: cont.ID, ExitCode: details.ExitCode, OOMKilled: details.OOMKilled}
+	}
+}
+
+// markOOMKilled flags a pane's container as OOM-killed: a warning in its
+// title and logs, a toast and a notification. It does nothing if the pane is
+// already flagged.
+func (m *Model) markOOMKilled(i int) tea.Cmd {
+	pane := &m.panes[i]
+	if pane.oomKilled {
+		return nil
+	}
+	pane.oomKilled = true
+	name := pane.Container.DisplayName()
+	logger.Warn("Container %s was OOM-killed", name)
+	pane.AddLogLine(docker.LogLine{
+		ContainerID: pane.ID,
+		Timestamp:   time.Now(),
+		Stream:      "system",
+		Content:     "--- Container was OOM-killed: it ran out of memory ---",
+	})
+	return tea.Batch(
+		m.toast.Show("OOM-killed", name+" ran out of memory", common.ToastError),
+		func() tea.Msg {
+			notify.Error(name + " was OOM-killed")
+			return nil
+		},
+	)
+}
+
 // containerDetailsLoadedMsg is sent when container details are loaded for tabs
 type containerDetailsLoadedMsg struct {
 	ContainerID string
@@ -0,0 +1,1351 @@
+package logview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cm/internal/config"
+	"cm/internal/debug"
+	"cm/internal/docker"
+	"cm/internal/ui/common"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeStreamer hands out log streams that emit a fixed number of lines and
+// then stay open until their context is cancelled
+type fakeStreamer struct {
+	mu     sync.Mutex
+	opened map[string]int
+	lines  int
+}
+
+func newFakeStreamer(lines int) *fakeStreamer {
+	return &fakeStreamer{opened: make(map[string]int), lines: lines}
+}
+
+func (f *fakeStreamer) StreamLogs(ctx context.Context, containerID string) (<-chan docker.LogLine, <-chan error) {
+	f.mu.Lock()
+	f.opened[containerID]++
+	gen := f.opened[containerID]
+	f.mu.Unlock()
+
+	logChan := make(chan docker.LogLine)
+	errChan := make(chan error, 1)
+	go func() {
+		defer close(logChan)
+		defer close(errChan)
+		for i := 0; i < f.lines; i++ {
+			select {
+			case <-ctx.Done():
+				return
+			case logChan <- docker.LogLine{
+				ContainerID: containerID,
+				Timestamp:   time.Now(),
+				Stream:      "stdout",
+				Content:     fmt.Sprintf("stream %d line %d", gen, i),
+			}:
+			}
+		}
+		<-ctx.Done()
+	}()
+	return logChan, errChan
+}
+
+func (f *fakeStreamer) count(containerID string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.opened[containerID]
+}
+
+// newTestModel builds a Model whose log streams come from the fake streamer
+func newTestModel(t *testing.T, streamer *fakeStreamer, ids ...string) Model {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	containers := make([]docker.Container, len(ids))
+	for i, id := range ids {
+		containers[i] = docker.Container{ID: id, Name: "svc-" + id[:4], State: "running"}
+	}
+	m := New(containers, nil, 120, 40, common.Tutorial{})
+	m.streamLogs = streamer.StreamLogs
+	t.Cleanup(m.Cleanup)
+	return m
+}
+
+// runner mimics the Bubble Tea loop: commands run in goroutines, messages
+// are delivered back to Update one at a time
+type runner struct {
+	msgs chan tea.Msg
+	done chan struct{}
+}
+
+func newRunner(t *testing.T) *runner {
+	r := &runner{msgs: make(chan tea.Msg, 256), done: make(chan struct{})}
+	t.Cleanup(func() { close(r.done) })
+	return r
+}
+
+func (r *runner) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	go func() {
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, c := range batch {
+				r.run(c)
+			}
+			return
+		}
+		if msg == nil {
+			return
+		}
+		select {
+		case r.msgs <- msg:
+		case <-r.done:
+		}
+	}()
+}
+
+// pump feeds messages through Update until cond holds or the timeout expires
+func (r *runner) pump(t *testing.T, m Model, timeout time.Duration, cond func(Model) bool) Model {
+	t.Helper()
+	deadline := time.After(timeout)
+	for !cond(m) {
+		select {
+		case msg := <-r.msgs:
+			var cmd tea.Cmd
+			m, cmd = m.Update(msg)
+			r.run(cmd)
+		case <-deadline:
+			t.Fatalf("timed out waiting for condition")
+		}
+	}
+	return m
+}
+
+func countLines(p Pane, prefix string) int {
+	n := 0
+	for _, l := range p.LogLines.Lines() {
+		if len(l.Content) >= len(prefix) && l.Content[:len(prefix)] == prefix {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRapidStreamRestartsKeepSingleReader(t *testing.T) {
+	const restarts = 20
+	const linesPerStream = 5
+
+	streamer := newFakeStreamer(linesPerStream)
+	m := newTestModel(t, streamer, "aaaaaaaaaaaaaaaa", "bbbbbbbbbbbbbbbb")
+	r := newRunner(t)
+	r.run(m.Init())
+
+	// Hammer restarts on both panes while their streams are still delivering
+	for i := 0; i < restarts; i++ {
+		for _, p := range m.panes {
+			var cmd tea.Cmd
+			m, cmd = m.Update(restartStreamMsg{OldContainerID: p.ID, NewContainer: p.Container})
+			r.run(cmd)
+		}
+		// Interleave with whatever the stale readers deliver
+		select {
+		case msg := <-r.msgs:
+			var cmd tea.Cmd
+			m, cmd = m.Update(msg)
+			r.run(cmd)
+		default:
+		}
+	}
+
+	final := fmt.Sprintf("stream %d line", restarts+1)
+	m = r.pump(t, m, 5*time.Second, func(m Model) bool {
+		for _, p := range m.panes {
+			if countLines(p, final) < linesPerStream {
+				return false
+			}
+		}
+		return true
+	})
+
+	for _, p := range m.panes {
+		if got := streamer.count(p.ID); got != restarts+1 {
+			t.Fatalf("%s: expected %d streams opened, got %d", p.ID, restarts+1, got)
+		}
+		if len(m.streams) != len(m.panes) {
+			t.Fatalf("expected one tracked stream per pane, got %d", len(m.streams))
+		}
+		if !p.Connected {
+			t.Fatalf("%s: expected pane to stay connected after restarts", p.ID)
+		}
+		// Only lines from the latest stream should be visible (restart clears the pane)
+		for _, l := range p.LogLines.Lines() {
+			if l.Stream == "system" {
+				continue
+			}
+			if len(l.Content) < len(final) || l.Content[:len(final)] != final {
+				t.Fatalf("%s: stale line leaked through after restart: %q", p.ID, l.Content)
+			}
+		}
+		if got := countLines(p, final); got != linesPerStream {
+			t.Fatalf("%s: expected %d lines from latest stream, got %d", p.ID, linesPerStream, got)
+		}
+	}
+}
+
+func TestStaleStreamCloseDoesNotDisconnectPane(t *testing.T) {
+	streamer := newFakeStreamer(0)
+	m := newTestModel(t, streamer, "cccccccccccccccc")
+	r := newRunner(t)
+	r.run(m.Init())
+
+	id := m.panes[0].ID
+	m = r.pump(t, m, 5*time.Second, func(m Model) bool {
+		_, ok := m.streams[id]
+		return ok
+	})
+	staleSource := m.streams[id].logChan
+
+	var cmd tea.Cmd
+	m, cmd = m.Update(restartStreamMsg{OldContainerID: id, NewContainer: m.panes[0].Container})
+	r.run(cmd)
+
+	// The replaced stream closing must not tear down its successor
+	m, _ = m.Update(StreamClosedMsg{ContainerID: id, source: staleSource})
+	if !m.panes[0].Connected {
+		t.Fatalf("expected pane to remain connected after stale stream close")
+	}
+	if _, ok := m.streams[id]; !ok {
+		t.Fatalf("expected current stream to remain registered")
+	}
+}
+
+func TestInitStreamsDeliverFirstLineToEachPane(t *testing.T) {
+	streamer := newFakeStreamer(1)
+	m := newTestModel(t, streamer, "dddddddddddddddd", "eeeeeeeeeeeeeeee", "ffffffffffffffff")
+	r := newRunner(t)
+
+	// Init must not register streams on its own copy of the model
+	r.run(m.Init())
+	if len(m.streams) != 0 {
+		t.Fatalf("expected Init to leave stream setup to Update, got %d streams", len(m.streams))
+	}
+
+	m = r.pump(t, m, 5*time.Second, func(m Model) bool {
+		for _, p := range m.panes {
+			if countLines(p, "stream 1 line 0") != 1 {
+				return false
+			}
+		}
+		return true
+	})
+
+	for _, p := range m.panes {
+		if got := streamer.count(p.ID); got != 1 {
+			t.Fatalf("%s: expected exactly one stream, got %d", p.ID, got)
+		}
+		if _, ok := m.streams[p.ID]; !ok {
+			t.Fatalf("%s: expected stream to be tracked by the updated model", p.ID)
+		}
+	}
+}
+
+func TestLogLinesKeepFlowingWhileModalOpen(t *testing.T) {
+	streamer := newFakeStreamer(3)
+	m := newTestModel(t, streamer, "1111111111111111")
+	r := newRunner(t)
+	r.run(m.Init())
+
+	m.helpModal.Open()
+	r.pump(t, m, 5*time.Second, func(m Model) bool {
+		return countLines(m.panes[0], "stream 1 line") == 3
+	})
+}
+
+func TestPaneTitlesQualifiedOnlyWhenServiceNamesCollide(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	containers := []docker.Container{
+		{ID: "aaaaaaaaaaaaaaaa", ComposeProject: "shop", ComposeService: "db", State: "running"},
+		{ID: "bbbbbbbbbbbbbbbb", ComposeProject: "blog", ComposeService: "db", State: "running"},
+		{ID: "cccccccccccccccc", ComposeProject: "shop", ComposeService: "api", State: "running"},
+	}
+	m := New(containers, nil, 120, 40, common.Tutorial{})
+	t.Cleanup(m.Cleanup)
+
+	want := []string{"shop/db", "blog/db", "api"}
+	for i, w := range want {
+		if got := m.panes[i].displayName(); got != w {
+			t.Fatalf("pane %d: expected title %q, got %q", i, w, got)
+		}
+	}
+
+	// Once the collision goes away the short name is used again
+	m, _ = m.Update(ContainerRemovedMsg{ContainerID: "bbbbbbbbbbbbbbbb"})
+	if got := m.panes[0].displayName(); got != "db" {
+		t.Fatalf("expected short title after removal, got %q", got)
+	}
+}
+
+func TestSwapFocusedPaneWithGridNeighbour(t *testing.T) {
+	streamer := newFakeStreamer(0)
+	m := newTestModel(t, streamer, "aaaaaaaaaaaaaaaa", "bbbbbbbbbbbbbbbb", "cccccccccccccccc", "dddddddddddddddd")
+	m.layoutBias = LayoutBalanced
+	m.recalculateLayout()
+	if m.layout.Rows != 2 || m.layout.Cols != 2 {
+		t.Fatalf("expected 2x2 grid, got %dx%d", m.layout.Rows, m.layout.Cols)
+	}
+
+	// Nothing to the left of the first column
+	if m.swapFocusedPane(0, -1) {
+		t.Fatalf("expected swap past the grid edge to be a no-op")
+	}
+
+	if !m.swapFocusedPane(0, 1) {
+		t.Fatalf("expected swap with right neighbour")
+	}
+	if m.panes[0].ID != "bbbbbbbbbbbbbbbb" || m.panes[1].ID != "aaaaaaaaaaaaaaaa" {
+		t.Fatalf("unexpected order after swap: %s, %s", m.panes[0].ID, m.panes[1].ID)
+	}
+	if m.focusedPane != 1 || !m.panes[1].Active || m.panes[0].Active {
+		t.Fatalf("expected focus to follow the moved pane, focused=%d", m.focusedPane)
+	}
+
+	if !m.swapFocusedPane(1, 0) {
+		t.Fatalf("expected swap with pane below")
+	}
+	if m.panes[3].ID != "aaaaaaaaaaaaaaaa" || m.focusedPane != 3 {
+		t.Fatalf("expected moved pane at index 3, got %s (focused=%d)", m.panes[3].ID, m.focusedPane)
+	}
+
+	m.maximizedPane = 3
+	if m.swapFocusedPane(-1, 0) {
+		t.Fatalf("expected swap to be disabled while maximized")
+	}
+}
+
+func TestFreezeLayoutKeepsRemovedPaneUntilDismissed(t *testing.T) {
+	streamer := newFakeStreamer(0)
+	m := newTestModel(t, streamer, "aaaaaaaaaaaaaaaa", "bbbbbbbbbbbbbbbb", "cccccccccccccccc")
+	m.freezeLayout = true
+	layout := m.layout
+
+	m, _ = m.Update(ContainerRemovedMsg{ContainerID: "bbbbbbbbbbbbbbbb"})
+	if len(m.panes) != 3 {
+		t.Fatalf("expected removed pane to keep its slot, got %d panes", len(m.panes))
+	}
+	if !m.panes[1].exited || m.panes[1].Connected {
+		t.Fatalf("expected pane to be an exited placeholder")
+	}
+	if m.layout.Rows != layout.Rows || m.layout.Cols != layout.Cols {
+		t.Fatalf("expected layout to stay %dx%d, got %dx%d", layout.Rows, layout.Cols, m.layout.Rows, m.layout.Cols)
+	}
+
+	// Dismissing a live pane is refused
+	m.setFocus(0)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if len(m.panes) != 3 {
+		t.Fatalf("expected live pane to stay when dismissed")
+	}
+
+	m.setFocus(1)
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if len(m.panes) != 2 {
+		t.Fatalf("expected exited pane to be dismissed, got %d panes", len(m.panes))
+	}
+	for _, p := range m.panes {
+		if p.ID == "bbbbbbbbbbbbbbbb" {
+			t.Fatalf("dismissed pane still present")
+		}
+	}
+}
+
+func TestRemovedPaneCollapsesLayoutWhenNotFrozen(t *testing.T) {
+	streamer := newFakeStreamer(0)
+	m := newTestModel(t, streamer, "aaaaaaaaaaaaaaaa", "bbbbbbbbbbbbbbbb")
+
+	m, _ = m.Update(ContainerRemovedMsg{ContainerID: "aaaaaaaaaaaaaaaa"})
+	if len(m.panes) != 1 || m.panes[0].ID != "bbbbbbbbbbbbbbbb" {
+		t.Fatalf("expected removed pane to be dropped")
+	}
+}
+
+func TestBuildOnStandaloneContainerPromptsForContext(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaaaaaa")
+	cfg := &config.Config{}
+	if err := cfg.SetBuildContext("svc-aaaa", config.BuildContext{Context: "./app"}); err != nil {
+		t.Fatalf("SetBuildContext: %v", err)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	if !m.buildPrompt.IsVisible() {
+		t.Fatalf("expected build prompt for a standalone container")
+	}
+	if m.panes[0].IsBuildMode() {
+		t.Fatalf("expected build to wait for the prompt")
+	}
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.buildPrompt.IsVisible() || cmd == nil {
+		t.Fatalf("expected enter to confirm the prompt")
+	}
+	confirmed, ok := cmd().(common.BuildPromptConfirmedMsg)
+	if !ok || confirmed.BuildContext.Context != "./app" || confirmed.ContainerID != "aaaaaaaa" {
+		t.Fatalf("expected remembered context to be confirmed, got %+v", confirmed)
+	}
+}
+
+func TestExportWritesFullHistoryToExportsDir(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaaaaaa")
+	m.exportLogs = func(ctx context.Context, containerID string, w io.Writer, progress func(int)) (int, error) {
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(w, "line %d\n", i)
+		}
+		progress(3)
+		return 3, nil
+	}
+
+	started, ok := m.startExport(m.panes[0].Container)().(exportProgressMsg)
+	if !ok {
+		t.Fatalf("expected export to start")
+	}
+	var done exportDoneMsg
+	for {
+		msg := m.waitForExport(started.export)()
+		if d, ok := msg.(exportDoneMsg); ok {
+			done = d
+			break
+		}
+	}
+	if done.err != nil || done.lines != 3 {
+		t.Fatalf("unexpected export result %+v", done)
+	}
+	if filepath.Dir(done.path) != config.GetExportsDir() || !strings.HasPrefix(filepath.Base(done.path), "svc-aaaa-") {
+		t.Fatalf("unexpected export path %q", done.path)
+	}
+	data, err := os.ReadFile(done.path)
+	if err != nil || string(data) != "line 0\nline 1\nline 2\n" {
+		t.Fatalf("unexpected export contents %q (%v)", data, err)
+	}
+}
+
+func TestLogBurstIsRenderedInBatches(t *testing.T) {
+	const burst = 500
+
+	streamer := newFakeStreamer(burst)
+	m := newTestModel(t, streamer, "aaaaaaaaaaaaaaaa")
+	r := newRunner(t)
+	r.run(m.Init())
+
+	m = r.pump(t, m, 5*time.Second, func(m Model) bool {
+		p := m.panes[0]
+		return countLines(p, "stream 1 line") == burst && !p.renderPending && p.renders > 0
+	})
+
+	if got := m.panes[0].renders; got > burst/10 {
+		t.Fatalf("expected a %d line burst to be coalesced into few renders, got %d", burst, got)
+	}
+	if !strings.Contains(m.panes[0].Viewport.View(), fmt.Sprintf("stream 1 line %d", burst-1)) {
+		t.Fatalf("expected the viewport to show the newest line after the flush")
+	}
+}
+
+func TestFlushRenderOnlyRendersWhenLinesArePending(t *testing.T) {
+	pane := NewPane(docker.Container{ID: "c1", Name: "test"}, 80, 12)
+	if pane.FlushRender() {
+		t.Fatalf("expected nothing to flush on an empty pane")
+	}
+	for i := 0; i < 100; i++ {
+		pane.AddLogLine(docker.LogLine{ContainerID: "c1", Timestamp: time.Now(), Stream: "stdout", Content: fmt.Sprintf("line %d", i)})
+	}
+	if pane.renders != 0 {
+		t.Fatalf("expected AddLogLine not to render, got %d renders", pane.renders)
+	}
+	if !pane.FlushRender() || pane.FlushRender() {
+		t.Fatalf("expected exactly one flush for the batch")
+	}
+	if pane.renders != 1 {
+		t.Fatalf("expected one render, got %d", pane.renders)
+	}
+}
+
+func TestQuitFlushesPausedLinesAndClosesExports(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaaaaaa")
+	m.exportLogs = func(ctx context.Context, containerID string, w io.Writer, progress func(int)) (int, error) {
+		fmt.Fprintln(w, "line 0")
+		<-ctx.Done()
+		return 1, ctx.Err()
+	}
+	started, ok := m.startExport(m.panes[0].Container)().(exportProgressMsg)
+	if !ok {
+		t.Fatalf("expected export to start")
+	}
+
+	m.panes[0].TogglePause()
+	for i := 0; i < 3; i++ {
+		m.panes[0].AddLogLine(docker.LogLine{ContainerID: "aaaaaaaa", Timestamp: time.Now(), Stream: "stdout", Content: fmt.Sprintf("held %d", i)})
+	}
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if cmd == nil {
+		t.Fatalf("expected quit command")
+	}
+	quits := false
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			if c != nil {
+				if _, ok := c().(tea.QuitMsg); ok {
+					quits = true
+				}
+			}
+		}
+	} else {
+		_, quits = msg.(tea.QuitMsg)
+	}
+	if !quits {
+		t.Fatalf("expected quit key to quit, got %T", msg)
+	}
+
+	if got := countLines(m.panes[0], "held"); got != 3 {
+		t.Fatalf("expected paused lines to be flushed on quit, got %d", got)
+	}
+	// Cleanup returns only once the export has closed its file
+	select {
+	case done := <-started.export.done:
+		if done.err != context.Canceled {
+			t.Fatalf("expected export to be cancelled, got %v", done.err)
+		}
+		data, err := os.ReadFile(done.path)
+		if err != nil || string(data) != "line 0\n" {
+			t.Fatalf("unexpected export contents %q (%v)", data, err)
+		}
+	default:
+		t.Fatalf("expected export to be finished after quit")
+	}
+}
+
+func TestDebugOverlayOnlyShowsWithDebugEnabled(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaaaaaa", "bbbbbbbb")
+	overlayKey := tea.KeyMsg{Type: tea.KeyCtrlO}
+
+	m, _ = m.Update(overlayKey)
+	if m.debugOverlay {
+		t.Fatalf("expected overlay to stay off while debug logging is disabled")
+	}
+
+	debug.Init(true, "")
+	t.Cleanup(func() {
+		debug.Init(false, "")
+		debug.Close()
+	})
+	debug.Log("overlay marker")
+
+	m, _ = m.Update(overlayKey)
+	if !m.debugOverlay {
+		t.Fatalf("expected overlay to toggle on")
+	}
+	view := m.View()
+	if !strings.Contains(view, "overlay marker") || !strings.Contains(view, "2  focused 0  maximized -1") {
+		t.Fatalf("expected overlay with model state and debug log tail, got:\n%s", view)
+	}
+
+	debug.Disable()
+	if strings.Contains(m.View(), "overlay marker") {
+		t.Fatalf("expected overlay to hide when debug logging is turned off")
+	}
+}
+
+func TestReloadConfigAppliesEditedFiles(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaaaaaa")
+	reloadKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")}
+
+	kb := config.DefaultKeyBindings()
+	kb.Quit = "Q"
+	if err := config.SaveKeyBindings(kb); err != nil {
+		t.Fatalf("SaveKeyBindings: %v", err)
+	}
+	cfg := &config.Config{Display: &config.DisplaySettings{UTCTimestamps: true}}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	m, _ = m.Update(reloadKey)
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Q")}, m.keys.Quit) {
+		t.Fatalf("expected the edited quit binding to apply after reload")
+	}
+	if !m.utcTimestamps || !m.panes[0].utcTimestamps {
+		t.Fatalf("expected UTC timestamps from the reloaded config")
+	}
+
+	os.WriteFile(config.GetConfigPath(), []byte(`{"display": {`), 0644)
+	m, _ = m.Update(reloadKey)
+	if view := m.View(); !strings.Contains(view, "config.json line") {
+		t.Fatalf("expected a toast naming the broken file, got:\n%s", view)
+	}
+}
+
+func TestSavedProjectViewIsRestored(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	projects := &config.Projects{SavedProjects: map[string]config.SavedProject{
+		"shop": {WorkingDir: "/src/shop", View: &config.SavedView{
+			Services:  []string{"worker", "api"},
+			WordWrap:  true,
+			Maximized: "api",
+			Tab:       "env",
+		}},
+	}}
+	if err := projects.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	containers := []docker.Container{
+		{ID: "aaaaaaaa", Name: "shop-api-1", ComposeProject: "shop", ComposeService: "api", State: "running"},
+		{ID: "bbbbbbbb", Name: "shop-db-1", ComposeProject: "shop", ComposeService: "db", State: "running"},
+		{ID: "cccccccc", Name: "shop-worker-1", ComposeProject: "shop", ComposeService: "worker", State: "running"},
+	}
+	m := New(containers, nil, 120, 40, common.Tutorial{})
+	m.streamLogs = newFakeStreamer(0).StreamLogs
+	t.Cleanup(m.Cleanup)
+
+	var order []string
+	for i := range m.panes {
+		order = append(order, m.panes[i].Container.ComposeService)
+	}
+	if strings.Join(order, ",") != "worker,api,db" {
+		t.Fatalf("expected panes in the saved order with unlisted services last, got %v", order)
+	}
+	if !m.wordWrap || m.maximizedPane != 1 || m.focusedPane != 1 {
+		t.Fatalf("expected word wrap on and api maximized, got wrap %v maximized %d", m.wordWrap, m.maximizedPane)
+	}
+
+	m, _ = m.Update(restoreTabMsg{})
+	if tab := m.panes[1].GetActiveTab(); tab != TabEnv {
+		t.Fatalf("expected the saved tab to be restored, got %s", tab)
+	}
+
+	view := m.currentViews()["shop"]
+	if strings.Join(view.Services, ",") != "worker,api,db" || view.Maximized != "api" || view.Tab != "env" || !view.WordWrap {
+		t.Fatalf("expected the current view to round-trip, got %+v", view)
+	}
+	if len(view.ColumnRatios) != m.layout.Cols || len(view.RowRatios) != m.layout.Rows {
+		t.Fatalf("expected the grid ratios in a single-project view, got %+v", view)
+	}
+}
+
+func TestStopKeyStopsFocusedPaneExceptOnEnvTab(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(2), "aaaa1111", "bbbb2222")
+	stopKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")}
+
+	m.maximizedPane = 0
+	m.panes[0].SetActiveTab(TabEnv)
+	m, _ = m.update(stopKey)
+	if !m.panes[0].IsShowingRedactedEnv() {
+		t.Fatalf("expected s on the Env tab to show redacted env vars")
+	}
+	if m.panes[0].LogLines.Len() != 0 {
+		t.Fatalf("expected no stop on the Env tab")
+	}
+
+	m.maximizedPane = -1
+	m.focusedPane = 1
+	m, cmd := m.update(stopKey)
+	lines := m.panes[1].LogLines
+	if cmd == nil || lines.At(lines.Len()-1).Content != "--- Stopping container... ---" {
+		t.Fatalf("expected s to stop the focused container")
+	}
+}
+
+func TestRenameFromInspectUpdatesPaneName(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(1), "aaaa1111")
+	m.inspectModal.Open("aaaa1111")
+
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	if m.inspectModal.IsVisible() || !m.renamePrompt.IsVisible() {
+		t.Fatalf("expected the rename key to swap the inspect modal for the rename prompt")
+	}
+	m.renamePrompt.Close()
+
+	m, _ = m.update(ContainerRenamedMsg{ContainerID: "aaaa1111", OldName: "svc-aaaa", NewName: "svc-renamed", Err: errors.New("name \"svc-renamed\" is already in use")})
+	if m.panes[0].Container.Name != "svc-aaaa" {
+		t.Fatalf("expected a failed rename to keep the old name")
+	}
+	m, _ = m.update(ContainerRenamedMsg{ContainerID: "aaaa1111", OldName: "svc-aaaa", NewName: "svc-renamed"})
+	if m.panes[0].Container.Name != "svc-renamed" {
+		t.Fatalf("expected the pane to take the new name, got %q", m.panes[0].Container.Name)
+	}
+}
+
+func TestUnboundKeyToastsOnlyWhenEnabled(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(1), "aaaa1111")
+	unbound := tea.KeyMsg{Type: tea.KeyCtrlB}
+
+	m, _ = m.update(unbound)
+	if m.toast.IsVisible() {
+		t.Fatalf("expected no toast for unbound keys by default")
+	}
+
+	cfg := &config.Config{Notifications: &config.NotificationSettings{UnboundKeys: true}}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	m.toast.ReloadConfig()
+
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("}")})
+	if m.toast.IsVisible() {
+		t.Fatalf("expected a bound key not to toast")
+	}
+	m, _ = m.update(unbound)
+	if !m.toast.IsVisible() || !strings.Contains(m.toast.RenderInline(), "ctrl+b") {
+		t.Fatalf("expected a toast naming the unbound key, got %q", m.toast.RenderInline())
+	}
+}
+
+func TestCountPrefixScrollsThePaneItWasTypedIn(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(1), "aaaa1111", "bbbb2222", "cccc3333")
+	for i := 0; i < 200; i++ {
+		m.panes[0].AddLogLine(docker.LogLine{ContainerID: "aaaa1111", Content: fmt.Sprintf("line %d", i)})
+	}
+	m.panes[0].FlushRender()
+	m.panes[0].Viewport.SetYOffset(0)
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	// The count is held without moving the focus, so a badge it is typed
+	// over stays
+	m.panes[1].unseenStderr = true
+	m, _ = m.update(runes("2"))
+	if m.focusedPane != 0 || !m.panes[1].unseenStderr {
+		t.Fatalf("expected a digit to wait for its motion before jumping")
+	}
+	m, _ = m.update(runes("0"))
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	if m.focusedPane != 0 {
+		t.Fatalf("expected the count to return to pane 0, got %d", m.focusedPane)
+	}
+	if got := m.panes[0].Viewport.YOffset; got != 60 {
+		t.Fatalf("expected 20 ctrl+d to scroll 60 lines, got %d", got)
+	}
+
+	m.maximizedPane = 0
+	m.recalculateLayout()
+	m.panes[0].Viewport.SetYOffset(0)
+	m, _ = m.update(runes("1"))
+	m, _ = m.update(runes("5"))
+	m, _ = m.update(runes("j"))
+	if m.maximizedPane != 0 || m.panes[0].GetActiveTab() != TabLogs {
+		t.Fatalf("expected the count to return to pane 0's logs, got pane %d", m.maximizedPane)
+	}
+	if got := m.panes[0].Viewport.YOffset; got != 15 {
+		t.Fatalf("expected 15j to scroll 15 lines, got %d", got)
+	}
+	m, _ = m.update(runes("j"))
+	if got := m.panes[0].Viewport.YOffset; got != 16 {
+		t.Fatalf("expected the count to reset after a motion, got offset %d", got)
+	}
+
+	m.maximizedPane = -1
+	m.recalculateLayout()
+	m, _ = m.update(runes("3"))
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	if m.focusedPane != 2 {
+		t.Fatalf("expected a non-motion key to apply the pane jump")
+	}
+	if !m.panes[1].unseenStderr {
+		t.Fatalf("expected the count typed over pane 2 to leave its badge")
+	}
+
+	// With no key after it, the digit jumps once the count times out
+	m, _ = m.update(runes("2"))
+	m, _ = m.update(countTimeoutMsg{gen: m.countGen - 1})
+	if m.focusedPane != 2 {
+		t.Fatalf("expected a stale timeout to be ignored")
+	}
+	m, _ = m.update(countTimeoutMsg{gen: m.countGen})
+	if m.focusedPane != 1 || m.count != 0 {
+		t.Fatalf("expected the timed out digit to jump to pane 1, got pane %d", m.focusedPane)
+	}
+}
+
+func TestComposeLogsPaneStreamsWholeProject(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	streamer := newFakeStreamer(3)
+	cont := docker.ComposeLogsContainer(docker.Container{ID: "aaaa1111", ComposeProject: "blog", ComposeService: "web", ComposeWorkingDir: "/src/blog"})
+	m := New([]docker.Container{cont}, nil, 120, 40, common.Tutorial{})
+	m.streamLogs = streamer.StreamLogs
+	var opened []string
+	m.composeLogs = func(ctx context.Context, c docker.Container) (<-chan docker.LogLine, <-chan error) {
+		opened = append(opened, c.ComposeProject)
+		return streamer.StreamLogs(ctx, c.ID)
+	}
+	t.Cleanup(m.Cleanup)
+
+	r := newRunner(t)
+	r.run(m.Init())
+	m = r.pump(t, m, 5*time.Second, func(m Model) bool { return countLines(m.panes[0], "stream 1 line") == 3 })
+	if len(opened) != 1 || opened[0] != "blog" {
+		t.Fatalf("expected one compose logs stream for blog, got %v", opened)
+	}
+
+	m.maximizedPane = 0
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	if m.panes[0].GetActiveTab() != TabLogs {
+		t.Fatalf("expected a compose logs pane to stay on the Logs tab")
+	}
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if !m.toast.IsVisible() || countLines(m.panes[0], "--- Restarting") != 0 {
+		t.Fatalf("expected container actions to be refused in a compose logs pane")
+	}
+
+	running := []docker.Container{{ID: "bbbb2222", ComposeProject: "blog", ComposeWorkingDir: "/src/blog", State: "running"}}
+	if c, ok := findReconnectTarget(running, cont); !ok || c.ID != cont.ID {
+		t.Fatalf("expected the compose logs pane to reconnect once a service runs again")
+	}
+	running[0].ComposeWorkingDir = "/other/blog"
+	if _, ok := findReconnectTarget(running, cont); ok {
+		t.Fatalf("expected a same-named project in another directory not to count")
+	}
+}
+
+func TestReplicaPanesAreNumberedAndReconnectToTheirOwnReplica(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	web1 := docker.Container{ID: "aaaa1111aaaa", Name: "blog-web-1", State: "running", ComposeProject: "blog", ComposeService: "web", ComposeReplica: 1}
+	web2 := docker.Container{ID: "bbbb2222bbbb", Name: "blog-web-2", State: "running", ComposeProject: "blog", ComposeService: "web", ComposeReplica: 2}
+	m := New([]docker.Container{web1, web2}, nil, 120, 40, common.Tutorial{})
+	m.streamLogs = newFakeStreamer(0).StreamLogs
+	t.Cleanup(m.Cleanup)
+
+	if got := m.panes[1].displayName(); got != "web #2" {
+		t.Fatalf("expected replicas to be told apart by number, got %q", got)
+	}
+
+	restarted := web2
+	restarted.ID = "cccc3333cccc"
+	containers := []docker.Container{web1, restarted}
+	if c, ok := findReconnectTarget(containers, web2); !ok || c.ID != restarted.ID {
+		t.Fatalf("expected replica 2 to reconnect to the new replica 2, got %+v", c)
+	}
+}
+
+func TestOpenPagerWritesLogsToTempFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+	t.Setenv("PAGER", "true")
+
+	if _, err := openPager("", "web"); err == nil {
+		t.Fatalf("expected an error for a pane without logs")
+	}
+	cmd, err := openPager("12:00:00 hello\n", "web")
+	if err != nil || cmd == nil {
+		t.Fatalf("openPager: %v", err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "cm-*.log"))
+	if len(files) != 1 {
+		t.Fatalf("expected one temp file, got %v", files)
+	}
+	if data, _ := os.ReadFile(files[0]); string(data) != "12:00:00 hello\n" {
+		t.Fatalf("expected the logs in the temp file, got %q", data)
+	}
+}
+
+func TestDragSelectionAutoScrollsPastTheBottomRow(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(1), "aaaa1111", "bbbb2222")
+	for i := 0; i < 200; i++ {
+		m.panes[0].AddLogLine(docker.LogLine{ContainerID: "aaaa1111", Content: fmt.Sprintf("line %d", i)})
+	}
+	m.panes[0].FlushRender()
+	m.panes[0].Viewport.SetYOffset(0)
+	height := m.panes[0].Viewport.Height
+	bottom := 2 + height - 1 // border + title line
+
+	m, _ = m.update(tea.MouseMsg{X: 1, Y: 2, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	for i := 0; i < 10; i++ {
+		m, _ = m.update(tea.MouseMsg{X: 15, Y: bottom, Action: tea.MouseActionMotion, Button: tea.MouseButtonLeft})
+	}
+	m, _ = m.update(tea.MouseMsg{X: 15, Y: bottom, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft})
+
+	if got := m.panes[0].Viewport.YOffset; got != 10 {
+		t.Fatalf("expected the drag to scroll 10 lines, got offset %d", got)
+	}
+	startLine, _, endLine, _ := m.selection.GetNormalizedRange()
+	text := m.panes[0].GetTextInRangeChar(m.selection.GetNormalizedRange())
+	if startLine != 0 || endLine != height+9 {
+		t.Fatalf("expected the selection to span lines 0-%d, got %d-%d", height+9, startLine, endLine)
+	}
+	if !strings.Contains(text, " line 0\n") || !strings.Contains(text, fmt.Sprintf("line %d", height+8)) {
+		t.Fatalf("expected the copy to include lines scrolled into view, got %q", text)
+	}
+
+	// Dragging back to the top row scrolls up again and keeps the start anchored
+	m.lastClickPaneID = "" // not a double-click
+	m, _ = m.update(tea.MouseMsg{X: 1, Y: 5, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	m, _ = m.update(tea.MouseMsg{X: 1, Y: 2, Action: tea.MouseActionMotion, Button: tea.MouseButtonLeft})
+	if got := m.panes[0].Viewport.YOffset; got != 9 {
+		t.Fatalf("expected the drag to scroll up a line, got offset %d", got)
+	}
+	if startLine, _, endLine, _ := m.selection.GetNormalizedRange(); startLine != 9 || endLine != 13 {
+		t.Fatalf("expected the selection to span lines 9-13, got %d-%d", startLine, endLine)
+	}
+}
+
+func TestMouseSelectionMapsRowsAndScrollbarColumn(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(1), "aaaa1111", "bbbb2222")
+	for i := 0; i < 200; i++ {
+		m.panes[0].AddLogLine(docker.LogLine{ContainerID: "aaaa1111", Content: fmt.Sprintf("line %d %s", i, strings.Repeat("x", 200))})
+	}
+	m.panes[0].FlushRender()
+	m.panes[0].Viewport.SetYOffset(0)
+	press := func(x, y int) {
+		m.lastClickPaneID = ""
+		m, _ = m.update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	}
+	drag := func(x, y int) {
+		m, _ = m.update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionMotion, Button: tea.MouseButtonLeft})
+	}
+	selected := func() string {
+		return m.panes[0].GetTextInRangeChar(m.selection.GetNormalizedRange())
+	}
+
+	// Tiled: the title is row 1, the scrollbar is the last column inside the border
+	_ = m.View()
+	scrollbar := m.panes[0].Viewport.Width
+	press(1, 2)
+	drag(scrollbar, 2)
+	if got, want := len(selected()), m.panes[0].Viewport.Width-1; got != want {
+		t.Fatalf("expected dragging onto the scrollbar to select the %d visible columns, got %d", want, got)
+	}
+	press(1, 3)
+	drag(10, 3)
+	if got := selected(); !strings.HasSuffix(got, " ") || m.selection.StartLine != 1 {
+		t.Fatalf("expected row 3 to be line 1's timestamp, got line %d %q", m.selection.StartLine, got)
+	}
+
+	// Maximized: the tab bar pushes the first log line down a row
+	m.maximizedPane = 0
+	m.recalculateLayout()
+	_ = m.View()
+	m.panes[0].Viewport.SetYOffset(0)
+	press(1, 3)
+	drag(16, 3)
+	if got := selected(); !strings.HasSuffix(got, "line 0") {
+		t.Fatalf("expected the first row under the tab bar to be line 0, got %q", got)
+	}
+}
+
+func TestPanesTooSmallToTileStackUnderAPaneList(t *testing.T) {
+	ids := []string{"aaaa1111", "bbbb2222", "cccc3333", "dddd4444", "eeee5555", "ffff6666", "gggg7777", "hhhh8888", "iiii9999"}
+	m := newTestModel(t, newFakeStreamer(1), ids...)
+	m, _ = m.update(tea.WindowSizeMsg{Width: 60, Height: 20})
+	m, _ = m.update(resizeTickMsg{})
+
+	if !m.stacked() {
+		t.Fatalf("expected nine panes on a 60x20 screen to stack")
+	}
+	if got := m.panes[4].Viewport.Width; got != 58 {
+		t.Fatalf("expected stacked panes to take the full width, got %d", got)
+	}
+	m.setFocus(4)
+	view := m.View()
+	if !strings.Contains(view, "too small to tile") || !strings.Contains(view, "[5 svc-eeee]") {
+		t.Fatalf("expected the pane list with pane 5 focused, got:\n%s", view)
+	}
+	if got := m.getPaneAtPosition(50, 10); got != 4 {
+		t.Fatalf("expected clicks to land on the focused pane, got %d", got)
+	}
+
+	m, _ = m.update(tea.WindowSizeMsg{Width: 200, Height: 60})
+	m, _ = m.update(resizeTickMsg{})
+	if m.stacked() || strings.Contains(m.View(), "too small to tile") {
+		t.Fatalf("expected a large screen to tile again")
+	}
+}
+
+func TestResizeResetBalancesTheGrid(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaa1111", "bbbb2222", "cccc3333", "dddd4444")
+	m.layoutBias = LayoutBalanced
+	m.recalculateLayout()
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	width, height := m.panes[0].Viewport.Width, m.panes[0].Viewport.Height
+
+	m, _ = m.update(runes(">"))
+	m, _ = m.update(runes("+"))
+	if m.panes[0].Viewport.Width == width || m.panes[0].Viewport.Height == height {
+		t.Fatalf("expected > and + to grow the focused pane")
+	}
+
+	m.maximizedPane = 0
+	m, _ = m.update(runes("="))
+	m.maximizedPane = -1
+	m.recalculateLayout()
+	if m.panes[0].Viewport.Width != width || m.panes[0].Viewport.Height != height {
+		t.Fatalf("expected = to restore the even %dx%d pane, got %dx%d",
+			width, height, m.panes[0].Viewport.Width, m.panes[0].Viewport.Height)
+	}
+}
+
+func TestHelpBarShowsResourceTotalsAcrossPanes(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaa1111", "bbbb2222", "cccc3333")
+	if strings.Contains(m.renderHelpBar(), "Σ") {
+		t.Fatalf("expected no totals before any sample")
+	}
+
+	now := time.Now()
+	m.panes[0].AddStats(docker.ContainerStats{CPUPercent: 12.5, MemoryUsage: 1 << 20, Timestamp: now})
+	m.panes[1].AddStats(docker.ContainerStats{CPUPercent: 7.5, MemoryUsage: 1 << 19, Timestamp: now})
+	m.panes[2].AddStats(docker.ContainerStats{CPUPercent: 99, MemoryUsage: 1 << 30, Timestamp: now.Add(-time.Minute)})
+	if bar := m.renderHelpBar(); !strings.Contains(bar, "Σ cpu 20.0% mem 1.5M") {
+		t.Fatalf("expected totals of the fresh samples, got %q", bar)
+	}
+
+	m, cmd := m.update(totalsSampledMsg{stats: map[string]docker.ContainerStats{
+		"cccc3333": {CPUPercent: 10, MemoryUsage: 1 << 20, Timestamp: time.Now()},
+	}})
+	if cmd == nil {
+		t.Fatalf("expected the next totals tick to be scheduled")
+	}
+	if bar := m.renderHelpBar(); !strings.Contains(bar, "Σ cpu 30.0% mem 2.5M") {
+		t.Fatalf("expected the batch sample to update the totals, got %q", bar)
+	}
+
+	// A failed sample is retried with backoff rather than given up on
+	for i := 0; i < 2; i++ {
+		if m, cmd = m.update(totalsSampledMsg{err: context.DeadlineExceeded}); cmd == nil {
+			t.Fatalf("expected a retry to be scheduled after failure %d", i+1)
+		}
+	}
+	if m.totalsFailures != 2 || totalsRetryDelay(2) != 4*totalsInterval || totalsRetryDelay(10) != totalsMaxRetryDelay {
+		t.Fatalf("expected a doubling, capped retry delay, got %d failures", m.totalsFailures)
+	}
+	m, _ = m.update(totalsSampledMsg{stats: map[string]docker.ContainerStats{}})
+	if m.totalsFailures != 0 {
+		t.Fatalf("expected a successful sample to reset the backoff")
+	}
+}
+
+func TestNewestPaneKeyFocusesLatestActivity(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaa1111", "bbbb2222", "cccc3333")
+	newest := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")}
+
+	m, _ = m.update(newest)
+	if m.focusedPane != 0 {
+		t.Fatalf("expected focus to stay put with no activity, got pane %d", m.focusedPane)
+	}
+
+	m.panes[2].AddLogLine(docker.LogLine{Timestamp: time.Now(), Stream: "stdout", Content: "older"})
+	m.panes[1].AddLogLine(docker.LogLine{Timestamp: time.Now(), Stream: "stdout", Content: "newer"})
+	m.panes[1].lastActivity = m.panes[2].lastActivity.Add(time.Millisecond)
+	m.panes[0].AddLogLine(docker.LogLine{Timestamp: time.Now(), Stream: "system", Content: "--- Stream ended ---"})
+
+	m, cmd := m.update(newest)
+	if m.focusedPane != 1 || !m.panes[1].Active || m.panes[0].Active {
+		t.Fatalf("expected pane 1 to be focused, got pane %d", m.focusedPane)
+	}
+	if cmd == nil || !time.Now().Before(m.panes[1].flashUntil) {
+		t.Fatalf("expected the focused pane's border to flash")
+	}
+}
+
+func TestUnfocusedPaneBadgesStderrUntilFocused(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaa1111", "bbbb2222")
+
+	m.panes[0].AddLogLine(docker.LogLine{Timestamp: time.Now(), Stream: "stderr", Content: "focused error"})
+	m.panes[1].AddLogLine(docker.LogLine{Timestamp: time.Now(), Stream: "stdout", Content: "fine"})
+	if m.panes[0].unseenStderr || m.panes[1].unseenStderr {
+		t.Fatalf("expected no badge for stderr in the focused pane or stdout elsewhere")
+	}
+
+	m.panes[1].AddLogLine(docker.LogLine{Timestamp: time.Now(), Stream: "stderr", Content: "boom"})
+	if !m.panes[1].unseenStderr || !strings.Contains(m.panes[1].View(60, 10, false), "!") {
+		t.Fatalf("expected the unfocused pane to show a stderr badge")
+	}
+
+	m.focusNextPane()
+	if m.panes[1].unseenStderr {
+		t.Fatalf("expected focusing the pane to clear its badge")
+	}
+}
+
+func TestReconnectAllRestartsEveryDisconnectedPane(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaa1111", "bbbb2222", "cccc3333")
+	m.panes[1].Connected = false
+	m.panes[2].Connected = false
+
+	m, cmd := m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l"), Alt: true})
+	if cmd == nil || m.panes[0].reconnecting || !m.panes[1].reconnecting || !m.panes[2].reconnecting {
+		t.Fatalf("expected only the disconnected panes to start reconnecting")
+	}
+
+	replacement := docker.Container{ID: "dddd4444", Name: m.panes[1].Container.Name, State: "running"}
+	m, _ = m.update(reconnectAllMsg{
+		restarts: []restartStreamMsg{{OldContainerID: "bbbb2222", NewContainer: replacement}},
+		failed:   []string{"cccc3333"},
+	})
+	if m.panes[1].ID != "dddd4444" || !m.panes[1].Connected || m.panes[1].reconnecting {
+		t.Fatalf("expected pane 1 to follow its replacement container, got %s", m.panes[1].ID)
+	}
+	if m.panes[2].Connected || m.panes[2].reconnecting {
+		t.Fatalf("expected pane 2 to stay disconnected and stop reconnecting")
+	}
+	if _, ok := m.streams["dddd4444"]; !ok {
+		t.Fatalf("expected a log stream for the replacement container")
+	}
+}
+
+func TestReconnectAllSkipsPanesAlreadyReconnectingAndSurvivesAModal(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaa1111", "bbbb2222", "cccc3333")
+	for i := range m.panes {
+		m.panes[i].Connected = false
+	}
+	m.panes[1].reconnecting = true
+	m.panes[2].Container = docker.ComposeLogsContainer(docker.Container{ComposeProject: "shop"})
+
+	m, cmd := m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l"), Alt: true})
+	if cmd == nil || !m.panes[0].reconnecting || m.panes[2].reconnecting {
+		t.Fatalf("expected only pane 0 to start reconnecting")
+	}
+
+	// The result still lands while a modal is open
+	m.helpModal.Open()
+	m, _ = m.update(reconnectAllMsg{failed: []string{"aaaa1111"}})
+	if m.panes[0].reconnecting {
+		t.Fatalf("expected the reconnect result to reach pane 0 with a modal open")
+	}
+}
+
+func TestContainerStartEventReconnectsDisconnectedPane(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaa1111", "bbbb2222")
+	m.panes[0].Container.ComposeProject = "shop"
+	m.panes[0].Container.ComposeService = "api"
+	m.panes[0].Connected = false
+	m.panes[0].reconnecting = true
+
+	events := make(chan docker.ContainerEvent, 1)
+	errs := make(chan error, 1)
+	m.streamEvents = func(context.Context, ...string) (<-chan docker.ContainerEvent, <-chan error) { return events, errs }
+	m, cmd := m.update(watchEventsMsg{})
+	if cmd == nil {
+		t.Fatalf("expected a command waiting for container events")
+	}
+	started := func(c docker.Container) containerEventMsg {
+		return containerEventMsg{Event: docker.ContainerEvent{Container: c, Action: "start"}, source: events, errs: errs}
+	}
+
+	// An unrelated container starting leaves the pane alone
+	m, _ = m.update(started(docker.Container{ID: "eeee5555", Name: "other", State: "running"}))
+	if m.panes[0].Connected {
+		t.Fatalf("expected the pane to stay disconnected for an unrelated container")
+	}
+
+	replacement := docker.Container{ID: "cccc3333", Name: "shop-api-1", State: "running", ComposeProject: "shop", ComposeService: "api"}
+	m, _ = m.update(started(replacement))
+	if m.panes[0].ID != "cccc3333" || !m.panes[0].Connected || m.panes[0].reconnecting {
+		t.Fatalf("expected the pane to follow the recreated container, got %s", m.panes[0].ID)
+	}
+
+	// The backoff loop finding the same container later is a no-op
+	m.panes[0].AddLogLine(docker.LogLine{Timestamp: time.Now(), Stream: "stdout", Content: "ready"})
+	lines := m.panes[0].LogLines.Len()
+	m, _ = m.update(restartStreamMsg{OldContainerID: "cccc3333", NewContainer: replacement, reconnect: true})
+	if m.panes[0].LogLines.Len() != lines {
+		t.Fatalf("expected a stale reconnect to leave the pane's logs alone")
+	}
+}
+
+func TestContainerEventsFillTheEventsTab(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaa1111", "bbbb2222")
+	at := time.Now()
+	die := docker.ContainerEvent{Container: docker.Container{ID: "bbbb2222"}, Action: "die", Detail: "exit code 137", ExitCode: 137, Time: at}
+	m, _ = m.update(containerEventMsg{Event: docker.ContainerEvent{Container: docker.Container{ID: "bbbb2222"}, Action: "oom", Time: at}})
+	m, _ = m.update(containerEventMsg{Event: die})
+	m, _ = m.update(containerEventMsg{Event: docker.ContainerEvent{Container: docker.Container{ID: "ffff6666"}, Action: "die", Time: at}})
+
+	if len(m.panes[0].events) != 0 || len(m.panes[1].events) != 2 {
+		t.Fatalf("expected both events on pane 1 only, got %d and %d", len(m.panes[0].events), len(m.panes[1].events))
+	}
+	m.panes[1].FlushRender()
+	logs := m.panes[1].GetPlainTextLogs()
+	if !strings.Contains(logs, "ran out of memory") || !strings.Contains(logs, "Container exited with code 137") {
+		t.Fatalf("expected oom and die to be noted in the logs, got %q", logs)
+	}
+
+	m.setFocus(1)
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("6")})
+	m, _ = m.update(countTimeoutMsg{gen: m.countGen})
+	if m.panes[1].GetActiveTab() != TabEvents {
+		t.Fatalf("expected 6 to open the Events tab while maximized, got %s", m.panes[1].GetActiveTab())
+	}
+	tab := m.panes[1].renderEventsTab(100, 20)
+	if strings.Index(tab, "die") > strings.Index(tab, "oom") || !strings.Contains(tab, "exit code 137") {
+		t.Fatalf("expected newest event first with its detail, got %q", tab)
+	}
+}
+
+func TestEventStreamRetriesBackOffUntilAnEventArrives(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaa1111")
+
+	for i := 0; i < 3; i++ {
+		var cmd tea.Cmd
+		if m, cmd = m.update(eventsClosedMsg{err: errors.New("daemon unavailable")}); cmd == nil {
+			t.Fatalf("expected a retry to be scheduled after failure %d", i+1)
+		}
+	}
+	if m.eventsFailures != 3 || eventsRetryDelay(1) != eventsRetryInterval || eventsRetryDelay(3) != 4*eventsRetryInterval || eventsRetryDelay(10) != eventsMaxRetryDelay {
+		t.Fatalf("expected a doubling, capped retry delay, got %d failures", m.eventsFailures)
+	}
+	m, _ = m.update(containerEventMsg{Event: docker.ContainerEvent{Container: docker.Container{ID: "ffff6666"}, Action: "start", Time: time.Now()}})
+	if m.eventsFailures != 0 {
+		t.Fatalf("expected an event to reset the backoff")
+	}
+}
+
+func TestOOMKillIsFlaggedUntilTheContainerRestarts(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaa1111", "bbbb2222")
+	oom := containerEventMsg{Event: docker.ContainerEvent{Container: docker.Container{ID: "bbbb2222"}, Action: "oom", Time: time.Now()}}
+
+	die := containerEventMsg{Event: docker.ContainerEvent{Container: docker.Container{ID: "bbbb2222"}, Action: "die", ExitCode: 137, Time: time.Now()}}
+
+	// The OOM killer took a process, but the container is still running
+	m, _ = m.update(oom)
+	if m.panes[1].oomKilled || strings.Contains(m.panes[1].View(80, 10, false), "OOM-KILLED") {
+		t.Fatalf("expected an oom event alone not to flag a running container")
+	}
+
+	m, cmd := m.update(die)
+	if !m.panes[1].oomKilled || cmd == nil {
+		t.Fatalf("expected the container dying after the oom event to flag the pane and notify")
+	}
+	m, _ = m.update(oom)
+	m, _ = m.update(die)
+	m.panes[1].FlushRender()
+	if n := strings.Count(m.panes[1].GetPlainTextLogs(), "OOM-killed"); n != 1 {
+		t.Fatalf("expected one OOM warning in the logs, got %d", n)
+	}
+	if !strings.Contains(m.panes[1].View(80, 10, false), "OOM-KILLED") {
+		t.Fatalf("expected the pane title to warn about the OOM kill")
+	}
+
+	m, _ = m.update(restartStreamMsg{OldContainerID: "bbbb2222", NewContainer: m.panes[1].Container})
+	m.panes[1].FlushRender()
+	if m.panes[1].oomKilled || !strings.Contains(m.panes[1].GetPlainTextLogs(), "restarted after being OOM-killed") {
+		t.Fatalf("expected the restart to clear the flag and say why the container had died")
+	}
+}
+
+func TestExitCodeShowsInTitleUntilReconnect(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaa1111", "bbbb2222")
+	m.panes[0].Connected = false
+	m.panes[1].Connected = false
+
+	m, _ = m.update(containerExitedMsg{ContainerID: "aaaa1111", ExitCode: 0})
+	m, _ = m.update(containerEventMsg{Event: docker.ContainerEvent{Container: docker.Container{ID: "bbbb2222"}, Action: "die", ExitCode: 2, Time: time.Now()}})
+	// Inspect after the die event doesn't repeat it
+	m, _ = m.update(containerExitedMsg{ContainerID: "bbbb2222", ExitCode: 2})
+
+	if !strings.Contains(m.panes[0].View(80, 10, false), "exit 0") || !strings.Contains(m.panes[1].View(80, 10, false), "exit 2") {
+		t.Fatalf("expected both titles to show their exit code")
+	}
+	m.panes[1].FlushRender()
+	if n := strings.Count(m.panes[1].GetPlainTextLogs(), "exited with code 2"); n != 1 {
+		t.Fatalf("expected one exit line, got %d", n)
+	}
+
+	m, _ = m.update(restartStreamMsg{OldContainerID: "bbbb2222", NewContainer: m.panes[1].Container})
+	if strings.Contains(m.panes[1].View(80, 10, false), "exit 2") {
+		t.Fatalf("expected the exit code to clear once the container is back")
+	}
+}
+
+func TestSyncScrollMovesEveryPaneTogether(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaa1111", "bbbb2222", "cccc3333")
+	for i := range m.panes {
+		for n := 0; n < 200; n++ {
+			m.panes[i].AddLogLine(docker.LogLine{Timestamp: time.Now(), Stream: "stdout", Content: fmt.Sprintf("line %d", n)})
+		}
+		m.panes[i].FlushRender()
+	}
+	m.panes[2].SetActiveTab(TabStats)
+	scrollUp := tea.KeyMsg{Type: tea.KeyCtrlU}
+	offsets := func() (a, b, c int) {
+		return m.panes[0].Viewport.YOffset, m.panes[1].Viewport.YOffset, m.panes[2].Viewport.YOffset
+	}
+	a0, b0, c0 := offsets()
+
+	m, _ = m.update(scrollUp)
+	if a, b, _ := offsets(); a != a0-3 || b != b0 {
+		t.Fatalf("expected only the focused pane to scroll, got offsets %d and %d", a, b)
+	}
+
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s"), Alt: true})
+	if !m.syncScroll || !strings.Contains(m.renderHelpBar(), "[sync scroll]") {
+		t.Fatalf("expected alt+s to turn on sync scroll")
+	}
+	m, _ = m.update(scrollUp)
+	if a, b, c := offsets(); a != a0-6 || b != b0-3 || c != c0 {
+		t.Fatalf("expected the logs panes to scroll together, got offsets %d, %d, %d", a, b, c)
+	}
+}
+
+func TestSyncTimeAlignsPanesByTimestamp(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaa1111", "bbbb2222")
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	// Pane 0 logs every second, pane 1 every ten seconds
+	for n := 0; n < 600; n++ {
+		m.panes[0].AddLogLine(docker.LogLine{Timestamp: start.Add(time.Duration(n) * time.Second), Stream: "stdout", Content: "fast"})
+	}
+	for n := 0; n < 60; n++ {
+		m.panes[1].AddLogLine(docker.LogLine{Timestamp: start.Add(time.Duration(n) * 10 * time.Second), Stream: "stdout", Content: "slow"})
+	}
+	m.panes[0].FlushRender()
+	m.panes[1].FlushRender()
+
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if !m.syncTime || !strings.Contains(m.renderHelpBar(), "[sync time]") {
+		t.Fatalf("expected ctrl+t to turn on sync time")
+	}
+
+	m.panes[0].Viewport.SetYOffset(300)
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	top, _ := m.panes[0].TopLineTime()
+	if got := start.Add(303 * time.Second); !top.Equal(got) {
+		t.Fatalf("focused pane top = %v, want %v", top, got)
+	}
+	if other, _ := m.panes[1].TopLineTime(); !other.Equal(start.Add(310 * time.Second)) {
+		t.Fatalf("expected the other pane at the first line after %v, got %v", top, other)
+	}
+}
+
+func TestInspectActionsRunOnTheInspectedPane(t *testing.T) {
+	m := newTestModel(t, newFakeStreamer(0), "aaaa1111", "bbbb2222")
+
+	m, cmd := m.update(common.InspectActionMsg{ContainerID: "bbbb2222", Action: common.InspectStop})
+	if cmd == nil {
+		t.Fatalf("expected a stop command")
+	}
+	m.panes[1].FlushRender()
+	if !strings.Contains(m.panes[1].GetPlainTextLogs(), "Stopping container") {
+		t.Fatalf("expected the stop to be noted in the inspected pane")
+	}
+
+	// Starting a running container is refused
+	m, _ = m.update(common.InspectActionMsg{ContainerID: "bbbb2222", Action: common.InspectStart})
+	m.panes[1].FlushRender()
+	if strings.Contains(m.panes[1].GetPlainTextLogs(), "Starting container") {
+		t.Fatalf("expected start to be refused while the container runs")
+	}
+}
@@ -4,8 +4,9 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
+	"unicode/utf8"
 
-	"cm/internal/debug"
 	"cm/internal/docker"
 	"cm/internal/ui/common"
 
@@ -15,120 +16,285 @@ import (
 	"github.com/muesli/reflow/wrap"
 )
 
-// Regex patterns for terminal control sequences to strip
-var (
-	// RIS - Reset to Initial State (ESC c) - THIS IS THE DANGEROUS ONE!
-	risRe = regexp.MustCompile(`\x1bc`)
-	// Screen clearing: CSI n J (ED - Erase in Display)
-	clearScreenRe = regexp.MustCompile(`\x1b\[\d*J`)
-	// Line clearing: CSI n K (EL - Erase in Line)
-	clearLineRe = regexp.MustCompile(`\x1b\[\d*K`)
-	// Cursor movement: CSI n;m H or CSI n;m f (CUP - Cursor Position)
-	cursorPosRe = regexp.MustCompile(`\x1b\[\d*;?\d*[Hf]`)
-	// Cursor up/down/forward/back: CSI n A/B/C/D
-	cursorMoveRe = regexp.MustCompile(`\x1b\[\d*[ABCD]`)
-	// Cursor horizontal absolute: CSI n G (CHA - move to column n)
-	cursorColumnRe = regexp.MustCompile(`\x1b\[\d*G`)
-	// Cursor save/restore: CSI s / CSI u or ESC 7 / ESC 8
-	cursorSaveRestoreRe = regexp.MustCompile(`\x1b\[?[su78]`)
-	// Scroll up/down: CSI n S / CSI n T
-	scrollRe = regexp.MustCompile(`\x1b\[\d*[ST]`)
-	// Set mode / Reset mode (including alt screen, cursor visibility): CSI ? n h / CSI ? n l
-	modeRe = regexp.MustCompile(`\x1b\[\??\d*[hl]`)
-	// Window manipulation: CSI n ; n ; n t
-	windowRe = regexp.MustCompile(`\x1b\[\d*;\d*;\d*t`)
-	// OSC sequences (title changes, etc.): ESC ] ... BEL or ESC ] ... ST
-	oscRe = regexp.MustCompile(`\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
-	// Carriage return that could cause line overwriting
-	carriageReturnRe = regexp.MustCompile(`\r`)
-	// Device status reports and other CSI sequences we don't need
-	miscCsiRe = regexp.MustCompile(`\x1b\[\d*[nqp]`)
-	// Catch-all for other CSI sequences (except SGR which uses 'm')
-	// This catches things like CSI ? sequences, CSI > sequences, etc.
-	otherCsiRe = regexp.MustCompile(`\x1b\[[?>=!]?[\d;]*[^m\d;]`)
-	// DCS (Device Control String) sequences
-	dcsRe = regexp.MustCompile(`\x1bP[^\x1b]*\x1b\\`)
-	// APC (Application Program Command) sequences
-	apcRe = regexp.MustCompile(`\x1b_[^\x1b]*\x1b\\`)
-	// PM (Privacy Message) sequences
-	pmRe = regexp.MustCompile(`\x1b\^[^\x1b]*\x1b\\`)
-	// SOS (Start of String) sequences
-	sosRe = regexp.MustCompile(`\x1bX[^\x1b]*\x1b\\`)
-	// SGR (color/style) sequences - we KEEP these for syntax highlighting
-	// The ansiReset at end of each line prevents bleeding into borders
-	// Single-character ESC sequences (like ESC c, ESC D, ESC M, etc.)
-	singleEscRe = regexp.MustCompile(`\x1b[cDEHMNOPVWXZ7-9=>]`)
+// Bytes the escape sequence scanner cares about
+const (
+	escByte = 0x1b
+	belByte = 0x07
+	delByte = 0x7f
 )
 
+// maxLineLength is the byte budget for a single sanitized log line
+const maxLineLength = 1000
+
 // sanitizeLogContent removes terminal control sequences that would mess up the viewport
-// Also strips color codes - we apply our own consistent styling
+// SGR (color/style) sequences are kept for syntax highlighting
 func sanitizeLogContent(content string) string {
-	// CRITICAL: Strip RIS (Reset to Initial State) first - this is the most dangerous!
-	content = risRe.ReplaceAllString(content, "")
-	// Strip other single-character ESC sequences
-	content = singleEscRe.ReplaceAllString(content, "")
-
-	// Strip problematic sequences
-	content = clearScreenRe.ReplaceAllString(content, "")
-	content = clearLineRe.ReplaceAllString(content, "")
-	content = cursorPosRe.ReplaceAllString(content, "")
-	content = cursorMoveRe.ReplaceAllString(content, "")
-	content = cursorColumnRe.ReplaceAllString(content, "")
-	content = cursorSaveRestoreRe.ReplaceAllString(content, "")
-	content = scrollRe.ReplaceAllString(content, "")
-	content = modeRe.ReplaceAllString(content, "")
-	content = windowRe.ReplaceAllString(content, "")
-	content = oscRe.ReplaceAllString(content, "")
-	content = miscCsiRe.ReplaceAllString(content, "")
-	content = carriageReturnRe.ReplaceAllString(content, "")
-
-	// Additional sequences that might cause issues
-	content = dcsRe.ReplaceAllString(content, "")
-	content = apcRe.ReplaceAllString(content, "")
-	content = pmRe.ReplaceAllString(content, "")
-	content = sosRe.ReplaceAllString(content, "")
+	// Carriage returns overwrite the line in a terminal - keep what would be visible
+	content = strings.TrimRight(content, "\r")
+	if idx := strings.LastIndexByte(content, '\r'); idx >= 0 {
+		content = content[idx+1:]
+	}
 
 	// NOTE: We keep SGR (color/style) sequences for syntax highlighting
 	// The ansiReset at end of each line in renderLogs() prevents bleeding
-
-	// Catch-all for other CSI sequences (must be last CSI-related)
-	content = otherCsiRe.ReplaceAllString(content, "")
+	content = stripControlSequences(content)
 
 	// Trim any leading/trailing whitespace that might result
 	content = strings.TrimRight(content, " \t")
 
 	// Limit line length to prevent rendering issues with very long lines
-	if len(content) > 1000 {
-		content = content[:1000] + "..."
+	if len(content) > maxLineLength {
+		content = truncateLogContent(content, maxLineLength) + "..."
 	}
 
 	return content
 }
 
+// stripControlSequences drops every escape sequence except SGR, plus C0
+// control characters other than tab and newline, in a single pass. Lines
+// without anything to drop are returned as-is without allocating.
+func stripControlSequences(s string) string {
+	var b strings.Builder
+	dropped := false
+	start := 0 // start of the run of bytes not yet copied to b
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		if (c >= 0x20 && c != delByte) || c == '\t' || c == '\n' {
+			i++
+			continue
+		}
+
+		next, keep := i+1, false
+		if c == escByte {
+			next, keep = scanEscape(s, i)
+		}
+		if keep {
+			i = next
+			continue
+		}
+
+		if !dropped {
+			b.Grow(len(s))
+			dropped = true
+		}
+		b.WriteString(s[start:i])
+		start, i = next, next
+	}
+
+	if !dropped {
+		return s
+	}
+	b.WriteString(s[start:])
+	return b.String()
+}
+
+// scanEscape parses the escape sequence starting at s[i] (an ESC) and returns
+// the index just past it and whether it should be kept. Only complete SGR
+// sequences are kept. A sequence cut off at the end of the line runs to the
+// end; a malformed one ends before the offending byte so it is scanned again.
+func scanEscape(s string, i int) (int, bool) {
+	if i+1 >= len(s) {
+		return len(s), false
+	}
+
+	switch s[i+1] {
+	case '[':
+		// CSI: parameter bytes, intermediate bytes, final byte. SGR is a
+		// final 'm' with plain numeric parameters (not e.g. ESC[>4;2m)
+		j := i + 2
+		sgr := true
+		for j < len(s) && s[j] >= 0x30 && s[j] <= 0x3f {
+			if s[j] > ';' {
+				sgr = false
+			}
+			j++
+		}
+		for j < len(s) && s[j] >= 0x20 && s[j] <= 0x2f {
+			sgr = false
+			j++
+		}
+		if j >= len(s) {
+			return len(s), false
+		}
+		if s[j] >= 0x40 && s[j] <= 0x7e {
+			return j + 1, sgr && s[j] == 'm'
+		}
+		return j, false
+
+	case ']':
+		// OSC (titles, hyperlinks): runs to BEL or ST
+		return scanStringSequence(s, i+2, true), false
+
+	case 'P', '_', '^', 'X':
+		// DCS, APC, PM and SOS: run to ST
+		return scanStringSequence(s, i+2, false), false
+	}
+
+	// Everything else is ESC, optional intermediates (charset designation
+	// like ESC ( B) and a final byte (RIS, cursor save/restore, keypad modes)
+	j := i + 1
+	for j < len(s) && s[j] >= 0x20 && s[j] <= 0x2f {
+		j++
+	}
+	if j >= len(s) {
+		return len(s), false
+	}
+	if s[j] >= 0x30 && s[j] <= 0x7e {
+		return j + 1, false
+	}
+	return j, false
+}
+
+// scanStringSequence returns the index just past the string terminator (ST,
+// or BEL when allowed) of a string sequence whose payload starts at s[j]. An
+// ESC that doesn't start ST ends the sequence so it can be scanned again.
+func scanStringSequence(s string, j int, bel bool) int {
+	for ; j < len(s); j++ {
+		switch s[j] {
+		case belByte:
+			if bel {
+				return j + 1
+			}
+		case escByte:
+			if j+1 < len(s) && s[j+1] == '\\' {
+				return j + 2
+			}
+			return j
+		}
+	}
+	return len(s)
+}
+
+// truncateLogContent cuts sanitized content to at most maxBytes without
+// splitting a UTF-8 rune or leaving a partial SGR sequence at the end
+func truncateLogContent(content string, maxBytes int) string {
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+	content = content[:cut]
+	if idx := strings.LastIndexByte(content, escByte); idx >= 0 && strings.IndexByte(content[idx:], 'm') < 0 {
+		content = content[:idx]
+	}
+	return content
+}
+
 const maxLogLines = 1000
 
+// StreamFilter selects which output streams a pane shows
+type StreamFilter int
+
+const (
+	StreamBoth   StreamFilter = iota // stdout and stderr
+	StreamStdout                     // stdout only
+	StreamStderr                     // stderr only
+)
+
+// ParseStreamFilter converts a config value ("both", "stdout", "stderr") to a
+// StreamFilter, defaulting to both
+func ParseStreamFilter(s string) StreamFilter {
+	switch s {
+	case "stdout":
+		return StreamStdout
+	case "stderr":
+		return StreamStderr
+	}
+	return StreamBoth
+}
+
+// Next returns the filter that follows f in the toggle cycle
+func (f StreamFilter) Next() StreamFilter {
+	return (f + 1) % 3
+}
+
+// String returns the config name of the filter
+func (f StreamFilter) String() string {
+	switch f {
+	case StreamStdout:
+		return "stdout"
+	case StreamStderr:
+		return "stderr"
+	}
+	return "both"
+}
+
+// Allows reports whether a line from stream is shown. System messages are
+// always shown.
+func (f StreamFilter) Allows(stream string) bool {
+	switch f {
+	case StreamStdout:
+		return stream != "stderr"
+	case StreamStderr:
+		return stream != "stdout"
+	}
+	return true
+}
+
 // Pane represents a single log pane
 type Pane struct {
 	ID        string
 	Container docker.Container
 	Viewport  viewport.Model
-	LogLines  []docker.LogLine
+	LogLines  logRing // lines shown, after the stream filter
 	Active    bool
 	Connected bool
+	// Set while a reconnect attempt is in flight
+	reconnecting bool
+	// Show project/service in the title (another pane has the same service name)
+	qualifyName bool
+	// Add the replica number to the title (another pane shows the same service)
+	showReplica bool
+	// Kept as a placeholder after its container went away (freeze layout)
+	exited bool
 	// Cached dimensions to avoid re-renders
 	lastWidth  int
 	lastHeight int
 	// Word wrap setting
 	wordWrap bool
+	// Render timestamps in UTC instead of local time
+	utcTimestamps bool
+	// Render timestamps with millisecond precision
+	msTimestamps bool
+	// Leave timestamps out of copied text
+	copyNoTimestamps bool
 	// Horizontal scroll offset (for non-wrapped mode)
 	xOffset int
 	// Pause state
 	Paused       bool
 	pausedBuffer []docker.LogLine
+	// Every buffered line regardless of stream, so the filter can be changed back
+	allLines     logRing
+	streamFilter StreamFilter
+	// Recent arrivals, for the lines/sec indicator in the title
+	rate rateMeter
+	// When the stream started waiting for the first line
+	waitingSince time.Time
+	// When the last container log line arrived
+	lastActivity time.Time
+	// Stderr arrived while the pane wasn't focused; cleared when it is
+	unseenStderr bool
+	// The container was OOM-killed; cleared when the pane reconnects
+	oomKilled bool
+	// An oom event arrived in the container's current run; a die confirms it
+	oomSeen bool
+	// Exit code of the container's last run, once known; cleared when the pane reconnects
+	exitCode  int
+	exitKnown bool
+	// Border is highlighted until then, after jumping to the pane
+	flashUntil time.Time
+	// New lines are waiting for FlushRender to reach the viewport
+	renderPending bool
+	// Number of full viewport re-renders from FlushRender
+	renders int
+	// Rendered text of LogLines, reused across renders
+	cache    renderCache
+	lineBase int // absolute index of LogLines[0] (advances as the buffer is trimmed)
+	linesGen int // bumped when LogLines is replaced
 
 	// Search state
 	searchQuery   string
-	matchIndices  []int // line indices that match
+	matchIndices  []int // absolute line indices (see lineBase) that match
 	currentMatch  int   // index into matchIndices
 
 	// Build mode state
@@ -141,6 +307,7 @@ type Pane struct {
 	activeTab        TabType
 	statsHistory     *StatsHistory
 	processes        []docker.ContainerProcess
+	events           []docker.ContainerEvent // newest last, at most maxPaneEvents
 	containerDetails *docker.ContainerDetails
 	detailsLoaded    bool
 	// Scroll offset for tab content
@@ -158,7 +325,6 @@ func NewPane(container docker.Container, width, height int) Pane {
 		ID:           container.ID,
 		Container:    container,
 		Viewport:     vp,
-		LogLines:     make([]docker.LogLine, 0, maxLogLines),
 		Active:       false,
 		Connected:    true,
 		lastWidth:    width,
@@ -186,26 +352,113 @@ func (p *Pane) AddLogLine(line docker.LogLine) {
 		return
 	}
 
+	// Strip SGR once here rather than on every render, search and copy
+	line.Plain = stripANSI(line.Content)
+
+	if line.Stream != "system" {
+		now := time.Now()
+		p.rate.Record(now)
+		p.lastActivity = now
+		if line.Stream == "stderr" && !p.Active {
+			p.unseenStderr = true
+		}
+	}
+
 	// If paused, buffer the log line instead of displaying it
 	if p.Paused {
 		p.pausedBuffer = append(p.pausedBuffer, line)
 		// Cap buffer size to prevent memory issues
-		if len(p.pausedBuffer) > maxLogLines {
-			p.pausedBuffer = p.pausedBuffer[len(p.pausedBuffer)-maxLogLines:]
+		if limit := p.LogLines.Cap(); len(p.pausedBuffer) > limit {
+			p.pausedBuffer = p.pausedBuffer[len(p.pausedBuffer)-limit:]
 		}
 		return
 	}
 
-	p.LogLines = append(p.LogLines, line)
-
-	// Trim if too many lines
-	if len(p.LogLines) > maxLogLines {
-		p.LogLines = p.LogLines[len(p.LogLines)-maxLogLines:]
+	if !p.appendLine(line) {
+		return
 	}
 
-	// Update viewport content
+	// Re-rendering the whole buffer per line is too slow under a flood, so
+	// the viewport is refreshed in batches by FlushRender
+	p.renderPending = true
+}
+
+// FlushRender re-renders the viewport if lines were added since the last
+// flush, and reports whether it did
+func (p *Pane) FlushRender() bool {
+	if !p.renderPending {
+		return false
+	}
+	p.renderPending = false
+	p.renders++
 	p.Viewport.SetContent(p.renderLogs())
 	p.Viewport.GotoBottom()
+	return true
+}
+
+// appendLine buffers a line and, if the stream filter allows it, adds it to
+// the shown lines. It reports whether the shown lines changed.
+func (p *Pane) appendLine(line docker.LogLine) bool {
+	p.allLines.Push(line)
+
+	if !p.streamFilter.Allows(line.Stream) {
+		return false
+	}
+	if p.LogLines.Push(line) {
+		p.lineBase++
+	}
+	return true
+}
+
+// SetBufferSize sets how many log lines the pane keeps. It must be called
+// before any lines are added.
+func (p *Pane) SetBufferSize(lines int) {
+	p.LogLines = logRing{size: lines}
+	p.allLines = logRing{size: lines}
+}
+
+// resetLogLines empties the buffer
+func (p *Pane) resetLogLines() {
+	p.LogLines.Reset()
+	p.allLines.Reset()
+	p.linesGen++
+}
+
+// SetStreamFilter changes which streams are shown and re-renders the existing buffer
+func (p *Pane) SetStreamFilter(f StreamFilter) {
+	p.streamFilter = f
+	p.LogLines.Reset()
+	p.linesGen++
+	for i := 0; i < p.allLines.Len(); i++ {
+		if line := p.allLines.At(i); f.Allows(line.Stream) {
+			p.LogLines.Push(line)
+		}
+	}
+
+	if p.searchQuery != "" {
+		p.SetSearch(p.searchQuery)
+	} else {
+		p.Viewport.SetContent(p.renderLogs())
+	}
+	p.Viewport.GotoBottom()
+}
+
+// LogRate returns the recent log rate in lines per second
+func (p *Pane) LogRate() float64 {
+	return p.rate.Rate(time.Now())
+}
+
+// StreamFilter returns the pane's current stream filter
+func (p *Pane) StreamFilter() StreamFilter {
+	return p.streamFilter
+}
+
+// streamFilterLabel returns the title suffix for a narrowed stream filter
+func (p *Pane) streamFilterLabel() string {
+	if p.streamFilter == StreamBoth {
+		return ""
+	}
+	return " [" + p.streamFilter.String() + "]"
 }
 
 // TogglePause toggles the pause state of the pane
@@ -214,15 +467,7 @@ func (p *Pane) TogglePause() bool {
 
 	if !p.Paused {
 		// Flush buffered logs when unpausing
-		for _, line := range p.pausedBuffer {
-			p.LogLines = append(p.LogLines, line)
-		}
-		// Trim if too many lines
-		if len(p.LogLines) > maxLogLines {
-			p.LogLines = p.LogLines[len(p.LogLines)-maxLogLines:]
-		}
-		// Clear buffer
-		p.pausedBuffer = nil
+		p.flushPaused()
 		// Update viewport
 		p.Viewport.SetContent(p.renderLogs())
 		p.Viewport.GotoBottom()
@@ -231,6 +476,20 @@ func (p *Pane) TogglePause() bool {
 	return p.Paused
 }
 
+// flushPaused moves lines held back while paused into the buffer and
+// returns how many there were
+func (p *Pane) flushPaused() int {
+	n := len(p.pausedBuffer)
+	for _, line := range p.pausedBuffer {
+		p.appendLine(line)
+	}
+	p.pausedBuffer = nil
+	if n > 0 {
+		p.renderPending = true
+	}
+	return n
+}
+
 // SetSearch sets the search query and finds matches
 func (p *Pane) SetSearch(query string) (matchCount int) {
 	p.searchQuery = query
@@ -244,9 +503,9 @@ func (p *Pane) SetSearch(query string) (matchCount int) {
 
 	// Find matching lines
 	queryLower := strings.ToLower(query)
-	for i, line := range p.LogLines {
-		if strings.Contains(strings.ToLower(line.Content), queryLower) {
-			p.matchIndices = append(p.matchIndices, i)
+	for i := 0; i < p.LogLines.Len(); i++ {
+		if strings.Contains(strings.ToLower(p.LogLines.At(i).Content), queryLower) {
+			p.matchIndices = append(p.matchIndices, p.lineBase+i)
 		}
 	}
 
@@ -346,30 +605,14 @@ func (p *Pane) jumpToMatch(matchIdx int) {
 		return
 	}
 
-	lineIdx := p.matchIndices[matchIdx]
-
-	// Calculate the display line (accounting for word wrap)
-	displayLine := lineIdx
-	if p.wordWrap {
-		// In word wrap mode, we need to count wrapped lines
-		displayLine = 0
-		const timestampWidth = 9
-		contentWidth := p.Viewport.Width - timestampWidth - 1
-		if contentWidth < 10 {
-			contentWidth = 10
-		}
-		for i := 0; i < lineIdx && i < len(p.LogLines); i++ {
-			content := stripANSI(p.LogLines[i].Content)
-			lines := (len(content) + contentWidth - 1) / contentWidth
-			if lines < 1 {
-				lines = 1
-			}
-			displayLine += lines
-		}
+	// The match may have rolled out of the buffer since the search ran
+	lineIdx := p.matchIndices[matchIdx] - p.lineBase
+	if lineIdx < 0 {
+		lineIdx = 0
 	}
 
 	// Center the match in the viewport
-	offset := displayLine - p.Viewport.Height/2
+	offset := p.displayLineOf(lineIdx) - p.Viewport.Height/2
 	if offset < 0 {
 		offset = 0
 	}
@@ -379,17 +622,133 @@ func (p *Pane) jumpToMatch(matchIdx int) {
 	p.Viewport.SetContent(p.renderLogsWithSearch())
 }
 
+// wrappedLines returns how many display lines log line i takes
+func (p *Pane) wrappedLines(i, contentWidth int) int {
+	if !p.wordWrap {
+		return 1
+	}
+	content := plainContent(p.LogLines.At(i))
+	return max((len(content)+contentWidth-1)/contentWidth, 1)
+}
+
+// displayLineOf returns the display line log line lineIdx starts on,
+// accounting for word wrap
+func (p *Pane) displayLineOf(lineIdx int) int {
+	if !p.wordWrap {
+		return lineIdx
+	}
+	contentWidth := p.contentWidth()
+	displayLine := 0
+	for i := 0; i < lineIdx && i < p.LogLines.Len(); i++ {
+		displayLine += p.wrappedLines(i, contentWidth)
+	}
+	return displayLine
+}
+
+// TopLineTime returns the timestamp of the log line at the top of the viewport
+func (p *Pane) TopLineTime() (time.Time, bool) {
+	contentWidth := p.contentWidth()
+	displayLine := 0
+	for i := 0; i < p.LogLines.Len(); i++ {
+		displayLine += p.wrappedLines(i, contentWidth)
+		if displayLine > p.Viewport.YOffset {
+			// Unstamped lines take the time of the next stamped one
+			for ; i < p.LogLines.Len(); i++ {
+				if ts := p.LogLines.At(i).Timestamp; !ts.IsZero() {
+					return ts, true
+				}
+			}
+			break
+		}
+	}
+	return time.Time{}, false
+}
+
+// JumpToTime scrolls the viewport so the first line logged at or after t is
+// at the top, or to the bottom when every line is older
+func (p *Pane) JumpToTime(t time.Time) {
+	for i := 0; i < p.LogLines.Len(); i++ {
+		if ts := p.LogLines.At(i).Timestamp; !ts.IsZero() && !ts.Before(t) {
+			p.Viewport.SetYOffset(p.displayLineOf(i))
+			return
+		}
+	}
+	p.Viewport.GotoBottom()
+}
+
+// markExitCode records how the container exited and notes it in the logs,
+// once per run
+func (p *Pane) markExitCode(code int, at time.Time) {
+	if p.exitKnown {
+		return
+	}
+	p.exitCode = code
+	p.exitKnown = true
+	p.AddLogLine(docker.LogLine{
+		ContainerID: p.ID,
+		Timestamp:   at,
+		Stream:      "system",
+		Content:     fmt.Sprintf("--- Container exited with code %d ---", code),
+	})
+}
+
+// exitBadge returns the title's exit code, green for a clean exit and red otherwise
+func (p *Pane) exitBadge() string {
+	if !p.exitKnown {
+		return ""
+	}
+	style := common.StoppedStyle
+	if p.exitCode == 0 {
+		style = common.RunningStyle
+	}
+	return " " + style.Render(fmt.Sprintf("exit %d", p.exitCode))
+}
+
+// oomBadge returns the title warning for an OOM-killed container
+func (p *Pane) oomBadge() string {
+	if !p.oomKilled {
+		return ""
+	}
+	return " " + common.StoppedStyle.Bold(true).Render("OOM-KILLED")
+}
+
+// oomNote returns a clause for system messages about an OOM-killed container
+func (p *Pane) oomNote() string {
+	if !p.oomKilled {
+		return ""
+	}
+	return " (OOM-killed)"
+}
+
+// noOutputHintDelay is how long an empty pane says it is waiting before
+// suggesting the container may not log at all
+const noOutputHintDelay = 10 * time.Second
+
+// emptyState renders the message shown while the pane has no log lines
+func (p *Pane) emptyState() string {
+	switch p.Container.State {
+	case "exited", "dead":
+		return common.SubtitleStyle.Render("Container is " + p.Container.State + " and wrote no logs")
+	case "created":
+		return common.SubtitleStyle.Render("Container was created but never started")
+	}
+	if !p.waitingSince.IsZero() && time.Since(p.waitingSince) >= noOutputHintDelay {
+		return common.SubtitleStyle.Render("No output yet — container may not log to stdout/stderr")
+	}
+	return common.SubtitleStyle.Render("Waiting for logs...")
+}
+
 // renderLogsWithSearch renders log lines with search highlighting
 func (p *Pane) renderLogsWithSearch() string {
-	if len(p.LogLines) == 0 {
-		return common.SubtitleStyle.Render("Waiting for logs...")
+	if p.LogLines.Len() == 0 {
+		return p.emptyState()
 	}
 
 	if p.searchQuery == "" {
 		return p.renderLogs()
 	}
 
-	const timestampWidth = 9
+	timestampWidth := p.timestampWidth()
 	contentWidth := p.Viewport.Width - timestampWidth - 1
 	if contentWidth < 10 {
 		contentWidth = 10
@@ -410,13 +769,14 @@ func (p *Pane) renderLogsWithSearch() string {
 	queryLower := strings.ToLower(p.searchQuery)
 	currentMatchLine := -1
 	if p.currentMatch > 0 && p.currentMatch <= len(p.matchIndices) {
-		currentMatchLine = p.matchIndices[p.currentMatch-1]
+		currentMatchLine = p.matchIndices[p.currentMatch-1] - p.lineBase
 	}
 
 	var b strings.Builder
 
-	for lineIdx, line := range p.LogLines {
-		plainContent := stripANSI(line.Content)
+	for lineIdx := 0; lineIdx < p.LogLines.Len(); lineIdx++ {
+		line := p.LogLines.At(lineIdx)
+		plainContent := plainContent(line)
 		isCurrentMatch := lineIdx == currentMatchLine
 		hasMatch := strings.Contains(strings.ToLower(plainContent), queryLower)
 
@@ -442,14 +802,14 @@ func (p *Pane) renderLogsWithSearch() string {
 			for i, wline := range wrappedLines {
 				var ts string
 				if i == 0 {
-					ts = common.TimestampStyle.Render(line.Timestamp.Format("15:04:05"))
+					ts = common.TimestampStyle.Render(p.formatTimestamp(line.Timestamp))
 				} else {
-					ts = strings.Repeat(" ", 8)
+					ts = strings.Repeat(" ", p.timestampLen())
 				}
 				b.WriteString(fmt.Sprintf("%s %s%s\n", ts, wline, ansiReset))
 			}
 		} else {
-			ts := common.TimestampStyle.Render(line.Timestamp.Format("15:04:05"))
+			ts := common.TimestampStyle.Render(p.formatTimestamp(line.Timestamp))
 			b.WriteString(fmt.Sprintf("%s %s%s\n", ts, content, ansiReset))
 		}
 	}
@@ -541,9 +901,77 @@ func (p *Pane) SetWordWrap(enabled bool) {
 	p.Viewport.SetContent(p.renderLogs())
 }
 
+// displayName returns the title name, qualified with the compose project
+// when it would otherwise be ambiguous
+func (p *Pane) displayName() string {
+	name := p.Container.DisplayName()
+	if p.qualifyName {
+		name = p.Container.QualifiedName()
+	}
+	if p.showReplica && p.Container.ComposeReplica > 0 {
+		name += fmt.Sprintf(" #%d", p.Container.ComposeReplica)
+	}
+	return name
+}
+
+// SetUTCTimestamps switches timestamps between UTC and local time and re-renders
+func (p *Pane) SetUTCTimestamps(enabled bool) {
+	p.utcTimestamps = enabled
+	p.rerenderTimestamps()
+}
+
+// SetMillisecondTimestamps enables or disables millisecond timestamp precision and re-renders
+func (p *Pane) SetMillisecondTimestamps(enabled bool) {
+	p.msTimestamps = enabled
+	p.rerenderTimestamps()
+}
+
+// rerenderTimestamps re-renders what the pane shows, build output included,
+// after the timestamp format changed
+func (p *Pane) rerenderTimestamps() {
+	if p.buildMode {
+		p.Viewport.SetContent(p.renderBuildLogs())
+		return
+	}
+	p.Viewport.SetContent(p.renderLogs())
+}
+
+// formatTimestamp formats a log timestamp for display and copy
+func (p *Pane) formatTimestamp(t time.Time) string {
+	if p.utcTimestamps {
+		t = t.UTC()
+	}
+	if p.msTimestamps {
+		return t.Format("15:04:05.000")
+	}
+	return t.Format("15:04:05")
+}
+
+// copyPrefix returns what goes before a line's content in copied text: its
+// timestamp and a space, or nothing when copies leave timestamps out
+func (p *Pane) copyPrefix(line docker.LogLine) string {
+	if p.copyNoTimestamps {
+		return ""
+	}
+	return p.formatTimestamp(line.Timestamp) + " "
+}
+
+// timestampLen returns the display width of a formatted timestamp
+func (p *Pane) timestampLen() int {
+	if p.msTimestamps {
+		return 12
+	}
+	return 8
+}
+
+// timestampWidth returns the width of the timestamp column including the separator space
+func (p *Pane) timestampWidth() int {
+	return p.timestampLen() + 1
+}
+
 // ClearLogs clears all log lines from the pane
 func (p *Pane) ClearLogs() {
-	p.LogLines = make([]docker.LogLine, 0, maxLogLines)
+	p.resetLogLines()
 	p.Viewport.SetContent(p.renderLogs())
 	p.Viewport.GotoTop()
 }
@@ -578,9 +1006,23 @@ func (p *Pane) GetScrollInfo() (yOffset, totalLines, visibleLines int) {
 	return p.Viewport.YOffset, p.Viewport.TotalLineCount(), p.Viewport.Height
 }
 
+// contentArea returns the rows above the first log line and the visible size of
+// the log area, as View or ViewMaximized lays them out
+func (p *Pane) contentArea(maximized bool) (headerRows, width, height int) {
+	if maximized {
+		// Border + tab bar + title line; no scrollbar
+		return 3, p.Viewport.Width, p.Viewport.Height
+	}
+	width = p.Viewport.Width
+	if p.Viewport.TotalLineCount() > p.Viewport.Height {
+		width-- // Scrollbar
+	}
+	return gridHeaderRows, width, p.Viewport.Height
+}
+
 // UpdateSelectionChar re-renders the pane with character-level selection highlighting
 func (p *Pane) UpdateSelectionChar(startLine, startCol, endLine, endCol int) {
-	debug.Log("Pane.UpdateSelectionChar: (%d,%d) to (%d,%d)", startLine, startCol, endLine, endCol)
+	logger.Debug("Pane.UpdateSelectionChar: (%d,%d) to (%d,%d)", startLine, startCol, endLine, endCol)
 	p.Viewport.SetContent(p.renderLogsWithCharSelection(startLine, startCol, endLine, endCol))
 }
 
@@ -642,119 +1084,204 @@ func (p *Pane) renderScrollBar(height, totalLines, offset int) string {
 	return sb.String()
 }
 
-// renderLogs renders all log lines as a string
-func (p *Pane) renderLogs() (result string) {
-	return p.renderLogsWithSelection(-1, -1)
+// renderKey captures the settings rendered lines depend on; the render cache
+// is rebuilt when any of them change
+type renderKey struct {
+	gen      int // bumped when LogLines is replaced rather than appended to
+	width    int
+	xOffset  int
+	wordWrap bool
+	utc      bool
+	millis   bool
 }
 
-// renderLogsWithSelection renders log lines with optional selection highlighting
-func (p *Pane) renderLogsWithSelection(selStartLine, selEndLine int) (result string) {
+// renderCache holds the rendered text of each line in LogLines, so appending
+// a line only renders that line rather than the whole buffer
+type renderCache struct {
+	key   renderKey
+	base  int      // absolute index of lines[0]
+	lines []string // rendered text per log line, newline-terminated
+}
+
+// renderKey returns the current render settings
+func (p *Pane) renderKey() renderKey {
+	return renderKey{
+		gen:      p.linesGen,
+		width:    p.Viewport.Width,
+		xOffset:  p.xOffset,
+		wordWrap: p.wordWrap,
+		utc:      p.utcTimestamps,
+		millis:   p.msTimestamps,
+	}
+}
+
+// renderLogs renders all log lines as a string, reusing the cached rendering
+// of lines that haven't changed
+func (p *Pane) renderLogs() (result string) {
 	// Recover from any panics during rendering
 	defer func() {
 		if r := recover(); r != nil {
+			p.cache = renderCache{}
 			result = common.StderrStyle.Render(fmt.Sprintf("Render error: %v", r))
 		}
 	}()
 
-	if len(p.LogLines) == 0 {
-		return common.SubtitleStyle.Render("Waiting for logs...")
+	if p.LogLines.Len() == 0 {
+		return p.emptyState()
+	}
+
+	c := &p.cache
+	if key := p.renderKey(); c.key != key || c.base > p.lineBase {
+		*c = renderCache{key: key, base: p.lineBase}
+	}
+
+	// Drop lines trimmed from the front of the buffer
+	if drop := p.lineBase - c.base; drop > 0 {
+		if drop >= len(c.lines) {
+			c.lines = c.lines[:0]
+		} else {
+			c.lines = c.lines[drop:]
+		}
+		c.base = p.lineBase
+	}
+
+	// Render only the lines appended since the last call
+	contentWidth := p.contentWidth()
+	for i := len(c.lines); i < p.LogLines.Len(); i++ {
+		rendered, _ := p.renderLine(p.LogLines.At(i), 0, -1, -1, contentWidth)
+		c.lines = append(c.lines, rendered)
 	}
 
-	// Timestamp takes 8 chars (HH:MM:SS) + 1 space
-	const timestampWidth = 9
+	return strings.Join(c.lines, "")
+}
+
+// contentWidth returns the width available for log content next to the timestamp column
+func (p *Pane) contentWidth() int {
+	// Timestamp column (HH:MM:SS or HH:MM:SS.mmm) + 1 space
 	// Reserve 1 extra char for scroll bar (shown when content exceeds viewport)
-	contentWidth := p.Viewport.Width - timestampWidth - 1
+	contentWidth := p.Viewport.Width - p.timestampWidth() - 1
 	if contentWidth < 10 {
 		contentWidth = 10
 	}
+	return contentWidth
+}
 
-	// Selection style (inverted colors)
-	selStyle := lipgloss.NewStyle().Reverse(true)
+// renderLogsWithSelection renders log lines with optional selection highlighting
+func (p *Pane) renderLogsWithSelection(selStartLine, selEndLine int) (result string) {
+	if selStartLine < 0 {
+		return p.renderLogs()
+	}
+
+	// Recover from any panics during rendering
+	defer func() {
+		if r := recover(); r != nil {
+			result = common.StderrStyle.Render(fmt.Sprintf("Render error: %v", r))
+		}
+	}()
 
+	if p.LogLines.Len() == 0 {
+		return p.emptyState()
+	}
+
+	contentWidth := p.contentWidth()
 	var b strings.Builder
 	displayLine := 0 // Track display line for selection highlighting
 
-	for _, line := range p.LogLines {
-		// Get plain content
-		plainContent := stripANSI(line.Content)
-		hasANSIContent := strings.Contains(line.Content, "\x1b[")
+	for i := 0; i < p.LogLines.Len(); i++ {
+		line := p.LogLines.At(i)
+		rendered, n := p.renderLine(line, displayLine, selStartLine, selEndLine, contentWidth)
+		b.WriteString(rendered)
+		displayLine += n
+	}
 
-		// Determine styling based on stream type
-		applyStyle := func(text string) string {
-			switch line.Stream {
-			case "stderr":
-				if !strings.Contains(line.Content, "\x1b[") {
-					return common.StderrStyle.Render(text)
-				}
-				return text
-			case "system":
-				return common.SubtitleStyle.Render(text)
-			default:
-				return text
+	return b.String()
+}
+
+// renderLine renders one log line starting at displayLine, highlighting the
+// display lines within the selection range, and returns the text and the
+// number of display lines it took
+func (p *Pane) renderLine(line docker.LogLine, displayLine, selStartLine, selEndLine, contentWidth int) (string, int) {
+	// Selection style (inverted colors)
+	selStyle := lipgloss.NewStyle().Reverse(true)
+
+	// Get plain content
+	plainContent := plainContent(line)
+	hasANSIContent := strings.Contains(line.Content, "\x1b[")
+
+	// Determine styling based on stream type
+	applyStyle := func(text string) string {
+		switch line.Stream {
+		case "stderr":
+			if !hasANSIContent {
+				return common.StderrStyle.Render(text)
 			}
+			return text
+		case "system":
+			return common.SubtitleStyle.Render(text)
+		default:
+			return text
 		}
+	}
 
-		if p.wordWrap {
-			contentForWrap := plainContent
-			// Preserve source ANSI styling for stdout logs while wrapped.
-			if hasANSIContent && line.Stream == "stdout" {
-				contentForWrap = line.Content
-			}
+	var b strings.Builder
+	if p.wordWrap {
+		contentForWrap := plainContent
+		// Preserve source ANSI styling for stdout logs while wrapped.
+		if hasANSIContent && line.Stream == "stdout" {
+			contentForWrap = line.Content
+		}
 
-			// Word wrap mode: hard wrap content to fit width (breaks long words)
-			wrapped := wrap.String(contentForWrap, contentWidth)
-			wrappedLines := strings.Split(wrapped, "\n")
+		// Word wrap mode: hard wrap content to fit width (breaks long words)
+		wrapped := wrap.String(contentForWrap, contentWidth)
+		wrappedLines := strings.Split(wrapped, "\n")
 
-			for i, wline := range wrappedLines {
-				isSelected := selStartLine >= 0 && displayLine >= selStartLine && displayLine <= selEndLine
+		for i, wline := range wrappedLines {
+			isSelected := selStartLine >= 0 && displayLine+i >= selStartLine && displayLine+i <= selEndLine
 
-				var ts string
-				if i == 0 {
-					ts = common.TimestampStyle.Render(line.Timestamp.Format("15:04:05"))
-					if isSelected {
-						ts = selStyle.Render(line.Timestamp.Format("15:04:05"))
-					}
-				} else {
-					ts = strings.Repeat(" ", 8) // Indent continuation lines
-				}
-
-				styledLine := applyStyle(wline)
+			var ts string
+			if i == 0 {
+				ts = common.TimestampStyle.Render(p.formatTimestamp(line.Timestamp))
 				if isSelected {
-					// Selection styling should operate on printable text only.
-					styledLine = selStyle.Render(stripANSI(wline))
+					ts = selStyle.Render(p.formatTimestamp(line.Timestamp))
 				}
-
-				b.WriteString(fmt.Sprintf("%s %s%s\n", ts, styledLine, ansiReset))
-				displayLine++
+			} else {
+				ts = strings.Repeat(" ", p.timestampLen()) // Indent continuation lines
 			}
-		} else {
-			// Non-wrap mode: apply horizontal scroll offset
-			isSelected := selStartLine >= 0 && displayLine >= selStartLine && displayLine <= selEndLine
 
-			ts := common.TimestampStyle.Render(line.Timestamp.Format("15:04:05"))
+			styledLine := applyStyle(wline)
 			if isSelected {
-				ts = selStyle.Render(line.Timestamp.Format("15:04:05"))
+				// Selection styling should operate on printable text only.
+				styledLine = selStyle.Render(stripANSI(wline))
 			}
 
-			// Apply horizontal scroll offset and clip to viewport width.
-			displayContent := cutPlainByWidth(plainContent, p.xOffset, contentWidth)
+			b.WriteString(fmt.Sprintf("%s %s%s\n", ts, styledLine, ansiReset))
+		}
+		return b.String(), len(wrappedLines)
+	}
 
-			content := applyStyle(displayContent)
-			if isSelected {
-				content = selStyle.Render(displayContent)
-			}
+	// Non-wrap mode: apply horizontal scroll offset
+	isSelected := selStartLine >= 0 && displayLine >= selStartLine && displayLine <= selEndLine
 
-			// Preserve ANSI colors for stdout while clipping to the visible window.
-			if !isSelected && strings.Contains(line.Content, "\x1b[") && line.Stream == "stdout" {
-				content = xansi.Cut(line.Content, p.xOffset, p.xOffset+contentWidth)
-			}
+	ts := common.TimestampStyle.Render(p.formatTimestamp(line.Timestamp))
+	if isSelected {
+		ts = selStyle.Render(p.formatTimestamp(line.Timestamp))
+	}
 
-			b.WriteString(fmt.Sprintf("%s %s%s\n", ts, content, ansiReset))
-			displayLine++
-		}
+	// Apply horizontal scroll offset and clip to viewport width.
+	displayContent := cutPlainByWidth(plainContent, p.xOffset, contentWidth)
+
+	content := applyStyle(displayContent)
+	if isSelected {
+		content = selStyle.Render(displayContent)
 	}
 
-	return b.String()
+	// Preserve ANSI colors for stdout while clipping to the visible window.
+	if !isSelected && hasANSIContent && line.Stream == "stdout" {
+		content = xansi.Cut(line.Content, p.xOffset, p.xOffset+contentWidth)
+	}
+
+	b.WriteString(fmt.Sprintf("%s %s%s\n", ts, content, ansiReset))
+	return b.String(), 1
 }
 
 // renderLogsWithCharSelection renders log lines with character-level selection highlighting
@@ -766,12 +1293,12 @@ func (p *Pane) renderLogsWithCharSelection(selStartLine, selStartCol, selEndLine
 		}
 	}()
 
-	if len(p.LogLines) == 0 {
-		return common.SubtitleStyle.Render("Waiting for logs...")
+	if p.LogLines.Len() == 0 {
+		return p.emptyState()
 	}
 
-	// Timestamp takes 8 chars (HH:MM:SS) + 1 space
-	const timestampWidth = 9
+	// Timestamp column (HH:MM:SS or HH:MM:SS.mmm) + 1 space
+	timestampWidth := p.timestampWidth()
 	contentWidth := p.Viewport.Width - timestampWidth - 1
 	if contentWidth < 10 {
 		contentWidth = 10
@@ -783,9 +1310,10 @@ func (p *Pane) renderLogsWithCharSelection(selStartLine, selStartCol, selEndLine
 	var b strings.Builder
 	displayLine := 0
 
-	for _, line := range p.LogLines {
-		plainContent := stripANSI(line.Content)
-		tsPlain := line.Timestamp.Format("15:04:05")
+	for lineIdx := 0; lineIdx < p.LogLines.Len(); lineIdx++ {
+		line := p.LogLines.At(lineIdx)
+		plainContent := plainContent(line)
+		tsPlain := p.formatTimestamp(line.Timestamp)
 
 		if p.wordWrap {
 			wrapped := wrap.String(plainContent, contentWidth)
@@ -796,7 +1324,7 @@ func (p *Pane) renderLogsWithCharSelection(selStartLine, selStartCol, selEndLine
 				if i == 0 {
 					tsDisplay = tsPlain
 				} else {
-					tsDisplay = strings.Repeat(" ", 8)
+					tsDisplay = strings.Repeat(" ", p.timestampLen())
 				}
 
 				// Build plain line for selection calculation
@@ -836,19 +1364,20 @@ func (p *Pane) renderLogsWithCharSelection(selStartLine, selStartCol, selEndLine
 func (p *Pane) applyCharSelectionPlain(plainLine string, lineNum, selStartLine, selStartCol, selEndLine, selEndCol int, selStyle lipgloss.Style, hasTimestamp bool) string {
 	runes := []rune(plainLine)
 	lineLen := len(runes)
+	tsLen := p.timestampLen()
 
 	// Check if this line is in the selection range
 	if lineNum < selStartLine || lineNum > selEndLine {
 		// No selection - apply normal styling
-		if hasTimestamp && lineLen >= 9 {
-			// Style timestamp (first 8 chars) + space + content
-			ts := common.TimestampStyle.Render(string(runes[:8]))
-			return ts + string(runes[8:])
+		if hasTimestamp && lineLen >= tsLen+1 {
+			// Style timestamp + space + content
+			ts := common.TimestampStyle.Render(string(runes[:tsLen]))
+			return ts + string(runes[tsLen:])
 		}
 		return plainLine
 	}
 
-	debug.Log("applyCharSelectionPlain: lineNum=%d lineLen=%d sel=(%d,%d)-(%d,%d)", lineNum, lineLen, selStartLine, selStartCol, selEndLine, selEndCol)
+	logger.Debug("applyCharSelectionPlain: lineNum=%d lineLen=%d sel=(%d,%d)-(%d,%d)", lineNum, lineLen, selStartLine, selStartCol, selEndLine, selEndCol)
 
 	// Determine selection bounds for this specific line
 	var startCol, endCol int
@@ -877,19 +1406,19 @@ func (p *Pane) applyCharSelectionPlain(plainLine string, lineNum, selStartLine,
 	if endCol > lineLen {
 		endCol = lineLen
 	}
-	debug.Log("applyCharSelectionPlain: after clamp startCol=%d endCol=%d", startCol, endCol)
+	logger.Debug("applyCharSelectionPlain: after clamp startCol=%d endCol=%d", startCol, endCol)
 
 	if startCol >= endCol {
 		// No actual selection on this line - apply normal styling
-		if hasTimestamp && lineLen >= 9 {
-			ts := common.TimestampStyle.Render(string(runes[:8]))
-			return ts + string(runes[8:])
+		if hasTimestamp && lineLen >= tsLen+1 {
+			ts := common.TimestampStyle.Render(string(runes[:tsLen]))
+			return ts + string(runes[tsLen:])
 		}
 		return plainLine
 	}
 
 	// Build the line with selection highlighting
-	// We need to handle the timestamp specially (first 8 chars + space)
+	// We need to handle the timestamp specially (timestamp chars + space)
 	var result strings.Builder
 
 	for i := 0; i < lineLen; i++ {
@@ -897,7 +1426,7 @@ func (p *Pane) applyCharSelectionPlain(plainLine string, lineNum, selStartLine,
 
 		if inSelection {
 			result.WriteString(selStyle.Render(string(runes[i])))
-		} else if hasTimestamp && i < 8 {
+		} else if hasTimestamp && i < tsLen {
 			// Timestamp character (not selected)
 			result.WriteString(common.TimestampStyle.Render(string(runes[i])))
 		} else {
@@ -908,10 +1437,23 @@ func (p *Pane) applyCharSelectionPlain(plainLine string, lineNum, selStartLine,
 	return result.String()
 }
 
+// plainContent returns a line's content without SGR sequences, using the
+// copy stripped at ingest when there is one
+func plainContent(line docker.LogLine) string {
+	if line.Plain == "" && line.Content != "" {
+		return stripANSI(line.Content)
+	}
+	return line.Plain
+}
+
 // stripANSI removes all ANSI escape sequences from a string
 var ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*m`)
 
 func stripANSI(s string) string {
+	// Most lines have no escapes at all; skip the regex for them
+	if strings.IndexByte(s, 0x1b) < 0 {
+		return s
+	}
 	return ansiRe.ReplaceAllString(s, "")
 }
 
@@ -922,28 +1464,56 @@ func cutPlainByWidth(s string, left, width int) string {
 	if left < 0 {
 		left = 0
 	}
-	runes := []rune(s)
-	if left >= len(runes) {
-		return ""
+	// Find the byte offsets of the rune range without converting to []rune,
+	// which allocated twice per line on every render
+	start, end := -1, len(s)
+	n := 0
+	for i := range s {
+		if n == left {
+			start = i
+		}
+		if n == left+width {
+			end = i
+			break
+		}
+		n++
 	}
-	right := left + width
-	if right > len(runes) {
-		right = len(runes)
+	if start < 0 {
+		return ""
 	}
-	return string(runes[left:right])
+	return s[start:end]
 }
 
 // GetPlainTextLogs returns all log lines as plain text (no ANSI codes)
 func (p *Pane) GetPlainTextLogs() string {
-	if len(p.LogLines) == 0 {
+	if p.LogLines.Len() == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for i := 0; i < p.LogLines.Len(); i++ {
+		line := p.LogLines.At(i)
+		b.WriteString(p.copyPrefix(line) + plainContent(line) + "\n")
+	}
+	return b.String()
+}
+
+// GetANSILogs is GetPlainTextLogs with the lines' SGR color codes kept, for
+// pasting into an ANSI-aware terminal. Lines with escapes end in a reset so a
+// color can't bleed into the next line.
+func (p *Pane) GetANSILogs() string {
+	if p.LogLines.Len() == 0 {
 		return ""
 	}
 
 	var b strings.Builder
-	for _, line := range p.LogLines {
-		ts := line.Timestamp.Format("15:04:05")
-		content := stripANSI(line.Content)
-		b.WriteString(fmt.Sprintf("%s %s\n", ts, content))
+	for i := 0; i < p.LogLines.Len(); i++ {
+		line := p.LogLines.At(i)
+		content := line.Content
+		if strings.IndexByte(content, 0x1b) >= 0 {
+			content += "\x1b[0m"
+		}
+		b.WriteString(p.copyPrefix(line) + content + "\n")
 	}
 	return b.String()
 }
@@ -1008,7 +1578,7 @@ func (p *Pane) renderBuildLogs() string {
 		return common.SubtitleStyle.Render("Waiting for output...")
 	}
 
-	const timestampWidth = 9
+	timestampWidth := p.timestampWidth()
 	contentWidth := p.Viewport.Width - timestampWidth - 1
 	if contentWidth < 10 {
 		contentWidth = 10
@@ -1016,7 +1586,7 @@ func (p *Pane) renderBuildLogs() string {
 
 	var b strings.Builder
 	for _, log := range p.buildLogs {
-		ts := common.TimestampStyle.Render(log.Timestamp.Format("15:04:05"))
+		ts := common.TimestampStyle.Render(p.formatTimestamp(log.Timestamp))
 		var content string
 		switch log.Stream {
 		case "stderr":
@@ -1038,7 +1608,7 @@ func (p *Pane) renderBuildLogs() string {
 				if i == 0 {
 					b.WriteString(fmt.Sprintf("%s %s%s\n", ts, wline, ansiReset))
 				} else {
-					b.WriteString(fmt.Sprintf("%s %s%s\n", strings.Repeat(" ", 8), wline, ansiReset))
+					b.WriteString(fmt.Sprintf("%s %s%s\n", strings.Repeat(" ", p.timestampLen()), wline, ansiReset))
 				}
 			}
 		} else {
@@ -1050,7 +1620,7 @@ func (p *Pane) renderBuildLogs() string {
 
 // GetTextInRange returns log lines in a given line range (0-indexed, relative to viewport)
 func (p *Pane) GetTextInRange(startLine, endLine int) string {
-	if len(p.LogLines) == 0 {
+	if p.LogLines.Len() == 0 {
 		return ""
 	}
 
@@ -1063,40 +1633,40 @@ func (p *Pane) GetTextInRange(startLine, endLine int) string {
 	if actualStart < 0 {
 		actualStart = 0
 	}
-	if actualEnd >= len(p.LogLines) {
-		actualEnd = len(p.LogLines) - 1
+	if actualEnd >= p.LogLines.Len() {
+		actualEnd = p.LogLines.Len() - 1
 	}
-	if actualStart > actualEnd || actualStart >= len(p.LogLines) {
+	if actualStart > actualEnd || actualStart >= p.LogLines.Len() {
 		return ""
 	}
 
 	var b strings.Builder
 	for i := actualStart; i <= actualEnd; i++ {
-		line := p.LogLines[i]
-		ts := line.Timestamp.Format("15:04:05")
-		content := stripANSI(line.Content)
-		b.WriteString(fmt.Sprintf("%s %s\n", ts, content))
+		line := p.LogLines.At(i)
+		b.WriteString(p.copyPrefix(line) + plainContent(line) + "\n")
 	}
 	return strings.TrimSuffix(b.String(), "\n")
 }
 
-// GetTextInRangeChar returns selected text with character-level precision
+// GetTextInRangeChar returns selected text with character-level precision.
+// Lines count display lines from the top of the content, not the viewport.
 func (p *Pane) GetTextInRangeChar(startLine, startCol, endLine, endCol int) string {
-	if len(p.LogLines) == 0 {
+	if p.LogLines.Len() == 0 {
 		return ""
 	}
 
 	// Build the display lines (same as render) to match what user sees
-	const timestampWidth = 9
+	timestampWidth := p.timestampWidth()
 	contentWidth := p.Viewport.Width - timestampWidth - 1
 	if contentWidth < 10 {
 		contentWidth = 10
 	}
 
 	var displayLines []string
-	for _, line := range p.LogLines {
-		plainContent := stripANSI(line.Content)
-		ts := line.Timestamp.Format("15:04:05")
+	for lineIdx := 0; lineIdx < p.LogLines.Len(); lineIdx++ {
+		line := p.LogLines.At(lineIdx)
+		plainContent := plainContent(line)
+		ts := p.formatTimestamp(line.Timestamp)
 
 		if p.wordWrap {
 			wrapped := wrap.String(plainContent, contentWidth)
@@ -1105,7 +1675,7 @@ func (p *Pane) GetTextInRangeChar(startLine, startCol, endLine, endCol int) stri
 				if i == 0 {
 					displayLines = append(displayLines, ts+" "+wline)
 				} else {
-					displayLines = append(displayLines, strings.Repeat(" ", 8)+" "+wline)
+					displayLines = append(displayLines, strings.Repeat(" ", p.timestampLen())+" "+wline)
 				}
 			}
 		} else {
@@ -1122,11 +1692,6 @@ func (p *Pane) GetTextInRangeChar(startLine, startCol, endLine, endCol int) stri
 		}
 	}
 
-	// Adjust for viewport scroll offset
-	offset := p.Viewport.YOffset
-	startLine += offset
-	endLine += offset
-
 	// Clamp to valid range
 	if startLine < 0 {
 		startLine = 0
@@ -1142,6 +1707,13 @@ func (p *Pane) GetTextInRangeChar(startLine, startCol, endLine, endCol int) stri
 		return ""
 	}
 
+	// Columns before this hold the timestamp (or its blank padding on
+	// wrapped lines) and are skipped when copies leave timestamps out
+	minCol := 0
+	if p.copyNoTimestamps {
+		minCol = p.timestampLen() + 1
+	}
+
 	// Extract selected text
 	var result strings.Builder
 	for i := startLine; i <= endLine; i++ {
@@ -1171,8 +1743,8 @@ func (p *Pane) GetTextInRangeChar(startLine, startCol, endLine, endCol int) stri
 		}
 
 		// Clamp
-		if lineStartCol < 0 {
-			lineStartCol = 0
+		if lineStartCol < minCol {
+			lineStartCol = minCol
 		}
 		if lineEndCol > lineLen {
 			lineEndCol = lineLen
@@ -1219,7 +1791,7 @@ func (p *Pane) View(width, height int, focused bool) (result string) {
 			opName = "Build"
 		}
 		opName = strings.ToUpper(opName[:1]) + opName[1:]
-		title = fmt.Sprintf("%s %s", opName, p.Container.DisplayName())
+		title = fmt.Sprintf("%s %s", opName, p.displayName())
 
 		// Status indicator based on build status
 		switch p.buildStatus {
@@ -1234,20 +1806,36 @@ func (p *Pane) View(width, height int, focused bool) (result string) {
 		}
 	} else {
 		// Normal mode title
-		title = p.Container.DisplayName()
-		if !p.Connected {
+		title = p.displayName()
+		if p.exited {
+			title += " (exited)"
+		} else if !p.Connected {
 			title += " (disconnected)"
+		} else if p.Container.State == "paused" {
+			title += " (frozen)"
 		}
+		title += p.exitBadge() + p.oomBadge()
+		title += p.streamFilterLabel()
 		if p.Paused {
 			title += " [PAUSED]"
 		}
+		if rate := formatRate(p.LogRate()); rate != "" {
+			title += " · " + rate
+		}
 
 		// Status indicator based on container state
-		if p.Container.State == "running" {
+		if p.exited {
+			status = common.MutedInlineStyle.Render("✕")
+		} else if p.Container.State == "running" {
 			status = common.RunningStyle.Render("●")
+		} else if p.Container.State == "paused" {
+			status = common.PausedStyle.Render("‖")
 		} else {
 			status = common.StoppedStyle.Render("○")
 		}
+		if p.unseenStderr {
+			status += common.StderrStyle.Render("!")
+		}
 	}
 
 	// Inner content width (excluding borders)
@@ -1256,11 +1844,15 @@ func (p *Pane) View(width, height int, focused bool) (result string) {
 		innerWidth = 1
 	}
 
-	// Build title with status
+	// Build title with status (greyed out for exited placeholders)
+	titleColor := lipgloss.Color("252")
+	if p.exited {
+		titleColor = lipgloss.Color("241")
+	}
 	fullTitle := fmt.Sprintf(" %s %s", status, title)
 	fullTitle = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("252")).
+		Foreground(titleColor).
 		Width(innerWidth).
 		MaxWidth(innerWidth).
 		Render(fullTitle)
@@ -1270,6 +1862,9 @@ func (p *Pane) View(width, height int, focused bool) (result string) {
 	if focused {
 		borderStyle = common.PaneActiveBorderStyle
 	}
+	if time.Now().Before(p.flashUntil) {
+		borderStyle = borderStyle.BorderForeground(common.PausedStyle.GetForeground())
+	}
 
 	// Calculate inner height (excluding borders)
 	innerHeight := height - 2
@@ -1338,12 +1933,18 @@ func (p *Pane) GetActiveTab() TabType {
 
 // SetActiveTab sets the active tab
 func (p *Pane) SetActiveTab(tab TabType) {
+	if p.Container.IsComposeLogs() {
+		tab = TabLogs // there's no single container to show stats or config for
+	}
 	p.activeTab = tab
 	p.tabScrollOffset = 0 // Reset scroll when changing tabs
 }
 
 // NextTab cycles to the next tab
 func (p *Pane) NextTab() TabType {
+	if p.Container.IsComposeLogs() {
+		return p.activeTab
+	}
 	p.activeTab = TabType((int(p.activeTab) + 1) % TabCount())
 	p.tabScrollOffset = 0
 	return p.activeTab
@@ -1351,6 +1952,9 @@ func (p *Pane) NextTab() TabType {
 
 // PrevTab cycles to the previous tab
 func (p *Pane) PrevTab() TabType {
+	if p.Container.IsComposeLogs() {
+		return p.activeTab
+	}
 	p.activeTab = TabType((int(p.activeTab) - 1 + TabCount()) % TabCount())
 	p.tabScrollOffset = 0
 	return p.activeTab
@@ -1363,6 +1967,17 @@ func (p *Pane) AddStats(stats docker.ContainerStats) {
 	}
 }
 
+// maxPaneEvents is how many lifecycle events a pane keeps
+const maxPaneEvents = 200
+
+// AddEvent records a lifecycle event of the pane's container
+func (p *Pane) AddEvent(ev docker.ContainerEvent) {
+	p.events = append(p.events, ev)
+	if len(p.events) > maxPaneEvents {
+		p.events = p.events[len(p.events)-maxPaneEvents:]
+	}
+}
+
 // SetProcesses updates the process list
 func (p *Pane) SetProcesses(processes []docker.ContainerProcess) {
 	p.processes = processes
@@ -1434,8 +2049,8 @@ func (p *Pane) renderStatsTab(width, height int) string {
 	var b strings.Builder
 
 	// Render bar charts for CPU and Memory
-	memUsage := FormatBytes(latest.MemoryUsage)
-	memLimit := FormatBytes(latest.MemoryLimit)
+	memUsage := common.FormatBytes(latest.MemoryUsage)
+	memLimit := common.FormatBytes(latest.MemoryLimit)
 
 	bars := RenderBarCharts(latest.CPUPercent, latest.MemoryPercent, memUsage, memLimit, width-4, height-6)
 	b.WriteString(bars)
@@ -1447,7 +2062,7 @@ func (p *Pane) renderStatsTab(width, height int) string {
 
 	b.WriteString(fmt.Sprintf("  %s %s    %s %d\n",
 		labelStyle.Render("Network I/O:"),
-		valueStyle.Render(fmt.Sprintf("%s rx / %s tx", FormatBytes(latest.NetworkRx), FormatBytes(latest.NetworkTx))),
+		valueStyle.Render(fmt.Sprintf("%s rx / %s tx", common.FormatBytes(latest.NetworkRx), common.FormatBytes(latest.NetworkTx))),
 		labelStyle.Render("PIDs:"),
 		latest.PIDs,
 	))
@@ -1560,7 +2175,7 @@ func (p *Pane) renderConfigTab(width, height int) string {
 	lines = append(lines, fmt.Sprintf("  %s  %s", common.StatsLabelStyle.Render("Container ID:"), d.ID))
 	lines = append(lines, fmt.Sprintf("  %s  %s", common.StatsLabelStyle.Render("Name:        "), d.Name))
 	lines = append(lines, fmt.Sprintf("  %s  %s", common.StatsLabelStyle.Render("Image:       "), d.Image))
-	lines = append(lines, fmt.Sprintf("  %s  %s", common.StatsLabelStyle.Render("Status:      "), d.Status))
+	lines = append(lines, fmt.Sprintf("  %s  %s", common.StatsLabelStyle.Render("Status:      "), d.StatusSummary()))
 	lines = append(lines, "")
 
 	// Times
@@ -1702,6 +2317,53 @@ func (p *Pane) renderTopTab(width, height int) string {
 	return b.String()
 }
 
+// renderEventsTab renders the Events tab content, newest event first
+func (p *Pane) renderEventsTab(width, height int) string {
+	if len(p.events) == 0 {
+		return common.SubtitleStyle.Render("  No lifecycle events since the log view opened\n\n  (start, die, oom, health changes... appear here)")
+	}
+
+	var b strings.Builder
+	headerFmt := "  %-12s %-14s %s\n"
+	b.WriteString(fmt.Sprintf(headerFmt,
+		common.TopHeaderStyle.Render("TIME"),
+		common.TopHeaderStyle.Render("EVENT"),
+		common.TopHeaderStyle.Render("DETAIL"),
+	))
+	b.WriteString("  " + strings.Repeat("-", width-4) + "\n")
+
+	rows := height - 5
+	if rows < 1 {
+		rows = 1
+	}
+	startIdx := p.tabScrollOffset
+	if startIdx > len(p.events)-1 {
+		startIdx = len(p.events) - 1
+	}
+	endIdx := startIdx + rows
+	if endIdx > len(p.events) {
+		endIdx = len(p.events)
+	}
+
+	for i := startIdx; i < endIdx; i++ {
+		ev := p.events[len(p.events)-1-i]
+		action := fmt.Sprintf("%-14s", ev.Action)
+		switch {
+		case ev.Action == "oom" || ev.Action == "die" || ev.Action == "kill" || ev.Detail == "unhealthy":
+			action = common.StoppedStyle.Render(action)
+		case ev.Action == "start" || ev.Detail == "healthy":
+			action = common.RunningStyle.Render(action)
+		}
+		b.WriteString(fmt.Sprintf("  %-12s %s %s\n", p.formatTimestamp(ev.Time), action, truncateString(ev.Detail, width-32)))
+	}
+
+	if len(p.events) > rows {
+		b.WriteString(fmt.Sprintf("\n  [%d-%d of %d events] (use arrows to scroll)", startIdx+1, endIdx, len(p.events)))
+	}
+
+	return b.String()
+}
+
 // ViewMaximized renders the pane in maximized mode with tabs
 func (p *Pane) ViewMaximized(width, height int) string {
 	// Tab bar takes 1 line, title takes 1 line, border takes 2 lines
@@ -1711,17 +2373,30 @@ func (p *Pane) ViewMaximized(width, height int) string {
 	var status string
 	if p.Container.State == "running" {
 		status = common.RunningStyle.Render("●")
+	} else if p.Container.State == "paused" {
+		status = common.PausedStyle.Render("‖")
 	} else {
 		status = common.StoppedStyle.Render("○")
 	}
 
-	title := p.Container.DisplayName()
-	if !p.Connected {
+	title := p.displayName()
+	if p.exited {
+		title += " (exited)"
+	} else if !p.Connected {
 		title += " (disconnected)"
+	} else if p.Container.State == "paused" {
+		title += " (frozen)"
+	}
+	title += p.exitBadge() + p.oomBadge()
+	if p.activeTab == TabLogs {
+		title += p.streamFilterLabel()
 	}
 	if p.Paused && p.activeTab == TabLogs {
 		title += " [PAUSED]"
 	}
+	if rate := formatRate(p.LogRate()); rate != "" {
+		title += " · " + rate
+	}
 
 	titleLine := fmt.Sprintf(" %s %s", status, title)
 	titleLine = lipgloss.NewStyle().
@@ -1752,6 +2427,8 @@ func (p *Pane) ViewMaximized(width, height int) string {
 		content = p.renderConfigTab(width-4, contentHeight)
 	case TabTop:
 		content = p.renderTopTab(width-4, contentHeight)
+	case TabEvents:
+		content = p.renderEventsTab(width-4, contentHeight)
 	}
 
 	// Constrain content to height
@@ -1763,7 +2440,7 @@ func (p *Pane) ViewMaximized(width, height int) string {
 
 	// Hint line
 	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-	hint := hintStyle.Render(" [/]:tabs  [1-5]:jump  arrows:scroll  esc:minimize")
+	hint := hintStyle.Render(" [/]:tabs  [1-6]:jump  arrows:scroll  esc:minimize")
 
 	// Combine all parts
 	innerContent := lipgloss.JoinVertical(lipgloss.Left,
@@ -0,0 +1,145 @@
+package logview
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"cm/internal/docker"
+)
+
+// benchBuffers are representative full buffers for the render benchmarks
+var benchBuffers = []struct {
+	name string
+	line func(i int) string
+}{
+	{"plain", func(i int) string {
+		return fmt.Sprintf("request %d handled in %dms path=/api/v1/items/%d status=200", i, i%250, i)
+	}},
+	{"long", func(i int) string {
+		return fmt.Sprintf("payload %d: %s", i, strings.Repeat("abcdefghij", 80))
+	}},
+	{"ansi", func(i int) string {
+		return fmt.Sprintf("\x1b[2m%d\x1b[0m \x1b[1;32mINFO\x1b[0m \x1b[36mhttp\x1b[0m \x1b[33mGET\x1b[0m /api/v1/items/%d \x1b[38;5;208m%dms\x1b[0m", i, i, i%250)
+	}},
+}
+
+// newBenchPane returns a pane with a full buffer of ANSI-colored lines
+func newBenchPane(b *testing.B) *Pane {
+	return newBenchPaneWith(b, func(i int) string { return benchLine(i).Content })
+}
+
+// newBenchPaneWith returns a pane whose buffer is filled with maxLogLines lines from line
+func newBenchPaneWith(b *testing.B, line func(i int) string) *Pane {
+	b.Helper()
+	pane := NewPane(docker.Container{ID: "c1", Name: "bench"}, 120, 40)
+	for i := 0; i < maxLogLines; i++ {
+		pane.AddLogLine(docker.LogLine{ContainerID: "c1", Timestamp: time.Now(), Stream: "stdout", Content: line(i)})
+	}
+	_ = pane.renderLogs()
+	return &pane
+}
+
+func benchLine(i int) docker.LogLine {
+	return docker.LogLine{
+		ContainerID: "c1",
+		Timestamp:   time.Now(),
+		Stream:      "stdout",
+		Content:     fmt.Sprintf("\x1b[32mINFO\x1b[0m request %d handled in %dms path=/api/v1/items/%d status=200", i, i%250, i),
+	}
+}
+
+// BenchmarkRenderLogs measures a full render of the buffer, as after a resize
+// or settings change
+func BenchmarkRenderLogs(b *testing.B) {
+	for _, buf := range benchBuffers {
+		b.Run(buf.name, func(b *testing.B) {
+			pane := newBenchPaneWith(b, buf.line)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				pane.cache = renderCache{}
+				_ = pane.renderLogs()
+			}
+		})
+	}
+}
+
+func BenchmarkRenderLogsWordWrap(b *testing.B) {
+	for _, buf := range benchBuffers {
+		b.Run(buf.name, func(b *testing.B) {
+			pane := newBenchPaneWith(b, buf.line)
+			pane.wordWrap = true
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				pane.cache = renderCache{}
+				_ = pane.renderLogs()
+			}
+		})
+	}
+}
+
+func BenchmarkRenderLogsWithSearch(b *testing.B) {
+	for _, buf := range benchBuffers {
+		b.Run(buf.name, func(b *testing.B) {
+			pane := newBenchPaneWith(b, buf.line)
+			pane.SetSearch("7")
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = pane.renderLogsWithSearch()
+			}
+		})
+	}
+}
+
+func BenchmarkSanitizeLogContent(b *testing.B) {
+	inputs := []struct{ name, content string }{
+		{"plain", benchBuffers[0].line(1)},
+		{"long", benchBuffers[1].line(1)},
+		{"ansi", benchBuffers[2].line(1)},
+		{"hostile", "\x1b]0;title\x07\x1b[2J\x1b[H\x1b[?1049h\x1b[31merror\x1b[0m\x1b[10;5Hmoved\x1bc\rprogress 50%\x1b[K"},
+	}
+	for _, in := range inputs {
+		b.Run(in.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = sanitizeLogContent(in.content)
+			}
+		})
+	}
+}
+
+// BenchmarkGetTextInRangeChar measures copying a selection of the whole buffer
+func BenchmarkGetTextInRangeChar(b *testing.B) {
+	for _, buf := range benchBuffers {
+		b.Run(buf.name, func(b *testing.B) {
+			pane := newBenchPaneWith(b, buf.line)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = pane.GetTextInRangeChar(0, 0, maxLogLines-1, 40)
+			}
+		})
+	}
+}
+
+// BenchmarkRenderLogsAppend measures steady-state streaming: one new line
+// into a full buffer, then a render
+func BenchmarkRenderLogsAppend(b *testing.B) {
+	pane := newBenchPane(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pane.AddLogLine(benchLine(i))
+		_ = pane.renderLogs()
+	}
+}
+
+// BenchmarkRenderLogsAppendUncached is BenchmarkRenderLogsAppend with every
+// line re-rendered, as it was before the render cache
+func BenchmarkRenderLogsAppendUncached(b *testing.B) {
+	pane := newBenchPane(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pane.AddLogLine(benchLine(i))
+		pane.cache = renderCache{}
+		_ = pane.renderLogs()
+	}
+}
@@ -1,6 +1,7 @@
 package logview
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -45,3 +46,241 @@ func TestRenderLogsNoWrapStaysSingleLineAfterToggle(t *testing.T) {
 		t.Fatalf("expected one rendered line in non-wrap mode, got %d", len(lines))
 	}
 }
+
+func TestPlainTextLogsUseUTCTimestampsWhenEnabled(t *testing.T) {
+	pane := NewPane(docker.Container{ID: "c1", Name: "test"}, 80, 12)
+
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("UTC+5", 5*60*60))
+	pane.AddLogLine(docker.LogLine{
+		ContainerID: "c1",
+		Timestamp:   ts,
+		Stream:      "stdout",
+		Content:     "hello",
+	})
+
+	pane.SetUTCTimestamps(true)
+	if text := pane.GetPlainTextLogs(); !strings.HasPrefix(text, "22:04:05 ") {
+		t.Fatalf("expected UTC timestamp in copied text, got %q", text)
+	}
+}
+
+func TestUTCToggleRerendersBuildOutput(t *testing.T) {
+	pane := NewPane(docker.Container{ID: "c1", Name: "test"}, 80, 12)
+	pane.SetBuildMode("Build")
+	pane.AddBuildLog(docker.OperationLog{Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("UTC+5", 5*60*60)), Content: "compiling"})
+
+	pane.SetUTCTimestamps(true)
+	if view := pane.Viewport.View(); !strings.Contains(view, "22:04:05") || !strings.Contains(view, "compiling") {
+		t.Fatalf("expected the build output re-rendered in UTC, got %q", view)
+	}
+}
+
+func TestANSILogsKeepColorsAndResetEachLine(t *testing.T) {
+	pane := NewPane(docker.Container{ID: "c1", Name: "test"}, 80, 12)
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	pane.AddLogLine(docker.LogLine{ContainerID: "c1", Timestamp: ts, Stream: "stdout", Content: "\x1b[31mfailed"})
+	pane.AddLogLine(docker.LogLine{ContainerID: "c1", Timestamp: ts, Stream: "stdout", Content: "plain"})
+	pane.SetUTCTimestamps(true)
+
+	want := "03:04:05 \x1b[31mfailed\x1b[0m\n03:04:05 plain\n"
+	if got := pane.GetANSILogs(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if strings.Contains(pane.GetPlainTextLogs(), "\x1b") {
+		t.Fatalf("expected the plain copy to stay stripped")
+	}
+}
+
+func TestCopiesLeaveTimestampsOutWhenDisabled(t *testing.T) {
+	pane := NewPane(docker.Container{ID: "c1", Name: "test"}, 80, 12)
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	pane.AddLogLine(docker.LogLine{ContainerID: "c1", Timestamp: ts, Stream: "stdout", Content: "first line"})
+	pane.AddLogLine(docker.LogLine{ContainerID: "c1", Timestamp: ts, Stream: "stdout", Content: "\x1b[32msecond\x1b[0m"})
+	pane.copyNoTimestamps = true
+
+	if got := pane.GetPlainTextLogs(); got != "first line\nsecond\n" {
+		t.Fatalf("expected plain copy without timestamps, got %q", got)
+	}
+	if got := pane.GetANSILogs(); got != "first line\n\x1b[32msecond\x1b[0m\x1b[0m\n" {
+		t.Fatalf("expected ANSI copy without timestamps, got %q", got)
+	}
+	if got := pane.GetTextInRange(0, 1); got != "first line\nsecond" {
+		t.Fatalf("expected line range without timestamps, got %q", got)
+	}
+	// A selection dragged from column 0 across both lines keeps only the messages
+	if got := pane.GetTextInRangeChar(0, 0, 1, 15); got != "first line\nsecond" {
+		t.Fatalf("expected char selection without timestamps, got %q", got)
+	}
+}
+
+func TestCharSelectionAccountsForMillisecondTimestamps(t *testing.T) {
+	pane := NewPane(docker.Container{ID: "c1", Name: "test"}, 80, 12)
+	pane.SetMillisecondTimestamps(true)
+
+	pane.AddLogLine(docker.LogLine{
+		ContainerID: "c1",
+		Timestamp:   time.Date(2024, 1, 2, 3, 4, 5, 123000000, time.Local),
+		Stream:      "stdout",
+		Content:     "hello world",
+	})
+
+	// Columns 13-18 cover "hello" after the 12-char timestamp and separator
+	if got := pane.GetTextInRangeChar(0, 13, 0, 18); got != "hello" {
+		t.Fatalf("expected selection to skip wider timestamp, got %q", got)
+	}
+	if got := pane.GetTextInRangeChar(0, 0, 0, 12); got != "03:04:05.123" {
+		t.Fatalf("expected millisecond timestamp, got %q", got)
+	}
+}
+
+func TestStreamFilterHidesAndRestoresBufferedLines(t *testing.T) {
+	pane := NewPane(docker.Container{ID: "c1", Name: "test"}, 80, 12)
+	for _, l := range []struct{ stream, content string }{
+		{"stdout", "out one"},
+		{"stderr", "err one"},
+		{"system", "--- reconnected ---"},
+		{"stdout", "out two"},
+	} {
+		pane.AddLogLine(docker.LogLine{ContainerID: "c1", Timestamp: time.Now(), Stream: l.stream, Content: l.content})
+	}
+
+	pane.SetStreamFilter(StreamStderr)
+	if pane.LogLines.Len() != 2 || pane.LogLines.At(0).Content != "err one" {
+		t.Fatalf("expected stderr and system lines only, got %+v", pane.LogLines.Lines())
+	}
+
+	// New lines respect the filter but are still buffered
+	pane.AddLogLine(docker.LogLine{ContainerID: "c1", Timestamp: time.Now(), Stream: "stdout", Content: "out three"})
+	if pane.LogLines.Len() != 2 {
+		t.Fatalf("expected stdout line to be hidden, got %+v", pane.LogLines.Lines())
+	}
+	if !strings.Contains(pane.View(80, 12, true), "[stderr]") {
+		t.Fatalf("expected filter in pane title")
+	}
+
+	pane.SetStreamFilter(StreamBoth)
+	if pane.LogLines.Len() != 5 || pane.LogLines.At(4).Content != "out three" {
+		t.Fatalf("expected every line back in order, got %+v", pane.LogLines.Lines())
+	}
+}
+
+func TestStreamFilterCycleAndParse(t *testing.T) {
+	f := ParseStreamFilter("")
+	for _, want := range []StreamFilter{StreamStdout, StreamStderr, StreamBoth} {
+		f = f.Next()
+		if f != want {
+			t.Fatalf("expected %v, got %v", want, f)
+		}
+	}
+	for _, f := range []StreamFilter{StreamBoth, StreamStdout, StreamStderr} {
+		if ParseStreamFilter(f.String()) != f {
+			t.Fatalf("expected %q to round-trip", f)
+		}
+	}
+}
+
+func TestRenderCacheMatchesFullRender(t *testing.T) {
+	pane := NewPane(docker.Container{ID: "c1", Name: "test"}, 60, 12)
+	fresh := func() string {
+		pane.cache = renderCache{}
+		return pane.renderLogs()
+	}
+	add := func(i int) {
+		stream := "stdout"
+		if i%3 == 0 {
+			stream = "stderr"
+		}
+		pane.AddLogLine(docker.LogLine{ContainerID: "c1", Timestamp: time.Now(), Stream: stream, Content: fmt.Sprintf("\x1b[3%dmline %d with some text to wrap around\x1b[0m", i%8, i)})
+	}
+
+	for i := 0; i < 10; i++ {
+		add(i)
+		_ = pane.renderLogs()
+	}
+	cached := pane.renderLogs()
+	if cached != fresh() {
+		t.Fatalf("incremental render differs from full render")
+	}
+
+	// Trimming the front of the buffer drops cached lines too
+	for i := 10; i < maxLogLines+25; i++ {
+		add(i)
+		if i%100 == 0 {
+			_ = pane.renderLogs()
+		}
+	}
+	cached = pane.renderLogs()
+	if cached != fresh() {
+		t.Fatalf("incremental render differs from full render after trimming")
+	}
+
+	// Settings changes invalidate the cache
+	pane.SetSize(40, 12)
+	if resized := pane.renderLogs(); resized == cached || resized != fresh() {
+		t.Fatalf("expected cache to be rebuilt after a resize")
+	}
+	pane.ClearLogs()
+	add(0)
+	if strings.Contains(pane.renderLogs(), "line 1000") {
+		t.Fatalf("expected cache to be dropped with the buffer")
+	}
+}
+
+func TestCutPlainByWidth(t *testing.T) {
+	tests := []struct {
+		s           string
+		left, width int
+		want        string
+	}{
+		{"hello world", 0, 5, "hello"},
+		{"hello world", 6, 100, "world"},
+		{"hello", 5, 3, ""},
+		{"hello", 9, 3, ""},
+		{"héllo wörld", 1, 4, "éllo"},
+		{"日本語テキスト", 2, 3, "語テキ"},
+		{"", 0, 10, ""},
+		{"abc", 0, 0, ""},
+	}
+	for _, tt := range tests {
+		if got := cutPlainByWidth(tt.s, tt.left, tt.width); got != tt.want {
+			t.Fatalf("cutPlainByWidth(%q, %d, %d) = %q, want %q", tt.s, tt.left, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestPlainContentIsStrippedOnceAtIngest(t *testing.T) {
+	pane := NewPane(docker.Container{ID: "c1", Name: "test"}, 80, 12)
+	pane.AddLogLine(docker.LogLine{ContainerID: "c1", Timestamp: time.Now(), Stream: "stdout", Content: "\x1b[1;31mfailed\x1b[0m to connect"})
+
+	line := pane.LogLines.At(0)
+	if line.Plain != "failed to connect" {
+		t.Fatalf("expected plain content to be stored at ingest, got %q", line.Plain)
+	}
+	if got := plainContent(line); got != line.Plain {
+		t.Fatalf("expected cached plain content to be used, got %q", got)
+	}
+	// Lines built without going through AddLogLine still work
+	if got := plainContent(docker.LogLine{Content: "\x1b[32mok\x1b[0m"}); got != "ok" {
+		t.Fatalf("expected fallback strip, got %q", got)
+	}
+	if !strings.Contains(pane.GetPlainTextLogs(), "failed to connect") {
+		t.Fatalf("expected copy to use plain content")
+	}
+}
+
+func TestEmptyPaneExplainsMissingOutput(t *testing.T) {
+	pane := NewPane(docker.Container{ID: "c1", Name: "test", State: "running"}, 80, 12)
+	if got := pane.renderLogs(); !strings.Contains(got, "Waiting for logs...") {
+		t.Fatalf("fresh pane shows %q, want the waiting message", got)
+	}
+
+	pane.waitingSince = time.Now().Add(-noOutputHintDelay)
+	if got := pane.renderLogs(); !strings.Contains(got, "may not log to stdout/stderr") {
+		t.Fatalf("pane waiting past the hint delay shows %q", got)
+	}
+
+	pane.Container.State = "exited"
+	if got := pane.renderLogs(); !strings.Contains(got, "Container is exited and wrote no logs") {
+		t.Fatalf("stopped container's pane shows %q", got)
+	}
+}
@@ -0,0 +1,68 @@
+package logview
+
+import (
+	"fmt"
+	"time"
+)
+
+// rateWindow is the number of one-second buckets the log rate is averaged over
+const rateWindow = 10
+
+// rateMeter counts line arrivals in per-second buckets over a sliding window.
+// Recording is O(1) amortised and reading is O(rateWindow), so it stays cheap
+// under heavy log volume.
+type rateMeter struct {
+	buckets [rateWindow]int
+	lastSec int64 // unix second of the newest bucket
+}
+
+// advance moves the window forward to sec, clearing buckets that fell out of it
+func (r *rateMeter) advance(sec int64) {
+	if sec <= r.lastSec {
+		return
+	}
+	gap := sec - r.lastSec
+	if gap > rateWindow {
+		gap = rateWindow
+	}
+	for i := int64(1); i <= gap; i++ {
+		r.buckets[(r.lastSec+i)%rateWindow] = 0
+	}
+	r.lastSec = sec
+}
+
+// Record counts one line arriving at now
+func (r *rateMeter) Record(now time.Time) {
+	sec := now.Unix()
+	r.advance(sec)
+	if sec < r.lastSec-rateWindow+1 {
+		return // clock went backwards past the window
+	}
+	r.buckets[sec%rateWindow]++
+}
+
+// Rate returns the average lines per second over the window ending at now.
+// It doesn't modify the meter, so it is safe to call while rendering.
+func (r *rateMeter) Rate(now time.Time) float64 {
+	from := now.Unix() - rateWindow + 1
+	if oldest := r.lastSec - rateWindow + 1; oldest > from {
+		from = oldest
+	}
+	total := 0
+	for sec := from; sec <= r.lastSec; sec++ {
+		total += r.buckets[sec%rateWindow]
+	}
+	return float64(total) / rateWindow
+}
+
+// formatRate renders a log rate for a pane title, or "" when idle
+func formatRate(rate float64) string {
+	switch {
+	case rate <= 0:
+		return ""
+	case rate < 10:
+		return fmt.Sprintf("%.1f/s", rate)
+	default:
+		return fmt.Sprintf("%.0f/s", rate)
+	}
+}
@@ -0,0 +1,50 @@
+package logview
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateMeterAveragesOverWindow(t *testing.T) {
+	var r rateMeter
+	start := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 50; i++ {
+		r.Record(start)
+	}
+	if got := r.Rate(start); got != 5 {
+		t.Fatalf("expected 50 lines over a 10s window to be 5/s, got %v", got)
+	}
+
+	// Still inside the window
+	if got := r.Rate(start.Add(9 * time.Second)); got != 5 {
+		t.Fatalf("expected rate to hold within the window, got %v", got)
+	}
+	// Fallen out of the window
+	if got := r.Rate(start.Add(10 * time.Second)); got != 0 {
+		t.Fatalf("expected rate to decay to 0, got %v", got)
+	}
+
+	// Buckets are reused once the window moves on
+	later := start.Add(25 * time.Second)
+	r.Record(later)
+	if got := r.Rate(later); got != 0.1 {
+		t.Fatalf("expected stale buckets to be cleared, got %v", got)
+	}
+}
+
+func TestFormatRate(t *testing.T) {
+	tests := []struct {
+		rate float64
+		want string
+	}{
+		{0, ""},
+		{0.1, "0.1/s"},
+		{9.94, "9.9/s"},
+		{120.4, "120/s"},
+	}
+	for _, tt := range tests {
+		if got := formatRate(tt.rate); got != tt.want {
+			t.Fatalf("formatRate(%v) = %q, want %q", tt.rate, got, tt.want)
+		}
+	}
+}
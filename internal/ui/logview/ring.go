@@ -0,0 +1,67 @@
+package logview
+
+import "cm/internal/docker"
+
+// logRing is a fixed-size circular buffer of log lines. Once it holds
+// Cap lines, each push overwrites the oldest one in place, so the
+// buffer never reallocates and its memory is bounded exactly. The zero value
+// is an empty ring; storage is allocated on the first push.
+type logRing struct {
+	buf  []docker.LogLine
+	head int // index in buf of the oldest line
+	n    int // number of lines held
+	size int // capacity; maxLogLines when zero
+}
+
+// Cap returns the number of lines the ring holds before dropping the oldest
+func (r *logRing) Cap() int {
+	if r.size > 0 {
+		return r.size
+	}
+	return maxLogLines
+}
+
+// Len returns the number of lines in the ring
+func (r *logRing) Len() int {
+	return r.n
+}
+
+// At returns the i-th line, oldest first
+func (r *logRing) At(i int) docker.LogLine {
+	if i < 0 || i >= r.n {
+		panic("logRing: index out of range")
+	}
+	return r.buf[(r.head+i)%len(r.buf)]
+}
+
+// Push appends a line, overwriting the oldest one when the ring is full, and
+// reports whether a line was dropped
+func (r *logRing) Push(line docker.LogLine) bool {
+	if r.buf == nil {
+		r.buf = make([]docker.LogLine, r.Cap())
+	}
+	if r.n < len(r.buf) {
+		r.buf[(r.head+r.n)%len(r.buf)] = line
+		r.n++
+		return false
+	}
+	r.buf[r.head] = line
+	r.head = (r.head + 1) % len(r.buf)
+	return true
+}
+
+// Reset empties the ring, keeping its storage
+func (r *logRing) Reset() {
+	clear(r.buf) // release the lines' strings
+	r.head = 0
+	r.n = 0
+}
+
+// Lines returns a copy of the lines, oldest first
+func (r *logRing) Lines() []docker.LogLine {
+	lines := make([]docker.LogLine, r.n)
+	for i := range lines {
+		lines[i] = r.At(i)
+	}
+	return lines
+}
@@ -0,0 +1,100 @@
+package logview
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"cm/internal/docker"
+)
+
+func TestLogRingWrapsAround(t *testing.T) {
+	var r logRing
+	line := func(i int) docker.LogLine { return docker.LogLine{Content: fmt.Sprintf("line %d", i)} }
+
+	for i := 0; i < maxLogLines; i++ {
+		if r.Push(line(i)) {
+			t.Fatalf("expected no drop before the ring is full (push %d)", i)
+		}
+	}
+	storage := &r.buf[0]
+
+	// Go around more than once so head passes the end of the storage twice
+	total := 2*maxLogLines + 37
+	for i := maxLogLines; i < total; i++ {
+		if !r.Push(line(i)) {
+			t.Fatalf("expected the oldest line to be dropped once full (push %d)", i)
+		}
+	}
+
+	if r.Len() != maxLogLines {
+		t.Fatalf("expected %d lines, got %d", maxLogLines, r.Len())
+	}
+	if &r.buf[0] != storage {
+		t.Fatalf("expected the ring to reuse its storage")
+	}
+	first := total - maxLogLines
+	for i := 0; i < r.Len(); i++ {
+		if want := fmt.Sprintf("line %d", first+i); r.At(i).Content != want {
+			t.Fatalf("At(%d) = %q, want %q", i, r.At(i).Content, want)
+		}
+	}
+	if lines := r.Lines(); len(lines) != maxLogLines || lines[0].Content != fmt.Sprintf("line %d", first) {
+		t.Fatalf("expected Lines to return the ring oldest first")
+	}
+
+	r.Reset()
+	if r.Len() != 0 {
+		t.Fatalf("expected an empty ring after reset, got %d lines", r.Len())
+	}
+	r.Push(line(7))
+	if r.Len() != 1 || r.At(0).Content != "line 7" {
+		t.Fatalf("expected a reset ring to start over")
+	}
+}
+
+func TestSearchMatchesSurviveBufferWraparound(t *testing.T) {
+	pane := NewPane(docker.Container{ID: "c1", Name: "test"}, 80, 12)
+	add := func(content string) {
+		pane.AddLogLine(docker.LogLine{ContainerID: "c1", Timestamp: time.Now(), Stream: "stdout", Content: content})
+	}
+
+	for i := 0; i < maxLogLines+50; i++ {
+		add(fmt.Sprintf("line %d", i))
+	}
+	if n := pane.SetSearch("needle"); n != 0 {
+		t.Fatalf("expected no matches yet, got %d", n)
+	}
+
+	add("needle here")
+	if n := pane.SetSearch("needle"); n != 1 {
+		t.Fatalf("expected one match, got %d", n)
+	}
+	// More lines push the buffer along; the match must still point at the same line
+	for i := 0; i < 10; i++ {
+		add(fmt.Sprintf("after %d", i))
+	}
+	pane.JumpToFirstMatch()
+
+	idx := pane.matchIndices[0] - pane.lineBase
+	if got := pane.LogLines.At(idx).Content; got != "needle here" {
+		t.Fatalf("expected match to track its line across wraparound, got %q", got)
+	}
+	if text := pane.GetTextInRange(0, pane.Viewport.Height); text == "" {
+		t.Fatalf("expected visible text after wraparound")
+	}
+}
+
+func TestPaneBufferSizeLimitsLines(t *testing.T) {
+	pane := NewPane(docker.Container{ID: "c1", Name: "test"}, 80, 12)
+	pane.SetBufferSize(150)
+	for i := 0; i < 400; i++ {
+		pane.AddLogLine(docker.LogLine{ContainerID: "c1", Timestamp: time.Now(), Stream: "stdout", Content: fmt.Sprintf("line %d", i)})
+	}
+	if pane.LogLines.Len() != 150 {
+		t.Fatalf("expected 150 lines, got %d", pane.LogLines.Len())
+	}
+	if got := pane.LogLines.At(0).Content; got != "line 250" {
+		t.Fatalf("expected the oldest kept line to be line 250, got %q", got)
+	}
+}
@@ -0,0 +1,142 @@
+package logview
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitizeLogContent(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text", "hello world", "hello world"},
+		{"trailing whitespace", "hello \t ", "hello"},
+		{"RIS reset", "before\x1bcafter", "beforeafter"},
+		{"clear screen", "\x1b[2Jhello", "hello"},
+		{"clear line", "hello\x1b[K", "hello"},
+		{"cursor position", "\x1b[10;20Hhello", "hello"},
+		{"cursor home", "\x1b[Hhello", "hello"},
+		{"cursor up", "\x1b[3Ahello", "hello"},
+		{"cursor column", "\x1b[1Ghello", "hello"},
+		{"cursor save restore CSI", "\x1b[shello\x1b[u", "hello"},
+		{"cursor save restore ESC", "\x1b7hello\x1b8", "hello"},
+		{"scroll", "\x1b[2Shello\x1b[1T", "hello"},
+		{"alt screen", "\x1b[?1049hhello\x1b[?1049l", "hello"},
+		{"hide cursor", "\x1b[?25lhello\x1b[?25h", "hello"},
+		{"window manipulation", "\x1b[8;24;80thello", "hello"},
+		{"device status report", "\x1b[6nhello", "hello"},
+		{"cursor style with intermediate", "\x1b[2 qhello", "hello"},
+		{"xterm modifyOtherKeys", "\x1b[>4;2mhello", "hello"},
+		{"single ESC index", "\x1bDhello\x1bM", "hello"},
+		{"keypad mode", "\x1b=hello\x1b>", "hello"},
+		{"OSC title BEL", "\x1b]0;my title\x07hello", "hello"},
+		{"OSC title ST", "\x1b]2;my title\x1b\\hello", "hello"},
+		{"OSC hyperlink", "\x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"DCS", "a\x1bPq#0;2;0;0;0\x1b\\b", "ab"},
+		{"APC", "a\x1b_Gf=24;payload\x1b\\b", "ab"},
+		{"PM", "a\x1b^private\x1b\\b", "ab"},
+		{"SOS", "a\x1bXstring\x1b\\b", "ab"},
+		{"bell", "ding\x07dong", "dingdong"},
+		{"backspace", "ab\x08c", "abc"},
+		{"trailing CR", "hello\r", "hello"},
+		{"CR overwrite keeps last segment", "progress 10%\rprogress 20%\rprogress 30%", "progress 30%"},
+		{"CR with trailing empty segment", "done\r\r", "done"},
+		{"keeps SGR color", "\x1b[32mgreen\x1b[0m", "\x1b[32mgreen\x1b[0m"},
+		{"keeps SGR with 256 color", "\x1b[38;5;208morange\x1b[m", "\x1b[38;5;208morange\x1b[m"},
+		{"keeps SGR but strips cursor", "\x1b[1m\x1b[2Kbold\x1b[0m", "\x1b[1mbold\x1b[0m"},
+		{"partial CSI at end", "hello\x1b[3", "hello"},
+		{"partial CSI private at end", "hello\x1b[?25", "hello"},
+		{"lone ESC at end", "hello\x1b", "hello"},
+		{"partial CSI exposed by stripping", "hello\x1b[3\x1b[K", "hello"},
+		{"charset designation", "\x1b(B\x1b[mhello", "\x1b[mhello"},
+		{"partial OSC at end", "hello\x1b]0;half a tit", "hello"},
+		{"partial DCS at end", "hello\x1bPq#0", "hello"},
+		{"tabs preserved", "a\tb", "a\tb"},
+		{"unicode preserved", "héllo 世界 🚀", "héllo 世界 🚀"},
+		{"SGR with colon params", "\x1b[38:2:255:0:0mred", "\x1b[38:2:255:0:0mred"},
+		{"CSI interrupted by ESC", "a\x1b[3\x1b[31mb", "a\x1b[31mb"},
+		{"CSI interrupted by control", "a\x1b[3\x07b", "ab"},
+		{"OSC interrupted by SGR", "a\x1b]0;title\x1b[1mb", "a\x1b[1mb"},
+		{"ESC before multibyte rune", "a\x1bé", "aé"},
+		{"delete char", "a\x7fb", "ab"},
+		{"newline preserved", "a\nb", "a\nb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeLogContent(tt.in); got != tt.want {
+				t.Fatalf("sanitizeLogContent(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeLogContentOnlyKeepsSGR(t *testing.T) {
+	// Whatever the input, the only escapes left must be complete SGR sequences
+	// and no control characters other than tab and newline may survive
+	pieces := []string{
+		"x", "世", "\x1b", "[", "]", "P", "\\", "m", "1", ";", "?", " ", "\x07", "\x08", "\x7f", "\t", "(", "B",
+	}
+	rng := rand.New(rand.NewSource(1))
+	for n := 0; n < 5000; n++ {
+		var b strings.Builder
+		for i := rng.Intn(24); i >= 0; i-- {
+			b.WriteString(pieces[rng.Intn(len(pieces))])
+		}
+		in := b.String()
+		got := sanitizeLogContent(in)
+
+		for i := 0; i < len(got); i++ {
+			c := got[i]
+			switch {
+			case c == 0x1b:
+				end := strings.IndexByte(got[i:], 'm')
+				if !strings.HasPrefix(got[i:], "\x1b[") || end < 0 || strings.Trim(got[i+2:i+end], "0123456789;:") != "" {
+					t.Fatalf("sanitizeLogContent(%q) = %q leaves a non-SGR escape", in, got)
+				}
+				i += end
+			case c == '\t' || c == '\n':
+			case c < 0x20 || c == 0x7f:
+				t.Fatalf("sanitizeLogContent(%q) = %q leaves control char %#x", in, got, c)
+			}
+		}
+	}
+}
+
+func TestSanitizeLogContentTruncatesRuneSafe(t *testing.T) {
+	// Multi-byte runes straddling the byte limit must not be split
+	in := strings.Repeat("世", 600)
+	got := sanitizeLogContent(in)
+
+	if !utf8.ValidString(got) {
+		t.Fatalf("expected valid UTF-8 after truncation")
+	}
+	if !strings.HasSuffix(got, "...") {
+		t.Fatalf("expected truncation marker, got suffix %q", got[len(got)-6:])
+	}
+	if n := len(strings.TrimSuffix(got, "...")); n > maxLineLength || n < maxLineLength-utf8.UTFMax {
+		t.Fatalf("expected cut close to %d bytes, got %d", maxLineLength, n)
+	}
+}
+
+func TestSanitizeLogContentTruncationDoesNotSplitEscape(t *testing.T) {
+	// An SGR sequence cut in half by truncation must not leak a partial escape
+	in := strings.Repeat("a", maxLineLength-3) + "\x1b[38;5;208mtail"
+	got := sanitizeLogContent(in)
+
+	body := strings.TrimSuffix(got, "...")
+	if strings.Contains(body, "\x1b") && !strings.HasSuffix(body, "m") {
+		t.Fatalf("truncation left a partial escape: %q", body[len(body)-10:])
+	}
+}
+
+func TestSanitizeLogContentShortLineNotTruncated(t *testing.T) {
+	in := strings.Repeat("x", maxLineLength)
+	if got := sanitizeLogContent(in); got != in {
+		t.Fatalf("expected line at the limit to be kept intact")
+	}
+}
@@ -0,0 +1,125 @@
+package logview
+
+import (
+	"strings"
+
+	"cm/internal/config"
+	"cm/internal/docker"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// restoreTabMsg switches the maximized pane to the saved view's tab once the
+// model is running, so tabs that stream (stats, top) start like a key press
+type restoreTabMsg struct{}
+
+// savedViewFor returns the saved view of the compose project all containers
+// belong to, or nil if they span projects or the project has no view
+func savedViewFor(containers []docker.Container) *config.SavedView {
+	if len(containers) == 0 {
+		return nil
+	}
+	project := containers[0].ComposeProject
+	if project == "" || containers[0].IsComposeLogs() {
+		return nil
+	}
+	for _, c := range containers[1:] {
+		if c.ComposeProject != project {
+			return nil
+		}
+	}
+	return config.LoadProjects().SavedProjects[project].View
+}
+
+// orderByServices returns the containers in the order of services; those
+// not listed keep their relative order after the listed ones
+func orderByServices(containers []docker.Container, services []string) []docker.Container {
+	ordered := make([]docker.Container, 0, len(containers))
+	used := make([]bool, len(containers))
+	for _, svc := range services {
+		for i, c := range containers {
+			if !used[i] && c.ComposeService == svc {
+				ordered = append(ordered, c)
+				used[i] = true
+			}
+		}
+	}
+	for i, c := range containers {
+		if !used[i] {
+			ordered = append(ordered, c)
+		}
+	}
+	return ordered
+}
+
+// applySavedView restores word wrap, pane sizes and the maximized pane from
+// a saved view. The panes must already be in the view's order.
+func (m *Model) applySavedView(view *config.SavedView) {
+	m.wordWrap = view.WordWrap
+	for i := range m.panes {
+		m.panes[i].SetWordWrap(m.wordWrap)
+	}
+
+	// Ratios only fit if the grid has the same shape as when it was saved
+	if len(view.ColumnRatios) == m.layout.Cols && len(view.RowRatios) == m.layout.Rows {
+		m.layout.ColumnRatios = append([]float64(nil), view.ColumnRatios...)
+		m.layout.RowRatios = append([]float64(nil), view.RowRatios...)
+	}
+
+	if view.Maximized == "" {
+		return
+	}
+	for i := range m.panes {
+		if m.panes[i].Container.ComposeService == view.Maximized {
+			m.panes[m.focusedPane].Active = false
+			m.focusedPane = i
+			m.maximizedPane = i
+			m.panes[i].Active = true
+			if tab, ok := ParseTab(view.Tab); ok {
+				m.restoreTab = tab
+			}
+			return
+		}
+	}
+}
+
+// currentViews describes what the log view shows for each compose project
+// among the panes, for saving as that project's view
+func (m Model) currentViews() map[string]config.SavedView {
+	views := make(map[string]config.SavedView)
+	projects := make(map[string]bool)
+	for i := range m.panes {
+		c := m.panes[i].Container
+		if c.ComposeProject == "" || c.ComposeService == "" {
+			continue
+		}
+		projects[c.ComposeProject] = true
+		view := views[c.ComposeProject]
+		view.Services = append(view.Services, c.ComposeService)
+		view.WordWrap = m.wordWrap
+		if i == m.maximizedPane {
+			view.Maximized = c.ComposeService
+			view.Tab = strings.ToLower(m.panes[i].GetActiveTab().String())
+		}
+		views[c.ComposeProject] = view
+	}
+
+	// The grid shape belongs to the whole log view, so it's only saved when
+	// every pane is from the same project
+	if len(projects) == 1 && len(views[m.panes[0].Container.ComposeProject].Services) == len(m.panes) {
+		project := m.panes[0].Container.ComposeProject
+		view := views[project]
+		view.ColumnRatios = append([]float64(nil), m.layout.ColumnRatios...)
+		view.RowRatios = append([]float64(nil), m.layout.RowRatios...)
+		views[project] = view
+	}
+	return views
+}
+
+// restoreTabCmd asks for the saved view's tab to be switched to after startup
+func (m Model) restoreTabCmd() tea.Cmd {
+	if m.restoreTab == TabLogs {
+		return nil
+	}
+	return func() tea.Msg { return restoreTabMsg{} }
+}
@@ -1,9 +1,5 @@
 package logview
 
-import (
-	"cm/internal/debug"
-)
-
 // Selection tracks mouse text selection state with character-level precision
 type Selection struct {
 	Selecting bool // Whether a drag selection is in progress
@@ -11,9 +7,15 @@ type Selection struct {
 	PaneIdx   int  // Which pane is being selected in
 	PaneX     int  // Pane's X offset on screen
 	PaneY     int  // Pane's Y offset on screen
+	YOffset   int  // Pane's viewport scroll offset, so lines count from the top of the content
+
+	// Where the log lines sit inside the pane, set before Start
+	HeaderRows int // Rows from the pane's top edge to its first log line
+	Width      int // Visible content columns, excluding the scrollbar (0: unclamped)
+	Height     int // Visible content rows (0: unclamped)
 
 	// Start position (where mouse was pressed)
-	StartLine int // Line number relative to viewport content
+	StartLine int // Line number within the pane's content
 	StartCol  int // Column (character) position within the line
 
 	// End position (current drag position)
@@ -21,12 +23,16 @@ type Selection struct {
 	EndCol  int
 }
 
+// gridHeaderRows is the top border plus the title line of a tiled pane
+const gridHeaderRows = 2
+
 // NewSelection creates a new empty selection
 func NewSelection() Selection {
 	return Selection{
-		Selecting: false,
-		Selected:  false,
-		PaneIdx:   -1,
+		Selecting:  false,
+		Selected:   false,
+		PaneIdx:    -1,
+		HeaderRows: gridHeaderRows,
 	}
 }
 
@@ -42,7 +48,7 @@ func (s *Selection) Start(screenX, screenY, paneIdx, paneX, paneY int) {
 	s.StartLine, s.StartCol = s.screenToLineCol(screenX, screenY)
 	s.EndLine = s.StartLine
 	s.EndCol = s.StartCol
-	debug.Log("Selection.Start: screen(%d,%d) pane(%d) panePos(%d,%d) -> line=%d col=%d",
+	logger.Debug("Selection.Start: screen(%d,%d) pane(%d) panePos(%d,%d) -> line=%d col=%d",
 		screenX, screenY, paneIdx, paneX, paneY, s.StartLine, s.StartCol)
 }
 
@@ -52,7 +58,7 @@ func (s *Selection) Update(screenX, screenY int) {
 		return
 	}
 	s.EndLine, s.EndCol = s.screenToLineCol(screenX, screenY)
-	debug.Log("Selection.Update: screen(%d,%d) -> endLine=%d endCol=%d", screenX, screenY, s.EndLine, s.EndCol)
+	logger.Debug("Selection.Update: screen(%d,%d) -> endLine=%d endCol=%d", screenX, screenY, s.EndLine, s.EndCol)
 }
 
 // Finalize completes the current drag selection and makes it persistent.
@@ -66,24 +72,36 @@ func (s *Selection) Finalize() bool {
 	return s.Selected
 }
 
-// screenToLineCol converts screen coordinates to line and column
+// ContentTop returns the screen row of the pane's first visible log line
+func (s *Selection) ContentTop() int {
+	return s.PaneY + s.HeaderRows
+}
+
+// screenToLineCol converts screen coordinates to line and column. Positions on
+// the border, title or scrollbar clamp to the nearest content cell; a column
+// one past the last visible one selects to the end of the line.
 func (s *Selection) screenToLineCol(screenX, screenY int) (line, col int) {
-	// Account for pane position, border (1 char) and title (1 line)
 	contentStartX := s.PaneX + 1 // Border
-	contentStartY := s.PaneY + 2 // Border + title line
+	contentStartY := s.ContentTop()
 
 	line = screenY - contentStartY
 	col = screenX - contentStartX
 
-	// Clamp to non-negative
+	// Clamp to the visible content area
 	if line < 0 {
 		line = 0
 	}
+	if s.Height > 0 && line >= s.Height {
+		line = s.Height - 1
+	}
 	if col < 0 {
 		col = 0
 	}
+	if s.Width > 0 && col > s.Width {
+		col = s.Width
+	}
 
-	return line, col
+	return line + s.YOffset, col
 }
 
 // Clear clears the selection
@@ -109,7 +127,7 @@ func (s *Selection) GetNormalizedRange() (startLine, startCol, endLine, endCol i
 		startCol, endCol = endCol, startCol
 	}
 
-	debug.Log("Selection.GetNormalizedRange: (%d,%d) to (%d,%d)", startLine, startCol, endLine, endCol)
+	logger.Debug("Selection.GetNormalizedRange: (%d,%d) to (%d,%d)", startLine, startCol, endLine, endCol)
 	return startLine, startCol, endLine, endCol
 }
 
@@ -76,3 +76,32 @@ func TestSelectionHasSelectedText(t *testing.T) {
 		t.Fatalf("expected no selected text on zero-width selection")
 	}
 }
+
+func TestSelectionClampsEdgePositionsToContent(t *testing.T) {
+	s := NewSelection()
+	s.Width, s.Height = 20, 5 // content origin is (11,7)
+
+	s.Start(10, 5, 0, 10, 5) // top-left border corner
+	if s.StartLine != 0 || s.StartCol != 0 {
+		t.Fatalf("expected the border corner to map to (0,0), got (%d,%d)", s.StartLine, s.StartCol)
+	}
+	s.Update(11, 6) // title line
+	if s.EndLine != 0 {
+		t.Fatalf("expected the title line to map to line 0, got %d", s.EndLine)
+	}
+	s.Update(31, 11) // scrollbar column on the last content row
+	if s.EndLine != 4 || s.EndCol != 20 {
+		t.Fatalf("expected the scrollbar to select to the end of line 4, got (%d,%d)", s.EndLine, s.EndCol)
+	}
+	s.Update(33, 12) // right and bottom borders
+	if s.EndLine != 4 || s.EndCol != 20 {
+		t.Fatalf("expected the bottom-right border to clamp to (4,20), got (%d,%d)", s.EndLine, s.EndCol)
+	}
+
+	s.HeaderRows = 3 // maximized: tab bar above the title
+	s.YOffset = 40
+	s.Start(11, 8, 0, 10, 5)
+	if s.StartLine != 40 || s.StartCol != 0 {
+		t.Fatalf("expected the first row under the tab bar to be line 40, got (%d,%d)", s.StartLine, s.StartCol)
+	}
+}
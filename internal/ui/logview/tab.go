@@ -1,5 +1,7 @@
 package logview
 
+import "strings"
+
 // TabType represents the different tab views in maximized mode
 type TabType int
 
@@ -9,10 +11,11 @@ const (
 	TabEnv
 	TabConfig
 	TabTop
+	TabEvents
 )
 
 // TabNames contains the display names for each tab
-var TabNames = []string{"Logs", "Stats", "Env", "Config", "Top"}
+var TabNames = []string{"Logs", "Stats", "Env", "Config", "Top", "Events"}
 
 // String returns the display name for a tab
 func (t TabType) String() string {
@@ -26,3 +29,13 @@ func (t TabType) String() string {
 func TabCount() int {
 	return len(TabNames)
 }
+
+// ParseTab returns the tab with the given name, matched case-insensitively
+func ParseTab(name string) (TabType, bool) {
+	for i, n := range TabNames {
+		if strings.EqualFold(n, name) {
+			return TabType(i), true
+		}
+	}
+	return TabLogs, false
+}
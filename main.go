@@ -11,10 +11,14 @@ import (
 	"cm/internal/docker"
 	"cm/internal/notify"
 	"cm/internal/ui"
+	"cm/internal/ui/common"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// logger tags startup and shutdown debug log messages
+var logger = debug.For("main")
+
 // Version information set by ldflags
 var (
 	Version   = "dev"
@@ -22,6 +26,18 @@ var (
 	BuildTime = "unknown"
 )
 
+// optionsHelp lists the command line flags, for --help and --usage-full
+const optionsHelp = `  -h, --help      Show this help message
+  -v, --version   Show version information
+  --doctor        Check Docker, compose, clipboard, terminal and config files
+  -d, --debug     Enable debug logging (<config dir>/debug.log)
+  --debug-file PATH
+                  Write debug logging to PATH instead (or set CM_DEBUG_FILE)
+  --completion SHELL
+                  Print a completion script for bash, zsh or fish
+  --usage-full    Print every flag, key binding and setting, as configured
+`
+
 func printHelp() {
 	help := `
     ██████╗███╗   ███╗
@@ -41,57 +57,123 @@ ARGUMENTS
                (partial matches supported)
 
 OPTIONS
-  -h, --help      Show this help message
-  -v, --version   Show version information
-  -d, --debug     Enable debug logging (~/.cm/debug.log)
-
+` + optionsHelp + `
 EXAMPLES
   cm              Start interactive container selector
   cm api          Stream logs from container matching "api"
   cm api db       Stream logs from multiple containers
+  cm shop         Open the saved view of project "shop"
 
 KEYBINDINGS
-  j/k, ↑/↓        Navigate up/down
+  j/k, ↑/↓        Navigate up/down (5j moves 5)
   space           Select/deselect container
   a/A             Select all / Clear selection
   enter           Confirm and view logs
+  N               Jump to the pane with the newest log line (log view)
+  alt+s           Scroll all panes together (toggle, log view)
+  ctrl+t          Keep panes at the scrolled one's time (toggle, log view)
+  shift+arrows    Move focused pane in the grid (log view)
+  < > - +         Resize focused pane's column / row
+  =               Balance the grid after resizing
+  I               Group by project / image
+  .               Only the current directory's project / all projects
+  alt+c           Copy a cm command that opens the selected containers
+  X               Remove all stopped containers (asks first)
+  M               System menu: prune containers, dangling images, volumes
   u/s/r           Start/stop/restart container
-  b               Build and restart (compose)
+  z               Pause/unpause container (freezes its processes)
+  ctrl+k          Send a signal to container (log view)
+  ctrl+n          Rename container (log view, also from inspect)
+  alt+l           Reconnect every disconnected pane (log view)
+  b               Build and recreate (compose, or docker build for standalone)
+  l               Follow docker compose logs for the whole project
   ctrl+shift+c    Copy selected text
   y               Copy logs to clipboard
+  alt+y           Copy logs with ANSI colors
+  E               Export full log history to <config dir>/exports
+  v               Open the pane's logs in $PAGER (less)
+  Y / ctrl+y      Copy container ID / name
+  ctrl+e          Copy a docker exec command for the container
   w               Toggle word wrap
+  T               Toggle UTC/local timestamps
+  alt+t           Toggle timestamps in copied text
+  o               Cycle stdout+stderr / stdout / stderr
+  F / x           Freeze layout / dismiss exited pane
   p               Manage saved projects
   c               Open configuration
+  C               Reload config files
   ctrl+g          Toggle debug logging
+  ctrl+o          Toggle debug overlay (log view, debug on)
   q               Quit
 
 CONFIG FILES
-  ~/.cm/config.json        General settings
-  ~/.cm/keybindings.json   Key bindings
-  ~/.cm/projects.json      Saved compose projects
+  config.json        General settings
+  keybindings.json   Key bindings
+  projects.json      Saved compose projects
+
+  The config dir is $XDG_CONFIG_HOME/cm (default ~/.config/cm) on Linux and
+  ~/.cm elsewhere. An existing ~/.cm is moved there once on Linux.
+
+ENVIRONMENT
+  CM_NOTIFY_MODE, CM_TOAST_DURATION, CM_TOAST_POSITION, CM_LOG_BUFFER
+                  Override the matching config.json settings
+  CM_DEBUG_FILE   Write debug logging to this path
 `
 	fmt.Println(help)
 }
 
 func main() {
-	// Ensure config files exist with defaults (runs early to update keybindings)
-	_ = config.EnsureDefaults()
+	// os.Exit skips deferred calls, so all cleanup lives in run
+	os.Exit(run())
+}
 
+// run starts the application and returns the process exit code. Every
+// resource is released by a deferred call so cleanup also happens when the
+// program errors out or panics.
+func run() (code int) {
 	// Parse flags and arguments
 	debugMode := false
+	debugFile := os.Getenv("CM_DEBUG_FILE")
 	var containerArgs []string
 
-	for _, arg := range os.Args[1:] {
+	args := os.Args[1:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
 		switch arg {
 		case "-h", "--help":
 			printHelp()
-			os.Exit(0)
+			return 0
 		case "-v", "--version":
 			fmt.Printf("cm %s (commit: %s, built: %s)\n", Version, Commit, BuildTime)
-			os.Exit(0)
+			return 0
+		case "--doctor":
+			// Runs before EnsureDefaults so a broken config file is reported, not rewritten
+			return runDoctor()
+		case "--usage-full":
+			return runUsageFull()
+		case "--completion":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: --completion needs a shell: bash, zsh or fish\n")
+				return 1
+			}
+			return runCompletion(args[i+1])
+		case "--list-containers":
+			// Hidden: names for shell completion
+			return runListContainers()
 		case "-d", "--debug":
 			debugMode = true
+		case "--debug-file":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "Error: --debug-file needs a path\n")
+				return 1
+			}
+			i++
+			debugFile = args[i]
 		default:
+			if path, ok := strings.CutPrefix(arg, "--debug-file="); ok {
+				debugFile = path
+				continue
+			}
 			// Treat as container name if not a flag
 			if !strings.HasPrefix(arg, "-") {
 				containerArgs = append(containerArgs, arg)
@@ -99,12 +181,29 @@ func main() {
 		}
 	}
 
+	// Move a pre-XDG ~/.cm into place before anything reads the config
+	migratedFrom, migratedTo, migrateErr := config.MigrateLegacyDir()
+
+	// Ensure config files exist with defaults (runs early to update keybindings)
+	_ = config.EnsureDefaults()
+
 	// Initialize debug logging
-	debug.Init(debugMode)
+	if cfg, err := config.Load(); err == nil {
+		debug.Configure(cfg.GetDebugSettings())
+	}
+	debug.Init(debugMode, debugFile)
 	defer debug.Close()
 	if debugMode {
 		fmt.Fprintf(os.Stderr, "Debug logging enabled: %s\n", debug.LogPath())
 	}
+	for _, w := range config.EnvWarnings() {
+		logger.Warn("%s", w)
+	}
+	if migratedTo != "" {
+		logger.Info("Migrated config dir %s to %s", migratedFrom, migratedTo)
+	} else if migrateErr != nil {
+		logger.Warn("Config dir migration failed: %v", migrateErr)
+	}
 
 	// Initialize notification system
 	notify.Initialize()
@@ -115,11 +214,19 @@ func main() {
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		fmt.Fprintf(os.Stderr, "Make sure Docker is running and accessible.\n")
-		os.Exit(1)
+		return 1
 	}
-	defer func() { _ = dockerClient.Close() }()
+	// Saves dirty config/projects; runs last so a panic still reaches it
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Panic: %v", r)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", r)
+			code = 1
+		}
+		_ = dockerClient.Close()
+	}()
 
-	debug.Log("Docker client initialized")
+	logger.Info("Docker client initialized")
 
 	// Check for container name arguments
 	var initialContainers []docker.Container
@@ -127,23 +234,35 @@ func main() {
 		initialContainers = findContainersByName(dockerClient, containerArgs)
 		if len(initialContainers) == 0 {
 			fmt.Fprintf(os.Stderr, "No matching containers found for: %s\n", strings.Join(containerArgs, ", "))
-			os.Exit(1)
+			return 1
 		}
-		debug.Log("Found %d containers matching args: %v", len(initialContainers), containerArgs)
+		logger.Info("Found %d containers matching args: %v", len(initialContainers), containerArgs)
 	}
 
 	// Create and run the application
 	app := ui.NewApp(dockerClient, initialContainers)
+	// Shown as a toast, since anything printed now is hidden by the alt screen
+	if migratedTo != "" {
+		app = app.WithStartupNotice("Config moved", fmt.Sprintf("%s to %s", migratedFrom, migratedTo), common.ToastInfo)
+	} else if migrateErr != nil {
+		app = app.WithStartupNotice("Could not move ~/.cm", "Still using it: "+migrateErr.Error(), common.ToastError)
+	}
 	p := tea.NewProgram(
 		app,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(), // Use cell motion instead of all motion for better terminal compatibility
 	)
 
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	// The model is also returned when the program stops on an error or panic
+	if app, ok := final.(ui.App); ok {
+		app.Cleanup()
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running application: %v\n", err)
-		os.Exit(1)
+		return 1
 	}
+	return 0
 }
 
 // findContainersByName finds containers matching the given names
@@ -153,15 +272,24 @@ func findContainersByName(client *docker.Client, names []string) []docker.Contai
 		return nil
 	}
 
+	projects := config.LoadProjects()
+
 	var matched []docker.Container
 	for _, name := range names {
 		name = strings.ToLower(strings.TrimSpace(name))
+		// A saved project with a view opens the services of that view
+		viewServices := make(map[string]bool)
+		for projName, proj := range projects.SavedProjects {
+			if strings.ToLower(projName) == name && proj.View != nil {
+				for _, svc := range proj.View.Services {
+					viewServices[projName+"/"+svc] = true
+				}
+			}
+		}
 		for _, c := range containers {
-			// Match against service name or container name
-			if strings.ToLower(c.ComposeService) == name ||
-				strings.ToLower(c.Name) == name ||
-				strings.Contains(strings.ToLower(c.Name), name) ||
-				strings.Contains(strings.ToLower(c.ComposeService), name) {
+			// Match against the saved view, service name or container name
+			if viewServices[c.ComposeProject+"/"+c.ComposeService] ||
+				(len(viewServices) == 0 && c.MatchesName(name)) {
 				// Avoid duplicates
 				found := false
 				for _, m := range matched {
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"cm/internal/config"
+	"cm/internal/debug"
+)
+
+// runUsageFull prints a plain reference of cm's flags, every key binding and
+// every config.json setting, for a pager or a docs generator. Bindings and
+// settings are read from the user's config files, so the reference shows the
+// values in effect, with the default next to any that were changed.
+func runUsageFull() int {
+	fmt.Printf("cm %s\n\nOPTIONS\n%s\n", Version, optionsHelp)
+
+	fmt.Printf("KEY BINDINGS (%s)\n", config.GetKeybindingsPath())
+	bindings := reflect.ValueOf(config.LoadKeyBindings())
+	defaults := reflect.ValueOf(config.DefaultKeyBindings())
+	for i := 0; i < bindings.NumField(); i++ {
+		name := jsonName(bindings.Type().Field(i))
+		if name == "" {
+			continue
+		}
+		value, def := bindings.Field(i).String(), defaults.Field(i).String()
+		if value != def {
+			fmt.Printf("  %-22s %s (default %s)\n", name, value, def)
+		} else {
+			fmt.Printf("  %-22s %s\n", name, value)
+		}
+	}
+
+	fmt.Printf("\nSETTINGS (%s)\n", config.GetConfigPath())
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("  (config.json could not be read, showing defaults: %v)\n", err)
+		cfg = &config.Config{}
+	}
+	// Defaults are compared without env overrides, so an overridden value
+	// shows as changed
+	defaultCfg := &config.Config{}
+	var tutorial config.TutorialSettings
+	if cfg.Tutorial != nil {
+		tutorial = *cfg.Tutorial
+	}
+	for _, section := range []struct {
+		name          string
+		value, defval interface{}
+	}{
+		{"notifications", cfg.GetNotificationSettings(), defaultCfg.SavedNotificationSettings()},
+		{"display", effectiveDisplay(cfg.GetDisplaySettings()), effectiveDisplay(defaultCfg.SavedDisplaySettings())},
+		{"reconnect", cfg.GetReconnectSettings(), defaultCfg.GetReconnectSettings()},
+		{"discovery", cfg.GetDiscoverySettings(), defaultCfg.GetDiscoverySettings()},
+		{"builds", cfg.GetBuildSettings(), defaultCfg.GetBuildSettings()},
+		{"reload", cfg.GetReloadSettings(), defaultCfg.GetReloadSettings()},
+		{"debug", effectiveDebug(cfg.GetDebugSettings()), effectiveDebug(defaultCfg.GetDebugSettings())},
+		{"tutorial", tutorial, config.TutorialSettings{}},
+	} {
+		printSettings(section.name, reflect.ValueOf(section.value), reflect.ValueOf(section.defval))
+	}
+	// Alerts and build contexts are lists; list an entry's fields
+	fmt.Printf("  %-40s %s (%d configured)\n", "alerts[]", fieldNames(config.AlertRule{}), len(cfg.GetAlertRules()))
+	fmt.Printf("  %-40s %s (%d remembered)\n", "build_contexts.<container>", fieldNames(config.BuildContext{}), len(cfg.BuildContexts))
+	return 0
+}
+
+// effectiveDisplay fills in the display settings left empty or out of range
+// with the values cm uses in their place
+func effectiveDisplay(d config.DisplaySettings) config.DisplaySettings {
+	if d.Layout != "balanced" {
+		d.Layout = "wide"
+	}
+	if d.StreamFilter != "stdout" && d.StreamFilter != "stderr" {
+		d.StreamFilter = "both"
+	}
+	d.LogBuffer = d.GetLogBuffer()
+	return d
+}
+
+// effectiveDebug fills in the debug settings left empty or out of range with
+// the values cm uses in their place
+func effectiveDebug(d config.DebugSettings) config.DebugSettings {
+	level, _ := debug.ParseLevel(d.Level)
+	d.Level = strings.ToLower(level.String())
+	d.MaxLogSizeMB = int(d.GetMaxLogSize() / (1024 * 1024))
+	return d
+}
+
+// fieldNames lists the config file names of a struct's fields
+func fieldNames(v interface{}) string {
+	var fields []string
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		fields = append(fields, jsonName(t.Field(i)))
+	}
+	return strings.Join(fields, ", ")
+}
+
+// printSettings prints the fields of a settings section as section.field
+func printSettings(section string, value, defval reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		name := jsonName(value.Type().Field(i))
+		if name == "" {
+			continue
+		}
+		v, def := formatSetting(value.Field(i)), formatSetting(defval.Field(i))
+		key := section + "." + name
+		if v != def {
+			fmt.Printf("  %-40s %s (default %s)\n", key, v, def)
+		} else {
+			fmt.Printf("  %-40s %s\n", key, v)
+		}
+	}
+}
+
+// formatSetting formats a setting's value as it's written in config.json
+func formatSetting(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return strconv.Quote(v.String())
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+// jsonName returns the name a struct field has in the config files
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}